@@ -0,0 +1,112 @@
+// Command catalog exports the catalog to a file or imports one.
+//
+//	go run ./cmd/catalog export [-format json|csv] [-kind products] [-o file]
+//	go run ./cmd/catalog import [-format json|csv] [-kind products] [-dry-run] file
+//
+// CSV files hold one kind of record: departments, categories, products or
+// options. Imports upsert departments and categories by name, products by
+// SKU and options by product, type and name; any invalid row rejects the
+// whole file. Exports are refused while a product has no SKU.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"furniture-shop/internal/config"
+	"furniture-shop/internal/database"
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service/catalogio"
+	domain "furniture-shop/internal/service/domain"
+	pg "furniture-shop/internal/storage/postgres"
+)
+
+func main() {
+	if len(os.Args) < 2 || (os.Args[1] != "export" && os.Args[1] != "import") {
+		fmt.Fprintln(os.Stderr, "usage: catalog export|import [flags]")
+		os.Exit(2)
+	}
+	cmd := os.Args[1]
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	format := fs.String("format", catalogio.FormatJSON, "file format: json or csv")
+	kind := fs.String("kind", "", "record kind of a CSV file")
+	out := fs.String("o", "", "export to this file instead of stdout")
+	dryRun := fs.Bool("dry-run", false, "validate the import and report without writing")
+	if err := fs.Parse(os.Args[2:]); err != nil {
+		// the flag set has printed the error and the usage
+		if err == flag.ErrHelp {
+			return
+		}
+		os.Exit(2)
+	}
+
+	if err := config.LoadEnvFile(); err != nil {
+		log.Fatalf("Env load failed: %v", err)
+	}
+	if err := config.LoadConfig(); err != nil {
+		log.Fatalf("Config load failed: %v", err)
+	}
+	if err := database.Connect(); err != nil {
+		log.Fatalf("DB connection failed: %v", err)
+	}
+
+	// the full service, so that imports alert customers waiting for products
+	services, err := domain.NewService(pg.NewRepository(database.DB), config.Env.JWTSecret)
+	if err != nil {
+		log.Fatalf("Service setup failed: %v", err)
+	}
+	svc := services.Transfer
+	ctx := context.Background()
+
+	if cmd == "export" {
+		doc, err := svc.Export(ctx)
+		if err != nil {
+			log.Fatalf("Export failed: %v", err)
+		}
+		var w io.Writer = os.Stdout
+		if *out != "" {
+			f, err := os.Create(*out)
+			if err != nil {
+				log.Fatalf("Export failed: %v", err)
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := catalogio.Encode(w, doc, *format, *kind); err != nil {
+			log.Fatalf("Export failed: %v", err)
+		}
+		return
+	}
+
+	if fs.NArg() != 1 {
+		log.Fatal("import needs exactly one file")
+	}
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+	defer f.Close()
+	doc, rowErrs, err := catalogio.Decode(f, *format, *kind)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+	rep := &ec.ImportReport{DryRun: *dryRun, Errors: rowErrs}
+	if len(rowErrs) == 0 {
+		if rep, err = svc.Import(ctx, doc, *dryRun); err != nil {
+			log.Fatalf("Import failed: %v", err)
+		}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(rep); err != nil {
+		log.Fatalf("Import report failed: %v", err)
+	}
+	if len(rep.Errors) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,132 @@
+// Command migrate-blobs moves files referenced as /uploads/... from the old
+// upload directory into the configured blob store, under content-addressed
+// keys, and rewrites the references in the catalog.
+//
+//	go run ./cmd/migrate-blobs [-dir uploads] [-dry-run] [-delete]
+//
+// Gallery originals go to the private originals/ prefix, everything else to
+// images/. With -delete the old files are removed once their references
+// point at the store.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io/fs"
+	"log"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"furniture-shop/internal/config"
+	"furniture-shop/internal/database"
+	"furniture-shop/internal/service/blob"
+	"furniture-shop/internal/service/images"
+	pg "furniture-shop/internal/storage/postgres"
+)
+
+func main() {
+	dir := flag.String("dir", "uploads", "directory the /uploads/ URLs were served from")
+	dryRun := flag.Bool("dry-run", false, "only report what would be moved")
+	deleteOld := flag.Bool("delete", false, "remove migrated files from -dir")
+	flag.Parse()
+
+	if err := config.LoadEnvFile(); err != nil {
+		log.Fatalf("Env load failed: %v", err)
+	}
+	if err := config.LoadConfig(); err != nil {
+		log.Fatalf("Config load failed: %v", err)
+	}
+	if err := database.Connect(); err != nil {
+		log.Fatalf("DB connection failed: %v", err)
+	}
+	store, err := blob.NewFromConfig()
+	if err != nil {
+		log.Fatalf("Blob store setup failed: %v", err)
+	}
+	repos := pg.NewRepository(database.DB)
+	ctx := context.Background()
+
+	urls, err := repos.ProductImages.ReferencedURLs(ctx)
+	if err != nil {
+		log.Fatalf("Listing references failed: %v", err)
+	}
+	originals, err := repos.ProductImages.ListOriginalURLs(ctx)
+	if err != nil {
+		log.Fatalf("Listing originals failed: %v", err)
+	}
+	isOriginal := make(map[string]bool, len(originals))
+	for _, u := range originals {
+		isOriginal[u] = true
+	}
+
+	var moved, missing int
+	for _, u := range urls {
+		if key, ok := store.KeyFromURL(u); ok && (strings.HasPrefix(key, "images/") || strings.HasPrefix(key, "originals/")) {
+			continue // already in the store
+		}
+		rel, ok := legacyPath(u)
+		if !ok {
+			continue // external URL
+		}
+		src := filepath.Join(*dir, filepath.FromSlash(rel))
+		data, err := os.ReadFile(src)
+		if errors.Is(err, fs.ErrNotExist) {
+			log.Printf("missing %s (referenced as %s)", src, u)
+			missing++
+			continue
+		}
+		if err != nil {
+			log.Fatalf("Reading %s failed: %v", src, err)
+		}
+		prefix := "images"
+		if isOriginal[u] {
+			prefix = "originals"
+		}
+		ext := strings.ToLower(filepath.Ext(rel))
+		if format := images.Detect(data); format != "" {
+			ext = images.Ext(format)
+		}
+		key := blob.ContentKey(prefix, data, ext)
+		if *dryRun {
+			log.Printf("would move %s -> %s", u, store.URL(key))
+			moved++
+			continue
+		}
+		if _, err := blob.PutContent(ctx, store, prefix, data, ext, mime.TypeByExtension(ext)); err != nil {
+			log.Fatalf("Storing %s failed: %v", src, err)
+		}
+		n, err := repos.ProductImages.ReplaceURL(ctx, u, store.URL(key))
+		if err != nil {
+			log.Fatalf("Rewriting %s failed: %v", u, err)
+		}
+		log.Printf("moved %s -> %s (%d references)", u, store.URL(key), n)
+		moved++
+		if *deleteOld {
+			if err := os.Remove(src); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				log.Printf("removing %s: %v", src, err)
+			}
+		}
+	}
+	log.Printf("Migrated %d files, %d missing", moved, missing)
+}
+
+// legacyPath returns the path below /uploads/ of a relative or absolute
+// upload URL.
+func legacyPath(u string) (string, bool) {
+	if strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://") {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return "", false
+		}
+		u = parsed.Path
+	}
+	rel, ok := strings.CutPrefix(u, "/uploads/")
+	if !ok || rel == "" || strings.Contains(rel, "..") {
+		return "", false
+	}
+	return rel, true
+}
@@ -0,0 +1,52 @@
+// Command reindex rebuilds the product search index from the catalog tables.
+//
+//	go run ./cmd/reindex [-backend postgres|memory]
+//
+// The memory index lives inside the server process and is rebuilt on every
+// start, so running this command against it only checks that every product
+// can be indexed.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"furniture-shop/internal/config"
+	"furniture-shop/internal/database"
+	"furniture-shop/internal/service/search"
+	pg "furniture-shop/internal/storage/postgres"
+)
+
+func main() {
+	backend := flag.String("backend", "", "search backend to rebuild (defaults to SEARCH.BACKEND)")
+	flag.Parse()
+
+	if err := config.LoadEnvFile(); err != nil {
+		log.Fatalf("Env load failed: %v", err)
+	}
+	if err := config.LoadConfig(); err != nil {
+		log.Fatalf("Config load failed: %v", err)
+	}
+	if *backend != "" {
+		config.Configurations.Search.Backend = *backend
+	}
+	if err := database.Connect(); err != nil {
+		log.Fatalf("DB connection failed: %v", err)
+	}
+
+	repos := pg.NewRepository(database.DB)
+	index, err := search.NewFromConfig(repos.Products)
+	if err != nil {
+		log.Fatalf("Search setup failed: %v", err)
+	}
+	indexer := search.NewIndexer(index, repos.Departments, repos.Categories, repos.Products, repos.ProductOptions, repos.Translations)
+
+	start := time.Now()
+	n, err := indexer.Rebuild(context.Background())
+	if err != nil {
+		log.Fatalf("Reindex failed after %d products: %v", n, err)
+	}
+	log.Printf("Reindexed %d products into the %s index in %s", n, config.Configurations.Search.Backend, time.Since(start).Round(time.Millisecond))
+}
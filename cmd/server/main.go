@@ -1,10 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"furniture-shop/internal/config"
 	"furniture-shop/internal/database"
+	"furniture-shop/internal/jobs"
 	httpserver "furniture-shop/internal/server/http"
 	domain "furniture-shop/internal/service/domain"
 	pg "furniture-shop/internal/storage/postgres"
@@ -25,7 +31,92 @@ func main() {
 	}
 
 	repos := pg.NewRepository(database.DB)
-	svc := domain.NewService(repos, config.Env.JWTSecret)
+	if n, err := repos.Slugs.Backfill(context.Background()); err != nil {
+		log.Fatalf("Slug backfill failed: %v", err)
+	} else if n > 0 {
+		log.Printf("Assigned slugs to %d catalog records", n)
+	}
+	svc, err := domain.NewService(repos, config.Env.JWTSecret)
+	if err != nil {
+		log.Fatalf("Service setup failed: %v", err)
+	}
+	if config.Configurations.Search.Backend == "memory" {
+		n, err := svc.Admin.ReindexSearch(context.Background())
+		if err != nil {
+			log.Fatalf("Search index build failed: %v", err)
+		}
+		log.Printf("search: indexed %d products in memory", n)
+	}
+
+	runner := jobs.NewRunner(jobs.Job{
+		Name:     "outbox",
+		Interval: time.Duration(config.Configurations.Outbox.PollIntervalSeconds) * time.Second,
+		Run: func(ctx context.Context) error {
+			_, err := svc.Outbox.DispatchDue(ctx)
+			return err
+		},
+	}, jobs.Job{
+		Name:     "delivery-reminders",
+		Interval: 15 * time.Minute,
+		Run: func(ctx context.Context) error {
+			_, err := svc.Notification.SendDeliveryReminders(ctx, time.Now())
+			return err
+		},
+	}, jobs.Job{
+		Name:     "product-publishing",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			_, err := svc.Admin.PublishDue(ctx, time.Now())
+			return err
+		},
+	}, jobs.Job{
+		Name:     "price-schedules",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			_, err := svc.Admin.ApplyPriceSchedules(ctx, time.Now())
+			return err
+		},
+	}, jobs.Job{
+		Name:     "recommendations",
+		Interval: time.Duration(config.Configurations.Recommend.RefreshIntervalMinutes) * time.Minute,
+		Run: func(ctx context.Context) error {
+			_, err := svc.Recommend.Refresh(ctx)
+			return err
+		},
+	}, jobs.Job{
+		Name:     "events-rollup",
+		Interval: time.Duration(config.Configurations.Events.RollupIntervalMinutes) * time.Minute,
+		Run: func(ctx context.Context) error {
+			return svc.Analytics.Rollup(ctx, time.Now())
+		},
+	}, jobs.Job{
+		Name:     "image-cleanup",
+		Interval: time.Duration(config.Configurations.Images.CleanupIntervalMinutes) * time.Minute,
+		Run: func(ctx context.Context) error {
+			_, err := svc.Images.CleanupOrphans(ctx, time.Now())
+			return err
+		},
+	})
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	runner.Start(ctx)
+	analyticsDone := make(chan struct{})
+	go func() {
+		svc.Analytics.Run(ctx)
+		close(analyticsDone)
+	}()
+
 	srv := httpserver.NewServer(svc)
-	log.Fatal(srv.Run())
+	go func() {
+		<-ctx.Done()
+		if err := srv.Shutdown(); err != nil {
+			log.Printf("server shutdown: %v", err)
+		}
+	}()
+	if err := srv.Run(); err != nil {
+		log.Fatal(err)
+	}
+	// the server has stopped taking requests; flush the queued events
+	stop()
+	<-analyticsDone
 }
@@ -3,11 +3,16 @@ import { Link, Navigate, Route, Routes, useNavigate } from "react-router-dom";
 import Home from "./pages/Home";
 import Catalog from "./pages/Catalog";
 import ProductDetails from "./pages/ProductDetails";
+import Compare from "./pages/Compare";
 import Cart from "./pages/Cart";
 import Checkout from "./pages/Checkout";
 import Login from "./pages/Login";
 import Register from "./pages/Register";
 import Orders from "./pages/Orders";
+import Wishlists from "./pages/Wishlists";
+import SharedWishlist from "./pages/SharedWishlist";
+import Unsubscribe from "./pages/Unsubscribe";
+import ConfirmSubscription from "./pages/ConfirmSubscription";
 import PaymentSuccess from "./pages/PaymentSuccess";
 import PaymentCancel from "./pages/PaymentCancel";
 import AdminLayout from "./components/AdminLayout";
@@ -15,6 +20,8 @@ import AdminDepartments from "./pages/AdminDepartments";
 import AdminCategories from "./pages/AdminCategories";
 import AdminProducts from "./pages/AdminProducts";
 import AdminOrders from "./pages/AdminOrders";
+import AdminReviews from "./pages/AdminReviews";
+import AdminQuestions from "./pages/AdminQuestions";
 import { useCart } from "./store/CartContext";
 import { useAuth } from "./store/AuthContext";
 import { useI18n } from "./store/I18nContext";
@@ -59,6 +66,11 @@ export default function App() {
                   <Link to="/orders">{t("nav.orders")}</Link>
                 </Menu.Item>
               )}
+              {user?.role !== "admin" && (
+                <Menu.Item key="wishlists">
+                  <Link to="/wishlists">{t("nav.wishlists")}</Link>
+                </Menu.Item>
+              )}
               {user?.role === "admin" && (
                 <Menu.Item key="admin">
                   <Link to="/admin">{t("nav.admin")}</Link>
@@ -100,6 +112,7 @@ export default function App() {
           <Route path="/" element={<Home />} />
           <Route path="/catalog" element={<Catalog />} />
           <Route path="/product/:id" element={<ProductDetails />} />
+          <Route path="/compare" element={<Compare />} />
           <Route path="/cart" element={<Cart />} />
           <Route
             path="/checkout"
@@ -137,6 +150,17 @@ export default function App() {
               </RequireAuth>
             }
           />
+          <Route
+            path="/wishlists"
+            element={
+              <RequireAuth>
+                <Wishlists />
+              </RequireAuth>
+            }
+          />
+          <Route path="/wishlists/shared/:token" element={<SharedWishlist />} />
+          <Route path="/subscriptions/confirm/:token" element={<ConfirmSubscription />} />
+          <Route path="/unsubscribe/:token" element={<Unsubscribe />} />
           <Route
             path="/admin"
             element={<RequireRole role="admin">{<AdminLayout />}</RequireRole>}
@@ -146,6 +170,8 @@ export default function App() {
             <Route path="categories" element={<AdminCategories />} />
             <Route path="products" element={<AdminProducts />} />
             <Route path="orders" element={<AdminOrders />} />
+            <Route path="reviews" element={<AdminReviews />} />
+            <Route path="questions" element={<AdminQuestions />} />
           </Route>
         </Routes>
       </Content>
@@ -0,0 +1,29 @@
+import { Modal } from "antd";
+import { api } from "./client";
+
+// archiveRecord archives a department, category or product. When that would
+// orphan active children the API answers 409 and the user may archive them
+// along with it.
+export const archiveRecord = async (path: string) => {
+  try {
+    await api.delete(path);
+  } catch (e: any) {
+    if (e.response?.status !== 409) throw e;
+    const confirmed = await new Promise<boolean>((resolve) =>
+      Modal.confirm({
+        title: "Archive everything under it?",
+        content: e.response.data?.message,
+        okText: "Archive all",
+        okButtonProps: { danger: true },
+        onOk: () => resolve(true),
+        onCancel: () => resolve(false),
+      })
+    );
+    if (confirmed) await api.delete(path, { params: { cascade: true } });
+  }
+};
+
+// restoreRecord brings an archived record back together with whatever was
+// archived along with it.
+export const restoreRecord = (path: string) =>
+  api.post(`${path}/restore`, null, { params: { cascade: true } });
@@ -1,9 +1,16 @@
-import { api } from './client'
+import { api, fetchAllPages } from './client'
 
 export const fetchDepartments = () => api.get('/departments').then(r => r.data)
 export const fetchCategories = (deptId: number) => api.get(`/departments/${deptId}/categories`).then(r => r.data)
-export const fetchProductsByCategory = (catId: number) => api.get(`/categories/${catId}/products`).then(r => r.data)
-export const searchProducts = (q: string) => api.get('/products/search', { params: { query: q } }).then(r => r.data)
-export const fetchProduct = (id: number) => api.get(`/products/${id}`).then(r => r.data)
-export const fetchRecommendations = (id: number) => api.get(`/products/${id}/recommendations`).then(r => r.data)
+// attrs are code:value or code:min..max filters; params adds e.g. sort and rating_min
+export const fetchProductsByCategory = (catId: number, attrs: string[] = [], params: Record<string, any> = {}) =>
+  fetchAllPages(`/categories/${catId}/products`, attrs.length ? { ...params, attr: attrs } : params)
+export const fetchCategoryAttributes = (catId: number) => api.get(`/categories/${catId}/attributes`).then(r => r.data)
+export const searchProducts = (q: string) => api.get('/products/search', { params: { q } }).then(r => r.data.hits)
+// products are addressed by id or slug; admins may preview unpublished ones
+export const fetchProduct = (ref: number | string, preview = false) =>
+  api.get(`/products/${ref}`, { params: preview ? { preview: true } : {} }).then(r => r.data)
+export const compareProducts = (ids: number[]) => api.get('/products/compare', { params: { ids: ids.join(',') } }).then(r => r.data)
+export const fetchRecommendations = (ref: number | string) => api.get(`/products/${ref}/recommendations`).then(r => r.data)
+export const fetchStructuredData = (ref: number | string) => api.get(`/products/${ref}/structured-data`).then(r => r.data)
 
@@ -4,6 +4,8 @@ const API_URL = "http://localhost:8080/api";
 
 export const api = axios.create({
   baseURL: API_URL,
+  // repeated filters such as attr are sent as attr=a&attr=b
+  paramsSerializer: { indexes: null },
 });
 
 export const setAuthToken = (token?: string) => {
@@ -16,6 +18,27 @@ export const setAuthToken = (token?: string) => {
   }
 };
 
+// fetchAllPages walks a paginated listing ({ items, pagination }) and returns every item.
+export const fetchAllPages = async <T = any>(url: string, params: Record<string, any> = {}): Promise<T[]> => {
+  const items: T[] = [];
+  for (let page = 1; ; page++) {
+    const res = await api.get(url, { params: { ...params, page, size: 100 } });
+    items.push(...res.data.items);
+    if (!res.data.pagination.has_next) return items;
+  }
+};
+
+// An anonymous per-browser id groups product views for "viewed together" recommendations.
+const sessionId = (() => {
+  let id = localStorage.getItem("session_id");
+  if (!id) {
+    id = crypto.randomUUID();
+    localStorage.setItem("session_id", id);
+  }
+  return id;
+})();
+api.defaults.headers.common["X-Session-ID"] = sessionId;
+
 const existing = localStorage.getItem("token");
 if (existing) setAuthToken(existing);
 
@@ -0,0 +1,22 @@
+import { api } from './client'
+
+// params: answered, page, size
+export const fetchProductQuestions = (productId: number, params: Record<string, any> = {}) =>
+  api.get(`/products/${productId}/questions`, { params }).then(r => r.data)
+export const fetchMyQuestions = (params: Record<string, any> = {}) => api.get('/user/questions', { params }).then(r => r.data)
+export const askQuestion = (productId: number, body: string) =>
+  api.post(`/user/products/${productId}/questions`, { body }).then(r => r.data)
+export const answerQuestion = (questionId: number, body: string) =>
+  api.post(`/user/questions/${questionId}/answers`, { body }).then(r => r.data)
+
+// params: status (pending by default, "all"), product_id, answered, page, size
+export const fetchQuestionQueue = (params: Record<string, any> = {}) => api.get('/admin/questions', { params }).then(r => r.data)
+export const moderateQuestion = (id: number, status: string) =>
+  api.patch(`/admin/questions/${id}`, { status }).then(r => r.data)
+export const deleteQuestion = (id: number) => api.delete(`/admin/questions/${id}`).then(r => r.data)
+export const answerAsShop = (questionId: number, body: string) =>
+  api.post(`/admin/questions/${questionId}/answers`, { body }).then(r => r.data)
+export const fetchAnswerQueue = (params: Record<string, any> = {}) => api.get('/admin/answers', { params }).then(r => r.data)
+export const moderateAnswer = (id: number, status: string) =>
+  api.patch(`/admin/answers/${id}`, { status }).then(r => r.data)
+export const deleteAnswer = (id: number) => api.delete(`/admin/answers/${id}`).then(r => r.data)
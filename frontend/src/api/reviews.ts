@@ -0,0 +1,15 @@
+import { api } from './client'
+
+// params: rating, verified, photos, sort, page, size
+export const fetchProductReviews = (productId: number, params: Record<string, any> = {}) =>
+  api.get(`/products/${productId}/reviews`, { params }).then(r => r.data)
+export const fetchMyReview = (productId: number) => api.get(`/user/products/${productId}/review`).then(r => r.data)
+// form carries rating, title, body and up to four photos files
+export const submitReview = (productId: number, form: FormData) =>
+  api.post(`/user/products/${productId}/review`, form).then(r => r.data)
+export const deleteMyReview = (id: number) => api.delete(`/user/reviews/${id}`).then(r => r.data)
+
+export const fetchModerationQueue = (params: Record<string, any> = {}) => api.get('/admin/reviews', { params }).then(r => r.data)
+export const moderateReview = (id: number, status: string, note = '') =>
+  api.patch(`/admin/reviews/${id}`, { status, note }).then(r => r.data)
+export const deleteReview = (id: number) => api.delete(`/admin/reviews/${id}`).then(r => r.data)
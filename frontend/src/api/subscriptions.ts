@@ -0,0 +1,12 @@
+import { api } from './client'
+
+// sub: kind ('back_in_stock' | 'price_drop'), options ({ id, type }[], as for
+// cart items) and, for guests, email and locale
+export const subscribeGuest = (productId: number, sub: any) =>
+  api.post(`/products/${productId}/subscriptions`, sub).then(r => r.data)
+export const subscribe = (productId: number, sub: any) =>
+  api.post(`/user/products/${productId}/subscriptions`, sub).then(r => r.data)
+export const fetchSubscriptions = () => api.get('/user/subscriptions').then(r => r.data)
+export const deleteSubscription = (id: number) => api.delete(`/user/subscriptions/${id}`).then(r => r.data)
+export const confirmSubscription = (token: string) => api.post(`/subscriptions/${token}/confirm`).then(r => r.data)
+export const unsubscribe = (token: string) => api.delete(`/subscriptions/${token}`).then(r => r.data)
@@ -0,0 +1,20 @@
+import { api } from './client'
+
+export const fetchWishlists = () => api.get('/user/wishlists').then(r => r.data)
+export const fetchWishlist = (id: number) => api.get(`/user/wishlists/${id}`).then(r => r.data)
+export const createWishlist = (name: string) => api.post('/user/wishlists', { name }).then(r => r.data)
+export const renameWishlist = (id: number, name: string) => api.patch(`/user/wishlists/${id}`, { name }).then(r => r.data)
+export const deleteWishlist = (id: number) => api.delete(`/user/wishlists/${id}`).then(r => r.data)
+export const shareWishlist = (id: number, shared: boolean) =>
+  (shared ? api.post(`/user/wishlists/${id}/share`) : api.delete(`/user/wishlists/${id}/share`)).then(r => r.data)
+export const fetchSharedWishlist = (token: string) => api.get(`/wishlists/shared/${token}`).then(r => r.data)
+
+// item: product_id, quantity, options ({ id, type }[], as for cart items)
+export const addWishlistItem = (id: number, item: any) => api.post(`/user/wishlists/${id}/items`, item).then(r => r.data)
+export const updateWishlistItem = (id: number, itemId: number, item: any) =>
+  api.patch(`/user/wishlists/${id}/items/${itemId}`, item).then(r => r.data)
+export const removeWishlistItem = (id: number, itemId: number) =>
+  api.delete(`/user/wishlists/${id}/items/${itemId}`).then(r => r.data)
+// moves every item when itemIds is empty; keep leaves them in the wishlist
+export const moveToCart = (id: number, itemIds: number[] = [], keep = false) =>
+  api.post(`/user/wishlists/${id}/move-to-cart`, { item_ids: itemIds, keep }).then(r => r.data)
@@ -9,6 +9,8 @@ export default function AdminNav() {
     { path: "/admin/categories", label: "Categories" },
     { path: "/admin/products", label: "Products" },
     { path: "/admin/orders", label: "Orders" },
+    { path: "/admin/reviews", label: "Reviews" },
+    { path: "/admin/questions", label: "Questions" },
   ];
   return (
     <Card style={{ marginBottom: 16 }}>
@@ -0,0 +1,67 @@
+import { Button, Dropdown, Input, Modal, message } from "antd";
+import { useState } from "react";
+import { subscribe, subscribeGuest } from "../api/subscriptions";
+import { useAuth } from "../store/AuthContext";
+import { useI18n } from "../store/I18nContext";
+
+type Props = {
+  productId: number;
+  inStock: boolean;
+  options: { id: number; type: string }[];
+};
+
+export default function NotifyMe({ productId, inStock, options }: Props) {
+  const { t, lang } = useI18n();
+  const { isAuthenticated } = useAuth();
+  const [kind, setKind] = useState<string>();
+  const [email, setEmail] = useState("");
+
+  const send = async (k: string, address?: string) => {
+    try {
+      const sub = isAuthenticated
+        ? await subscribe(productId, { kind: k, options })
+        : await subscribeGuest(productId, { kind: k, options, email: address, locale: lang });
+      message.success(t(sub.confirmed_at ? "alerts.subscribed" : "alerts.check_email"));
+      setKind(undefined);
+      setEmail("");
+    } catch (e: any) {
+      message.error(e?.response?.data?.message || "Failed to subscribe");
+    }
+  };
+
+  const choose = (k: string) => (isAuthenticated ? send(k) : setKind(k));
+
+  return (
+    <>
+      <Dropdown
+        trigger={["click"]}
+        menu={{
+          items: [
+            ...(inStock ? [] : [{ key: "back_in_stock", label: t("alerts.back_in_stock") }]),
+            { key: "price_drop", label: t("alerts.price_drop") },
+          ],
+          onClick: ({ key }) => choose(key),
+        }}
+      >
+        <Button>{t("alerts.notify")}</Button>
+      </Dropdown>
+      <Modal
+        open={!!kind}
+        title={kind && t(`alerts.${kind}`)}
+        okText={t("alerts.subscribe")}
+        okButtonProps={{ disabled: !email.trim() }}
+        onOk={() => kind && send(kind, email.trim())}
+        onCancel={() => setKind(undefined)}
+      >
+        <Input
+          type="email"
+          maxLength={255}
+          placeholder={t("alerts.email")}
+          value={email}
+          onChange={(e) => setEmail(e.target.value)}
+          onPressEnter={() => kind && email.trim() && send(kind, email.trim())}
+        />
+      </Modal>
+    </>
+  );
+}
@@ -0,0 +1,30 @@
+import { Tag, Typography } from "antd";
+import { useI18n } from "../store/I18nContext";
+
+type Props = {
+  product: { base_price: number; sale_price?: number | null; sale_ends_at?: string | null };
+  showEnd?: boolean;
+};
+
+// Price shows what a product sells for; during a sale the base price it
+// was is struck through next to the sale price.
+export default function Price({ product, showEnd }: Props) {
+  const { t } = useI18n();
+  if (product.sale_price == null) return <>{Number(product.base_price).toFixed(2)}</>;
+  return (
+    <>
+      <Typography.Text delete type="secondary">
+        {Number(product.base_price).toFixed(2)}
+      </Typography.Text>{" "}
+      <Typography.Text strong type="danger">
+        {Number(product.sale_price).toFixed(2)}
+      </Typography.Text>{" "}
+      <Tag color="red">{t("product.sale")}</Tag>
+      {showEnd && product.sale_ends_at && (
+        <Typography.Text type="secondary">
+          {t("product.sale_ends")} {new Date(product.sale_ends_at).toLocaleString()}
+        </Typography.Text>
+      )}
+    </>
+  );
+}
@@ -0,0 +1,168 @@
+import { Button, Card, Empty, Input, Pagination, Segmented, Tag, Typography, message } from "antd";
+import { useEffect, useState } from "react";
+import { Link } from "react-router-dom";
+import { answerQuestion, askQuestion, fetchProductQuestions } from "../api/questions";
+import { useAuth } from "../store/AuthContext";
+import { useI18n } from "../store/I18nContext";
+
+const PAGE_SIZE = 10;
+
+export default function ProductQuestions({ productId }: { productId: number }) {
+  const { t } = useI18n();
+  const { isAuthenticated } = useAuth();
+  const [data, setData] = useState<any>();
+  const [answered, setAnswered] = useState("all");
+  const [page, setPage] = useState(1);
+  const [question, setQuestion] = useState("");
+  const [answers, setAnswers] = useState<Record<number, string>>({});
+  const [replying, setReplying] = useState<number | null>(null);
+  const [saving, setSaving] = useState(false);
+
+  const load = () =>
+    fetchProductQuestions(productId, {
+      answered: answered === "all" ? undefined : answered === "answered",
+      page,
+      size: PAGE_SIZE,
+    })
+      .then(setData)
+      .catch(() => setData(undefined));
+  useEffect(() => {
+    load();
+  }, [productId, answered, page]);
+
+  const ask = async () => {
+    setSaving(true);
+    try {
+      await askQuestion(productId, question);
+      setQuestion("");
+      message.success(t("questions.submitted"));
+    } catch (e: any) {
+      message.error(e?.response?.data?.message || "Failed to send question");
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const answer = async (q: any) => {
+    setSaving(true);
+    try {
+      await answerQuestion(q.id, answers[q.id] || "");
+      setAnswers((prev) => ({ ...prev, [q.id]: "" }));
+      setReplying(null);
+      message.success(t("questions.answer_submitted"));
+    } catch (e: any) {
+      message.error(e?.response?.data?.message || "Failed to send answer");
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  return (
+    <div id="questions" style={{ marginTop: 24 }}>
+      <Typography.Title level={4}>{t("questions")}</Typography.Title>
+      <Segmented
+        style={{ marginBottom: 12 }}
+        value={answered}
+        onChange={(v) => {
+          setAnswered(String(v));
+          setPage(1);
+        }}
+        options={[
+          { value: "all", label: t("questions.filter.all") },
+          { value: "answered", label: t("questions.filter.answered") },
+          { value: "unanswered", label: t("questions.filter.unanswered") },
+        ]}
+      />
+      {!data?.items?.length ? (
+        <Empty description={t("questions.none")} />
+      ) : (
+        data.items.map((q: any) => (
+          <Card key={q.id} size="small" style={{ marginBottom: 12 }}>
+            <Typography.Text strong style={{ whiteSpace: "pre-line" }}>
+              {t("questions.q")} {q.body}
+            </Typography.Text>
+            <div>
+              <Typography.Text type="secondary">
+                {q.author_name || t("reviews.anonymous")} · {new Date(q.created_at).toLocaleDateString()}
+              </Typography.Text>
+            </div>
+            {(q.answers || []).map((a: any) => (
+              <div key={a.id} style={{ marginTop: 8, paddingLeft: 16, borderLeft: "2px solid #f0f0f0" }}>
+                <div style={{ whiteSpace: "pre-line" }}>
+                  {t("questions.a")} {a.body}
+                </div>
+                <Typography.Text type="secondary">
+                  {a.official ? t("questions.shop") : a.author_name || t("reviews.anonymous")} ·{" "}
+                  {new Date(a.created_at).toLocaleDateString()}
+                </Typography.Text>{" "}
+                {a.official && <Tag color="blue">{t("questions.official")}</Tag>}
+                {a.verified && <Tag color="green">{t("reviews.verified")}</Tag>}
+              </div>
+            ))}
+            {isAuthenticated &&
+              (replying === q.id ? (
+                <div style={{ marginTop: 8 }}>
+                  <Input.TextArea
+                    rows={2}
+                    maxLength={2000}
+                    value={answers[q.id] || ""}
+                    onChange={(e) => setAnswers((prev) => ({ ...prev, [q.id]: e.target.value }))}
+                  />
+                  <div style={{ display: "flex", gap: 8, marginTop: 8 }}>
+                    <Button
+                      type="primary"
+                      size="small"
+                      loading={saving}
+                      disabled={(answers[q.id] || "").trim().length < 3}
+                      onClick={() => answer(q)}
+                    >
+                      {t("questions.send_answer")}
+                    </Button>
+                    <Button size="small" onClick={() => setReplying(null)}>
+                      {t("questions.cancel")}
+                    </Button>
+                  </div>
+                  <Typography.Text type="secondary">{t("questions.buyers_only")}</Typography.Text>
+                </div>
+              ) : (
+                <Button type="link" size="small" style={{ paddingLeft: 0 }} onClick={() => setReplying(q.id)}>
+                  {t("questions.answer")}
+                </Button>
+              ))}
+          </Card>
+        ))
+      )}
+      {(data?.pagination?.pages || 0) > 1 && (
+        <Pagination
+          current={page}
+          pageSize={PAGE_SIZE}
+          total={data.pagination.total}
+          onChange={setPage}
+          style={{ marginBottom: 16 }}
+        />
+      )}
+      {!isAuthenticated ? (
+        <Link to="/login">{t("questions.login")}</Link>
+      ) : (
+        <Card size="small" title={t("questions.ask")} style={{ marginTop: 16 }}>
+          <Input.TextArea
+            rows={3}
+            maxLength={2000}
+            placeholder={t("questions.placeholder")}
+            value={question}
+            onChange={(e) => setQuestion(e.target.value)}
+          />
+          <Button
+            type="primary"
+            style={{ marginTop: 8 }}
+            loading={saving}
+            disabled={question.trim().length < 3}
+            onClick={ask}
+          >
+            {t("questions.send")}
+          </Button>
+        </Card>
+      )}
+    </div>
+  );
+}
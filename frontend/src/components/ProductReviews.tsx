@@ -0,0 +1,267 @@
+import {
+  Button,
+  Card,
+  Checkbox,
+  Empty,
+  Form,
+  Input,
+  Pagination,
+  Popconfirm,
+  Progress,
+  Rate,
+  Select,
+  Tag,
+  Typography,
+  Upload,
+  message,
+} from "antd";
+import { useEffect, useState } from "react";
+import { Link } from "react-router-dom";
+import {
+  deleteMyReview,
+  fetchMyReview,
+  fetchProductReviews,
+  submitReview,
+} from "../api/reviews";
+import { getApiOrigin } from "../api/client";
+import { useAuth } from "../store/AuthContext";
+import { useI18n } from "../store/I18nContext";
+
+const SORTS = ["newest", "oldest", "rating_desc", "rating_asc"];
+const PAGE_SIZE = 10;
+
+const abs = (u?: string) => {
+  const origin = getApiOrigin();
+  return u && !/^https?:/i.test(u) ? origin + u : u;
+};
+
+export default function ProductReviews({ productId, canReview }: { productId: number; canReview: boolean }) {
+  const { t } = useI18n();
+  const { isAuthenticated } = useAuth();
+  const [data, setData] = useState<any>();
+  const [mine, setMine] = useState<any>(null);
+  const [sort, setSort] = useState("newest");
+  const [rating, setRating] = useState<number | undefined>();
+  const [verified, setVerified] = useState(false);
+  const [photos, setPhotos] = useState(false);
+  const [page, setPage] = useState(1);
+  const [files, setFiles] = useState<any[]>([]);
+  const [saving, setSaving] = useState(false);
+  const [form] = Form.useForm();
+
+  const load = () =>
+    fetchProductReviews(productId, {
+      sort,
+      rating,
+      verified: verified || undefined,
+      photos: photos || undefined,
+      page,
+      size: PAGE_SIZE,
+    })
+      .then(setData)
+      .catch(() => setData(undefined));
+  useEffect(() => {
+    load();
+  }, [productId, sort, rating, verified, photos, page]);
+  useEffect(() => {
+    if (!isAuthenticated) return setMine(null);
+    fetchMyReview(productId)
+      .then((r) => {
+        setMine(r);
+        form.setFieldsValue(r ? { rating: r.rating, title: r.title, body: r.body } : { rating: 0, title: "", body: "" });
+      })
+      .catch(() => setMine(null));
+  }, [productId, isAuthenticated]);
+
+  const submit = async (v: any) => {
+    const fd = new FormData();
+    fd.append("rating", String(v.rating));
+    fd.append("title", v.title || "");
+    fd.append("body", v.body || "");
+    files.forEach((f) => fd.append("photos", f.originFileObj || f));
+    setSaving(true);
+    try {
+      setMine(await submitReview(productId, fd));
+      setFiles([]);
+      message.success(t("reviews.submitted"));
+    } catch (e: any) {
+      message.error(e?.response?.data?.message || "Failed to submit review");
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const summary = data?.summary;
+  return (
+    <div style={{ marginTop: 24 }}>
+      <Typography.Title level={4}>{t("reviews")}</Typography.Title>
+      {summary && summary.count > 0 && (
+        <div style={{ display: "flex", gap: 32, flexWrap: "wrap", marginBottom: 16 }}>
+          <div>
+            <Typography.Title level={2} style={{ margin: 0 }}>
+              {summary.average.toFixed(1)}
+            </Typography.Title>
+            <Rate disabled allowHalf value={Math.round(summary.average * 2) / 2} />
+            <div>
+              {summary.count} {t("reviews.count")}
+            </div>
+          </div>
+          <div style={{ minWidth: 240 }}>
+            {[5, 4, 3, 2, 1].map((n) => (
+              <div
+                key={n}
+                style={{ display: "flex", gap: 8, alignItems: "center", cursor: "pointer" }}
+                onClick={() => {
+                  setRating(rating === n ? undefined : n);
+                  setPage(1);
+                }}
+              >
+                <span style={{ width: 24, fontWeight: rating === n ? 600 : 400 }}>{n}★</span>
+                <Progress
+                  percent={(summary.stars[n - 1] / summary.count) * 100}
+                  showInfo={false}
+                  size="small"
+                  style={{ width: 160, margin: 0 }}
+                />
+                <span>{summary.stars[n - 1]}</span>
+              </div>
+            ))}
+          </div>
+        </div>
+      )}
+      <div style={{ display: "flex", gap: 12, alignItems: "center", flexWrap: "wrap", marginBottom: 12 }}>
+        <Select
+          value={sort}
+          style={{ minWidth: 180 }}
+          onChange={(v) => {
+            setSort(v);
+            setPage(1);
+          }}
+          options={SORTS.map((s) => ({ value: s, label: t(`sort.${s}`) }))}
+        />
+        <Checkbox
+          checked={verified}
+          onChange={(e) => {
+            setVerified(e.target.checked);
+            setPage(1);
+          }}
+        >
+          {t("reviews.verified_only")}
+        </Checkbox>
+        <Checkbox
+          checked={photos}
+          onChange={(e) => {
+            setPhotos(e.target.checked);
+            setPage(1);
+          }}
+        >
+          {t("reviews.with_photos")}
+        </Checkbox>
+      </div>
+      {!data?.items?.length ? (
+        <Empty description={t("reviews.none")} />
+      ) : (
+        data.items.map((r: any) => (
+          <Card key={r.id} size="small" style={{ marginBottom: 12 }}>
+            <div style={{ display: "flex", gap: 8, alignItems: "center" }}>
+              <Rate disabled value={r.rating} style={{ fontSize: 14 }} />
+              <Typography.Text strong>{r.title}</Typography.Text>
+              {r.verified && <Tag color="green">{t("reviews.verified")}</Tag>}
+            </div>
+            <Typography.Text type="secondary">
+              {r.author_name || t("reviews.anonymous")} · {new Date(r.created_at).toLocaleDateString()}
+            </Typography.Text>
+            {r.body && <p style={{ marginTop: 8, whiteSpace: "pre-line" }}>{r.body}</p>}
+            {!!r.photos?.length && (
+              <div style={{ display: "flex", gap: 8 }}>
+                {r.photos.map((p: any) => (
+                  <a key={p.id} href={abs(p.url)} target="_blank" rel="noreferrer">
+                    <img src={abs(p.url)} alt="" style={{ width: 80, height: 80, objectFit: "cover", borderRadius: 4 }} />
+                  </a>
+                ))}
+              </div>
+            )}
+          </Card>
+        ))
+      )}
+      {(data?.pagination?.pages || 0) > 1 && (
+        <Pagination
+          current={page}
+          pageSize={PAGE_SIZE}
+          total={data.pagination.total}
+          onChange={setPage}
+          style={{ marginBottom: 16 }}
+        />
+      )}
+      {!isAuthenticated ? (
+        canReview && <Link to="/login">{t("reviews.login")}</Link>
+      ) : (
+        (canReview || mine) && (
+          <Card size="small" title={mine ? t("reviews.edit") : t("reviews.write")} style={{ marginTop: 16 }}>
+            {mine && (
+              <div style={{ marginBottom: 12 }}>
+                <Tag>{t(`reviews.status.${mine.status}`)}</Tag>
+                {mine.verified && <Tag color="green">{t("reviews.verified")}</Tag>}
+                {mine.status === "approved" && (
+                  <Typography.Text type="secondary">{t("reviews.edit_unpublishes")}</Typography.Text>
+                )}
+                {mine.status === "rejected" && mine.moderation_note && (
+                  <Typography.Text type="secondary">{mine.moderation_note}</Typography.Text>
+                )}
+              </div>
+            )}
+            <Form form={form} layout="vertical" onFinish={submit}>
+              <Form.Item
+                name="rating"
+                label={t("reviews.rating")}
+                rules={[{ required: true, type: "number", min: 1, message: t("validation.required") }]}
+              >
+                <Rate />
+              </Form.Item>
+              <Form.Item name="title" label={t("reviews.review_title")} rules={[{ max: 120 }]}>
+                <Input />
+              </Form.Item>
+              <Form.Item name="body" label={t("reviews.body")} rules={[{ max: 5000 }]}>
+                <Input.TextArea rows={4} />
+              </Form.Item>
+              <Form.Item
+                label={t("reviews.photos")}
+                extra={mine?.photos?.length ? t("reviews.photos_kept") : undefined}
+              >
+                <Upload
+                  accept="image/*"
+                  listType="picture-card"
+                  maxCount={4}
+                  multiple
+                  fileList={files}
+                  beforeUpload={() => false}
+                  onChange={({ fileList }) => setFiles(fileList)}
+                >
+                  {files.length < 4 && "+"}
+                </Upload>
+              </Form.Item>
+              <div style={{ display: "flex", gap: 8 }}>
+                <Button type="primary" htmlType="submit" loading={saving}>
+                  {t("reviews.submit")}
+                </Button>
+                {mine && (
+                  <Popconfirm
+                    title={t("reviews.delete")}
+                    onConfirm={async () => {
+                      await deleteMyReview(mine.id);
+                      setMine(null);
+                      form.resetFields();
+                      load();
+                    }}
+                  >
+                    <Button danger>{t("reviews.delete")}</Button>
+                  </Popconfirm>
+                )}
+              </div>
+            </Form>
+          </Card>
+        )
+      )}
+    </div>
+  );
+}
@@ -0,0 +1,71 @@
+import { Button, Dropdown, Input, Modal, message } from "antd";
+import { useState } from "react";
+import { addWishlistItem, createWishlist, fetchWishlists } from "../api/wishlists";
+import { useI18n } from "../store/I18nContext";
+
+type Props = {
+  productId: number;
+  quantity: number;
+  options: { id: number; type: string }[];
+};
+
+export default function SaveToWishlist({ productId, quantity, options }: Props) {
+  const { t } = useI18n();
+  const [lists, setLists] = useState<any[]>([]);
+  const [naming, setNaming] = useState(false);
+  const [name, setName] = useState("");
+
+  const save = async (listId: number) => {
+    try {
+      await addWishlistItem(listId, { product_id: productId, quantity, options });
+      message.success(t("wishlists.saved"));
+    } catch (e: any) {
+      message.error(e?.response?.data?.message || "Failed to save");
+    }
+  };
+
+  const saveToNew = async () => {
+    try {
+      const w = await createWishlist(name.trim());
+      await save(w.id);
+      setNaming(false);
+      setName("");
+    } catch (e: any) {
+      message.error(e?.response?.data?.message || "Failed to create wishlist");
+    }
+  };
+
+  return (
+    <>
+      <Dropdown
+        trigger={["click"]}
+        onOpenChange={(open) => open && fetchWishlists().then(setLists).catch(() => setLists([]))}
+        menu={{
+          items: [
+            ...lists.map((w) => ({ key: String(w.id), label: w.name })),
+            ...(lists.length ? [{ type: "divider" as const }] : []),
+            { key: "new", label: t("wishlists.new") },
+          ],
+          onClick: ({ key }) => (key === "new" ? setNaming(true) : save(Number(key))),
+        }}
+      >
+        <Button>{t("wishlists.save")}</Button>
+      </Dropdown>
+      <Modal
+        open={naming}
+        title={t("wishlists.new")}
+        okButtonProps={{ disabled: !name.trim() }}
+        onOk={saveToNew}
+        onCancel={() => setNaming(false)}
+      >
+        <Input
+          maxLength={120}
+          placeholder={t("wishlists.name")}
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+          onPressEnter={() => name.trim() && saveToNew()}
+        />
+      </Modal>
+    </>
+  );
+}
@@ -0,0 +1,74 @@
+import { Space, Table, Tag, Typography } from "antd";
+import { useEffect, useState, type ReactNode } from "react";
+import { Link } from "react-router-dom";
+import { fetchProduct } from "../api/catalog";
+import { useI18n } from "../store/I18nContext";
+
+// describe renders the selected options of an item as option names.
+const describe = (product: any, item: any) => {
+  const selected: any[] = JSON.parse(item.selected_options_json || "[]") || [];
+  return selected
+    .map((s) => (product?.options || []).find((o: any) => o.id === s.id))
+    .filter(Boolean)
+    .map((o: any) => `${o.option_type}: ${o.option_name}`)
+    .join(", ");
+};
+
+type Props = {
+  items: any[];
+  actions: (item: any, product: any) => ReactNode;
+};
+
+export default function WishlistItems({ items, actions }: Props) {
+  const { t } = useI18n();
+  const [products, setProducts] = useState<Record<number, any>>({});
+
+  useEffect(() => {
+    const missing = [...new Set(items.map((it) => it.product_id))].filter((id) => !(id in products));
+    if (!missing.length) return;
+    Promise.all(missing.map((id) => fetchProduct(id).catch(() => null))).then((loaded) =>
+      setProducts((prev) => {
+        const next = { ...prev };
+        missing.forEach((id, i) => (next[id] = loaded[i]));
+        return next;
+      })
+    );
+  }, [items]);
+
+  return (
+    <Table
+      rowKey="id"
+      pagination={false}
+      dataSource={items}
+      locale={{ emptyText: t("wishlists.empty") }}
+      columns={[
+        {
+          title: t("reviews.product"),
+          render: (_: any, it: any) => {
+            const p = products[it.product_id];
+            return (
+              <Space direction="vertical" size={0}>
+                <Link to={`/product/${p?.slug || it.product_id}`}>{p?.name || `#${it.product_id}`}</Link>
+                <Typography.Text type="secondary">{describe(p, it)}</Typography.Text>
+              </Space>
+            );
+          },
+        },
+        { title: t("product.quantity"), dataIndex: "quantity" },
+        {
+          title: t("wishlists.price"),
+          render: (_: any, it: any) =>
+            it.available ? (
+              <>
+                {it.unit_price.toFixed(2)}{" "}
+                {products[it.product_id]?.quantity <= 0 && <Tag>{t("wishlists.made_to_order")}</Tag>}
+              </>
+            ) : (
+              <Tag color="red">{t("product.unavailable")}</Tag>
+            ),
+        },
+        { title: t("actions"), render: (_: any, it: any) => actions(it, products[it.product_id]) },
+      ]}
+    />
+  );
+}
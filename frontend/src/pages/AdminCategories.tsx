@@ -3,14 +3,18 @@ import {
   Card,
   Form,
   Input,
+  InputNumber,
   Modal,
   Popconfirm,
   Select,
+  Space,
+  Switch,
   Table,
   message,
 } from "antd";
 import { useEffect, useState } from "react";
 import { api } from "../api/client";
+import { archiveRecord, restoreRecord } from "../api/archive";
 import { useI18n } from "../store/I18nContext";
 import { useNavigate } from "react-router-dom";
 
@@ -22,12 +26,14 @@ export default function AdminCategories() {
   const [openCategory, setOpenCategory] = useState(false);
   const [editing, setEditing] = useState<any | null>(null);
   const [categoryForm] = Form.useForm();
+  const [archived, setArchived] = useState(false);
+  const [attributesOf, setAttributesOf] = useState<any | null>(null);
 
   const load = async () => {
     try {
       const [d, c] = await Promise.all([
         api.get("/admin/departments"),
-        api.get("/admin/categories"),
+        api.get("/admin/categories", { params: { archived } }),
       ]);
       setDepts(d.data);
       setCategories(c.data);
@@ -38,7 +44,7 @@ export default function AdminCategories() {
 
   useEffect(() => {
     load();
-  }, []);
+  }, [archived]);
 
   const submitCategory = async () => {
     const v = await categoryForm.validateFields();
@@ -58,9 +64,17 @@ export default function AdminCategories() {
       <Card
         title="Categories"
         extra={
-          <Button onClick={() => setOpenCategory(true)}>
-            {t("create_category")}
-          </Button>
+          <Space>
+            <Switch
+              checked={archived}
+              onChange={setArchived}
+              checkedChildren={t("archived")}
+              unCheckedChildren={t("active")}
+            />
+            <Button onClick={() => setOpenCategory(true)}>
+              {t("create_category")}
+            </Button>
+          </Space>
         }
       >
         <Table
@@ -78,36 +92,63 @@ export default function AdminCategories() {
             },
             {
               title: t("actions"),
-              render: (_: any, r: any) => (
-                <>
+              render: (_: any, r: any) =>
+                archived ? (
                   <Button
                     size="small"
-                    onClick={() => {
-                      setEditing(r);
-                      categoryForm.setFieldsValue({
-                        department_id: r.department_id,
-                        name: r.name,
-                        description: r.description,
-                      });
-                      setOpenCategory(true);
+                    onClick={async () => {
+                      try {
+                        await restoreRecord(`/admin/categories/${r.id}`);
+                        load();
+                      } catch (e: any) {
+                        message.error(
+                          e.response?.data?.message || "Restore failed",
+                        );
+                      }
                     }}
-                    style={{ marginRight: 8 }}
                   >
-                    Edit
+                    {t("restore")}
                   </Button>
-                  <Popconfirm
-                    title="Delete category?"
-                    onConfirm={async () => {
-                      await api.delete(`/admin/categories/${r.id}`);
-                      load();
-                    }}
-                  >
-                    <Button danger size="small">
-                      Delete
+                ) : (
+                  <>
+                    <Button
+                      size="small"
+                      onClick={() => {
+                        setEditing(r);
+                        categoryForm.setFieldsValue({
+                          department_id: r.department_id,
+                          name: r.name,
+                          description: r.description,
+                          slug: r.slug,
+                          seo_title: r.seo_title,
+                          seo_description: r.seo_description,
+                        });
+                        setOpenCategory(true);
+                      }}
+                      style={{ marginRight: 8 }}
+                    >
+                      Edit
+                    </Button>
+                    <Button
+                      size="small"
+                      onClick={() => setAttributesOf(r)}
+                      style={{ marginRight: 8 }}
+                    >
+                      {t("attributes")}
                     </Button>
-                  </Popconfirm>
-                </>
-              ),
+                    <Popconfirm
+                      title="Archive category?"
+                      onConfirm={async () => {
+                        await archiveRecord(`/admin/categories/${r.id}`);
+                        load();
+                      }}
+                    >
+                      <Button danger size="small">
+                        {t("archive")}
+                      </Button>
+                    </Popconfirm>
+                  </>
+                ),
             },
           ]}
         />
@@ -145,9 +186,208 @@ export default function AdminCategories() {
             <Form.Item name="description" label={t("category_description")}>
               <Input />
             </Form.Item>
+            <Form.Item name="slug" label={t("slug")}>
+              <Input />
+            </Form.Item>
+            <Form.Item name="seo_title" label={t("seo_title")}>
+              <Input maxLength={255} />
+            </Form.Item>
+            <Form.Item name="seo_description" label={t("seo_description")}>
+              <Input.TextArea rows={2} maxLength={500} />
+            </Form.Item>
           </Form>
         </Modal>
+        {attributesOf && (
+          <CategoryAttributes
+            category={attributesOf}
+            onClose={() => setAttributesOf(null)}
+          />
+        )}
       </Card>
     </div>
   );
 }
+
+// CategoryAttributes manages the typed attributes the products of a
+// category carry, e.g. seat height or assembly required.
+function CategoryAttributes({
+  category,
+  onClose,
+}: {
+  category: any;
+  onClose: () => void;
+}) {
+  const { t } = useI18n();
+  const [items, setItems] = useState<any[]>([]);
+  const [editing, setEditing] = useState<any | null>(null);
+  const [form] = Form.useForm();
+  const type = Form.useWatch("type", form);
+
+  const load = () =>
+    api
+      .get(`/admin/categories/${category.id}/attributes`)
+      .then((r) => setItems(r.data))
+      .catch(() => message.error("Failed to load attributes"));
+
+  useEffect(() => {
+    load();
+  }, [category.id]);
+
+  const submit = async () => {
+    const v = await form.validateFields();
+    try {
+      if (editing?.id) {
+        await api.put(`/admin/attributes/${editing.id}`, v);
+      } else {
+        await api.post(`/admin/categories/${category.id}/attributes`, v);
+      }
+      setEditing(null);
+      form.resetFields();
+      load();
+    } catch (e: any) {
+      message.error(e.response?.data?.message || "Save failed");
+    }
+  };
+
+  return (
+    <Modal
+      title={`${t("attributes")}: ${category.name}`}
+      open
+      width={760}
+      footer={null}
+      onCancel={onClose}
+    >
+      <Table
+        rowKey="id"
+        size="small"
+        pagination={false}
+        dataSource={items}
+        columns={[
+          { title: t("attribute.code"), dataIndex: "code" },
+          { title: t("attribute.name"), dataIndex: "name" },
+          {
+            title: t("attribute.type"),
+            dataIndex: "type",
+            render: (v: string, r: any) => (r.unit ? `${v} (${r.unit})` : v),
+          },
+          {
+            title: t("attribute.required"),
+            dataIndex: "required",
+            render: (v: boolean) => (v ? t("yes") : t("no")),
+          },
+          {
+            title: t("attribute.filterable"),
+            dataIndex: "filterable",
+            render: (v: boolean) => (v ? t("yes") : t("no")),
+          },
+          {
+            title: t("actions"),
+            render: (_: any, r: any) => (
+              <>
+                <Button
+                  size="small"
+                  style={{ marginRight: 8 }}
+                  onClick={() => {
+                    setEditing(r);
+                    form.setFieldsValue(r);
+                  }}
+                >
+                  Edit
+                </Button>
+                <Popconfirm
+                  title="Delete attribute and its product values?"
+                  onConfirm={async () => {
+                    await api.delete(`/admin/attributes/${r.id}`);
+                    load();
+                  }}
+                >
+                  <Button danger size="small">
+                    Delete
+                  </Button>
+                </Popconfirm>
+              </>
+            ),
+          },
+        ]}
+      />
+      <Form
+        layout="vertical"
+        form={form}
+        initialValues={{ type: "text", position: 0 }}
+        style={{ marginTop: 16 }}
+      >
+        <Space wrap>
+          <Form.Item
+            name="code"
+            label={t("attribute.code")}
+            rules={[{ required: true, pattern: /^[a-z][a-z0-9_]*$/ }]}
+          >
+            <Input placeholder="seat_height" />
+          </Form.Item>
+          <Form.Item
+            name="name"
+            label={t("attribute.name")}
+            rules={[{ required: true }]}
+          >
+            <Input />
+          </Form.Item>
+          <Form.Item name="type" label={t("attribute.type")}>
+            <Select
+              style={{ width: 120 }}
+              options={["text", "number", "boolean", "enum"].map((v) => ({
+                value: v,
+                label: v,
+              }))}
+            />
+          </Form.Item>
+          {type === "number" && (
+            <Form.Item name="unit" label={t("attribute.unit")}>
+              <Input style={{ width: 80 }} placeholder="cm" />
+            </Form.Item>
+          )}
+          <Form.Item name="position" label={t("attribute.position")}>
+            <InputNumber />
+          </Form.Item>
+          <Form.Item
+            name="required"
+            label={t("attribute.required")}
+            valuePropName="checked"
+          >
+            <Switch />
+          </Form.Item>
+          <Form.Item
+            name="filterable"
+            label={t("attribute.filterable")}
+            valuePropName="checked"
+          >
+            <Switch />
+          </Form.Item>
+        </Space>
+        {type === "enum" && (
+          <Form.Item
+            name="choices"
+            label={t("attribute.choices")}
+            rules={[{ required: true }]}
+          >
+            <Select mode="tags" />
+          </Form.Item>
+        )}
+        <Space>
+          <Button type="primary" onClick={submit}>
+            {editing?.id ? "Save" : t("attribute.add")}
+          </Button>
+          {editing && (
+            <Button
+              onClick={() => {
+                setEditing(null);
+                form.resetFields();
+              }}
+            >
+              Cancel
+            </Button>
+          )}
+        </Space>
+      </Form>
+    </Modal>
+  );
+}
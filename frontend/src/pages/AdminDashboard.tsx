@@ -15,7 +15,7 @@ import {
 } from "antd";
 import { UploadOutlined } from "@ant-design/icons";
 import { useEffect, useMemo, useState } from "react";
-import { api } from "../api/client";
+import { api, fetchAllPages } from "../api/client";
 import { useI18n } from "../store/I18nContext";
 
 export default function AdminDashboard() {
@@ -72,13 +72,13 @@ export default function AdminDashboard() {
     try {
       const [d, o, p, c] = await Promise.all([
         api.get("/admin/departments"),
-        api.get("/admin/orders"),
-        api.get("/admin/products"),
+        fetchAllPages("/admin/orders"),
+        fetchAllPages("/admin/products"),
         api.get("/admin/categories"),
       ]);
       setDepts(d.data);
-      setOrders(o.data);
-      setProducts(p.data);
+      setOrders(o);
+      setProducts(p);
       setCategories(c.data);
     } catch {
       message.error("Failed to load admin data");
@@ -1,7 +1,19 @@
-import { Button, Card, Form, Input, Modal, Table, Upload, message } from "antd";
+import {
+  Button,
+  Card,
+  Form,
+  Input,
+  Modal,
+  Space,
+  Switch,
+  Table,
+  Upload,
+  message,
+} from "antd";
 import { UploadOutlined } from "@ant-design/icons";
 import { useEffect, useState } from "react";
 import { api } from "../api/client";
+import { archiveRecord, restoreRecord } from "../api/archive";
 import { useI18n } from "../store/I18nContext";
 import { Link, useNavigate } from "react-router-dom";
 
@@ -12,10 +24,11 @@ export default function AdminDepartments() {
   const [openDept, setOpenDept] = useState(false);
   const [editing, setEditing] = useState<any | null>(null);
   const [deptForm] = Form.useForm();
+  const [archived, setArchived] = useState(false);
 
   const load = async () => {
     try {
-      const d = await api.get("/admin/departments");
+      const d = await api.get("/admin/departments", { params: { archived } });
       setDepts(d.data);
     } catch {
       message.error("Failed to load departments");
@@ -24,7 +37,7 @@ export default function AdminDepartments() {
 
   useEffect(() => {
     load();
-  }, []);
+  }, [archived]);
 
   const submitDept = async () => {
     const v = await deptForm.validateFields();
@@ -44,9 +57,17 @@ export default function AdminDepartments() {
       <Card
         title={t("departments")}
         extra={
-          <Button onClick={() => setOpenDept(true)}>
-            {t("create_department")}
-          </Button>
+          <Space>
+            <Switch
+              checked={archived}
+              onChange={setArchived}
+              checkedChildren={t("archived")}
+              unCheckedChildren={t("active")}
+            />
+            <Button onClick={() => setOpenDept(true)}>
+              {t("create_department")}
+            </Button>
+          </Space>
         }
       >
         <Table
@@ -60,35 +81,55 @@ export default function AdminDepartments() {
             },
             {
               title: t("actions"),
-              render: (_: any, r: any) => (
-                <>
+              render: (_: any, r: any) =>
+                archived ? (
                   <Button
-                    size="small"
-                    onClick={() => {
-                      setEditing(r);
-                      deptForm.setFieldsValue({
-                        name: r.name,
-                        description: r.description,
-                        image_url: r.image_url,
-                      });
-                      setOpenDept(true);
-                    }}
-                    style={{ marginRight: 8 }}
-                  >
-                    Edit
-                  </Button>
-                  <Button
-                    danger
                     size="small"
                     onClick={async () => {
-                      await api.delete(`/admin/departments/${r.id}`);
-                      load();
+                      try {
+                        await restoreRecord(`/admin/departments/${r.id}`);
+                        load();
+                      } catch (e: any) {
+                        message.error(
+                          e.response?.data?.message || "Restore failed",
+                        );
+                      }
                     }}
                   >
-                    Delete
+                    {t("restore")}
                   </Button>
-                </>
-              ),
+                ) : (
+                  <>
+                    <Button
+                      size="small"
+                      onClick={() => {
+                        setEditing(r);
+                        deptForm.setFieldsValue({
+                          name: r.name,
+                          description: r.description,
+                          image_url: r.image_url,
+                          slug: r.slug,
+                          seo_title: r.seo_title,
+                          seo_description: r.seo_description,
+                        });
+                        setOpenDept(true);
+                      }}
+                      style={{ marginRight: 8 }}
+                    >
+                      Edit
+                    </Button>
+                    <Button
+                      danger
+                      size="small"
+                      onClick={async () => {
+                        await archiveRecord(`/admin/departments/${r.id}`);
+                        load();
+                      }}
+                    >
+                      {t("archive")}
+                    </Button>
+                  </>
+                ),
             },
           ]}
         />
@@ -116,6 +157,15 @@ export default function AdminDepartments() {
             <Form.Item name="image_url" label={t("department_image")}>
               <Input placeholder="data:image/...;base64,... or upload below" />
             </Form.Item>
+            <Form.Item name="slug" label={t("slug")}>
+              <Input />
+            </Form.Item>
+            <Form.Item name="seo_title" label={t("seo_title")}>
+              <Input maxLength={255} />
+            </Form.Item>
+            <Form.Item name="seo_description" label={t("seo_description")}>
+              <Input.TextArea rows={2} maxLength={500} />
+            </Form.Item>
             <Upload
               accept="image/*"
               showUploadList={false}
@@ -1,6 +1,6 @@
 import { Button, Card, Select, Table, Tag, message } from "antd";
 import { useEffect, useState } from "react";
-import { api } from "../api/client";
+import { api, fetchAllPages } from "../api/client";
 import { useI18n } from "../store/I18nContext";
 import { useNavigate } from "react-router-dom";
 
@@ -21,8 +21,7 @@ export default function AdminOrders() {
 
   const load = async () => {
     try {
-      const res = await api.get("/admin/orders", { params: { status } });
-      setOrders(res.data);
+      setOrders(await fetchAllPages("/admin/orders", { status }));
     } catch {
       message.error("Failed to load orders");
     }
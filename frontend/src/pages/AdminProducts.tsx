@@ -7,16 +7,39 @@ import {
   Modal,
   Popconfirm,
   Select,
+  Space,
+  Switch,
   Table,
+  Tabs,
+  Tag,
   Upload,
   message,
 } from "antd";
 import { UploadOutlined } from "@ant-design/icons";
 import { useEffect, useMemo, useState } from "react";
-import { api } from "../api/client";
+import { api, fetchAllPages } from "../api/client";
+import { archiveRecord, restoreRecord } from "../api/archive";
+import { fetchProduct } from "../api/catalog";
 import { useI18n } from "../store/I18nContext";
 import { useNavigate } from "react-router-dom";
 
+const statusColors: Record<string, string> = {
+  draft: "default",
+  scheduled: "blue",
+  published: "green",
+  discontinued: "orange",
+};
+
+// datetime-local inputs hold local time without a zone
+const toLocalInput = (iso?: string | null) => {
+  if (!iso) return undefined;
+  const d = new Date(iso);
+  return new Date(d.getTime() - d.getTimezoneOffset() * 60000)
+    .toISOString()
+    .slice(0, 16);
+};
+const fromLocalInput = (v?: string) => (v ? new Date(v).toISOString() : null);
+
 export default function AdminProducts() {
   const { t } = useI18n();
   const nav = useNavigate();
@@ -30,10 +53,18 @@ export default function AdminProducts() {
   const [materialOptions, setMaterialOptions] = useState<string[]>([]);
   const [extraOptions, setExtraOptions] = useState<string[]>([]);
   const [imagePreview, setImagePreview] = useState<string | null>(null);
+  const [archived, setArchived] = useState(false);
   const selectedDept: number | undefined = Form.useWatch(
     "department_id",
     productForm
   );
+  const selectedCategory: number | undefined = Form.useWatch(
+    "category_id",
+    productForm
+  );
+  const [attributeDefs, setAttributeDefs] = useState<any[]>([]);
+  const [bundleOf, setBundleOf] = useState<any | null>(null);
+  const [pricesOf, setPricesOf] = useState<any | null>(null);
   const commonColours = useMemo(
     () => [
       "White",
@@ -68,11 +99,11 @@ export default function AdminProducts() {
       const [d, c, p] = await Promise.all([
         api.get("/admin/departments"),
         api.get("/admin/categories"),
-        api.get("/admin/products"),
+        fetchAllPages("/admin/products", { archived }),
       ]);
       setDepts(d.data);
       setCategories(c.data);
-      setProducts(p.data);
+      setProducts(p);
     } catch {
       message.error("Failed to load products");
     }
@@ -80,12 +111,24 @@ export default function AdminProducts() {
 
   useEffect(() => {
     load();
-  }, []);
+  }, [archived]);
+
+  useEffect(() => {
+    if (!selectedCategory) {
+      setAttributeDefs([]);
+      return;
+    }
+    api
+      .get(`/admin/categories/${selectedCategory}/attributes`)
+      .then((r) => setAttributeDefs(r.data))
+      .catch(() => setAttributeDefs([]));
+  }, [selectedCategory]);
 
   const submitProduct = async () => {
     const v = await productForm.validateFields();
     const payload = {
       category_id: v.category_id,
+      sku: v.sku,
       name: v.name,
       short_description: v.description,
       long_description: v.description,
@@ -97,6 +140,16 @@ export default function AdminProducts() {
       default_depth: v.default_depth,
       base_material: v.base_material,
       quantity: v.quantity ?? 0,
+      slug: v.slug,
+      seo_title: v.seo_title,
+      seo_description: v.seo_description,
+      status: v.status,
+      publish_at: fromLocalInput(v.publish_at),
+      unpublish_at: fromLocalInput(v.unpublish_at),
+      // only the attributes of the chosen category are sent
+      attributes: Object.fromEntries(
+        attributeDefs.map((d) => [d.code, v.attributes?.[d.code] ?? null]),
+      ),
     };
     if (editing) {
       await api.put(`/admin/products/${editing.id}`, payload);
@@ -195,7 +248,7 @@ export default function AdminProducts() {
   };
 
   const removeProduct = async (id: number) => {
-    await api.delete(`/admin/products/${id}`);
+    await archiveRecord(`/admin/products/${id}`);
     load();
   };
 
@@ -204,19 +257,27 @@ export default function AdminProducts() {
       <Card
         title={t("products")}
         extra={
-          <Button
-            onClick={() => {
-              setEditing(null);
-              setColorOptions([]);
-              setMaterialOptions([]);
-              setExtraOptions([]);
-              setImagePreview(null);
-              productForm.resetFields();
-              setOpenProduct(true);
-            }}
-          >
-            {t("create_product")}
-          </Button>
+          <Space>
+            <Switch
+              checked={archived}
+              onChange={setArchived}
+              checkedChildren={t("archived")}
+              unCheckedChildren={t("active")}
+            />
+            <Button
+              onClick={() => {
+                setEditing(null);
+                setColorOptions([]);
+                setMaterialOptions([]);
+                setExtraOptions([]);
+                setImagePreview(null);
+                productForm.resetFields();
+                setOpenProduct(true);
+              }}
+            >
+              {t("create_product")}
+            </Button>
+          </Space>
         }
       >
         <Table
@@ -228,6 +289,22 @@ export default function AdminProducts() {
             { title: t("product_description"), dataIndex: "short_description" },
             { title: "Quantity", dataIndex: "quantity" },
             { title: t("product_price"), dataIndex: "base_price" },
+            {
+              title: t("status"),
+              dataIndex: "status",
+              render: (st: string, r: any) => (
+                <Tag
+                  color={statusColors[st]}
+                  title={
+                    st === "scheduled" && r.publish_at
+                      ? new Date(r.publish_at).toLocaleString()
+                      : undefined
+                  }
+                >
+                  {t(`status.${st}`)}
+                </Tag>
+              ),
+            },
             {
               title: t("orders.col.eta_days"),
               dataIndex: "base_production_time_days",
@@ -253,83 +330,147 @@ export default function AdminProducts() {
             },
             {
               title: t("actions"),
-              render: (_: any, r: any) => (
-                <>
+              render: (_: any, r: any) =>
+                archived ? (
                   <Button
                     size="small"
-                    style={{ marginRight: 8 }}
-                    onClick={() => {
-                      setEditing(r);
-                      setOpenProduct(true);
-                      const origin = (() => {
-                        try {
-                          return new URL(api.defaults.baseURL as string).origin;
-                        } catch {
-                          return "";
-                        }
-                      })();
-                      const imageVal =
-                        r.image_url && !/^https?:/i.test(r.image_url)
-                          ? origin + r.image_url
-                          : r.image_url;
-                      productForm.setFieldsValue({
-                        department_id: categories.find(
-                          (c: any) => c.id === r.category_id
-                        )?.department_id,
-                        category_id: r.category_id,
-                        name: r.name,
-                        description: r.short_description || r.long_description,
-                        price: r.base_price,
-                        quantity: r.quantity,
-                        production_days: r.base_production_time_days,
-                        image: imageVal,
-                        default_width: r.default_width,
-                        default_height: r.default_height,
-                        default_depth: r.default_depth,
-                        base_material: r.base_material,
-                      });
-                      setImagePreview(imageVal || null);
-                      api
-                        .get(`/admin/product_options`, {
-                          params: { product_id: r.id },
-                        })
-                        .then((res) => {
-                          const items = (res.data || []) as any[];
-                          setColorOptions(
-                            items
-                              .filter((o) => o.option_type === "color")
-                              .map((o) => o.option_name)
-                          );
-                          setMaterialOptions(
-                            items
-                              .filter((o) => o.option_type === "material")
-                              .map((o) => o.option_name)
-                          );
-                          setExtraOptions(
-                            items
-                              .filter((o) => o.option_type === "extra")
-                              .map((o) => o.option_name)
-                          );
-                        })
-                        .catch(() => {
-                          setColorOptions([]);
-                          setMaterialOptions([]);
-                          setExtraOptions([]);
-                        });
+                    onClick={async () => {
+                      try {
+                        await restoreRecord(`/admin/products/${r.id}`);
+                        load();
+                      } catch (e: any) {
+                        message.error(
+                          e.response?.data?.message || "Restore failed",
+                        );
+                      }
                     }}
                   >
-                    Edit
+                    {t("restore")}
                   </Button>
-                  <Popconfirm
-                    title="Delete product?"
-                    onConfirm={() => removeProduct(r.id)}
-                  >
-                    <Button danger size="small">
-                      Delete
+                ) : (
+                  <>
+                    <Button
+                      size="small"
+                      style={{ marginRight: 8 }}
+                      onClick={() => {
+                        setEditing(r);
+                        setOpenProduct(true);
+                        const origin = (() => {
+                          try {
+                            return new URL(api.defaults.baseURL as string).origin;
+                          } catch {
+                            return "";
+                          }
+                        })();
+                        const imageVal =
+                          r.image_url && !/^https?:/i.test(r.image_url)
+                            ? origin + r.image_url
+                            : r.image_url;
+                        productForm.setFieldsValue({
+                          department_id: categories.find(
+                            (c: any) => c.id === r.category_id
+                          )?.department_id,
+                          category_id: r.category_id,
+                          sku: r.sku,
+                          name: r.name,
+                          description: r.short_description || r.long_description,
+                          price: r.base_price,
+                          quantity: r.quantity,
+                          production_days: r.base_production_time_days,
+                          image: imageVal,
+                          default_width: r.default_width,
+                          default_height: r.default_height,
+                          default_depth: r.default_depth,
+                          base_material: r.base_material,
+                          slug: r.slug,
+                          seo_title: r.seo_title,
+                          seo_description: r.seo_description,
+                          status: r.status,
+                          publish_at: toLocalInput(r.publish_at),
+                          unpublish_at: toLocalInput(r.unpublish_at),
+                        });
+                        setImagePreview(imageVal || null);
+                        fetchProduct(r.id, true).then((p) =>
+                          productForm.setFieldsValue({
+                            attributes: Object.fromEntries(
+                              (p.attributes || []).map((a: any) => [
+                                a.attribute?.code,
+                                a.attribute?.type === "number"
+                                  ? a.number
+                                  : a.attribute?.type === "boolean"
+                                    ? a.value === "true"
+                                    : a.value,
+                              ]),
+                            ),
+                          }),
+                        );
+                        api
+                          .get(`/admin/product_options`, {
+                            params: { product_id: r.id },
+                          })
+                          .then((res) => {
+                            const items = (res.data || []) as any[];
+                            setColorOptions(
+                              items
+                                .filter((o) => o.option_type === "color")
+                                .map((o) => o.option_name)
+                            );
+                            setMaterialOptions(
+                              items
+                                .filter((o) => o.option_type === "material")
+                                .map((o) => o.option_name)
+                            );
+                            setExtraOptions(
+                              items
+                                .filter((o) => o.option_type === "extra")
+                                .map((o) => o.option_name)
+                            );
+                          })
+                          .catch(() => {
+                            setColorOptions([]);
+                            setMaterialOptions([]);
+                            setExtraOptions([]);
+                          });
+                      }}
+                    >
+                      Edit
                     </Button>
-                  </Popconfirm>
-                </>
-              ),
+                    <Button
+                      size="small"
+                      style={{ marginRight: 8 }}
+                      onClick={() =>
+                        window.open(
+                          `/product/${r.slug || r.id}?preview=true`,
+                          "_blank",
+                        )
+                      }
+                    >
+                      {t("preview")}
+                    </Button>
+                    <Button
+                      size="small"
+                      style={{ marginRight: 8 }}
+                      onClick={() => setBundleOf(r)}
+                    >
+                      {t("bundle")}
+                    </Button>
+                    <Button
+                      size="small"
+                      style={{ marginRight: 8 }}
+                      onClick={() => setPricesOf(r)}
+                    >
+                      {t("prices")}
+                    </Button>
+                    <Popconfirm
+                      title="Archive product?"
+                      onConfirm={() => removeProduct(r.id)}
+                    >
+                      <Button danger size="small">
+                        {t("archive")}
+                      </Button>
+                    </Popconfirm>
+                  </>
+                ),
             },
           ]}
         />
@@ -472,9 +613,59 @@ export default function AdminProducts() {
                 allowClear
               />
             </Form.Item>
+            {attributeDefs.map((d) => (
+              <Form.Item
+                key={d.id}
+                name={["attributes", d.code]}
+                label={d.unit ? `${d.name} (${d.unit})` : d.name}
+                rules={d.required ? [{ required: true }] : []}
+                valuePropName={d.type === "boolean" ? "checked" : "value"}
+              >
+                {d.type === "number" ? (
+                  <InputNumber style={{ width: "100%" }} />
+                ) : d.type === "boolean" ? (
+                  <Switch />
+                ) : d.type === "enum" ? (
+                  <Select
+                    allowClear
+                    options={(d.choices || []).map((c: string) => ({
+                      value: c,
+                      label: c,
+                    }))}
+                  />
+                ) : (
+                  <Input maxLength={500} />
+                )}
+              </Form.Item>
+            ))}
+            <Form.Item name="sku" label="SKU">
+              <Input maxLength={64} />
+            </Form.Item>
             <Form.Item name="base_material" label="Base Material">
               <Input placeholder="e.g., MDF, Wood, Metal" />
             </Form.Item>
+            <Form.Item name="slug" label={t("slug")}>
+              <Input />
+            </Form.Item>
+            <Form.Item name="seo_title" label={t("seo_title")}>
+              <Input maxLength={255} />
+            </Form.Item>
+            <Form.Item name="seo_description" label={t("seo_description")}>
+              <Input.TextArea rows={2} maxLength={500} />
+            </Form.Item>
+            <Form.Item name="status" label={t("status")} initialValue="draft">
+              <Select
+                options={["draft", "scheduled", "published", "discontinued"].map(
+                  (st) => ({ value: st, label: t(`status.${st}`) }),
+                )}
+              />
+            </Form.Item>
+            <Form.Item name="publish_at" label={t("publish_at")}>
+              <Input type="datetime-local" />
+            </Form.Item>
+            <Form.Item name="unpublish_at" label={t("unpublish_at")}>
+              <Input type="datetime-local" />
+            </Form.Item>
             <Form.Item label="Extras">
               <Select
                 mode="tags"
@@ -550,7 +741,420 @@ export default function AdminProducts() {
             </Upload>
           </Form>
         </Modal>
+        {bundleOf && (
+          <BundleEditor
+            product={bundleOf}
+            products={products}
+            onClose={() => {
+              setBundleOf(null);
+              load();
+            }}
+          />
+        )}
+        {pricesOf && (
+          <PriceEditor
+            product={pricesOf}
+            onClose={() => {
+              setPricesOf(null);
+              load();
+            }}
+          />
+        )}
       </Card>
     </div>
   );
 }
+
+// BundleEditor turns a product into a set of other products (e.g. a table
+// and six chairs) priced at the product's price or at a discount off them.
+function BundleEditor({
+  product,
+  products,
+  onClose,
+}: {
+  product: any;
+  products: any[];
+  onClose: () => void;
+}) {
+  const { t } = useI18n();
+  const [form] = Form.useForm();
+  const [exists, setExists] = useState(false);
+  const [options, setOptions] = useState<Record<number, any[]>>({});
+  const pricing = Form.useWatch("pricing", form);
+  const components: any[] = Form.useWatch("components", form) || [];
+
+  const loadOptions = (productId?: number) => {
+    if (!productId || options[productId]) return;
+    api
+      .get(`/admin/product_options`, { params: { product_id: productId } })
+      .then((r) => setOptions((prev) => ({ ...prev, [productId]: r.data })));
+  };
+
+  useEffect(() => {
+    api
+      .get(`/admin/products/${product.id}/bundle`)
+      .then((r) => {
+        setExists(true);
+        form.setFieldsValue({
+          pricing: r.data.pricing,
+          discount_percent: r.data.discount_percent,
+          components: r.data.components.map((c: any) => ({
+            product_id: c.product_id,
+            quantity: c.quantity,
+            option_ids: c.option_ids || [],
+          })),
+        });
+        r.data.components.forEach((c: any) => loadOptions(c.product_id));
+      })
+      .catch(() =>
+        form.setFieldsValue({
+          pricing: "fixed",
+          discount_percent: 0,
+          components: [{ quantity: 1, option_ids: [] }],
+        }),
+      );
+  }, [product.id]);
+
+  const save = async () => {
+    const v = await form.validateFields();
+    try {
+      await api.put(`/admin/products/${product.id}/bundle`, v);
+      message.success("Saved");
+      onClose();
+    } catch (e: any) {
+      message.error(e.response?.data?.message || "Save failed");
+    }
+  };
+
+  return (
+    <Modal
+      title={`${t("bundle")}: ${product.name}`}
+      open
+      width={720}
+      onCancel={onClose}
+      footer={[
+        exists && (
+          <Popconfirm
+            key="remove"
+            title={t("bundle.remove") + "?"}
+            onConfirm={async () => {
+              await api.delete(`/admin/products/${product.id}/bundle`);
+              onClose();
+            }}
+          >
+            <Button danger>{t("bundle.remove")}</Button>
+          </Popconfirm>
+        ),
+        <Button key="save" type="primary" onClick={save}>
+          Save
+        </Button>,
+      ]}
+    >
+      <Form layout="vertical" form={form}>
+        <Space>
+          <Form.Item name="pricing" label={t("bundle.pricing")}>
+            <Select
+              style={{ width: 260 }}
+              options={[
+                { value: "fixed", label: t("bundle.fixed") },
+                { value: "discount", label: t("bundle.discount") },
+              ]}
+            />
+          </Form.Item>
+          {pricing === "discount" && (
+            <Form.Item
+              name="discount_percent"
+              label={t("bundle.discount_percent")}
+            >
+              <InputNumber min={0} max={99} />
+            </Form.Item>
+          )}
+        </Space>
+        <Form.List name="components">
+          {(fields, { add, remove }) => (
+            <>
+              {fields.map((field, i) => (
+                <Space key={field.key} align="baseline" wrap>
+                  <Form.Item
+                    name={[field.name, "product_id"]}
+                    rules={[{ required: true }]}
+                  >
+                    <Select
+                      showSearch
+                      optionFilterProp="label"
+                      style={{ width: 240 }}
+                      placeholder={t("products")}
+                      onChange={(id: number) => {
+                        loadOptions(id);
+                        form.setFieldValue(
+                          ["components", field.name, "option_ids"],
+                          [],
+                        );
+                      }}
+                      options={products
+                        .filter((p) => p.id !== product.id)
+                        .map((p) => ({ value: p.id, label: p.name }))}
+                    />
+                  </Form.Item>
+                  <Form.Item
+                    name={[field.name, "quantity"]}
+                    rules={[{ required: true }]}
+                  >
+                    <InputNumber min={1} />
+                  </Form.Item>
+                  <Form.Item name={[field.name, "option_ids"]}>
+                    <Select
+                      mode="multiple"
+                      style={{ width: 220 }}
+                      placeholder={t("product.options")}
+                      options={(options[components[i]?.product_id] || []).map(
+                        (o: any) => ({
+                          value: o.id,
+                          label: `${o.option_name} (${o.option_type})`,
+                        }),
+                      )}
+                    />
+                  </Form.Item>
+                  <Button onClick={() => remove(field.name)}>−</Button>
+                </Space>
+              ))}
+              <Button onClick={() => add({ quantity: 1, option_ids: [] })}>
+                {t("bundle.add_component")}
+              </Button>
+            </>
+          )}
+        </Form.List>
+      </Form>
+    </Modal>
+  );
+}
+
+// PriceEditor plans price changes and sales of a product and its options
+// and shows the history of its prices.
+function PriceEditor({
+  product,
+  onClose,
+}: {
+  product: any;
+  onClose: () => void;
+}) {
+  const { t } = useI18n();
+  const [form] = Form.useForm();
+  const [schedules, setSchedules] = useState<any[]>([]);
+  const [history, setHistory] = useState<any[]>([]);
+  const [historyPage, setHistoryPage] = useState(1);
+  const [historyTotal, setHistoryTotal] = useState(0);
+  const [options, setOptions] = useState<any[]>([]);
+  const kind = Form.useWatch("kind", form);
+
+  const optionName = (id?: number) =>
+    id ? options.find((o) => o.id === id)?.option_name || `#${id}` : "";
+
+  const load = () => {
+    api
+      .get(`/admin/products/${product.id}/price-schedules`)
+      .then((r) => setSchedules(r.data));
+    api
+      .get(`/admin/products/${product.id}/price-history`, {
+        params: { page: historyPage },
+      })
+      .then((r) => {
+        setHistory(r.data.items);
+        setHistoryTotal(r.data.pagination.total);
+      });
+  };
+
+  useEffect(() => {
+    api
+      .get(`/admin/product_options`, { params: { product_id: product.id } })
+      .then((r) => setOptions(r.data));
+    form.setFieldsValue({ kind: "change" });
+  }, [product.id]);
+  useEffect(load, [product.id, historyPage]);
+
+  const schedule = async () => {
+    const v = await form.validateFields();
+    try {
+      await api.post(`/admin/products/${product.id}/price-schedules`, {
+        ...v,
+        option_id: v.kind === "change" ? v.option_id : undefined,
+        starts_at: fromLocalInput(v.starts_at) || undefined,
+        ends_at: v.kind === "sale" ? fromLocalInput(v.ends_at) : undefined,
+      });
+      message.success("Saved");
+      form.resetFields();
+      form.setFieldsValue({ kind: "change" });
+      load();
+    } catch (e: any) {
+      message.error(e.response?.data?.message || "Save failed");
+    }
+  };
+
+  const cancel = async (id: number) => {
+    try {
+      await api.delete(`/admin/price-schedules/${id}`);
+      load();
+    } catch (e: any) {
+      message.error(e.response?.data?.message || "Cancel failed");
+    }
+  };
+
+  const when = (v?: string) => (v ? new Date(v).toLocaleString() : "");
+
+  return (
+    <Modal
+      title={`${t("prices")}: ${product.name}`}
+      open
+      width={860}
+      onCancel={onClose}
+      footer={null}
+    >
+      <Tabs
+        items={[
+          {
+            key: "schedules",
+            label: t("prices.schedules"),
+            children: (
+              <>
+                <Form layout="inline" form={form} style={{ marginBottom: 16 }}>
+                  <Form.Item name="kind">
+                    <Select
+                      style={{ width: 150 }}
+                      options={[
+                        { value: "change", label: t("prices.kind.change") },
+                        { value: "sale", label: t("prices.kind.sale") },
+                      ]}
+                    />
+                  </Form.Item>
+                  {kind === "change" && (
+                    <Form.Item name="option_id">
+                      <Select
+                        allowClear
+                        style={{ width: 180 }}
+                        placeholder={t("product.base_price")}
+                        options={options.map((o: any) => ({
+                          value: o.id,
+                          label: `${o.option_name} (${o.option_type})`,
+                        }))}
+                      />
+                    </Form.Item>
+                  )}
+                  <Form.Item name="price" rules={[{ required: true }]}>
+                    <InputNumber min={0} step={0.01} placeholder={t("prices.price")} />
+                  </Form.Item>
+                  <Form.Item name="starts_at" label={t("prices.starts_at")}>
+                    <Input type="datetime-local" />
+                  </Form.Item>
+                  {kind === "sale" && (
+                    <Form.Item
+                      name="ends_at"
+                      label={t("prices.ends_at")}
+                      rules={[{ required: true }]}
+                    >
+                      <Input type="datetime-local" />
+                    </Form.Item>
+                  )}
+                  <Button type="primary" onClick={schedule}>
+                    {t("prices.schedule")}
+                  </Button>
+                </Form>
+                <Table
+                  rowKey="id"
+                  size="small"
+                  pagination={false}
+                  dataSource={schedules}
+                  columns={[
+                    {
+                      title: t("prices.kind"),
+                      dataIndex: "kind",
+                      render: (k: string, r: any) =>
+                        [t(`prices.kind.${k}`), optionName(r.option_id)]
+                          .filter(Boolean)
+                          .join(": "),
+                    },
+                    {
+                      title: t("prices.price"),
+                      dataIndex: "price",
+                      render: (v: number) => Number(v).toFixed(2),
+                    },
+                    {
+                      title: t("prices.starts_at"),
+                      dataIndex: "starts_at",
+                      render: when,
+                    },
+                    { title: t("prices.ends_at"), dataIndex: "ends_at", render: when },
+                    {
+                      title: t("status"),
+                      dataIndex: "status",
+                      render: (st: string) => <Tag>{t(`prices.status.${st}`)}</Tag>,
+                    },
+                    {
+                      title: t("actions"),
+                      render: (_: any, r: any) =>
+                        r.status !== "done" && (
+                          <Popconfirm
+                            title={t("prices.cancel") + "?"}
+                            onConfirm={() => cancel(r.id)}
+                          >
+                            <Button danger size="small">
+                              {t("prices.cancel")}
+                            </Button>
+                          </Popconfirm>
+                        ),
+                    },
+                  ]}
+                />
+              </>
+            ),
+          },
+          {
+            key: "history",
+            label: t("prices.history"),
+            children: (
+              <Table
+                rowKey="id"
+                size="small"
+                dataSource={history}
+                pagination={{
+                  current: historyPage,
+                  total: historyTotal,
+                  pageSize: 24,
+                  onChange: setHistoryPage,
+                }}
+                columns={[
+                  {
+                    title: t("prices.changed_at"),
+                    dataIndex: "created_at",
+                    render: when,
+                  },
+                  {
+                    title: t("prices.option"),
+                    dataIndex: "option_id",
+                    render: (id?: number) => optionName(id),
+                  },
+                  {
+                    title: t("prices.old_price"),
+                    dataIndex: "old_price",
+                    render: (v: number) => Number(v).toFixed(2),
+                  },
+                  {
+                    title: t("prices.new_price"),
+                    dataIndex: "new_price",
+                    render: (v: number, r: any) =>
+                      Number(v).toFixed(2) +
+                      (r.modifier_type === "percent" ? "%" : ""),
+                  },
+                  {
+                    title: t("prices.reason"),
+                    dataIndex: "reason",
+                    render: (v: string) => t(`prices.reason.${v}`),
+                  },
+                ]}
+              />
+            ),
+          },
+        ]}
+      />
+    </Modal>
+  );
+}
@@ -0,0 +1,181 @@
+import { Button, Card, Input, Popconfirm, Select, Space, Table, Tabs, Tag, message } from "antd";
+import { useEffect, useState } from "react";
+import { Link } from "react-router-dom";
+import { fetchAllPages } from "../api/client";
+import {
+  answerAsShop,
+  deleteAnswer,
+  deleteQuestion,
+  moderateAnswer,
+  moderateQuestion,
+} from "../api/questions";
+import { useI18n } from "../store/I18nContext";
+
+const STATUSES = ["pending", "approved", "rejected", "all"];
+
+const productLink = (p: any, id: number) => <Link to={`/product/${p?.slug || id}`}>{p?.name || id}</Link>;
+
+export default function AdminQuestions() {
+  const { t } = useI18n();
+  const [tab, setTab] = useState("questions");
+  const [status, setStatus] = useState("pending");
+  const [questions, setQuestions] = useState<any[]>([]);
+  const [answers, setAnswers] = useState<any[]>([]);
+  const [replies, setReplies] = useState<Record<number, string>>({});
+
+  const load = async () => {
+    try {
+      if (tab === "questions") setQuestions(await fetchAllPages("/admin/questions", { status }));
+      else setAnswers(await fetchAllPages("/admin/answers", { status }));
+    } catch {
+      message.error("Failed to load questions");
+    }
+  };
+
+  useEffect(() => {
+    load();
+  }, [tab, status]);
+
+  const run = async (fn: () => Promise<any>) => {
+    try {
+      await fn();
+      load();
+    } catch (e: any) {
+      message.error(e?.response?.data?.message || "Failed to update");
+    }
+  };
+
+  const actions = (r: any, moderate: (id: number, s: string) => Promise<any>, remove: (id: number) => Promise<any>) => (
+    <Space>
+      {r.status !== "approved" && (
+        <Button type="primary" size="small" onClick={() => run(() => moderate(r.id, "approved"))}>
+          {t("reviews.approve")}
+        </Button>
+      )}
+      {r.status !== "rejected" && (
+        <Button size="small" onClick={() => run(() => moderate(r.id, "rejected"))}>
+          {t("reviews.reject")}
+        </Button>
+      )}
+      <Popconfirm title={t("questions.delete")} onConfirm={() => run(() => remove(r.id))}>
+        <Button danger size="small">
+          {t("questions.delete")}
+        </Button>
+      </Popconfirm>
+    </Space>
+  );
+
+  const statusColumn = {
+    title: t("orders.col.status"),
+    dataIndex: "status",
+    render: (s: string) => <Tag>{s}</Tag>,
+  };
+
+  return (
+    <Card
+      title={t("questions")}
+      extra={
+        <Select
+          style={{ minWidth: 180 }}
+          value={status}
+          onChange={setStatus}
+          options={STATUSES.map((s) => ({ value: s, label: s }))}
+        />
+      }
+    >
+      <Tabs
+        activeKey={tab}
+        onChange={setTab}
+        items={[
+          {
+            key: "questions",
+            label: t("questions"),
+            children: (
+              <Table
+                rowKey="id"
+                dataSource={questions}
+                columns={[
+                  { title: t("reviews.product"), render: (_: any, q: any) => productLink(q.product, q.product_id) },
+                  { title: t("reviews.author"), render: (_: any, q: any) => q.author_name || t("reviews.anonymous") },
+                  {
+                    title: t("questions.question"),
+                    render: (_: any, q: any) => (
+                      <div style={{ maxWidth: 360 }}>
+                        <div style={{ whiteSpace: "pre-line" }}>{q.body}</div>
+                        {(q.answers || []).map((a: any) => (
+                          <div key={a.id} style={{ marginTop: 4, color: "#888" }}>
+                            {a.official ? t("questions.official") : a.author_name}: {a.body} <Tag>{a.status}</Tag>
+                          </div>
+                        ))}
+                      </div>
+                    ),
+                  },
+                  statusColumn,
+                  {
+                    title: t("actions"),
+                    render: (_: any, q: any) => (
+                      <Space direction="vertical">
+                        <Input.TextArea
+                          rows={2}
+                          placeholder={t("questions.answer")}
+                          value={replies[q.id] || ""}
+                          onChange={(e) => setReplies((prev) => ({ ...prev, [q.id]: e.target.value }))}
+                        />
+                        <Button
+                          size="small"
+                          disabled={q.status === "rejected" || (replies[q.id] || "").trim().length < 3}
+                          onClick={() =>
+                            run(async () => {
+                              await answerAsShop(q.id, replies[q.id]);
+                              setReplies((prev) => ({ ...prev, [q.id]: "" }));
+                            })
+                          }
+                        >
+                          {t("questions.send_answer")}
+                        </Button>
+                        {actions(q, moderateQuestion, deleteQuestion)}
+                      </Space>
+                    ),
+                  },
+                ]}
+              />
+            ),
+          },
+          {
+            key: "answers",
+            label: t("questions.answers"),
+            children: (
+              <Table
+                rowKey="id"
+                dataSource={answers}
+                columns={[
+                  {
+                    title: t("reviews.product"),
+                    render: (_: any, a: any) => productLink(a.question?.product, a.question?.product_id),
+                  },
+                  { title: t("questions.question"), render: (_: any, a: any) => a.question?.body },
+                  {
+                    title: t("reviews.author"),
+                    render: (_: any, a: any) => (
+                      <>
+                        {a.author_name || t("reviews.anonymous")}{" "}
+                        {a.official && <Tag color="blue">{t("questions.official")}</Tag>}
+                        {a.verified && <Tag color="green">{t("reviews.verified")}</Tag>}
+                      </>
+                    ),
+                  },
+                  {
+                    title: t("questions.answers"),
+                    render: (_: any, a: any) => <div style={{ maxWidth: 360, whiteSpace: "pre-line" }}>{a.body}</div>,
+                  },
+                  statusColumn,
+                  { title: t("actions"), render: (_: any, a: any) => actions(a, moderateAnswer, deleteAnswer) },
+                ]}
+              />
+            ),
+          },
+        ]}
+      />
+    </Card>
+  );
+}
@@ -0,0 +1,135 @@
+import { Button, Card, Input, Popconfirm, Rate, Select, Space, Table, Tag, message } from "antd";
+import { useEffect, useState } from "react";
+import { Link } from "react-router-dom";
+import { fetchAllPages, getApiOrigin } from "../api/client";
+import { deleteReview, moderateReview } from "../api/reviews";
+import { useI18n } from "../store/I18nContext";
+
+const REVIEW_STATUSES = ["pending", "approved", "rejected", "all"];
+
+export default function AdminReviews() {
+  const { t } = useI18n();
+  const [reviews, setReviews] = useState<any[]>([]);
+  const [status, setStatus] = useState("pending");
+  const [notes, setNotes] = useState<Record<number, string>>({});
+
+  const load = async () => {
+    try {
+      setReviews(await fetchAllPages("/admin/reviews", { status, sort: "oldest" }));
+    } catch {
+      message.error("Failed to load reviews");
+    }
+  };
+
+  useEffect(() => {
+    load();
+  }, [status]);
+
+  const moderate = async (r: any, s: string) => {
+    try {
+      await moderateReview(r.id, s, notes[r.id] ?? r.moderation_note ?? "");
+      load();
+    } catch {
+      message.error("Failed to update review");
+    }
+  };
+
+  const origin = getApiOrigin();
+  const abs = (u: string) => (/^https?:/i.test(u) ? u : origin + u);
+  return (
+    <Card
+      title={t("reviews")}
+      extra={
+        <Select
+          style={{ minWidth: 180 }}
+          value={status}
+          onChange={setStatus}
+          options={REVIEW_STATUSES.map((s) => ({ value: s, label: s }))}
+        />
+      }
+    >
+      <Table
+        rowKey="id"
+        dataSource={reviews}
+        columns={[
+          {
+            title: t("reviews.product"),
+            render: (_: any, r: any) => (
+              <Link to={`/product/${r.product?.slug || r.product_id}`}>{r.product?.name || r.product_id}</Link>
+            ),
+          },
+          {
+            title: t("reviews.author"),
+            render: (_: any, r: any) => (
+              <>
+                {r.author_name || t("reviews.anonymous")}{" "}
+                {r.verified && <Tag color="green">{t("reviews.verified")}</Tag>}
+              </>
+            ),
+          },
+          {
+            title: t("reviews.rating"),
+            dataIndex: "rating",
+            render: (v: number) => <Rate disabled value={v} style={{ fontSize: 12 }} />,
+          },
+          {
+            title: t("reviews"),
+            render: (_: any, r: any) => (
+              <div style={{ maxWidth: 360 }}>
+                <strong>{r.title}</strong>
+                <div style={{ whiteSpace: "pre-line" }}>{r.body}</div>
+                <Space style={{ marginTop: 4 }}>
+                  {(r.photos || []).map((p: any) => (
+                    <a key={p.id} href={abs(p.url)} target="_blank" rel="noreferrer">
+                      <img src={abs(p.url)} alt="" style={{ width: 48, height: 48, objectFit: "cover" }} />
+                    </a>
+                  ))}
+                </Space>
+              </div>
+            ),
+          },
+          {
+            title: t("orders.col.status"),
+            dataIndex: "status",
+            render: (s: string) => <Tag>{s}</Tag>,
+          },
+          {
+            title: t("actions"),
+            render: (_: any, r: any) => (
+              <Space direction="vertical">
+                <Input
+                  placeholder={t("reviews.note")}
+                  defaultValue={r.moderation_note}
+                  onChange={(e) => setNotes((prev) => ({ ...prev, [r.id]: e.target.value }))}
+                />
+                <Space>
+                  {r.status !== "approved" && (
+                    <Button type="primary" size="small" onClick={() => moderate(r, "approved")}>
+                      {t("reviews.approve")}
+                    </Button>
+                  )}
+                  {r.status !== "rejected" && (
+                    <Button size="small" onClick={() => moderate(r, "rejected")}>
+                      {t("reviews.reject")}
+                    </Button>
+                  )}
+                  <Popconfirm
+                    title={t("reviews.delete")}
+                    onConfirm={async () => {
+                      await deleteReview(r.id);
+                      load();
+                    }}
+                  >
+                    <Button danger size="small">
+                      {t("reviews.delete")}
+                    </Button>
+                  </Popconfirm>
+                </Space>
+              </Space>
+            ),
+          },
+        ]}
+      />
+    </Card>
+  );
+}
@@ -5,7 +5,7 @@ import { useI18n } from "../store/I18nContext";
 import { getApiOrigin } from "../api/client";
 
 function unitPrice(product: any, selected: { id: number; type: string }[]) {
-  let price = Number(product.base_price || 0);
+  let price = Number(product.sale_price ?? product.base_price ?? 0);
   const byId: Record<number, any> = {};
   (product.options || []).forEach((o: any) => (byId[o.id] = o));
   selected.forEach((so) => {
@@ -1,13 +1,17 @@
-import { Button, Card, Col, Row, Typography } from "antd";
+import { Button, Card, Checkbox, Col, Rate, Row, Select, Typography } from "antd";
 import { useEffect, useState } from "react";
 import {
+  fetchCategoryAttributes,
   fetchCategories,
   fetchDepartments,
   fetchProductsByCategory,
 } from "../api/catalog";
-import { Link, useSearchParams } from "react-router-dom";
+import { Link, useNavigate, useSearchParams } from "react-router-dom";
 import { useI18n } from "../store/I18nContext";
 import { getApiOrigin } from "../api/client";
+import Price from "../components/Price";
+
+const SORTS = ["newest", "popularity", "rating", "price_asc", "price_desc", "name"];
 
 export default function Catalog() {
   const [depts, setDepts] = useState<any[]>([]);
@@ -15,21 +19,44 @@ export default function Catalog() {
   const [products, setProducts] = useState<any[]>([]);
   const [deptId, setDeptId] = useState<number | undefined>();
   const [catId, setCatId] = useState<number | undefined>();
-  const { t } = useI18n();
+  const [facets, setFacets] = useState<any[]>([]);
+  const [attrs, setAttrs] = useState<Record<string, string>>({});
+  const [compared, setCompared] = useState<number[]>([]);
+  const [sort, setSort] = useState("newest");
+  const [ratingMin, setRatingMin] = useState<number | undefined>();
+  const nav = useNavigate();
+  const { t, lang } = useI18n();
   const [searchParams, setSearchParams] = useSearchParams();
   useEffect(() => {
     fetchDepartments().then(setDepts);
-  }, []);
+  }, [lang]);
+  // dept and cat are slugs, or ids in older links
   useEffect(() => {
     const s = searchParams.get("dept");
-    if (s) setDeptId(Number(s));
-  }, [searchParams]);
+    const d = depts.find((d) => d.slug === s || String(d.id) === s);
+    if (d) setDeptId(d.id);
+  }, [searchParams, depts]);
+  useEffect(() => {
+    const c = searchParams.get("cat");
+    const cat = cats.find((x) => x.slug === c || String(x.id) === c);
+    if (cat) setCatId(cat.id);
+  }, [searchParams, cats]);
   useEffect(() => {
     if (deptId) fetchCategories(deptId).then(setCats);
-  }, [deptId]);
+  }, [deptId, lang]);
   useEffect(() => {
-    if (catId) fetchProductsByCategory(catId).then(setProducts);
-  }, [catId]);
+    setAttrs({});
+    setFacets([]);
+    if (catId) fetchCategoryAttributes(catId).then(setFacets);
+  }, [catId, lang]);
+  useEffect(() => {
+    if (catId)
+      fetchProductsByCategory(
+        catId,
+        Object.entries(attrs).map(([code, v]) => `${code}:${v}`),
+        { sort, rating_min: ratingMin },
+      ).then(setProducts);
+  }, [catId, lang, attrs, sort, ratingMin]);
   return (
     <div>
       <Typography.Title level={2}>{t("catalog.title")}</Typography.Title>
@@ -90,7 +117,7 @@ export default function Catalog() {
                     setCatId(undefined);
                     setSearchParams((sp) => {
                       const next = new URLSearchParams(sp);
-                      next.set("dept", String(d.id));
+                      next.set("dept", d.slug || String(d.id));
                       return next;
                     });
                   }}
@@ -113,6 +140,64 @@ export default function Catalog() {
           ))}
         </Row>
       )}
+      {!!catId && (
+        <div style={{ display: "flex", gap: 8, flexWrap: "wrap", marginBottom: 12 }}>
+          <Select
+            value={sort}
+            style={{ minWidth: 180 }}
+            onChange={setSort}
+            options={SORTS.map((s) => ({ value: s, label: `${t("sort")}: ${t(`sort.${s}`)}` }))}
+          />
+          <Select
+            allowClear
+            placeholder={t("catalog.rating_min")}
+            style={{ minWidth: 160 }}
+            value={ratingMin}
+            onChange={setRatingMin}
+            options={[4, 3, 2, 1].map((n) => ({ value: n, label: `${n}★ +` }))}
+          />
+          {facets
+            .filter((f) => f.values.length > 0)
+            .map((f) => (
+              <Select
+                key={f.code}
+                allowClear
+                placeholder={f.name}
+                style={{ minWidth: 160 }}
+                value={attrs[f.code]}
+                onChange={(v) =>
+                  setAttrs((prev) => {
+                    const next = { ...prev };
+                    if (v === undefined) delete next[f.code];
+                    else next[f.code] = v;
+                    return next;
+                  })
+                }
+                options={f.values.map((v: any) => ({
+                  value: v.value,
+                  label: `${
+                    f.type === "boolean"
+                      ? `${f.name}: ${t(v.value === "true" ? "yes" : "no")}`
+                      : f.unit
+                        ? `${v.label} ${f.unit}`
+                        : v.label
+                  } (${v.count})`,
+                }))}
+              />
+            ))}
+        </div>
+      )}
+      {compared.length > 0 && (
+        <div style={{ marginBottom: 12 }}>
+          <Button
+            type="primary"
+            disabled={compared.length < 2}
+            onClick={() => nav(`/compare?ids=${compared.join(",")}`)}
+          >
+            {t("compare")} ({compared.length})
+          </Button>
+        </div>
+      )}
       <Row gutter={[16, 16]}>
         {products.map((p) => {
           const origin = getApiOrigin();
@@ -122,7 +207,10 @@ export default function Catalog() {
               : p.image_url;
           return (
             <Col key={p.id} xs={24} sm={12} md={8}>
-              <Link to={`/product/${p.id}`} style={{ display: "block" }}>
+              <Link
+                to={`/product/${p.slug || p.id}`}
+                style={{ display: "block" }}
+              >
                 <Card
                   hoverable
                   title={p.name}
@@ -143,6 +231,17 @@ export default function Catalog() {
                   {!!p.short_description && (
                     <p style={{ color: "#666" }}>{p.short_description}</p>
                   )}
+                  {p.rating_count > 0 && (
+                    <div style={{ marginBottom: 8 }}>
+                      <Rate
+                        disabled
+                        allowHalf
+                        value={Math.round(p.rating_average * 2) / 2}
+                        style={{ fontSize: 14 }}
+                      />{" "}
+                      <span style={{ color: "#666" }}>({p.rating_count})</span>
+                    </div>
+                  )}
                   <div
                     style={{
                       display: "flex",
@@ -150,8 +249,7 @@ export default function Catalog() {
                     }}
                   >
                     <span>
-                      {t("product.base_price")}:{" "}
-                      {Number(p.base_price).toFixed(2)}
+                      {t("product.base_price")}: <Price product={p} />
                     </span>
                     <span>
                       {t("product.base_prod_time")}:{" "}
@@ -160,6 +258,20 @@ export default function Catalog() {
                   </div>
                 </Card>
               </Link>
+              <Checkbox
+                checked={compared.includes(p.id)}
+                disabled={!compared.includes(p.id) && compared.length >= 4}
+                onChange={(e) => {
+                  setCompared((prev) =>
+                    e.target.checked
+                      ? [...prev, p.id]
+                      : prev.filter((id) => id !== p.id),
+                  );
+                }}
+                style={{ marginTop: 8 }}
+              >
+                {t("compare")}
+              </Checkbox>
             </Col>
           );
         })}
@@ -0,0 +1,60 @@
+import { Table, Tag, Typography } from "antd";
+import { useEffect, useState } from "react";
+import { Link, useSearchParams } from "react-router-dom";
+import { compareProducts } from "../api/catalog";
+import { useI18n } from "../store/I18nContext";
+
+export default function Compare() {
+  const [params] = useSearchParams();
+  const { t, lang } = useI18n();
+  const [data, setData] = useState<any>();
+  const [error, setError] = useState<string>();
+  const ids = (params.get("ids") || "").split(",").filter(Boolean).map(Number);
+  useEffect(() => {
+    compareProducts(ids)
+      .then(setData)
+      .catch((e) => setError(e.response?.data?.message || "Failed to compare"));
+  }, [params, lang]);
+  if (error) return <Typography.Text type="danger">{error}</Typography.Text>;
+  if (!data) return null;
+  const show = (row: any, v: any) => {
+    if (v === null || v === undefined) return "—";
+    if (typeof v === "boolean") return t(v ? "yes" : "no");
+    if (row.key === "base_price") return Number(v).toFixed(2);
+    return row.unit && row.group !== "option" ? `${v} ${row.unit}` : String(v);
+  };
+  return (
+    <div>
+      <Typography.Title level={2}>{t("compare.title")}</Typography.Title>
+      <Table
+        rowKey="key"
+        pagination={false}
+        bordered
+        dataSource={data.rows}
+        columns={[
+          {
+            title: "",
+            render: (_: any, r: any) =>
+              r.group === "option" ? (
+                <>
+                  {r.label} <Tag>{r.unit}</Tag>
+                </>
+              ) : r.group === "general" ? (
+                t(`compare.${r.key}`)
+              ) : (
+                r.label
+              ),
+          },
+          ...data.products.map((p: any, i: number) => ({
+            title: <Link to={`/product/${p.slug || p.id}`}>{p.name}</Link>,
+            render: (_: any, r: any) => (
+              <span style={r.different ? { fontWeight: 600, color: "#d46b08" } : undefined}>
+                {show(r, r.values[i])}
+              </span>
+            ),
+          })),
+        ]}
+      />
+    </div>
+  );
+}
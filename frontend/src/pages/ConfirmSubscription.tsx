@@ -0,0 +1,34 @@
+import { Button, Result } from "antd";
+import { useEffect, useState } from "react";
+import { Link, useParams } from "react-router-dom";
+import { confirmSubscription } from "../api/subscriptions";
+import { useI18n } from "../store/I18nContext";
+
+export default function ConfirmSubscription() {
+  const { token } = useParams();
+  const { t } = useI18n();
+  const [sub, setSub] = useState<any>();
+  const [missing, setMissing] = useState(false);
+
+  useEffect(() => {
+    if (token)
+      confirmSubscription(token)
+        .then(setSub)
+        .catch(() => setMissing(true));
+  }, [token]);
+
+  if (missing) return <Result status="404" title={t("alerts.not_found")} />;
+  if (!sub) return null;
+  return (
+    <Result
+      status="success"
+      title={t("alerts.confirmed")}
+      subTitle={sub.email}
+      extra={
+        <Link to={`/product/${sub.product_id}`}>
+          <Button>{t("alerts.view_product")}</Button>
+        </Link>
+      }
+    />
+  );
+}
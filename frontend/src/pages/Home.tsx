@@ -7,12 +7,12 @@ import { useI18n } from "../store/I18nContext";
 
 export default function Home() {
   const [depts, setDepts] = useState<any[]>([]);
-  const { t } = useI18n();
+  const { t, lang } = useI18n();
   useEffect(() => {
     fetchDepartments()
       .then(setDepts)
       .catch(() => setDepts([]));
-  }, []);
+  }, [lang]);
   return (
     <div>
       <Typography.Title level={2}>{t("home.title")}</Typography.Title>
@@ -20,7 +20,7 @@ export default function Home() {
       <Row gutter={[16, 16]}>
         {depts.map((d) => (
           <Col key={d.id} xs={24} sm={12} md={8}>
-            <Link to={`/catalog?dept=${d.id}`} style={{ display: "block" }}>
+            <Link to={`/catalog?dept=${d.slug || d.id}`} style={{ display: "block" }}>
               <Card
                 hoverable
                 title={d.name}
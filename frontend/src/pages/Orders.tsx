@@ -37,6 +37,8 @@ type OrderItem = {
   quantity: number;
   unit_price: number | string;
   line_total: number | string;
+  bundle_product_id?: number;
+  bundle_group?: number;
 };
 
 type OrderDetails = OrderRow & {
@@ -194,7 +196,11 @@ export default function Orders() {
 
         setDetailsById((prev) => ({ ...prev, [orderId]: d }));
 
-        const productIds = (d.items ?? []).map((it) => Number(it.product_id));
+        const productIds = (d.items ?? []).flatMap((it) =>
+          it.bundle_product_id
+            ? [Number(it.product_id), Number(it.bundle_product_id)]
+            : [Number(it.product_id)],
+        );
         await ensureProductsCached(productIds);
       } catch {
         message.error("Unable to load order details");
@@ -237,6 +243,13 @@ export default function Orders() {
                 <Link to={`/product/${it.product_id}`}>
                   {p?.name || `#${it.product_id}`}
                 </Link>
+                {it.bundle_product_id && (
+                  <div style={{ color: "#888", fontSize: 12 }}>
+                    {t("bundle")}:{" "}
+                    {productCache[it.bundle_product_id]?.name ||
+                      `#${it.bundle_product_id}`}
+                  </div>
+                )}
               </div>
             </Space>
           );
@@ -246,7 +259,7 @@ export default function Orders() {
       { title: "Unit Price", dataIndex: "unit_price" },
       { title: "Line Total", dataIndex: "line_total" },
     ],
-    [apiOrigin, productCache]
+    [apiOrigin, productCache, t]
   );
 
   const expandedRowRender = useCallback(
@@ -1,35 +1,81 @@
 import {
+  Alert,
   Button,
   Card,
   Col,
+  Descriptions,
   InputNumber,
+  Rate,
   Row,
   Select,
   Typography,
   message,
 } from "antd";
 import { useEffect, useState } from "react";
-import { Link, useParams } from "react-router-dom";
-import { fetchProduct, fetchRecommendations } from "../api/catalog";
+import { Link, useParams, useSearchParams } from "react-router-dom";
+import {
+  fetchProduct,
+  fetchRecommendations,
+  fetchStructuredData,
+} from "../api/catalog";
 import { useCart } from "../store/CartContext";
 import { useI18n } from "../store/I18nContext";
 import { getApiOrigin } from "../api/client";
+import ProductReviews from "../components/ProductReviews";
+import ProductQuestions from "../components/ProductQuestions";
+import SaveToWishlist from "../components/SaveToWishlist";
+import NotifyMe from "../components/NotifyMe";
+import Price from "../components/Price";
+import { useAuth } from "../store/AuthContext";
 
 export default function ProductDetails() {
   const { id } = useParams();
+  const [params] = useSearchParams();
+  const preview = params.get("preview") === "true";
   const [product, setProduct] = useState<any>();
   const [rec, setRec] = useState<any[]>([]);
   const [qty, setQty] = useState<number>(1);
   const [selected, setSelected] = useState<number[]>([]);
+  const [active, setActive] = useState<number>(0);
   const { add } = useCart();
-  const { t } = useI18n();
+  const { isAuthenticated } = useAuth();
+  const { t, lang } = useI18n();
   useEffect(() => {
     if (id) {
-      fetchProduct(Number(id)).then(setProduct);
-      fetchRecommendations(Number(id)).then(setRec);
+      fetchProduct(id, preview).then(setProduct);
+      fetchRecommendations(id)
+        .then(setRec)
+        .catch(() => setRec([]));
+    }
+  }, [id, preview, lang]);
+  useEffect(() => {
+    if (!product) return;
+    const prevTitle = document.title;
+    document.title = product.seo_title || product.name;
+    const meta = document.querySelector('meta[name="description"]');
+    const prevDescription = meta?.getAttribute("content");
+    meta?.setAttribute(
+      "content",
+      product.seo_description || product.short_description || "",
+    );
+    const ld = document.createElement("script");
+    ld.type = "application/ld+json";
+    if (!product.deleted_at && product.status === "published") {
+      fetchStructuredData(product.id).then((data) => {
+        ld.textContent = JSON.stringify(data);
+        document.head.appendChild(ld);
+      });
     }
-  }, [id]);
+    return () => {
+      document.title = prevTitle;
+      if (prevDescription != null) {
+        meta?.setAttribute("content", prevDescription);
+      }
+      ld.remove();
+    };
+  }, [product]);
   if (!product) return null;
+  const orderable = !product.deleted_at && product.status === "published";
   return (
     <div>
       <Row gutter={24}>
@@ -44,29 +90,88 @@ export default function ProductDetails() {
         >
           {(() => {
             const origin = getApiOrigin();
-            const img =
-              product.image_url && !/^https?:/i.test(product.image_url)
-                ? origin + product.image_url
-                : product.image_url;
+            const abs = (u?: string) =>
+              u && !/^https?:/i.test(u) ? origin + u : u;
+            const url = (im: any, size: string, format: string) =>
+              abs(
+                (im.renditions || []).find(
+                  (r: any) => r.size === size && r.format === format,
+                )?.url,
+              );
+            // images of a selected option (e.g. the chosen fabric) come first
+            const images: any[] = [...(product.images || [])].sort(
+              (a, b) =>
+                Number(selected.includes(b.option_id)) -
+                Number(selected.includes(a.option_id)),
+            );
+            const current = images[active] || images[0];
+            if (!current) {
+              return (
+                <img
+                  src={abs(product.image_url)}
+                  alt={product.name}
+                  style={{
+                    width: "100%",
+                    maxHeight: 360,
+                    objectFit: "cover",
+                    borderRadius: 4,
+                  }}
+                />
+              );
+            }
             return (
-              <img
-                src={img}
-                alt={product.name}
-                style={{
-                  width: "100%",
-                  maxHeight: 360,
-                  objectFit: "cover",
-                  borderRadius: 4,
-                }}
-              />
+              <div>
+                <picture>
+                  <source srcSet={url(current, "medium", "webp")} type="image/webp" />
+                  <img
+                    src={url(current, "large", "jpeg")}
+                    alt={current.alt_text || product.name}
+                    style={{
+                      width: "100%",
+                      maxHeight: 360,
+                      objectFit: "cover",
+                      borderRadius: 4,
+                    }}
+                  />
+                </picture>
+                {images.length > 1 && (
+                  <div style={{ display: "flex", gap: 8, marginTop: 8 }}>
+                    {images.map((im, i) => (
+                      <picture key={im.id} onClick={() => setActive(i)}>
+                        <source srcSet={url(im, "thumbnail", "webp")} type="image/webp" />
+                        <img
+                          src={url(im, "thumbnail", "jpeg")}
+                          alt={im.alt_text || product.name}
+                          style={{
+                            width: 64,
+                            height: 64,
+                            objectFit: "cover",
+                            borderRadius: 4,
+                            cursor: "pointer",
+                            outline: im === current ? "2px solid #1677ff" : "none",
+                          }}
+                        />
+                      </picture>
+                    ))}
+                  </div>
+                )}
+              </div>
             );
           })()}
         </Col>
         <Col md={12} xs={24} style={{ paddingLeft: 16 }}>
           <Typography.Title level={3}>{product.name}</Typography.Title>
+          {product.rating_count > 0 && (
+            <div style={{ marginBottom: 8 }}>
+              <Rate disabled allowHalf value={Math.round(product.rating_average * 2) / 2} />{" "}
+              <Typography.Text type="secondary">
+                {product.rating_average.toFixed(1)} ({product.rating_count} {t("reviews.count")})
+              </Typography.Text>
+            </div>
+          )}
           <p>{product.long_description}</p>
           <p>
-            {t("product.base_price")}: {product.base_price.toFixed(2)}
+            {t("product.base_price")}: <Price product={product} showEnd />
           </p>
           <p>
             {t("product.base_prod_time")}: {product.base_production_time_days}
@@ -75,6 +180,45 @@ export default function ProductDetails() {
             Dimensions (cm): {product.default_width}W × {product.default_height}
             H × {product.default_depth}D
           </p>
+          {!!product.bundle?.components?.length && (
+            <div style={{ margin: "12px 0" }}>
+              <Typography.Text strong>{t("product.bundle_includes")}</Typography.Text>
+              <ul style={{ marginTop: 4 }}>
+                {product.bundle.components.map((c: any) => (
+                  <li key={c.id}>
+                    {c.quantity} ×{" "}
+                    <Link to={`/product/${c.product?.slug || c.product_id}`}>
+                      {c.product?.name}
+                    </Link>
+                    {(c.option_ids || []).length > 0 &&
+                      ` (${(c.product?.options || [])
+                        .filter((o: any) => c.option_ids.includes(o.id))
+                        .map((o: any) => o.option_name)
+                        .join(", ")})`}
+                  </li>
+                ))}
+              </ul>
+            </div>
+          )}
+          {!!product.attributes?.length && (
+            <Descriptions
+              title={t("product.specifications")}
+              column={1}
+              size="small"
+              bordered
+              style={{ margin: "12px 0" }}
+            >
+              {product.attributes.map((a: any) => (
+                <Descriptions.Item key={a.attribute_id} label={a.attribute?.name}>
+                  {a.attribute?.type === "boolean"
+                    ? t(a.value === "true" ? "yes" : "no")
+                    : a.attribute?.unit
+                      ? `${a.value} ${a.attribute.unit}`
+                      : a.value}
+                </Descriptions.Item>
+              ))}
+            </Descriptions>
+          )}
           <div style={{ margin: "12px 0" }}>
             <Typography.Text>{t("product.options")}:</Typography.Text>
             {product.options && product.options.length > 0 ? (
@@ -85,7 +229,10 @@ export default function ProductDetails() {
                   "Select options (colours, materials, extras)"
                 }
                 style={{ minWidth: 320 }}
-                onChange={(vals) => setSelected(vals as number[])}
+                onChange={(vals) => {
+                  setSelected(vals as number[]);
+                  setActive(0);
+                }}
                 options={(product.options || []).map((o: any) => ({
                   value: o.id,
                   label: `${o.option_name} (${o.option_type})`,
@@ -97,29 +244,55 @@ export default function ProductDetails() {
               </Typography.Text>
             )}
           </div>
-          <div style={{ display: "flex", gap: 12, alignItems: "center" }}>
-            <Typography.Text>{t("product.quantity")}:</Typography.Text>
-            <InputNumber
-              min={1}
-              value={qty}
-              onChange={(v) => setQty(Number(v))}
+          {!orderable ? (
+            <Alert
+              type="warning"
+              showIcon
+              message={
+                product.status === "draft" || product.status === "scheduled"
+                  ? t("product.preview")
+                  : t("product.unavailable")
+              }
             />
-            <Button
-              type="primary"
-              onClick={() => {
-                add({
-                  product,
-                  quantity: qty,
-                  options: selected.map((id) => ({ id, type: "extra" })),
-                });
-                message.success(t("product.added"));
-              }}
-            >
-              {t("product.add_to_cart")}
-            </Button>
-          </div>
+          ) : (
+            <div style={{ display: "flex", gap: 12, alignItems: "center" }}>
+              <Typography.Text>{t("product.quantity")}:</Typography.Text>
+              <InputNumber
+                min={1}
+                value={qty}
+                onChange={(v) => setQty(Number(v))}
+              />
+              <Button
+                type="primary"
+                onClick={() => {
+                  add({
+                    product,
+                    quantity: qty,
+                    options: selected.map((id) => ({ id, type: "extra" })),
+                  });
+                  message.success(t("product.added"));
+                }}
+              >
+                {t("product.add_to_cart")}
+              </Button>
+              {isAuthenticated && (
+                <SaveToWishlist
+                  productId={product.id}
+                  quantity={qty}
+                  options={selected.map((id) => ({ id, type: "extra" }))}
+                />
+              )}
+              <NotifyMe
+                productId={product.id}
+                inStock={product.quantity > 0}
+                options={selected.map((id) => ({ id, type: "extra" }))}
+              />
+            </div>
+          )}
         </Col>
       </Row>
+      <ProductReviews productId={product.id} canReview={orderable} />
+      <ProductQuestions productId={product.id} />
       {!!rec.length && (
         <div style={{ marginTop: 24 }}>
           <Typography.Title level={4} style={{ textAlign: "center" }}>
@@ -134,7 +307,10 @@ export default function ProductDetails() {
                   : r.image_url;
               return (
                 <Col key={r.id} xs={12} sm={8} md={6} lg={6}>
-                  <Link to={`/product/${r.id}`} style={{ display: "block" }}>
+                  <Link
+                    to={`/product/${r.slug || r.id}`}
+                    style={{ display: "block" }}
+                  >
                     <Card
                       hoverable
                       bodyStyle={{ textAlign: "center" }}
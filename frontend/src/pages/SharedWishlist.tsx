@@ -0,0 +1,48 @@
+import { Button, Card, Result, message } from "antd";
+import { useEffect, useState } from "react";
+import { useParams } from "react-router-dom";
+import { fetchSharedWishlist } from "../api/wishlists";
+import WishlistItems from "../components/WishlistItems";
+import { useCart } from "../store/CartContext";
+import { useI18n } from "../store/I18nContext";
+
+export default function SharedWishlist() {
+  const { token } = useParams();
+  const { t } = useI18n();
+  const { add } = useCart();
+  const [list, setList] = useState<any>();
+  const [missing, setMissing] = useState(false);
+
+  useEffect(() => {
+    if (token)
+      fetchSharedWishlist(token)
+        .then(setList)
+        .catch(() => setMissing(true));
+  }, [token]);
+
+  if (missing) return <Result status="404" title={t("wishlists.not_found")} />;
+  if (!list) return null;
+  return (
+    <Card title={list.name}>
+      <WishlistItems
+        items={list.items || []}
+        actions={(it, product) => (
+          <Button
+            size="small"
+            disabled={!it.available || !product}
+            onClick={async () => {
+              await add({
+                product,
+                quantity: it.quantity,
+                options: JSON.parse(it.selected_options_json || "[]") || [],
+              });
+              message.success(t("product.added"));
+            }}
+          >
+            {t("product.add_to_cart")}
+          </Button>
+        )}
+      />
+    </Card>
+  );
+}
@@ -0,0 +1,118 @@
+import { Button, Card, Empty, Input, Popconfirm, Space, Switch, Typography, message } from "antd";
+import { useEffect, useState } from "react";
+import {
+  createWishlist,
+  deleteWishlist,
+  fetchWishlists,
+  moveToCart,
+  removeWishlistItem,
+  renameWishlist,
+  shareWishlist,
+} from "../api/wishlists";
+import WishlistItems from "../components/WishlistItems";
+import { useCart } from "../store/CartContext";
+import { useI18n } from "../store/I18nContext";
+
+const shareURL = (token: string) => `${window.location.origin}/wishlists/shared/${token}`;
+
+export default function Wishlists() {
+  const { t } = useI18n();
+  const { reload } = useCart();
+  const [lists, setLists] = useState<any[]>([]);
+  const [name, setName] = useState("");
+
+  const load = () =>
+    fetchWishlists()
+      .then(setLists)
+      .catch(() => message.error("Failed to load wishlists"));
+  useEffect(() => {
+    load();
+  }, []);
+
+  const run = async (fn: () => Promise<any>) => {
+    try {
+      await fn();
+      load();
+    } catch (e: any) {
+      message.error(e?.response?.data?.message || "Failed to update wishlist");
+    }
+  };
+
+  const toCart = (w: any, ids: number[] = []) =>
+    run(async () => {
+      await moveToCart(w.id, ids);
+      await reload();
+      message.success(t("wishlists.moved"));
+    });
+
+  return (
+    <div>
+      <Typography.Title level={3}>{t("wishlists")}</Typography.Title>
+      <Space style={{ marginBottom: 16 }}>
+        <Input
+          maxLength={120}
+          placeholder={t("wishlists.name")}
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+        />
+        <Button
+          type="primary"
+          disabled={!name.trim()}
+          onClick={() =>
+            run(async () => {
+              await createWishlist(name.trim());
+              setName("");
+            })
+          }
+        >
+          {t("wishlists.new")}
+        </Button>
+      </Space>
+      {!lists.length && <Empty description={t("wishlists.none")} />}
+      {lists.map((w) => (
+        <Card
+          key={w.id}
+          style={{ marginBottom: 16 }}
+          title={
+            <Typography.Text editable={{ onChange: (v) => v.trim() && run(() => renameWishlist(w.id, v.trim())) }}>
+              {w.name}
+            </Typography.Text>
+          }
+          extra={
+            <Space>
+              <Button type="primary" disabled={!w.items?.some((it: any) => it.available)} onClick={() => toCart(w)}>
+                {t("wishlists.move_all")}
+              </Button>
+              <Popconfirm title={t("wishlists.delete")} onConfirm={() => run(() => deleteWishlist(w.id))}>
+                <Button danger>{t("wishlists.delete")}</Button>
+              </Popconfirm>
+            </Space>
+          }
+        >
+          <Space style={{ marginBottom: 12 }}>
+            <Switch checked={!!w.share_token} onChange={(on) => run(() => shareWishlist(w.id, on))} />
+            {t("wishlists.share")}
+            {w.share_token && (
+              <Typography.Text copyable={{ text: shareURL(w.share_token) }} type="secondary">
+                {shareURL(w.share_token)}
+              </Typography.Text>
+            )}
+          </Space>
+          <WishlistItems
+            items={w.items || []}
+            actions={(it) => (
+              <Space>
+                <Button size="small" disabled={!it.available} onClick={() => toCart(w, [it.id])}>
+                  {t("wishlists.move")}
+                </Button>
+                <Button size="small" danger onClick={() => run(() => removeWishlistItem(w.id, it.id))}>
+                  {t("wishlists.remove")}
+                </Button>
+              </Space>
+            )}
+          />
+        </Card>
+      ))}
+    </div>
+  );
+}
@@ -31,6 +31,7 @@ type CartCtxType = {
   decrement: (productId: number) => Promise<void>;
   clear: () => Promise<void>;
   clearLocal: () => Promise<void>;
+  reload: () => Promise<void>;
 };
 
 const CartCtx = createContext<CartCtxType | undefined>(undefined);
@@ -226,6 +227,19 @@ export const CartProvider: React.FC<{ children: React.ReactNode }> = ({
       clearLocal: async () => {
         setItems([]);
       },
+      reload: async () => {
+        if (!isAuthenticated) return;
+        const s = await apiGet();
+        const mapped: CartItem[] = await Promise.all(
+          (s.items || []).map(async (it: any) => ({
+            id: it.id,
+            product: await fetchProduct(it.product_id),
+            quantity: it.quantity,
+            options: JSON.parse(it.selected_options_json || "[]"),
+          }))
+        );
+        setItems(mapped);
+      },
     }),
     [items, isAuthenticated]
   );
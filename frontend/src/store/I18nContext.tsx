@@ -1,4 +1,5 @@
 import React, { createContext, useContext, useMemo, useState } from "react";
+import { api } from "../api/client";
 
 type Lang = "en" | "bg";
 
@@ -14,6 +15,7 @@ const dict: Record<Lang, Record<string, string>> = {
     "nav.catalog": "Catalog",
     "nav.cart": "Cart",
     "nav.orders": "My Orders",
+    "nav.wishlists": "Wishlists",
     "nav.admin": "Admin",
     "nav.login": "Login",
     "nav.register": "Register",
@@ -27,6 +29,15 @@ const dict: Record<Lang, Record<string, string>> = {
     "catalog.select.department": "Select department",
     "catalog.select.category": "Select category",
     "catalog.view": "View",
+    "compare": "Compare",
+    "compare.title": "Compare products",
+    "compare.base_price": "Price",
+    "compare.default_width": "Width",
+    "compare.default_height": "Height",
+    "compare.default_depth": "Depth",
+    "compare.base_material": "Material",
+    "compare.base_production_time_days": "Production time",
+    "compare.in_stock": "In stock",
 
     "cart.title": "Cart",
 
@@ -91,13 +102,21 @@ const dict: Record<Lang, Record<string, string>> = {
     "orders.col.eta_days": "ETA (days)",
 
     "product.base_price": "Base price",
+    "product.sale": "Sale",
+    "product.sale_ends": "Sale ends",
     "product.base_prod_time": "Base production time (days)",
     "product.options": "Options",
     "product.select_options": "Select options",
     "product.quantity": "Quantity",
     "product.add_to_cart": "Add to cart",
+    "product.unavailable": "This product is no longer available.",
+    "product.preview": "Preview: this product is not visible to customers yet.",
     "product.added": "Added to cart",
     "product.recommended": "Recommended products",
+    "product.specifications": "Specifications",
+    "product.bundle_includes": "This set includes",
+    "yes": "Yes",
+    "no": "No",
 
     "admin.departments": "Departments",
     "admin.create_department": "Create Department",
@@ -136,6 +155,150 @@ const dict: Record<Lang, Record<string, string>> = {
     "create_category": "Create Category",
     "category_name": "Category Name",
     "category_description": "Category Description",
+    "slug": "URL slug (generated from the name if empty)",
+    "seo_title": "SEO title",
+    "seo_description": "SEO description",
+    "archive": "Archive",
+    "archived": "Archived",
+    "active": "Active",
+    "restore": "Restore",
+    "status": "Status",
+    "status.draft": "Draft",
+    "status.scheduled": "Scheduled",
+    "status.published": "Published",
+    "status.discontinued": "Discontinued",
+    "publish_at": "Publish at",
+    "unpublish_at": "Unpublish at",
+    "preview": "Preview",
+    "attributes": "Attributes",
+    "attribute.code": "Code",
+    "attribute.name": "Name",
+    "attribute.type": "Type",
+    "attribute.unit": "Unit",
+    "attribute.choices": "Choices",
+    "attribute.required": "Required",
+    "attribute.filterable": "Filterable",
+    "attribute.position": "Position",
+    "attribute.add": "Add attribute",
+    "bundle": "Bundle",
+    "bundle.pricing": "Pricing",
+    "bundle.fixed": "Fixed price (product price)",
+    "bundle.discount": "Discount off components",
+    "bundle.discount_percent": "Discount (%)",
+    "bundle.components": "Components",
+    "bundle.add_component": "Add component",
+    "bundle.remove": "Make single product",
+    "prices": "Prices",
+    "prices.schedules": "Scheduled",
+    "prices.history": "History",
+    "prices.kind": "Kind",
+    "prices.kind.change": "Price change",
+    "prices.kind.sale": "Sale",
+    "prices.price": "Price",
+    "prices.starts_at": "Starts",
+    "prices.ends_at": "Ends",
+    "prices.schedule": "Schedule",
+    "prices.cancel": "Cancel",
+    "prices.status.pending": "Pending",
+    "prices.status.active": "Running",
+    "prices.status.done": "Done",
+    "prices.changed_at": "Changed",
+    "prices.option": "Option",
+    "prices.old_price": "Old price",
+    "prices.new_price": "New price",
+    "prices.reason": "Reason",
+    "prices.reason.manual": "Edited",
+    "prices.reason.import": "Import",
+    "prices.reason.scheduled": "Scheduled change",
+    "prices.reason.sale_start": "Sale started",
+    "prices.reason.sale_end": "Sale ended",
+    "reviews": "Reviews",
+    "reviews.none": "No reviews yet",
+    "reviews.count": "reviews",
+    "reviews.verified": "Verified purchase",
+    "reviews.verified_only": "Verified purchases only",
+    "reviews.with_photos": "With photos",
+    "reviews.write": "Write a review",
+    "reviews.edit": "Update your review",
+    "reviews.login": "Log in to write a review",
+    "reviews.rating": "Rating",
+    "reviews.review_title": "Title",
+    "reviews.body": "Your review",
+    "reviews.photos": "Photos",
+    "reviews.photos_kept": "New photos replace the attached ones",
+    "reviews.edit_unpublishes": "Updating your review hides it until it is approved again",
+    "reviews.submit": "Submit review",
+    "reviews.submitted": "Thank you! Your review will appear once approved.",
+    "reviews.status.pending": "Awaiting approval",
+    "reviews.status.approved": "Published",
+    "reviews.status.rejected": "Rejected",
+    "reviews.delete": "Delete review",
+    "reviews.approve": "Approve",
+    "reviews.reject": "Reject",
+    "reviews.note": "Moderation note",
+    "reviews.product": "Product",
+    "reviews.author": "Author",
+    "reviews.anonymous": "Customer",
+    "questions": "Questions & answers",
+    "questions.none": "No questions yet",
+    "questions.filter.all": "All",
+    "questions.filter.answered": "Answered",
+    "questions.filter.unanswered": "Unanswered",
+    "questions.q": "Q:",
+    "questions.a": "A:",
+    "questions.shop": "Furniture Shop",
+    "questions.official": "Official answer",
+    "questions.ask": "Ask a question",
+    "questions.placeholder": "What would you like to know about this product?",
+    "questions.send": "Send question",
+    "questions.submitted": "Thank you! Your question will appear once approved.",
+    "questions.login": "Log in to ask a question",
+    "questions.answer": "Answer",
+    "questions.send_answer": "Send answer",
+    "questions.answer_submitted": "Thank you! Your answer will appear once approved.",
+    "questions.buyers_only": "Only customers who received this product can answer.",
+    "questions.cancel": "Cancel",
+    "questions.answers": "Answers",
+    "questions.question": "Question",
+    "questions.delete": "Delete",
+    "wishlists": "Wishlists",
+    "wishlists.none": "You have no wishlists yet",
+    "wishlists.empty": "Nothing saved here yet",
+    "wishlists.new": "New wishlist",
+    "wishlists.name": "Wishlist name",
+    "wishlists.save": "Save to wishlist",
+    "wishlists.saved": "Saved to your wishlist",
+    "wishlists.delete": "Delete wishlist",
+    "wishlists.share": "Share with a link",
+    "wishlists.move": "Move to cart",
+    "wishlists.move_all": "Move all to cart",
+    "wishlists.moved": "Moved to your cart",
+    "wishlists.remove": "Remove",
+    "wishlists.price": "Current price",
+    "wishlists.made_to_order": "Made to order",
+    "wishlists.not_found": "This wishlist is no longer shared",
+    "alerts.notify": "Notify me",
+    "alerts.back_in_stock": "When back in stock",
+    "alerts.price_drop": "When the price drops",
+    "alerts.email": "Your email address",
+    "alerts.subscribe": "Subscribe",
+    "alerts.subscribed": "We will email you",
+    "alerts.check_email": "Check your email to confirm the alert",
+    "alerts.confirmed": "Your alert is confirmed",
+    "alerts.unsubscribed": "You will no longer get these alerts",
+    "alerts.not_found": "This alert has already ended",
+    "alerts.view_product": "View the product",
+    "sort": "Sort by",
+    "sort.newest": "Newest",
+    "sort.oldest": "Oldest",
+    "sort.rating_desc": "Highest rated",
+    "sort.rating_asc": "Lowest rated",
+    "sort.price_asc": "Price: low to high",
+    "sort.price_desc": "Price: high to low",
+    "sort.popularity": "Most popular",
+    "sort.name": "Name",
+    "sort.rating": "Top rated",
+    "catalog.rating_min": "Minimum rating",
     "department": "Department",
     "product_production_days": "Estimated delivery (days)",
     "upload_image": "Upload Image",
@@ -150,6 +313,7 @@ const dict: Record<Lang, Record<string, string>> = {
     "nav.catalog": "Каталог",
     "nav.cart": "Количка",
     "nav.orders": "Моите поръчки",
+    "nav.wishlists": "Списъци с желания",
     "nav.admin": "Админ",
     "nav.login": "Вход",
     "nav.register": "Регистрация",
@@ -161,6 +325,15 @@ const dict: Record<Lang, Record<string, string>> = {
     "catalog.select.department": "Изберете отдел",
     "catalog.select.category": "Изберете категория",
     "catalog.view": "Преглед",
+    "compare": "Сравни",
+    "compare.title": "Сравнение на продукти",
+    "compare.base_price": "Цена",
+    "compare.default_width": "Ширина",
+    "compare.default_height": "Височина",
+    "compare.default_depth": "Дълбочина",
+    "compare.base_material": "Материал",
+    "compare.base_production_time_days": "Време за изработка",
+    "compare.in_stock": "В наличност",
     "cart.title": "Количка",
     "checkout.title": "Поръчка",
     "checkout.form.title": "Създаване на поръчка и избор на плащане",
@@ -209,13 +382,133 @@ const dict: Record<Lang, Record<string, string>> = {
     "orders.col.total": "Общо",
     "orders.col.eta_days": "Срок (дни)",
     "product.base_price": "Базова цена",
+    "product.sale": "Намаление",
+    "product.sale_ends": "Намалението изтича на",
     "product.base_prod_time": "Базово време за изработка (дни)",
     "product.options": "Опции",
     "product.select_options": "Изберете опции",
     "product.quantity": "Количество",
     "product.add_to_cart": "Добави в количката",
+    "product.unavailable": "Този продукт вече не се предлага.",
+    "product.preview": "Преглед: този продукт все още не е видим за клиентите.",
     "product.added": "Добавено в количката",
     "product.recommended": "Подобни продукти",
+    "product.specifications": "Характеристики",
+    "product.bundle_includes": "Комплектът включва",
+    "bundle": "Комплект",
+    "prices": "Цени",
+    "prices.schedules": "Планирани",
+    "prices.history": "История",
+    "prices.kind": "Вид",
+    "prices.kind.change": "Промяна на цената",
+    "prices.kind.sale": "Намаление",
+    "prices.price": "Цена",
+    "prices.starts_at": "Начало",
+    "prices.ends_at": "Край",
+    "prices.schedule": "Планирай",
+    "prices.cancel": "Отмени",
+    "prices.status.pending": "Предстои",
+    "prices.status.active": "Текущо",
+    "prices.status.done": "Приключено",
+    "prices.changed_at": "Променена",
+    "prices.option": "Опция",
+    "prices.old_price": "Стара цена",
+    "prices.new_price": "Нова цена",
+    "prices.reason": "Причина",
+    "prices.reason.manual": "Редакция",
+    "prices.reason.import": "Импорт",
+    "prices.reason.scheduled": "Планирана промяна",
+    "prices.reason.sale_start": "Начало на намаление",
+    "prices.reason.sale_end": "Край на намаление",
+    "reviews": "Отзиви",
+    "reviews.none": "Все още няма отзиви",
+    "reviews.count": "отзива",
+    "reviews.verified": "Потвърдена покупка",
+    "reviews.verified_only": "Само потвърдени покупки",
+    "reviews.with_photos": "Със снимки",
+    "reviews.write": "Напишете отзив",
+    "reviews.edit": "Редактирайте отзива си",
+    "reviews.login": "Влезте, за да напишете отзив",
+    "reviews.rating": "Оценка",
+    "reviews.review_title": "Заглавие",
+    "reviews.body": "Вашият отзив",
+    "reviews.photos": "Снимки",
+    "reviews.photos_kept": "Новите снимки заменят прикачените",
+    "reviews.edit_unpublishes": "Редактираният отзив се скрива до повторното му одобрение",
+    "reviews.submit": "Изпрати отзив",
+    "reviews.submitted": "Благодарим! Отзивът ще бъде публикуван след одобрение.",
+    "reviews.status.pending": "Очаква одобрение",
+    "reviews.status.approved": "Публикуван",
+    "reviews.status.rejected": "Отхвърлен",
+    "reviews.delete": "Изтрий отзива",
+    "reviews.approve": "Одобри",
+    "reviews.reject": "Отхвърли",
+    "reviews.note": "Бележка",
+    "reviews.product": "Продукт",
+    "reviews.author": "Автор",
+    "reviews.anonymous": "Клиент",
+    "questions": "Въпроси и отговори",
+    "questions.none": "Все още няма въпроси",
+    "questions.filter.all": "Всички",
+    "questions.filter.answered": "С отговор",
+    "questions.filter.unanswered": "Без отговор",
+    "questions.q": "В:",
+    "questions.a": "О:",
+    "questions.shop": "Furniture Shop",
+    "questions.official": "Официален отговор",
+    "questions.ask": "Задайте въпрос",
+    "questions.placeholder": "Какво бихте искали да знаете за този продукт?",
+    "questions.send": "Изпрати въпрос",
+    "questions.submitted": "Благодарим! Въпросът ще бъде публикуван след одобрение.",
+    "questions.login": "Влезте, за да зададете въпрос",
+    "questions.answer": "Отговори",
+    "questions.send_answer": "Изпрати отговор",
+    "questions.answer_submitted": "Благодарим! Отговорът ще бъде публикуван след одобрение.",
+    "questions.buyers_only": "Само клиенти, получили този продукт, могат да отговарят.",
+    "questions.cancel": "Отказ",
+    "questions.answers": "Отговори",
+    "questions.question": "Въпрос",
+    "questions.delete": "Изтрий",
+    "wishlists": "Списъци с желания",
+    "wishlists.none": "Все още нямате списъци с желания",
+    "wishlists.empty": "Тук още няма нищо",
+    "wishlists.new": "Нов списък",
+    "wishlists.name": "Име на списъка",
+    "wishlists.save": "Запази в списък",
+    "wishlists.saved": "Запазено в списъка ви",
+    "wishlists.delete": "Изтрий списъка",
+    "wishlists.share": "Споделяне с връзка",
+    "wishlists.move": "Премести в количката",
+    "wishlists.move_all": "Премести всичко в количката",
+    "wishlists.moved": "Преместено в количката",
+    "wishlists.remove": "Премахни",
+    "wishlists.price": "Текуща цена",
+    "wishlists.made_to_order": "По поръчка",
+    "wishlists.not_found": "Този списък вече не е споделен",
+    "alerts.notify": "Уведоми ме",
+    "alerts.back_in_stock": "При наличност",
+    "alerts.price_drop": "При по-ниска цена",
+    "alerts.email": "Вашият имейл адрес",
+    "alerts.subscribe": "Абонирай се",
+    "alerts.subscribed": "Ще ви изпратим имейл",
+    "alerts.check_email": "Проверете имейла си, за да потвърдите известието",
+    "alerts.confirmed": "Известието е потвърдено",
+    "alerts.unsubscribed": "Вече няма да получавате тези известия",
+    "alerts.not_found": "Това известие вече е прекратено",
+    "alerts.view_product": "Вижте продукта",
+    "sort": "Подреди по",
+    "sort.newest": "Най-нови",
+    "sort.oldest": "Най-стари",
+    "sort.rating_desc": "Най-висока оценка",
+    "sort.rating_asc": "Най-ниска оценка",
+    "sort.price_asc": "Цена: възходящо",
+    "sort.price_desc": "Цена: низходящо",
+    "sort.popularity": "Най-популярни",
+    "sort.name": "Име",
+    "sort.rating": "Най-добре оценени",
+    "catalog.rating_min": "Минимална оценка",
+    "yes": "Да",
+    "no": "Не",
     "admin.departments": "Отдели",
     "admin.create_department": "Създай отдел",
     "admin.department_name": "Име",
@@ -256,10 +549,14 @@ const I18nContext = createContext<I18nCtx | undefined>(undefined);
 export const I18nProvider: React.FC<{ children: React.ReactNode }> = ({
   children,
 }) => {
-  const [lang, setLangState] = useState<Lang>(
-    () => (localStorage.getItem("lang") as Lang) || "en"
-  );
+  const [lang, setLangState] = useState<Lang>(() => {
+    const l = (localStorage.getItem("lang") as Lang) || "en";
+    // catalog text comes back in the interface language
+    api.defaults.headers.common["Accept-Language"] = l;
+    return l;
+  });
   const setLang = (l: Lang) => {
+    api.defaults.headers.common["Accept-Language"] = l;
     setLangState(l);
     localStorage.setItem("lang", l);
   };
@@ -1,6 +1,6 @@
 module furniture-shop
 
-go 1.21
+go 1.22.2
 
 require (
 	github.com/go-playground/validator/v10 v10.22.0
@@ -13,7 +13,11 @@ require (
 	gorm.io/gorm v1.25.10
 )
 
-require github.com/golang-jwt/jwt/v4 v4.5.0
+require (
+	github.com/HugoSmits86/nativewebp v1.1.0
+	github.com/golang-jwt/jwt/v4 v4.5.0
+	golang.org/x/image v0.24.0
+)
 
 require (
 	github.com/andybalholm/brotli v1.0.5 // indirect
@@ -37,7 +41,7 @@ require (
 	github.com/valyala/fasthttp v1.51.0 // indirect
 	github.com/valyala/tcplisten v1.0.0 // indirect
 	golang.org/x/net v0.23.0 // indirect
-	golang.org/x/sync v0.1.0 // indirect
+	golang.org/x/sync v0.11.0 // indirect
 	golang.org/x/sys v0.18.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
+	golang.org/x/text v0.22.0 // indirect
 )
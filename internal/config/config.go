@@ -1,8 +1,19 @@
 package config
 
 type Config struct {
-	DB          DBConfig `json:"DB"`
-	CORSOrigins []string `json:"CORS_ORIGINS"`
+	DB          DBConfig        `json:"DB"`
+	CORSOrigins []string        `json:"CORS_ORIGINS"`
+	FrontendURL string          `json:"FRONTEND_URL"`
+	Invoice     InvoiceConfig   `json:"INVOICE"`
+	Mail        MailConfig      `json:"MAIL"`
+	Outbox      OutboxConfig    `json:"OUTBOX"`
+	Notify      NotifyConfig    `json:"NOTIFICATIONS"`
+	Search      SearchConfig    `json:"SEARCH"`
+	Recommend   RecommendConfig `json:"RECOMMENDATIONS"`
+	Events      EventsConfig    `json:"EVENTS"`
+	Images      ImagesConfig    `json:"IMAGES"`
+	Blobs       BlobsConfig     `json:"BLOBS"`
+	Locales     LocalesConfig   `json:"LOCALES"`
 }
 
 type DBConfig struct {
@@ -11,3 +22,135 @@ type DBConfig struct {
 	Port uint   `json:"PORT"`
 	SSL  string `json:"SSL"`
 }
+
+// InvoiceConfig holds seller details printed on invoices. Prices in the shop
+// are tax-inclusive; TaxRate is the percentage contained in them.
+type InvoiceConfig struct {
+	SellerName    string  `json:"SELLER_NAME"`
+	SellerAddress string  `json:"SELLER_ADDRESS"`
+	SellerVATID   string  `json:"SELLER_VAT_ID"`
+	TaxRate       float64 `json:"TAX_RATE"`
+	Currency      string  `json:"CURRENCY"`
+}
+
+// MailConfig selects the email transport: "smtp" (default) uses the
+// EMAIL_SENDER_* credentials, "maildir" writes messages to a local Maildir
+// for development.
+type MailConfig struct {
+	Driver  string `json:"DRIVER"`
+	Maildir string `json:"MAILDIR"`
+}
+
+// OutboxConfig tunes the background notification dispatcher.
+type OutboxConfig struct {
+	PollIntervalSeconds int `json:"POLL_INTERVAL_SECONDS"`
+	BatchSize           int `json:"BATCH_SIZE"`
+	MaxAttempts         int `json:"MAX_ATTEMPTS"`
+	BaseBackoffSeconds  int `json:"BASE_BACKOFF_SECONDS"`
+	MaxBackoffSeconds   int `json:"MAX_BACKOFF_SECONDS"`
+}
+
+// NotifyConfig routes notification events (template names) to channels and
+// configures the SMS and Web Push transports. A user receives an event on the
+// routed channels they opted into; events without a route go to email only.
+type NotifyConfig struct {
+	Routes map[string][]string `json:"ROUTES"`
+	SMS    SMSConfig           `json:"SMS"`
+	Push   PushConfig          `json:"PUSH"`
+}
+
+// SMSConfig selects the SMS transport: "log" (default) only logs messages,
+// "http" posts them to URL authenticated with the SMS_API_TOKEN env variable.
+type SMSConfig struct {
+	Driver string `json:"DRIVER"`
+	URL    string `json:"URL"`
+	Sender string `json:"SENDER"`
+}
+
+// PushConfig selects the Web Push transport: "log" (default) only logs
+// messages, "webpush" delivers them with VAPID authentication. The private
+// key is read from the VAPID_PRIVATE_KEY env variable.
+type PushConfig struct {
+	Driver         string `json:"DRIVER"`
+	VAPIDPublicKey string `json:"VAPID_PUBLIC_KEY"`
+	Subject        string `json:"SUBJECT"`
+}
+
+// SearchConfig selects the product search backend: "postgres" (default) for
+// the database full-text index, "memory" for the embedded inverted index.
+type SearchConfig struct {
+	Backend string `json:"BACKEND"`
+}
+
+// RecommendConfig tunes the background job computing "bought together" and
+// "viewed together" associations: orders and views older than LookbackDays
+// are ignored, pairs need MinSupport shared orders or sessions, and at most
+// PerProduct associations are kept per product. Views only reach back as far
+// as EVENTS.RETENTION_DAYS.
+type RecommendConfig struct {
+	RefreshIntervalMinutes int `json:"REFRESH_INTERVAL_MINUTES"`
+	LookbackDays           int `json:"LOOKBACK_DAYS"`
+	MinSupport             int `json:"MIN_SUPPORT"`
+	PerProduct             int `json:"PER_PRODUCT"`
+}
+
+// EventsConfig tunes behavior event tracking. Events are queued in memory
+// (up to QueueSize, further events are dropped) and written in batches of
+// BatchSize at least every FlushIntervalSeconds. Browsing events repeated by
+// a session within DedupWindowMinutes count once. The rollup job refreshes
+// the daily aggregates and product popularity (over PopularityDays) every
+// RollupIntervalMinutes; raw events are kept for RetentionDays.
+type EventsConfig struct {
+	QueueSize             int `json:"QUEUE_SIZE"`
+	BatchSize             int `json:"BATCH_SIZE"`
+	FlushIntervalSeconds  int `json:"FLUSH_INTERVAL_SECONDS"`
+	DedupWindowMinutes    int `json:"DEDUP_WINDOW_MINUTES"`
+	RollupIntervalMinutes int `json:"ROLLUP_INTERVAL_MINUTES"`
+	PopularityDays        int `json:"POPULARITY_DAYS"`
+	RetentionDays         int `json:"RETENTION_DAYS"`
+}
+
+// ImagesConfig tunes image uploads: uploads over MaxUploadMB megabytes or
+// MaxPixels pixels are rejected. Renditions are encoded as JPEG with
+// JPEGQuality. The cleanup job runs every CleanupIntervalMinutes and removes
+// files no longer referenced by the catalog once they are OrphanGraceHours
+// old.
+type ImagesConfig struct {
+	MaxUploadMB            int `json:"MAX_UPLOAD_MB"`
+	MaxPixels              int `json:"MAX_PIXELS"`
+	JPEGQuality            int `json:"JPEG_QUALITY"`
+	CleanupIntervalMinutes int `json:"CLEANUP_INTERVAL_MINUTES"`
+	OrphanGraceHours       int `json:"ORPHAN_GRACE_HOURS"`
+}
+
+// BlobsConfig selects where uploaded files are stored: "local" (default)
+// keeps them in LocalDir, served at /uploads; "s3" uses an S3-compatible
+// bucket authenticated with the S3_ACCESS_KEY and S3_SECRET_KEY env
+// variables. Signed URLs are valid for SignedURLTTLMinutes, at most 7 days
+// with S3.
+type BlobsConfig struct {
+	Driver              string   `json:"DRIVER"`
+	LocalDir            string   `json:"LOCAL_DIR"`
+	S3                  S3Config `json:"S3"`
+	SignedURLTTLMinutes int      `json:"SIGNED_URL_TTL_MINUTES"`
+}
+
+// S3Config points at an S3-compatible service. Endpoint is the service root,
+// e.g. https://s3.eu-central-1.amazonaws.com or http://localhost:9000 for
+// MinIO, which also needs PathStyle. Public objects are linked through
+// PublicURL when set, e.g. a CDN, and through the endpoint otherwise.
+type S3Config struct {
+	Endpoint  string `json:"ENDPOINT"`
+	Region    string `json:"REGION"`
+	Bucket    string `json:"BUCKET"`
+	PublicURL string `json:"PUBLIC_URL"`
+	PathStyle bool   `json:"PATH_STYLE"`
+}
+
+// LocalesConfig lists the languages the catalog is offered in. Catalog
+// records are written in Default; translations into the other Supported
+// locales are stored separately and fall back to the default text.
+type LocalesConfig struct {
+	Default   string   `json:"DEFAULT"`
+	Supported []string `json:"SUPPORTED"`
+}
@@ -11,4 +11,9 @@ type EnvConfig struct {
 	EmailSenderUser     string
 	EmailSenderPass     string
 	EmailSenderFrom     string
+	SMSAPIToken         string
+	VAPIDPrivateKey     string
+	S3AccessKey         string
+	S3SecretKey         string
+	BlobSigningKey      string
 }
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -48,6 +49,112 @@ func LoadConfig() error {
 		return fmt.Errorf("failed to decode config file: %w", err)
 	}
 
+	if cfg.FrontendURL == "" {
+		cfg.FrontendURL = "http://localhost:5173"
+	}
+	cfg.FrontendURL = strings.TrimRight(cfg.FrontendURL, "/")
+	if cfg.Mail.Driver == "" {
+		cfg.Mail.Driver = "smtp"
+	}
+	if cfg.Mail.Maildir == "" {
+		cfg.Mail.Maildir = "maildir"
+	}
+	if cfg.Outbox.PollIntervalSeconds <= 0 {
+		cfg.Outbox.PollIntervalSeconds = 5
+	}
+	if cfg.Outbox.BatchSize <= 0 {
+		cfg.Outbox.BatchSize = 20
+	}
+	if cfg.Outbox.MaxAttempts <= 0 {
+		cfg.Outbox.MaxAttempts = 8
+	}
+	if cfg.Outbox.BaseBackoffSeconds <= 0 {
+		cfg.Outbox.BaseBackoffSeconds = 30
+	}
+	if cfg.Outbox.MaxBackoffSeconds <= 0 {
+		cfg.Outbox.MaxBackoffSeconds = 6 * 60 * 60
+	}
+	if cfg.Notify.SMS.Driver == "" {
+		cfg.Notify.SMS.Driver = "log"
+	}
+	if cfg.Notify.Push.Driver == "" {
+		cfg.Notify.Push.Driver = "log"
+	}
+	if cfg.Search.Backend == "" {
+		cfg.Search.Backend = "postgres"
+	}
+	if cfg.Recommend.RefreshIntervalMinutes <= 0 {
+		cfg.Recommend.RefreshIntervalMinutes = 60
+	}
+	if cfg.Recommend.LookbackDays <= 0 {
+		cfg.Recommend.LookbackDays = 365
+	}
+	if cfg.Recommend.MinSupport <= 0 {
+		cfg.Recommend.MinSupport = 2
+	}
+	if cfg.Recommend.PerProduct <= 0 {
+		cfg.Recommend.PerProduct = 20
+	}
+	if cfg.Events.QueueSize <= 0 {
+		cfg.Events.QueueSize = 10000
+	}
+	if cfg.Events.BatchSize <= 0 {
+		cfg.Events.BatchSize = 500
+	}
+	if cfg.Events.FlushIntervalSeconds <= 0 {
+		cfg.Events.FlushIntervalSeconds = 2
+	}
+	if cfg.Events.DedupWindowMinutes <= 0 {
+		cfg.Events.DedupWindowMinutes = 30
+	}
+	if cfg.Events.RollupIntervalMinutes <= 0 {
+		cfg.Events.RollupIntervalMinutes = 10
+	}
+	if cfg.Events.PopularityDays <= 0 {
+		cfg.Events.PopularityDays = 30
+	}
+	if cfg.Events.RetentionDays <= 0 {
+		cfg.Events.RetentionDays = 90
+	}
+	if cfg.Images.MaxUploadMB <= 0 {
+		cfg.Images.MaxUploadMB = 10
+	}
+	if cfg.Images.MaxPixels <= 0 {
+		cfg.Images.MaxPixels = 40000000
+	}
+	if cfg.Images.JPEGQuality <= 0 || cfg.Images.JPEGQuality > 100 {
+		cfg.Images.JPEGQuality = 85
+	}
+	if cfg.Images.CleanupIntervalMinutes <= 0 {
+		cfg.Images.CleanupIntervalMinutes = 360
+	}
+	if cfg.Images.OrphanGraceHours <= 0 {
+		cfg.Images.OrphanGraceHours = 24
+	}
+	if cfg.Blobs.Driver == "" {
+		cfg.Blobs.Driver = "local"
+	}
+	if cfg.Blobs.LocalDir == "" {
+		cfg.Blobs.LocalDir = "uploads"
+	}
+	if cfg.Blobs.S3.Region == "" {
+		cfg.Blobs.S3.Region = "us-east-1"
+	}
+	if cfg.Blobs.SignedURLTTLMinutes <= 0 {
+		cfg.Blobs.SignedURLTTLMinutes = 15
+	}
+	if cfg.Locales.Default == "" {
+		cfg.Locales.Default = "en"
+	}
+	cfg.Locales.Default = strings.ToLower(cfg.Locales.Default)
+	supported := []string{cfg.Locales.Default}
+	for _, l := range cfg.Locales.Supported {
+		if l = strings.ToLower(strings.TrimSpace(l)); l != "" && !slices.Contains(supported, l) {
+			supported = append(supported, l)
+		}
+	}
+	cfg.Locales.Supported = supported
+
 	Configurations = cfg
 	return nil
 }
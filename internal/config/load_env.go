@@ -67,6 +67,11 @@ func LoadEnvFile() error {
 		EmailSenderUser:     emailSenderUser,
 		EmailSenderPass:     emailSenderPass,
 		EmailSenderFrom:     emailSenderFrom,
+		SMSAPIToken:         os.Getenv("SMS_API_TOKEN"),
+		VAPIDPrivateKey:     os.Getenv("VAPID_PRIVATE_KEY"),
+		S3AccessKey:         os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:         os.Getenv("S3_SECRET_KEY"),
+		BlobSigningKey:      os.Getenv("BLOB_SIGNING_KEY"),
 	}
 
 	return nil
@@ -8,7 +8,9 @@ import (
 	"gorm.io/gorm"
 
 	"furniture-shop/internal/config"
+	ea "furniture-shop/internal/entities/analytics"
 	ec "furniture-shop/internal/entities/catalog"
+	en "furniture-shop/internal/entities/notification"
 	eo "furniture-shop/internal/entities/orders"
 	eu "furniture-shop/internal/entities/user"
 )
@@ -51,14 +53,69 @@ func AutoMigrateAndSeed() error {
 		&ec.Category{},
 		&ec.Product{},
 		&ec.ProductOption{},
+		&ec.ProductImage{},
+		&ec.ImageRendition{},
+		&ec.SlugRedirect{},
+		&ec.Translation{},
+		&ec.AttributeDefinition{},
+		&ec.ProductAttribute{},
+		&ec.Bundle{},
+		&ec.BundleComponent{},
+		&ec.PriceChange{},
+		&ec.PriceSchedule{},
+		&ec.Review{},
+		&ec.ReviewPhoto{},
+		&ec.Question{},
+		&ec.Answer{},
 		&eu.User{},
 		&eo.Order{},
 		&eo.OrderItem{},
+		&eo.OrderAmendment{},
+		&eo.Invoice{},
+		&eo.InvoiceLine{},
+		&eo.InvoiceSequence{},
 		&eo.Cart{},
 		&eo.CartItem{},
+		&eo.Wishlist{},
+		&eo.WishlistItem{},
 		&ec.RecommendationCounter{},
+		&ec.ProductAssociation{},
+		&ec.RecommendationOverride{},
+		&en.OutboxMessage{},
+		&en.PushSubscription{},
+		&en.ProductSubscription{},
+		&ea.Event{},
+		&ea.DailyProductStat{},
 	); err != nil {
 		return err
 	}
+	if err := migrateSearch(); err != nil {
+		return err
+	}
+	if err := migrateProductViews(); err != nil {
+		return err
+	}
+	// Subscriptions of signed-in customers need no confirmation, including
+	// those taken before confirmations existed.
+	if err := DB.Exec("UPDATE product_subscriptions SET confirmed_at = created_at WHERE confirmed_at IS NULL AND user_id IS NOT NULL").Error; err != nil {
+		return err
+	}
 	return seedData()
 }
+
+// migrateProductViews moves the product views recorded before behavior
+// events were tracked into the events table and drops their table.
+func migrateProductViews() error {
+	if !DB.Migrator().HasTable("product_views") {
+		return nil
+	}
+	return DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO events (type, product_id, session_id, occurred_at, quantity, value, dedup_key)
+SELECT ?, product_id, session_id, viewed_at, 0, 0, 'product_views:' || id FROM product_views
+ON CONFLICT (dedup_key) DO NOTHING`, ea.EventProductViewed).Error
+		if err != nil {
+			return err
+		}
+		return tx.Migrator().DropTable("product_views")
+	})
+}
@@ -0,0 +1,123 @@
+package database
+
+// searchDDL installs the product full-text search: a weighted tsvector over
+// name, material, option names and descriptions, and one per locale over
+// their translations in product_search_locales, kept current by triggers on
+// products, product_options and catalog_translations, plus a vocabulary of
+// indexed words used with pg_trgm to correct misspelled query terms. The
+// default texts are English and stemmed as such; there is no text search
+// configuration for every locale, so translations are indexed unstemmed with
+// 'simple'. tsvector_agg concatenates the vectors of a locale's fallbacks.
+var searchDDL = []string{
+	`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+	`ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+	`CREATE TABLE IF NOT EXISTS product_search_terms (term text PRIMARY KEY)`,
+	`CREATE TABLE IF NOT EXISTS product_search_locales (
+  product_id bigint NOT NULL,
+  locale text NOT NULL,
+  search_vector tsvector NOT NULL,
+  PRIMARY KEY (product_id, locale)
+)`,
+	`CREATE OR REPLACE AGGREGATE tsvector_agg(tsvector) (SFUNC = tsvector_concat, STYPE = tsvector, INITCOND = '')`,
+	`CREATE OR REPLACE FUNCTION products_search_refresh() RETURNS trigger LANGUAGE plpgsql AS $$
+DECLARE
+  v_options text;
+  v_options_tr text;
+  v_name text;
+  v_short text;
+  v_long text;
+BEGIN
+  SELECT COALESCE(string_agg(o.option_name, ' '), ''),
+         COALESCE(string_agg(t.value, ' '), '') INTO v_options, v_options_tr
+    FROM product_options o
+    LEFT JOIN catalog_translations t ON t.kind = 'option' AND t.record_id = o.id
+   WHERE o.product_id = NEW.id;
+  SELECT COALESCE(string_agg(value, ' ') FILTER (WHERE field = 'name'), ''),
+         COALESCE(string_agg(value, ' ') FILTER (WHERE field = 'short_description'), ''),
+         COALESCE(string_agg(value, ' ') FILTER (WHERE field = 'long_description'), '')
+    INTO v_name, v_short, v_long
+    FROM catalog_translations WHERE kind = 'product' AND record_id = NEW.id;
+  NEW.search_vector :=
+    setweight(to_tsvector('english', COALESCE(NEW.name, '')), 'A') ||
+    setweight(to_tsvector('english', concat_ws(' ', NEW.base_material, v_options)), 'B') ||
+    setweight(to_tsvector('english', COALESCE(NEW.short_description, '')), 'C') ||
+    setweight(to_tsvector('english', COALESCE(NEW.long_description, '')), 'D');
+  DELETE FROM product_search_locales WHERE product_id = NEW.id;
+  INSERT INTO product_search_locales(product_id, locale, search_vector)
+    SELECT NEW.id, l.locale,
+           setweight(to_tsvector('simple', COALESCE(string_agg(l.value, ' ') FILTER (WHERE l.field = 'name'), '')), 'A') ||
+           setweight(to_tsvector('simple', COALESCE(string_agg(l.value, ' ') FILTER (WHERE l.field = 'option_name'), '')), 'B') ||
+           setweight(to_tsvector('simple', COALESCE(string_agg(l.value, ' ') FILTER (WHERE l.field = 'short_description'), '')), 'C') ||
+           setweight(to_tsvector('simple', COALESCE(string_agg(l.value, ' ') FILTER (WHERE l.field = 'long_description'), '')), 'D')
+      FROM (
+        SELECT locale, field, value FROM catalog_translations WHERE kind = 'product' AND record_id = NEW.id
+        UNION ALL
+        SELECT t.locale, t.field, t.value
+          FROM product_options o
+          JOIN catalog_translations t ON t.kind = 'option' AND t.record_id = o.id
+         WHERE o.product_id = NEW.id
+      ) l
+     GROUP BY l.locale;
+  INSERT INTO product_search_terms(term)
+    SELECT DISTINCT t FROM unnest(tsvector_to_array(to_tsvector('simple',
+      concat_ws(' ', NEW.name, v_name, NEW.base_material, v_options, v_options_tr, NEW.short_description, v_short, NEW.long_description, v_long)))) AS t
+     WHERE length(t) > 2
+  ON CONFLICT DO NOTHING;
+  RETURN NEW;
+END $$`,
+	`DROP TRIGGER IF EXISTS products_search_refresh ON products`,
+	`CREATE TRIGGER products_search_refresh
+  BEFORE INSERT OR UPDATE OF name, short_description, long_description, base_material, search_vector ON products
+  FOR EACH ROW EXECUTE FUNCTION products_search_refresh()`,
+	`CREATE OR REPLACE FUNCTION product_options_search_touch() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    UPDATE products SET search_vector = NULL WHERE id = OLD.product_id;
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    UPDATE products SET search_vector = NULL WHERE id = NEW.product_id;
+  END IF;
+  RETURN NULL;
+END $$`,
+	`DROP TRIGGER IF EXISTS product_options_search_touch ON product_options`,
+	`CREATE TRIGGER product_options_search_touch
+  AFTER INSERT OR UPDATE OR DELETE ON product_options
+  FOR EACH ROW EXECUTE FUNCTION product_options_search_touch()`,
+	`CREATE OR REPLACE FUNCTION catalog_translations_search_touch() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+  IF TG_OP <> 'INSERT' THEN
+    UPDATE products SET search_vector = NULL
+     WHERE (OLD.kind = 'product' AND id = OLD.record_id)
+        OR (OLD.kind = 'option' AND id IN (SELECT product_id FROM product_options WHERE id = OLD.record_id));
+  END IF;
+  IF TG_OP <> 'DELETE' THEN
+    UPDATE products SET search_vector = NULL
+     WHERE (NEW.kind = 'product' AND id = NEW.record_id)
+        OR (NEW.kind = 'option' AND id IN (SELECT product_id FROM product_options WHERE id = NEW.record_id));
+  END IF;
+  RETURN NULL;
+END $$`,
+	`DROP TRIGGER IF EXISTS catalog_translations_search_touch ON catalog_translations`,
+	`CREATE TRIGGER catalog_translations_search_touch
+  AFTER INSERT OR UPDATE OR DELETE ON catalog_translations
+  FOR EACH ROW EXECUTE FUNCTION catalog_translations_search_touch()`,
+	`CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector)`,
+	// backfill rows created before the trigger existed, and reindex the
+	// rest once translations are indexed per locale
+	`UPDATE products SET search_vector = NULL WHERE search_vector IS NULL`,
+	`DO $$ BEGIN
+  IF NOT EXISTS (SELECT 1 FROM pg_description WHERE objoid = 'products_search_refresh'::regproc AND description = 'v3') THEN
+    UPDATE products SET search_vector = NULL;
+    COMMENT ON FUNCTION products_search_refresh() IS 'v3';
+  END IF;
+END $$`,
+}
+
+func migrateSearch() error {
+	for _, stmt := range searchDDL {
+		if err := DB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -15,7 +15,7 @@ import (
 
 func seedData() error {
 	if strings.EqualFold(os.Getenv("SEED_RESET"), "true") {
-		_ = DB.Exec("TRUNCATE TABLE product_options, products, categories, departments, recommendation_counters, users RESTART IDENTITY CASCADE").Error
+		_ = DB.Exec("TRUNCATE TABLE product_options, products, categories, departments, recommendation_counters, slug_redirects, catalog_translations, attribute_definitions, product_attributes, bundles, bundle_components, price_changes, price_schedules, reviews, review_photos, questions, answers, wishlists, wishlist_items, product_subscriptions, users RESTART IDENTITY CASCADE").Error
 	}
 	var count int64
 	if err := DB.Model(&ec.Department{}).Count(&count).Error; err != nil {
@@ -166,38 +166,8 @@ func seedData() error {
 	return nil
 }
 
-func slug(s string) string {
-	out := make([]rune, 0, len(s))
-	for _, r := range s {
-		switch {
-		case r >= 'A' && r <= 'Z':
-			out = append(out, r+('a'-'A'))
-		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
-			out = append(out, r)
-		case r == ' ' || r == '_' || r == '-' || r == '/' || r == '+':
-			out = append(out, '-')
-		default:
-			// skip other symbols
-		}
-	}
-	res := make([]rune, 0, len(out))
-	var prevDash bool
-	for _, r := range out {
-		if r == '-' {
-			if !prevDash {
-				res = append(res, r)
-				prevDash = true
-			}
-		} else {
-			res = append(res, r)
-			prevDash = false
-		}
-	}
-	return string(res)
-}
-
 func findUploadImage(categoryName string, idx int) string {
-	baseSlug := slug(categoryName)
+	baseSlug := ec.Slugify(categoryName)
 	baseName := strings.TrimSpace(categoryName)
 	try := func(fn string) string {
 		p := filepath.Join("uploads", fn)
@@ -0,0 +1,12 @@
+package admin
+
+type AttributeDTO struct {
+	Code       string   `json:"code" validate:"required,max=64"`
+	Name       string   `json:"name" validate:"required,min=1,max=255"`
+	Type       string   `json:"type" validate:"required,oneof=text number boolean enum"`
+	Unit       string   `json:"unit" validate:"omitempty,max=16"`
+	Choices    []string `json:"choices" validate:"omitempty,dive,max=255"`
+	Required   bool     `json:"required"`
+	Filterable bool     `json:"filterable"`
+	Position   int      `json:"position"`
+}
@@ -0,0 +1,15 @@
+package admin
+
+// BundleDTO lists the components of a bundle product. Pricing is fixed (the
+// product's base price) or discount (DiscountPercent off the components).
+type BundleDTO struct {
+	Pricing         string               `json:"pricing" validate:"required,oneof=fixed discount"`
+	DiscountPercent float64              `json:"discount_percent" validate:"gte=0,lt=100"`
+	Components      []BundleComponentDTO `json:"components" validate:"required,min=1,dive"`
+}
+
+type BundleComponentDTO struct {
+	ProductID uint   `json:"product_id" validate:"required,gt=0"`
+	Quantity  int    `json:"quantity" validate:"required,gt=0"`
+	OptionIDs []uint `json:"option_ids"`
+}
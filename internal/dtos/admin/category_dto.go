@@ -1,7 +1,10 @@
 package admin
 
 type CategoryDTO struct {
-	DepartmentID uint   `json:"department_id" validate:"required,gt=0"`
-	Name         string `json:"name" validate:"required,min=2"`
-	Description  string `json:"description" validate:"omitempty,min=2"`
+	DepartmentID   uint   `json:"department_id" validate:"required,gt=0"`
+	Name           string `json:"name" validate:"required,min=2"`
+	Description    string `json:"description" validate:"omitempty,min=2"`
+	Slug           string `json:"slug" validate:"omitempty,max=160"`
+	SEOTitle       string `json:"seo_title" validate:"omitempty,max=255"`
+	SEODescription string `json:"seo_description" validate:"omitempty,max=500"`
 }
@@ -1,7 +1,10 @@
 package admin
 
 type DepartmentDTO struct {
-	Name        string `json:"name" validate:"required,min=2"`
-	Description string `json:"description" validate:"omitempty,min=2"`
-	ImageURL    string `json:"image_url" validate:"omitempty,url"`
+	Name           string `json:"name" validate:"required,min=2"`
+	Description    string `json:"description" validate:"omitempty,min=2"`
+	ImageURL       string `json:"image_url" validate:"omitempty,url"`
+	Slug           string `json:"slug" validate:"omitempty,max=160"`
+	SEOTitle       string `json:"seo_title" validate:"omitempty,max=255"`
+	SEODescription string `json:"seo_description" validate:"omitempty,max=500"`
 }
@@ -0,0 +1,14 @@
+package admin
+
+import "time"
+
+// PriceScheduleDTO plans a price change (a new base price, or a new modifier
+// value of OptionID) or a sale of the base price between StartsAt and
+// EndsAt. A missing StartsAt means now.
+type PriceScheduleDTO struct {
+	Kind     string     `json:"kind" validate:"required,oneof=change sale"`
+	OptionID *uint      `json:"option_id"`
+	Price    float64    `json:"price" validate:"gte=0"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
@@ -1,7 +1,10 @@
 package admin
 
+import "time"
+
 type ProductDTO struct {
 	CategoryID             uint    `json:"category_id" validate:"required,gt=0"`
+	SKU                    string  `json:"sku" validate:"omitempty,max=64"`
 	Name                   string  `json:"name" validate:"required,min=2"`
 	ShortDescription       string  `json:"short_description" validate:"omitempty,min=2"`
 	LongDescription        string  `json:"long_description" validate:"omitempty,min=2"`
@@ -13,4 +16,15 @@ type ProductDTO struct {
 	DefaultDepth           int     `json:"default_depth" validate:"required,gt=0"`
 	BaseMaterial           string  `json:"base_material" validate:"omitempty,min=1"`
 	Quantity               int     `json:"quantity" validate:"omitempty,gte=0"`
+	Slug                   string  `json:"slug" validate:"omitempty,max=160"`
+	SEOTitle               string  `json:"seo_title" validate:"omitempty,max=255"`
+	SEODescription         string  `json:"seo_description" validate:"omitempty,max=500"`
+	// Status defaults to draft for new products and to the current state on
+	// update.
+	Status      string     `json:"status" validate:"omitempty,oneof=draft scheduled published discontinued"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+	// Attributes are values keyed by attribute code. Omitting them keeps
+	// the current values on update.
+	Attributes map[string]any `json:"attributes"`
 }
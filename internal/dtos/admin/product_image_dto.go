@@ -0,0 +1,13 @@
+package admin
+
+// ProductImageDTO describes a gallery image; OptionID ties it to the
+// product option it shows.
+type ProductImageDTO struct {
+	AltText  string `json:"alt_text" form:"alt_text" validate:"max=255"`
+	OptionID *uint  `json:"option_id" form:"option_id"`
+}
+
+// ReorderImagesDTO lists every image id of a product in the new order.
+type ReorderImagesDTO struct {
+	IDs []uint `json:"ids" validate:"required,min=1"`
+}
@@ -0,0 +1,10 @@
+package admin
+
+// RecommendationOverrideDTO pins or excludes RelatedProductID in the
+// recommendations of ProductID; ProductID 0 applies to every product.
+type RecommendationOverrideDTO struct {
+	ProductID        uint   `json:"product_id"`
+	RelatedProductID uint   `json:"related_product_id" validate:"required"`
+	Action           string `json:"action" validate:"required,oneof=pin exclude"`
+	Position         int    `json:"position" validate:"gte=0"`
+}
@@ -6,4 +6,5 @@ type RegisterRequest struct {
 	Password string `json:"password" validate:"required,min=6"`
 	Address  string `json:"address" validate:"omitempty,min=5"`
 	Phone    string `json:"phone" validate:"omitempty,phone"`
+	Locale   string `json:"locale" validate:"omitempty,oneof=en bg"`
 }
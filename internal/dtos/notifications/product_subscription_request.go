@@ -0,0 +1,13 @@
+package notifications
+
+import cartdto "furniture-shop/internal/dtos/cart"
+
+// ProductSubscriptionRequest subscribes to alerts about a product in an
+// option selection. Email and Locale are only read for guests; signed-in
+// customers are alerted at their account address and language.
+type ProductSubscriptionRequest struct {
+	Kind    string                   `json:"kind" validate:"required,oneof=back_in_stock price_drop"`
+	Options []cartdto.SelectedOption `json:"options"`
+	Email   string                   `json:"email" validate:"omitempty,email,max=255"`
+	Locale  string                   `json:"locale" validate:"omitempty,oneof=en bg"`
+}
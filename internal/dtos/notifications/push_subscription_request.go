@@ -0,0 +1,12 @@
+package notifications
+
+// PushSubscriptionRequest mirrors the JSON form of a browser PushSubscription.
+type PushSubscriptionRequest struct {
+	Endpoint string               `json:"endpoint" validate:"required,url"`
+	Keys     PushSubscriptionKeys `json:"keys" validate:"required"`
+}
+
+type PushSubscriptionKeys struct {
+	P256dh string `json:"p256dh" validate:"required"`
+	Auth   string `json:"auth" validate:"required"`
+}
@@ -0,0 +1,5 @@
+package notifications
+
+type UpdatePreferencesRequest struct {
+	Channels []string `json:"channels" validate:"dive,oneof=email sms push"`
+}
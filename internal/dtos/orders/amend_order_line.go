@@ -0,0 +1,7 @@
+package orders
+
+type AmendOrderLine struct {
+	ItemID   uint             `json:"item_id" validate:"required,gt=0"`
+	Quantity int              `json:"quantity" validate:"required,gt=0"`
+	Options  []SelectedOption `json:"options"`
+}
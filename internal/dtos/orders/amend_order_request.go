@@ -0,0 +1,8 @@
+package orders
+
+type AmendOrderRequest struct {
+	Add    []CreateOrderItem `json:"add" validate:"omitempty,dive"`
+	Update []AmendOrderLine  `json:"update" validate:"omitempty,dive"`
+	Remove []uint            `json:"remove" validate:"omitempty,dive,gt=0"`
+	Note   string            `json:"note" validate:"omitempty,max=500"`
+}
@@ -0,0 +1,7 @@
+package orders
+
+// ScheduleDeliveryRequest sets the delivery day of an order; an empty date
+// clears it.
+type ScheduleDeliveryRequest struct {
+	Date string `json:"date" validate:"omitempty,datetime=2006-01-02"`
+}
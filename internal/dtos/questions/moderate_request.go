@@ -0,0 +1,5 @@
+package questions
+
+type ModerateRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending approved rejected"`
+}
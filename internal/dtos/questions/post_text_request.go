@@ -0,0 +1,6 @@
+package questions
+
+// PostTextRequest is the text of a question or an answer.
+type PostTextRequest struct {
+	Body string `json:"body" validate:"required,max=2000"`
+}
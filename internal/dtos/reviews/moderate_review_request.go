@@ -0,0 +1,6 @@
+package reviews
+
+type ModerateReviewRequest struct {
+	Status string `json:"status" validate:"required,oneof=pending approved rejected"`
+	Note   string `json:"note" validate:"max=500"`
+}
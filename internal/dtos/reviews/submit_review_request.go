@@ -0,0 +1,9 @@
+package reviews
+
+// SubmitReviewRequest is the text of a review, sent as a multipart form
+// together with up to four "photos" files.
+type SubmitReviewRequest struct {
+	Rating int    `json:"rating" form:"rating" validate:"required,min=1,max=5"`
+	Title  string `json:"title" form:"title" validate:"max=120"`
+	Body   string `json:"body" form:"body" validate:"max=5000"`
+}
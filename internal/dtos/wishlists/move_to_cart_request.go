@@ -0,0 +1,8 @@
+package wishlists
+
+// MoveToCartRequest moves the given items, or every item when ItemIDs is
+// empty, to the cart. Keep leaves them in the wishlist as well.
+type MoveToCartRequest struct {
+	ItemIDs []uint `json:"item_ids"`
+	Keep    bool   `json:"keep"`
+}
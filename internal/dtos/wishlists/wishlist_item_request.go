@@ -0,0 +1,11 @@
+package wishlists
+
+import cartdto "furniture-shop/internal/dtos/cart"
+
+// WishlistItemRequest saves a product configuration; ProductID is ignored
+// when updating an item.
+type WishlistItemRequest struct {
+	ProductID uint                     `json:"product_id"`
+	Quantity  int                      `json:"quantity" validate:"gte=0"`
+	Options   []cartdto.SelectedOption `json:"options"`
+}
@@ -0,0 +1,6 @@
+package wishlists
+
+// WishlistRequest creates or renames a wishlist.
+type WishlistRequest struct {
+	Name string `json:"name" validate:"required,max=120"`
+}
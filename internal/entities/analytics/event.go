@@ -0,0 +1,57 @@
+package analytics
+
+import (
+	"fmt"
+	"time"
+)
+
+// Event types
+const (
+	EventProductViewed   = "product_viewed"
+	EventAddedToCart     = "added_to_cart"
+	EventCheckoutStarted = "checkout_started"
+	EventOrderPaid       = "order_paid"
+)
+
+// Event is a raw behavior event. Order events are recorded once per order
+// line (OrderItemID) so that every event refers to a product; order_paid
+// events use "order:<id>" as their session, so distinct sessions count
+// orders.
+type Event struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Type        string    `gorm:"size:32;index:idx_events_type_time" json:"type"`
+	ProductID   uint      `gorm:"index" json:"product_id"`
+	OrderID     *uint     `json:"order_id"`
+	OrderItemID *uint     `json:"order_item_id"`
+	UserID      *uint     `json:"user_id"`
+	SessionID   string    `gorm:"size:64" json:"session_id"`
+	Quantity    int       `json:"quantity"`
+	Value       float64   `json:"value"`
+	OccurredAt  time.Time `gorm:"index:idx_events_type_time" json:"occurred_at"`
+	// DedupKey collapses repeats: browsing events of a session count once per
+	// product and dedup window, order events once per order line.
+	DedupKey string `gorm:"size:160;uniqueIndex" json:"-"`
+}
+
+// Key derives the dedup key of the event for the given window.
+func (e Event) Key(window time.Duration) string {
+	if e.OrderItemID != nil {
+		return fmt.Sprintf("%s:i%d", e.Type, *e.OrderItemID)
+	}
+	bucket := int64(0)
+	if window > 0 {
+		bucket = e.OccurredAt.Unix() / int64(window/time.Second)
+	}
+	return fmt.Sprintf("%s:%s:p%d:%d", e.Type, e.SessionID, e.ProductID, bucket)
+}
+
+// DailyProductStat aggregates the events of one type for a product and day.
+type DailyProductStat struct {
+	Day       time.Time `gorm:"primaryKey;type:date" json:"day"`
+	ProductID uint      `gorm:"primaryKey;autoIncrement:false" json:"product_id"`
+	Type      string    `gorm:"primaryKey;size:32" json:"type"`
+	Events    int64     `json:"events"`
+	Sessions  int64     `json:"sessions"`
+	Quantity  int64     `json:"quantity"`
+	Value     float64   `json:"value"`
+}
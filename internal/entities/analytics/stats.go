@@ -0,0 +1,45 @@
+package analytics
+
+import "time"
+
+// Product analytics sort keys
+const (
+	StatsSortViews      = "views"
+	StatsSortCart       = "added_to_cart"
+	StatsSortUnits      = "units"
+	StatsSortRevenue    = "revenue"
+	StatsSortConversion = "conversion"
+)
+
+// StatsFilter narrows the analytics to [From, To) and optionally a product.
+type StatsFilter struct {
+	From      time.Time
+	To        time.Time
+	ProductID uint
+	Sort      string
+}
+
+// ProductStats is the funnel of a product over a period. Conversion is the
+// share of viewing sessions that ended in a paid order.
+type ProductStats struct {
+	ProductID       uint    `json:"product_id"`
+	Name            string  `json:"name"`
+	Views           int64   `json:"views"`
+	ViewSessions    int64   `json:"view_sessions"`
+	AddedToCart     int64   `json:"added_to_cart"`
+	CheckoutStarted int64   `json:"checkout_started"`
+	Orders          int64   `json:"orders"`
+	Units           int64   `json:"units"`
+	Revenue         float64 `json:"revenue"`
+	Conversion      float64 `json:"conversion"`
+}
+
+// DailyTotals sums the events of a day.
+type DailyTotals struct {
+	Day             time.Time `json:"day"`
+	Views           int64     `json:"views"`
+	AddedToCart     int64     `json:"added_to_cart"`
+	CheckoutStarted int64     `json:"checkout_started"`
+	Units           int64     `json:"units"`
+	Revenue         float64   `json:"revenue"`
+}
@@ -0,0 +1,21 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrParentArchived refuses to restore a record under an archived
+// department or category.
+var ErrParentArchived = errors.New("parent is archived; restore it first")
+
+// ArchiveBlockedError refuses to archive a record that still has active
+// children, which would be left without a visible parent.
+type ArchiveBlockedError struct {
+	Kind     string
+	Children int64
+}
+
+func (e *ArchiveBlockedError) Error() string {
+	return fmt.Sprintf("%d active %s would be orphaned; archive them first or pass cascade=true", e.Children, e.Kind)
+}
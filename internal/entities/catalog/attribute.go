@@ -0,0 +1,196 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Attribute types.
+const (
+	AttributeText    = "text"
+	AttributeNumber  = "number"
+	AttributeBoolean = "boolean"
+	AttributeEnum    = "enum"
+)
+
+// TranslationAttribute is the translation kind of attribute names.
+const TranslationAttribute = "attribute"
+
+// maxAttributeText bounds free-text attribute values.
+const maxAttributeText = 500
+
+// ErrInvalidAttribute rejects an attribute definition or a product value
+// that does not fit its definition.
+var ErrInvalidAttribute = errors.New("invalid attribute")
+
+var attributeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// AttributeDefinition is a typed specification the products of a category
+// can carry, e.g. seat_height (number, cm) or assembly_required (boolean).
+type AttributeDefinition struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	CategoryID uint   `gorm:"not null;uniqueIndex:idx_attribute_definitions_code,priority:1" json:"category_id"`
+	Code       string `gorm:"size:64;not null;uniqueIndex:idx_attribute_definitions_code,priority:2" json:"code"`
+	Name       string `json:"name"`
+	Type       string `gorm:"size:16;not null" json:"type"`
+	// Unit is shown after number values, e.g. cm or kg.
+	Unit string `gorm:"size:16" json:"unit"`
+	// Choices are the allowed values of an enum attribute.
+	Choices    []string  `gorm:"serializer:json" json:"choices"`
+	Required   bool      `json:"required"`
+	Filterable bool      `json:"filterable"`
+	Position   int       `json:"position"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func (d *AttributeDefinition) TextFields() map[string]*string {
+	return map[string]*string{"name": &d.Name}
+}
+
+// Validate checks a definition before it is saved.
+func (d *AttributeDefinition) Validate() error {
+	if !attributeCodePattern.MatchString(d.Code) {
+		return fmt.Errorf("%w: code must be lower-case letters, digits and underscores", ErrInvalidAttribute)
+	}
+	switch d.Type {
+	case AttributeText, AttributeNumber, AttributeBoolean:
+		d.Choices = nil
+	case AttributeEnum:
+		if len(d.Choices) == 0 {
+			return fmt.Errorf("%w: an enum attribute needs choices", ErrInvalidAttribute)
+		}
+		for _, c := range d.Choices {
+			if strings.TrimSpace(c) == "" {
+				return fmt.Errorf("%w: empty choice", ErrInvalidAttribute)
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidAttribute, d.Type)
+	}
+	if d.Type != AttributeNumber {
+		d.Unit = ""
+	}
+	return nil
+}
+
+// Parse checks a submitted value, a JSON string, number or boolean, and
+// returns it as stored. A nil or empty value leaves the attribute unset.
+func (d *AttributeDefinition) Parse(v any) (*ProductAttribute, error) {
+	if s, ok := v.(string); ok {
+		v = strings.TrimSpace(s)
+	}
+	if v == nil || v == "" {
+		if d.Required {
+			return nil, fmt.Errorf("%w: %s is required", ErrInvalidAttribute, d.Code)
+		}
+		return nil, nil
+	}
+	out := &ProductAttribute{AttributeID: d.ID}
+	switch d.Type {
+	case AttributeText:
+		s, ok := v.(string)
+		if !ok || len(s) > maxAttributeText {
+			return nil, fmt.Errorf("%w: %s must be text of at most %d characters", ErrInvalidAttribute, d.Code, maxAttributeText)
+		}
+		out.Value = s
+	case AttributeNumber:
+		var n float64
+		switch x := v.(type) {
+		case float64:
+			n = x
+		case string:
+			f, err := strconv.ParseFloat(x, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s must be a number", ErrInvalidAttribute, d.Code)
+			}
+			n = f
+		default:
+			return nil, fmt.Errorf("%w: %s must be a number", ErrInvalidAttribute, d.Code)
+		}
+		if math.IsNaN(n) || math.IsInf(n, 0) {
+			return nil, fmt.Errorf("%w: %s must be a finite number", ErrInvalidAttribute, d.Code)
+		}
+		out.Value, out.Number = strconv.FormatFloat(n, 'f', -1, 64), &n
+	case AttributeBoolean:
+		var b bool
+		switch x := v.(type) {
+		case bool:
+			b = x
+		case string:
+			p, err := strconv.ParseBool(x)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s must be true or false", ErrInvalidAttribute, d.Code)
+			}
+			b = p
+		default:
+			return nil, fmt.Errorf("%w: %s must be true or false", ErrInvalidAttribute, d.Code)
+		}
+		out.Value = strconv.FormatBool(b)
+	case AttributeEnum:
+		s, _ := v.(string)
+		i := slices.IndexFunc(d.Choices, func(c string) bool { return strings.EqualFold(c, s) })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s must be one of %s", ErrInvalidAttribute, d.Code, strings.Join(d.Choices, ", "))
+		}
+		out.Value = d.Choices[i]
+	}
+	return out, nil
+}
+
+// ProductAttribute is the value of an attribute for a product. Value holds
+// the canonical text of every type; Number repeats numbers for range filters.
+type ProductAttribute struct {
+	ID          uint                 `gorm:"primaryKey" json:"-"`
+	ProductID   uint                 `gorm:"not null;uniqueIndex:idx_product_attributes_key,priority:1" json:"-"`
+	AttributeID uint                 `gorm:"not null;uniqueIndex:idx_product_attributes_key,priority:2;index" json:"attribute_id"`
+	Value       string               `gorm:"type:text;not null" json:"value"`
+	Number      *float64             `json:"number,omitempty"`
+	Attribute   *AttributeDefinition `gorm:"constraint:OnDelete:CASCADE" json:"attribute,omitempty"`
+}
+
+// AttributeFilter requires products to have an attribute of the listed
+// category with the given value, or a number within Min and Max.
+type AttributeFilter struct {
+	Code  string
+	Value string
+	Min   *float64
+	Max   *float64
+}
+
+// ParseAttributes checks the submitted values of a product against the
+// definitions of its category, keyed by code, and returns the values to
+// store. Unknown codes and missing required values are rejected.
+func ParseAttributes(defs []AttributeDefinition, values map[string]any) ([]ProductAttribute, error) {
+	for code := range values {
+		if !slices.ContainsFunc(defs, func(d AttributeDefinition) bool { return d.Code == code }) {
+			return nil, fmt.Errorf("%w: unknown attribute %q", ErrInvalidAttribute, code)
+		}
+	}
+	out := []ProductAttribute{}
+	for i := range defs {
+		a, err := defs[i].Parse(values[defs[i].Code])
+		if err != nil {
+			return nil, err
+		}
+		if a != nil {
+			out = append(out, *a)
+		}
+	}
+	return out, nil
+}
+
+// AttributeFacet describes how the listed products of a category vary in a
+// filterable attribute: the counts of each value, and the range of numbers.
+type AttributeFacet struct {
+	AttributeDefinition
+	Values []FacetCount `json:"values"`
+	Min    *float64     `json:"min,omitempty"`
+	Max    *float64     `json:"max,omitempty"`
+}
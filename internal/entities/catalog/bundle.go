@@ -0,0 +1,179 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// Bundle pricing: a fixed price set on the bundle product, or a discount
+// off the price of its components.
+const (
+	BundleFixed    = "fixed"
+	BundleDiscount = "discount"
+)
+
+// ErrInvalidBundle rejects a bundle definition or an order of a bundle
+// whose components cannot be sold.
+var ErrInvalidBundle = errors.New("invalid bundle")
+
+// Bundle makes a product a set of other products, e.g. a dining set of a
+// table and six chairs. Ordering it orders its components.
+type Bundle struct {
+	ProductID       uint              `gorm:"primaryKey;autoIncrement:false" json:"product_id"`
+	Pricing         string            `gorm:"size:16;not null" json:"pricing"`
+	DiscountPercent float64           `json:"discount_percent"`
+	Components      []BundleComponent `gorm:"foreignKey:BundleID;references:ProductID;constraint:OnDelete:CASCADE" json:"components"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+// BundleComponent is a product in a bundle, with the options it comes with.
+type BundleComponent struct {
+	ID        uint     `gorm:"primaryKey" json:"id"`
+	BundleID  uint     `gorm:"not null;index" json:"bundle_id"`
+	ProductID uint     `gorm:"not null;index" json:"product_id"`
+	Quantity  int      `gorm:"not null" json:"quantity"`
+	OptionIDs []uint   `gorm:"serializer:json" json:"option_ids"`
+	Position  int      `json:"position"`
+	Product   *Product `gorm:"constraint:OnDelete:CASCADE" json:"product,omitempty"`
+}
+
+// Validate checks a bundle of the product bundleID whose components are
+// loaded with their options.
+func (b *Bundle) Validate(bundleID uint) error {
+	switch b.Pricing {
+	case BundleFixed:
+		b.DiscountPercent = 0
+	case BundleDiscount:
+		if b.DiscountPercent < 0 || b.DiscountPercent >= 100 {
+			return fmt.Errorf("%w: discount must be between 0 and 100 percent", ErrInvalidBundle)
+		}
+	default:
+		return fmt.Errorf("%w: unknown pricing %q", ErrInvalidBundle, b.Pricing)
+	}
+	if len(b.Components) == 0 {
+		return fmt.Errorf("%w: a bundle needs components", ErrInvalidBundle)
+	}
+	for i := range b.Components {
+		c := &b.Components[i]
+		switch {
+		case c.Product == nil:
+			return fmt.Errorf("%w: product %d not found", ErrInvalidBundle, c.ProductID)
+		case c.ProductID == bundleID:
+			return fmt.Errorf("%w: a bundle cannot contain itself", ErrInvalidBundle)
+		case c.Product.Bundle != nil:
+			return fmt.Errorf("%w: product %d is a bundle itself", ErrInvalidBundle, c.ProductID)
+		case c.Quantity <= 0:
+			return fmt.Errorf("%w: quantity of product %d must be positive", ErrInvalidBundle, c.ProductID)
+		}
+		for _, id := range c.OptionIDs {
+			if !slices.ContainsFunc(c.Product.Options, func(o ProductOption) bool { return o.ID == id }) {
+				return fmt.Errorf("%w: option %d is not an option of product %d", ErrInvalidBundle, id, c.ProductID)
+			}
+		}
+		c.BundleID, c.Position = bundleID, i
+	}
+	return nil
+}
+
+// Options returns the options a component is ordered with: its own, except
+// that an option of the component among selected replaces the bundle's
+// choice of the same type (e.g. another fabric).
+func (c *BundleComponent) Options(selected []uint) []uint {
+	var chosen []ProductOption
+	for _, id := range selected {
+		if i := slices.IndexFunc(c.Product.Options, func(o ProductOption) bool { return o.ID == id }); i >= 0 {
+			chosen = append(chosen, c.Product.Options[i])
+		}
+	}
+	var out []uint
+	for _, id := range c.OptionIDs {
+		i := slices.IndexFunc(c.Product.Options, func(o ProductOption) bool { return o.ID == id })
+		if i >= 0 && slices.ContainsFunc(chosen, func(o ProductOption) bool { return o.OptionType == c.Product.Options[i].OptionType }) {
+			continue
+		}
+		out = append(out, id)
+	}
+	for _, o := range chosen {
+		out = append(out, o.ID)
+	}
+	return out
+}
+
+// ComponentsPrice is the price of the components bought one by one, with
+// the options of selected.
+func (b *Bundle) ComponentsPrice(selected []uint) float64 {
+	var sum float64
+	for i := range b.Components {
+		c := &b.Components[i]
+		if c.Product != nil {
+			sum += c.Product.PriceWith(c.Options(selected)) * float64(c.Quantity)
+		}
+	}
+	return sum
+}
+
+// Price is the price of one bundle whose product costs base. Options picked
+// for a component change a fixed price by what they change the component's.
+func (b *Bundle) Price(base float64, selected []uint) float64 {
+	if b.Pricing == BundleDiscount {
+		return math.Round(b.ComponentsPrice(selected)*(100-b.DiscountPercent)) / 100
+	}
+	return base + b.ComponentsPrice(selected) - b.ComponentsPrice(nil)
+}
+
+// Stock is the number of complete bundles the components in stock make up.
+func (b *Bundle) Stock() int {
+	stock := -1
+	for _, c := range b.Components {
+		if c.Product == nil {
+			return 0
+		}
+		if n := c.Product.Quantity / c.Quantity; stock < 0 || n < stock {
+			stock = n
+		}
+	}
+	return max(stock, 0)
+}
+
+// Sellable reports whether every component can still be ordered.
+func (b *Bundle) Sellable() bool {
+	return !slices.ContainsFunc(b.Components, func(c BundleComponent) bool { return c.Product == nil || !c.Product.Published() })
+}
+
+// ApplyBundle replaces the stored price and quantity of a bundle product,
+// loaded with its components, by the ones its components make up.
+func (p *Product) ApplyBundle() {
+	if p.Bundle == nil {
+		return
+	}
+	p.BasePrice = p.Bundle.Price(p.BasePrice, nil)
+	if p.SalePrice != nil {
+		sale := p.Bundle.Price(*p.SalePrice, nil)
+		p.SalePrice = &sale
+	}
+	p.Quantity = p.Bundle.Stock()
+}
+
+// PriceWith is the unit price of the product with the given options, applied
+// in order to its current price: absolute modifiers add to it, percent ones
+// scale it.
+func (p *Product) PriceWith(optionIDs []uint) float64 {
+	price := p.Price()
+	for _, id := range optionIDs {
+		i := slices.IndexFunc(p.Options, func(o ProductOption) bool { return o.ID == id })
+		if i < 0 {
+			continue
+		}
+		switch o := p.Options[i]; o.PriceModifierType {
+		case "absolute":
+			price += o.PriceModifierValue
+		case "percent":
+			price = price * (1.0 + o.PriceModifierValue/100.0)
+		}
+	}
+	return price
+}
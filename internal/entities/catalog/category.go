@@ -1,12 +1,20 @@
 package catalog
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Category struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	DepartmentID uint      `json:"department_id"`
-	Name         string    `json:"name"`
-	Description  string    `json:"description"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	DepartmentID   uint           `json:"department_id"`
+	Name           string         `json:"name"`
+	Slug           string         `gorm:"size:160;not null;default:'';index:idx_categories_slug,unique,where:slug <> ''" json:"slug"`
+	Description    string         `json:"description"`
+	SEOTitle       string         `gorm:"size:255" json:"seo_title"`
+	SEODescription string         `gorm:"size:500" json:"seo_description"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
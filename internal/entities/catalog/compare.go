@@ -0,0 +1,138 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// MaxCompared bounds the number of products compared side by side.
+const MaxCompared = 4
+
+// ErrInvalidComparison rejects a comparison of too few or too many products.
+var ErrInvalidComparison = errors.New("invalid comparison")
+
+// Comparison row groups.
+const (
+	ComparisonGeneral   = "general"
+	ComparisonOption    = "option"
+	ComparisonAttribute = "attribute"
+)
+
+// ComparedProduct heads a column of a comparison.
+type ComparedProduct struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	ImageURL string `json:"image_url"`
+}
+
+// ComparisonRow holds one property of every compared product, in the order
+// of the columns. A nil value means the product does not have it. Different
+// marks rows whose values are not all the same.
+type ComparisonRow struct {
+	Key       string `json:"key"`
+	Group     string `json:"group"`
+	Label     string `json:"label"`
+	Unit      string `json:"unit,omitempty"`
+	Values    []any  `json:"values"`
+	Different bool   `json:"different"`
+}
+
+// ProductComparison is an aligned matrix of products and their properties.
+type ProductComparison struct {
+	Products []ComparedProduct `json:"products"`
+	Rows     []ComparisonRow   `json:"rows"`
+}
+
+// Compare lines up the general properties, the options and the attributes
+// of products loaded with their options and attributes. Options are
+// compared by type and name, attributes by code, so products of different
+// categories share the rows they have in common.
+func Compare(items []Product) *ProductComparison {
+	out := &ProductComparison{Products: make([]ComparedProduct, len(items))}
+	general := []struct {
+		key, label, unit string
+		value            func(p *Product) any
+	}{
+		{"base_price", "Price", "", func(p *Product) any { return p.Price() }},
+		{"default_width", "Width", "cm", func(p *Product) any { return p.DefaultWidth }},
+		{"default_height", "Height", "cm", func(p *Product) any { return p.DefaultHeight }},
+		{"default_depth", "Depth", "cm", func(p *Product) any { return p.DefaultDepth }},
+		{"base_material", "Material", "", func(p *Product) any { return p.BaseMaterial }},
+		{"base_production_time_days", "Production time", "days", func(p *Product) any { return p.BaseProductionTimeDays }},
+		{"in_stock", "In stock", "", func(p *Product) any { return p.Quantity > 0 }},
+	}
+	for _, g := range general {
+		row := ComparisonRow{Key: g.key, Group: ComparisonGeneral, Label: g.label, Unit: g.unit, Values: make([]any, len(items))}
+		for i := range items {
+			row.Values[i] = g.value(&items[i])
+		}
+		out.Rows = append(out.Rows, row)
+	}
+
+	var options, attributes []ComparisonRow
+	optionRow := map[string]int{}
+	attributeRow := map[string]int{}
+	for i := range items {
+		p := &items[i]
+		out.Products[i] = ComparedProduct{ID: p.ID, Name: p.Name, Slug: p.Slug, ImageURL: p.ImageURL}
+		for _, o := range p.Options {
+			key := "option:" + o.OptionType + ":" + o.OptionName
+			j, ok := optionRow[key]
+			if !ok {
+				j = len(options)
+				optionRow[key] = j
+				options = append(options, ComparisonRow{Key: key, Group: ComparisonOption, Label: o.OptionName, Unit: o.OptionType, Values: make([]any, len(items))})
+			}
+			options[j].Values[i] = true
+		}
+		for _, a := range p.Attributes {
+			if a.Attribute == nil {
+				continue
+			}
+			key := "attribute:" + a.Attribute.Code
+			j, ok := attributeRow[key]
+			if !ok {
+				j = len(attributes)
+				attributeRow[key] = j
+				attributes = append(attributes, ComparisonRow{Key: key, Group: ComparisonAttribute, Label: a.Attribute.Name, Unit: a.Attribute.Unit, Values: make([]any, len(items))})
+			}
+			attributes[j].Values[i] = a.value()
+		}
+	}
+	// products without an option simply do not offer it
+	for j := range options {
+		for i, v := range options[j].Values {
+			if v == nil {
+				options[j].Values[i] = false
+			}
+		}
+	}
+	out.Rows = append(append(out.Rows, options...), attributes...)
+	for j := range out.Rows {
+		out.Rows[j].Different = differs(out.Rows[j].Values)
+	}
+	return out
+}
+
+// value returns the attribute value typed by its definition.
+func (a ProductAttribute) value() any {
+	switch {
+	case a.Number != nil:
+		return *a.Number
+	case a.Attribute.Type == AttributeBoolean:
+		b, _ := strconv.ParseBool(a.Value)
+		return b
+	}
+	return a.Value
+}
+
+func differs(values []any) bool {
+	for _, v := range values[1:] {
+		if fmt.Sprint(v) != fmt.Sprint(values[0]) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,12 +1,20 @@
 package catalog
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Department struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	ImageURL    string    `json:"image_url"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             uint           `gorm:"primaryKey" json:"id"`
+	Name           string         `json:"name"`
+	Slug           string         `gorm:"size:160;not null;default:'';index:idx_departments_slug,unique,where:slug <> ''" json:"slug"`
+	Description    string         `json:"description"`
+	ImageURL       string         `json:"image_url"`
+	SEOTitle       string         `gorm:"size:255" json:"seo_title"`
+	SEODescription string         `gorm:"size:500" json:"seo_description"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
@@ -0,0 +1,47 @@
+package catalog
+
+// Product listing sort keys
+const (
+	ProductSortNewest     = "newest"
+	ProductSortPriceAsc   = "price_asc"
+	ProductSortPriceDesc  = "price_desc"
+	ProductSortPopularity = "popularity"
+	ProductSortName       = "name"
+	ProductSortRating     = "rating"
+)
+
+// IntRange is an inclusive range; a nil bound is open.
+type IntRange struct {
+	Min *int
+	Max *int
+}
+
+// OptionFilter requires a product to offer an option with this name, and of
+// this type when Type is set (e.g. color:Oak).
+type OptionFilter struct {
+	Type string
+	Name string
+}
+
+// ProductFilter narrows and orders product listings.
+type ProductFilter struct {
+	CategoryID uint
+	PriceMin   *float64
+	PriceMax   *float64
+	Material   string
+	InStock    *bool
+	// RatingMin keeps products whose average rating is at least this.
+	RatingMin  *float64
+	Width      IntRange
+	Height     IntRange
+	Depth      IntRange
+	Options    []OptionFilter
+	Attributes []AttributeFilter
+	Sort       string
+	// Status keeps products in this lifecycle state; empty means any.
+	Status string
+	// Preview lists unpublished products too (admin preview of the storefront).
+	Preview bool
+	// Archived lists archived products instead of active ones (admin only).
+	Archived bool
+}
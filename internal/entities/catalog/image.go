@@ -0,0 +1,59 @@
+package catalog
+
+import "time"
+
+// Image rendition sizes
+const (
+	ImageThumbnail = "thumbnail"
+	ImageMedium    = "medium"
+	ImageLarge     = "large"
+)
+
+// Image formats
+const (
+	ImageJPEG = "jpeg"
+	ImagePNG  = "png"
+	ImageGIF  = "gif"
+	ImageWebP = "webp"
+)
+
+// ProductImage is one picture of a product gallery, ordered by Position. An
+// image tied to an option (e.g. a fabric color) shows the product in that
+// option. The uploaded original is private; admins get a signed link.
+type ProductImage struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	ProductID   uint             `gorm:"index" json:"product_id"`
+	OptionID    *uint            `gorm:"index" json:"option_id"`
+	Option      *ProductOption   `gorm:"constraint:OnDelete:SET NULL" json:"-"`
+	Position    int              `json:"position"`
+	AltText     string           `json:"alt_text"`
+	Width       int              `json:"width"`
+	Height      int              `json:"height"`
+	OriginalURL string           `json:"-"`
+	Renditions  []ImageRendition `gorm:"foreignKey:ImageID;constraint:OnDelete:CASCADE" json:"renditions"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// URL returns the address of the rendition of the given size and format, or
+// "" when there is none.
+func (i ProductImage) URL(size, format string) string {
+	for _, r := range i.Renditions {
+		if r.Size == size && r.Format == format {
+			return r.URL
+		}
+	}
+	return ""
+}
+
+// ImageRendition is a resized copy of a product image.
+type ImageRendition struct {
+	ID      uint   `gorm:"primaryKey" json:"-"`
+	ImageID uint   `gorm:"index" json:"-"`
+	Size    string `gorm:"size:16" json:"size"`
+	Format  string `gorm:"size:8" json:"format"`
+	Width   int    `json:"width"`
+	Height  int    `json:"height"`
+	Bytes   int    `json:"bytes"`
+	URL     string `json:"url"`
+}
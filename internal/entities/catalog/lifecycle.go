@@ -0,0 +1,58 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Product lifecycle states. Only published products are listed, searched
+// and sold; discontinued ones keep their page for links from past orders.
+const (
+	ProductDraft        = "draft"
+	ProductScheduled    = "scheduled"
+	ProductPublished    = "published"
+	ProductDiscontinued = "discontinued"
+)
+
+var (
+	// ErrNotPublished hides a draft or scheduled product from customers.
+	ErrNotPublished = errors.New("product is not published")
+	// ErrInvalidSchedule rejects inconsistent publish and unpublish times.
+	ErrInvalidSchedule = errors.New("invalid publishing schedule")
+)
+
+// Published reports whether customers can find and order the product.
+func (p *Product) Published() bool {
+	return p.Status == ProductPublished && !p.DeletedAt.Valid
+}
+
+// Visible reports whether customers can open the product page.
+func (p *Product) Visible() bool {
+	return p.Status == ProductPublished || p.Status == ProductDiscontinued
+}
+
+// Schedule checks the lifecycle fields of a product about to be saved and
+// moves it to the state its timestamps call for at now: a scheduled product
+// whose publish time has passed is published, a published one past its
+// unpublish time is discontinued. An empty status means draft.
+func (p *Product) Schedule(now time.Time) error {
+	if p.Status == "" {
+		p.Status = ProductDraft
+	}
+	if p.PublishAt != nil && p.UnpublishAt != nil && !p.UnpublishAt.After(*p.PublishAt) {
+		return fmt.Errorf("%w: unpublish_at must be after publish_at", ErrInvalidSchedule)
+	}
+	if p.Status == ProductScheduled {
+		if p.PublishAt == nil {
+			return fmt.Errorf("%w: a scheduled product needs publish_at", ErrInvalidSchedule)
+		}
+		if !p.PublishAt.After(now) {
+			p.Status = ProductPublished
+		}
+	}
+	if p.Status == ProductPublished && p.UnpublishAt != nil && !p.UnpublishAt.After(now) {
+		p.Status = ProductDiscontinued
+	}
+	return nil
+}
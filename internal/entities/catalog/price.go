@@ -0,0 +1,110 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Reasons of a price change
+const (
+	PriceChangeManual    = "manual"
+	PriceChangeImport    = "import"
+	PriceChangeScheduled = "scheduled"
+	PriceChangeSaleStart = "sale_start"
+	PriceChangeSaleEnd   = "sale_end"
+)
+
+// Price schedule kinds: a change sets a new price for good, a sale
+// replaces the base price between StartsAt and EndsAt.
+const (
+	PriceScheduleChange = "change"
+	PriceScheduleSale   = "sale"
+)
+
+// Price schedule states. A change goes from pending to done, a sale from
+// pending to active while it runs and to done when it ends.
+const (
+	PriceSchedulePending = "pending"
+	PriceScheduleActive  = "active"
+	PriceScheduleDone    = "done"
+)
+
+// ErrInvalidPriceSchedule rejects a schedule that cannot be carried out.
+var ErrInvalidPriceSchedule = errors.New("invalid price schedule")
+
+// PriceChange is an entry of the price history of a product: a change of
+// its base price or sale price or, with OptionID set, of an option's price
+// modifier. Changes are recorded as they are stored; orders keep the unit
+// prices they were placed at.
+type PriceChange struct {
+	ID        uint  `gorm:"primaryKey" json:"id"`
+	ProductID uint  `gorm:"not null;index:idx_price_changes_product" json:"product_id"`
+	OptionID  *uint `gorm:"index" json:"option_id,omitempty"`
+	// ModifierType is the new price modifier type of an option.
+	ModifierType string  `gorm:"size:16" json:"modifier_type,omitempty"`
+	OldPrice     float64 `json:"old_price"`
+	NewPrice     float64 `json:"new_price"`
+	Reason       string  `gorm:"size:16;not null" json:"reason"`
+	// ScheduleID is the price schedule that made the change, if any.
+	ScheduleID *uint     `json:"schedule_id,omitempty"`
+	CreatedAt  time.Time `gorm:"index:idx_price_changes_product" json:"created_at"`
+}
+
+// PriceSchedule is a price change or sale planned by an admin. Price is the
+// new base price, the new modifier value of the option OptionID, or the
+// sale price; sales only apply to the base price.
+type PriceSchedule struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	ProductID uint       `gorm:"not null;index" json:"product_id"`
+	OptionID  *uint      `json:"option_id,omitempty"`
+	Kind      string     `gorm:"size:8;not null" json:"kind"`
+	Price     float64    `gorm:"not null" json:"price"`
+	StartsAt  time.Time  `gorm:"not null;index" json:"starts_at"`
+	EndsAt    *time.Time `gorm:"index" json:"ends_at"`
+	Status    string     `gorm:"size:16;not null;default:'pending';index" json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Validate checks a new schedule at now; a missing start means now.
+func (s *PriceSchedule) Validate(now time.Time) error {
+	if s.StartsAt.IsZero() {
+		s.StartsAt = now
+	}
+	if s.Price < 0 {
+		return fmt.Errorf("%w: price must not be negative", ErrInvalidPriceSchedule)
+	}
+	switch s.Kind {
+	case PriceScheduleChange:
+		if s.EndsAt != nil {
+			return fmt.Errorf("%w: a price change has no end", ErrInvalidPriceSchedule)
+		}
+	case PriceScheduleSale:
+		if s.OptionID != nil {
+			return fmt.Errorf("%w: sales apply to the base price", ErrInvalidPriceSchedule)
+		}
+		if s.EndsAt == nil || !s.EndsAt.After(s.StartsAt) || !s.EndsAt.After(now) {
+			return fmt.Errorf("%w: a sale needs an end after its start and after now", ErrInvalidPriceSchedule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidPriceSchedule, s.Kind)
+	}
+	return nil
+}
+
+// Overlaps reports whether two sales run at the same time.
+func (s *PriceSchedule) Overlaps(o *PriceSchedule) bool {
+	return s.Kind == PriceScheduleSale && o.Kind == PriceScheduleSale &&
+		s.StartsAt.Before(*o.EndsAt) && o.StartsAt.Before(*s.EndsAt)
+}
+
+// Price is what the product sells for without options: its sale price
+// while a sale runs, its base price otherwise. SalePrice and SaleEndsAt are
+// only set and cleared by price schedules.
+func (p *Product) Price() float64 {
+	if p.SalePrice != nil {
+		return *p.SalePrice
+	}
+	return p.BasePrice
+}
@@ -1,14 +1,22 @@
 package catalog
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Product struct {
 	ID                     uint            `gorm:"primaryKey" json:"id"`
 	CategoryID             uint            `json:"category_id"`
+	SKU                    string          `gorm:"size:64;not null;default:'';index:idx_products_sku,unique,where:sku <> ''" json:"sku"`
 	Name                   string          `json:"name"`
+	Slug                   string          `gorm:"size:160;not null;default:'';index:idx_products_slug,unique,where:slug <> ''" json:"slug"`
 	ShortDescription       string          `json:"short_description"`
 	LongDescription        string          `json:"long_description"`
 	BasePrice              float64         `json:"base_price"`
+	SalePrice              *float64        `json:"sale_price"`
+	SaleEndsAt             *time.Time      `json:"sale_ends_at"`
 	BaseProductionTimeDays int             `json:"base_production_time_days"`
 	ImageURL               string          `json:"image_url"`
 	BaseMaterial           string          `json:"base_material"`
@@ -16,9 +24,24 @@ type Product struct {
 	DefaultWidth           int             `json:"default_width"`
 	DefaultHeight          int             `json:"default_height"`
 	DefaultDepth           int             `json:"default_depth"`
+	SEOTitle               string          `gorm:"size:255" json:"seo_title"`
+	SEODescription         string          `gorm:"size:500" json:"seo_description"`
+	Status                 string          `gorm:"size:16;not null;default:'published';index" json:"status"`
+	PublishAt              *time.Time      `json:"publish_at"`
+	UnpublishAt            *time.Time      `json:"unpublish_at"`
+	RatingAverage          float64         `gorm:"not null;default:0" json:"rating_average"`
+	RatingCount            int             `gorm:"not null;default:0" json:"rating_count"`
 	CreatedAt              time.Time       `json:"created_at"`
 	UpdatedAt              time.Time       `json:"updated_at"`
+	DeletedAt              gorm.DeletedAt  `gorm:"index" json:"deleted_at"`
 	Options                []ProductOption `json:"options"`
+	Images                 []ProductImage  `gorm:"constraint:OnDelete:CASCADE" json:"images,omitempty"`
+	// Attributes are the product's specifications, with their definitions
+	// in the order of the category's attributes.
+	Attributes []ProductAttribute `gorm:"constraint:OnDelete:CASCADE" json:"attributes,omitempty"`
+	// Bundle is set on products sold as a set of other products; their
+	// price and quantity then follow from the components.
+	Bundle *Bundle `gorm:"constraint:OnDelete:CASCADE" json:"bundle,omitempty"`
 }
 
 type ProductOption struct {
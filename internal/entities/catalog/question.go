@@ -0,0 +1,87 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Question and answer moderation statuses
+const (
+	QuestionPending  = "pending"
+	QuestionApproved = "approved"
+	QuestionRejected = "rejected"
+)
+
+// ErrInvalidQuestion rejects a question or answer that is empty or too long.
+var ErrInvalidQuestion = errors.New("invalid question")
+
+// ErrNotVerifiedBuyer refuses answers from customers who have not received
+// the product.
+var ErrNotVerifiedBuyer = errors.New("only customers who received this product can answer")
+
+// Question is a customer's question about a product. Questions and their
+// answers are public once approved, under AuthorName; UserID is never
+// serialized.
+type Question struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	ProductID   uint       `gorm:"not null;index" json:"product_id"`
+	UserID      uint       `gorm:"not null;index" json:"-"`
+	AuthorName  string     `gorm:"size:120" json:"author_name"`
+	Body        string     `gorm:"not null" json:"body"`
+	Status      string     `gorm:"size:16;not null;default:'pending';index" json:"status"`
+	ModeratedAt *time.Time `json:"moderated_at,omitempty"`
+	Answers     []Answer   `gorm:"constraint:OnDelete:CASCADE" json:"answers"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// Product is loaded for the moderation queue only.
+	Product *Product `gorm:"constraint:OnDelete:CASCADE" json:"product,omitempty"`
+}
+
+// Answer replies to a question, either officially by the shop or by a
+// customer who received the product. The asker is told about it once it is
+// approved; NotifiedAt keeps that to a single message.
+type Answer struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	QuestionID  uint       `gorm:"not null;index" json:"question_id"`
+	UserID      uint       `gorm:"not null;index" json:"-"`
+	AuthorName  string     `gorm:"size:120" json:"author_name"`
+	Body        string     `gorm:"not null" json:"body"`
+	Official    bool       `gorm:"not null;default:false" json:"official"`
+	Verified    bool       `gorm:"not null;default:false" json:"verified"`
+	Status      string     `gorm:"size:16;not null;default:'pending';index" json:"status"`
+	ModeratedAt *time.Time `json:"moderated_at,omitempty"`
+	NotifiedAt  *time.Time `json:"-"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	// Question is loaded for the moderation queue only.
+	Question *Question `gorm:"constraint:OnDelete:CASCADE" json:"question,omitempty"`
+}
+
+// CheckQuestionText trims a question or answer and checks its length.
+func CheckQuestionText(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	switch n := utf8.RuneCountInString(s); {
+	case n < 3:
+		return s, fmt.Errorf("%w: text is too short", ErrInvalidQuestion)
+	case n > 2000:
+		return s, fmt.Errorf("%w: text is too long", ErrInvalidQuestion)
+	}
+	return s, nil
+}
+
+// QuestionFilter narrows question listings.
+type QuestionFilter struct {
+	ProductID uint
+	UserID    uint
+	// Status keeps questions in this moderation state; empty means any.
+	Status string
+	// AnswerStatus selects the answers loaded with the questions; empty
+	// means all of them.
+	AnswerStatus string
+	// Answered keeps questions with (true) or without (false) an approved
+	// answer.
+	Answered *bool
+}
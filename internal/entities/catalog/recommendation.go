@@ -0,0 +1,48 @@
+package catalog
+
+import "time"
+
+// Recommendation kinds
+const (
+	RecommendBoughtTogether = "bought_together"
+	RecommendViewedTogether = "viewed_together"
+	// RecommendSimilar is the fallback: popular products of the same category.
+	RecommendSimilar = "similar"
+)
+
+// Recommendation override actions
+const (
+	OverridePin     = "pin"
+	OverrideExclude = "exclude"
+)
+
+// ProductAssociation is a precomputed item-to-item recommendation. Support is
+// the number of orders (or sessions) containing both products and Score their
+// cosine similarity.
+type ProductAssociation struct {
+	ProductID        uint      `gorm:"primaryKey;autoIncrement:false" json:"product_id"`
+	RelatedProductID uint      `gorm:"primaryKey;autoIncrement:false" json:"related_product_id"`
+	Kind             string    `gorm:"primaryKey;size:32" json:"kind"`
+	Support          int       `json:"support"`
+	Score            float64   `json:"score"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// RecommendationOverride pins a product to the top of another product's
+// recommendations or excludes it from them. ProductID 0 applies the override
+// to every product.
+type RecommendationOverride struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	ProductID        uint      `gorm:"uniqueIndex:idx_recommendation_override" json:"product_id"`
+	RelatedProductID uint      `gorm:"uniqueIndex:idx_recommendation_override" json:"related_product_id"`
+	Action           string    `gorm:"size:16" json:"action"`
+	Position         int       `json:"position"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// RecommendationRefresh reports what a recommendation refresh did.
+type RecommendationRefresh struct {
+	BoughtTogether int64 `json:"bought_together"`
+	ViewedTogether int64 `json:"viewed_together"`
+}
@@ -0,0 +1,101 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Review moderation statuses
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// Review listing sort keys
+const (
+	ReviewSortNewest     = "newest"
+	ReviewSortOldest     = "oldest"
+	ReviewSortRatingDesc = "rating_desc"
+	ReviewSortRatingAsc  = "rating_asc"
+)
+
+// MaxReviewPhotos bounds the photos attached to one review.
+const MaxReviewPhotos = 4
+
+// ErrInvalidReview rejects a review that is incomplete or out of bounds.
+var ErrInvalidReview = errors.New("invalid review")
+
+// Review is a customer's rating of a product, one per customer and product.
+// Reviews are public once approved, under AuthorName; the author's UserID is
+// never serialized. Verified marks authors who received the product in a
+// delivered order.
+type Review struct {
+	ID             uint          `gorm:"primaryKey" json:"id"`
+	ProductID      uint          `gorm:"not null;uniqueIndex:idx_reviews_product_user" json:"product_id"`
+	UserID         uint          `gorm:"not null;uniqueIndex:idx_reviews_product_user;index" json:"-"`
+	Rating         int           `gorm:"not null" json:"rating"`
+	Title          string        `gorm:"size:120" json:"title"`
+	Body           string        `json:"body"`
+	AuthorName     string        `gorm:"size:120" json:"author_name"`
+	Verified       bool          `gorm:"not null;default:false" json:"verified"`
+	Status         string        `gorm:"size:16;not null;default:'pending';index" json:"status"`
+	ModerationNote string        `gorm:"size:500" json:"moderation_note,omitempty"`
+	ModeratedAt    *time.Time    `json:"moderated_at,omitempty"`
+	Photos         []ReviewPhoto `gorm:"constraint:OnDelete:CASCADE" json:"photos"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+	// Product is loaded for the moderation queue only.
+	Product *Product `gorm:"constraint:OnDelete:CASCADE" json:"product,omitempty"`
+}
+
+// ReviewPhoto is an image attached to a review.
+type ReviewPhoto struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ReviewID  uint      `gorm:"not null;index" json:"review_id"`
+	URL       string    `gorm:"not null" json:"url"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Validate checks the rating, the text lengths and the number of photos.
+func (r *Review) Validate() error {
+	r.Title = strings.TrimSpace(r.Title)
+	r.Body = strings.TrimSpace(r.Body)
+	switch {
+	case r.Rating < 1 || r.Rating > 5:
+		return fmt.Errorf("%w: rating must be between 1 and 5", ErrInvalidReview)
+	case utf8.RuneCountInString(r.Title) > 120:
+		return fmt.Errorf("%w: title is too long", ErrInvalidReview)
+	case utf8.RuneCountInString(r.Body) > 5000:
+		return fmt.Errorf("%w: text is too long", ErrInvalidReview)
+	case len(r.Photos) > MaxReviewPhotos:
+		return fmt.Errorf("%w: at most %d photos", ErrInvalidReview, MaxReviewPhotos)
+	}
+	return nil
+}
+
+// ReviewFilter narrows and orders review listings.
+type ReviewFilter struct {
+	ProductID uint
+	UserID    uint
+	// Status keeps reviews in this moderation state; empty means any.
+	Status   string
+	Rating   *int
+	Verified *bool
+	// WithPhotos keeps reviews with at least one photo.
+	WithPhotos bool
+	Sort       string
+}
+
+// RatingSummary aggregates the approved reviews of a product. Stars counts
+// the reviews per rating, one star first.
+type RatingSummary struct {
+	Average  float64 `json:"average"`
+	Count    int     `json:"count"`
+	Verified int     `json:"verified"`
+	Stars    [5]int  `json:"stars"`
+}
@@ -0,0 +1,68 @@
+package catalog
+
+import "fmt"
+
+// ProductSearchParams is a full-text product query with optional facet filters.
+type ProductSearchParams struct {
+	Query        string
+	DepartmentID uint
+	CategoryID   uint
+	Material     string
+	PriceMin     *float64
+	PriceMax     *float64
+	Limit        int
+	Offset       int
+	// Locale selects the translations highlighted and returned.
+	Locale string
+}
+
+// ProductSearchHit is a matching product with its relevance and its name and
+// description as HTML, escaped, with the matched words wrapped in <mark> tags.
+type ProductSearchHit struct {
+	Product
+	Rank                 float64 `json:"rank"`
+	NameHighlight        string  `json:"name_highlight"`
+	DescriptionHighlight string  `json:"description_highlight"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+// SearchFacets counts matches per facet value. Each facet ignores its own
+// filter so that the alternatives to the current selection stay visible.
+type SearchFacets struct {
+	Departments []FacetCount `json:"departments"`
+	Categories  []FacetCount `json:"categories"`
+	Materials   []FacetCount `json:"materials"`
+	PriceRanges []FacetCount `json:"price_ranges"`
+}
+
+type ProductSearchResult struct {
+	Query string `json:"query"`
+	// Corrections maps misspelled query words to the indexed words searched instead.
+	Corrections map[string]string  `json:"corrections,omitempty"`
+	Total       int64              `json:"total"`
+	Limit       int                `json:"limit"`
+	Offset      int                `json:"offset"`
+	Hits        []ProductSearchHit `json:"hits"`
+	Facets      SearchFacets       `json:"facets"`
+}
+
+// PriceBuckets are the price range facet boundaries; 0 as the upper bound means open-ended.
+var PriceBuckets = [][2]float64{{0, 250}, {250, 500}, {500, 1000}, {1000, 2000}, {2000, 0}}
+
+// PriceBucket labels a price with its bucket, e.g. "250-500" or "2000-".
+func PriceBucket(price float64) string {
+	for _, bk := range PriceBuckets {
+		if bk[1] == 0 {
+			return fmt.Sprintf("%g-", bk[0])
+		}
+		if price < bk[1] {
+			return fmt.Sprintf("%g-%g", bk[0], bk[1])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package catalog
+
+import "time"
+
+// SitemapURL is one <url> entry of the storefront sitemap.
+type SitemapURL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// ProductLD is schema.org Product structured data, served as JSON-LD for
+// the storefront to embed in product pages.
+type ProductLD struct {
+	Context     string   `json:"@context"`
+	Type        string   `json:"@type"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	SKU         string   `json:"sku,omitempty"`
+	Image       []string `json:"image,omitempty"`
+	URL         string   `json:"url"`
+	Material    string   `json:"material,omitempty"`
+	Offers      OfferLD  `json:"offers"`
+}
+
+type OfferLD struct {
+	Type          string `json:"@type"`
+	URL           string `json:"url"`
+	Price         string `json:"price"`
+	PriceCurrency string `json:"priceCurrency"`
+	Availability  string `json:"availability"`
+}
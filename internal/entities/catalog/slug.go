@@ -0,0 +1,56 @@
+package catalog
+
+import (
+	"strings"
+	"time"
+)
+
+// Slug kinds: the record types that have slugs.
+const (
+	SlugDepartment = "department"
+	SlugCategory   = "category"
+	SlugProduct    = "product"
+)
+
+// SlugRedirect remembers a slug a record used to have, so old links keep
+// resolving after a rename.
+type SlugRedirect struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Kind      string    `gorm:"size:16;not null;uniqueIndex:idx_slug_redirects_kind_slug" json:"kind"`
+	Slug      string    `gorm:"size:160;not null;uniqueIndex:idx_slug_redirects_kind_slug" json:"slug"`
+	TargetID  uint      `gorm:"not null;index" json:"target_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SlugRef is a record a path reference resolved to. Moved is set when the
+// reference was a former slug; Slug is always the current one.
+type SlugRef struct {
+	ID    uint
+	Slug  string
+	Moved bool
+}
+
+// Slugify lowercases s and keeps ASCII letters and digits, joining words
+// with single dashes.
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range s {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			b.WriteRune(r + ('a' - 'A'))
+			dash = false
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+			dash = false
+		case r == ' ' || r == '_' || r == '-' || r == '/' || r == '+':
+			if !dash && b.Len() > 0 {
+				b.WriteByte('-')
+				dash = true
+			}
+		default:
+			// skip other symbols
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
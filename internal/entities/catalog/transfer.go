@@ -0,0 +1,137 @@
+package catalog
+
+import (
+	"errors"
+	"strings"
+)
+
+// Catalog import/export kinds; a CSV file holds one kind, a JSON document
+// all of them.
+const (
+	TransferDepartments = "departments"
+	TransferCategories  = "categories"
+	TransferProducts    = "products"
+	TransferOptions     = "options"
+)
+
+// ErrMissingSKU refuses an export holding products without a SKU, which an
+// import could not match back to them.
+var ErrMissingSKU = errors.New("products without a SKU cannot be exported")
+
+// CatalogDocument is the import/export format. Records refer to each other
+// by name and SKU instead of database ids, so a document can be moved
+// between installations: categories by department name, products by
+// department and category name, options by product SKU.
+type CatalogDocument struct {
+	Departments []DepartmentRecord `json:"departments"`
+	Categories  []CategoryRecord   `json:"categories"`
+	Products    []ProductRecord    `json:"products"`
+	Options     []OptionRecord     `json:"options"`
+}
+
+type DepartmentRecord struct {
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	ImageURL       string `json:"image_url"`
+	Slug           string `json:"slug"`
+	SEOTitle       string `json:"seo_title"`
+	SEODescription string `json:"seo_description"`
+}
+
+type CategoryRecord struct {
+	Department     string `json:"department"`
+	Name           string `json:"name"`
+	Description    string `json:"description"`
+	Slug           string `json:"slug"`
+	SEOTitle       string `json:"seo_title"`
+	SEODescription string `json:"seo_description"`
+}
+
+type ProductRecord struct {
+	SKU                    string  `json:"sku"`
+	Department             string  `json:"department"`
+	Category               string  `json:"category"`
+	Name                   string  `json:"name"`
+	ShortDescription       string  `json:"short_description"`
+	LongDescription        string  `json:"long_description"`
+	BasePrice              float64 `json:"base_price"`
+	BaseProductionTimeDays int     `json:"base_production_time_days"`
+	ImageURL               string  `json:"image_url"`
+	BaseMaterial           string  `json:"base_material"`
+	Quantity               int     `json:"quantity"`
+	DefaultWidth           int     `json:"default_width"`
+	DefaultHeight          int     `json:"default_height"`
+	DefaultDepth           int     `json:"default_depth"`
+	Slug                   string  `json:"slug"`
+	SEOTitle               string  `json:"seo_title"`
+	SEODescription         string  `json:"seo_description"`
+}
+
+type OptionRecord struct {
+	ProductSKU                    string  `json:"product_sku"`
+	OptionType                    string  `json:"option_type"`
+	OptionName                    string  `json:"option_name"`
+	PriceModifierType             string  `json:"price_modifier_type"`
+	PriceModifierValue            float64 `json:"price_modifier_value"`
+	ProductionTimeModifierDays    int     `json:"production_time_modifier_days"`
+	ProductionTimeModifierPercent *int    `json:"production_time_modifier_percent"`
+}
+
+// ImportError points at an invalid record; Row counts the records of a kind
+// from 1, not counting the CSV header.
+type ImportError struct {
+	Kind    string `json:"kind"`
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportCounts tells how many records of a kind create or update a row.
+type ImportCounts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// ImportReport is the outcome of an import. Nothing is written unless the
+// whole document is valid and it is not a dry run.
+type ImportReport struct {
+	DryRun      bool          `json:"dry_run"`
+	Applied     bool          `json:"applied"`
+	Errors      []ImportError `json:"errors"`
+	Departments ImportCounts  `json:"departments"`
+	Categories  ImportCounts  `json:"categories"`
+	Products    ImportCounts  `json:"products"`
+	Options     ImportCounts  `json:"options"`
+}
+
+// ImportPlan is a validated import: rows with an ID are updated, the others
+// created. References by name are resolved while it is applied, as they may
+// point at rows created by the same import.
+type ImportPlan struct {
+	Departments []Department
+	Categories  []PlannedCategory
+	Products    []PlannedProduct
+	Options     []PlannedOption
+}
+
+type PlannedCategory struct {
+	Category
+	Department string
+}
+
+type PlannedProduct struct {
+	Product
+	Department string
+	Category   string
+}
+
+type PlannedOption struct {
+	ProductOption
+	ProductSKU string
+}
+
+// NameKey is how imports match departments and categories by name: trimmed
+// and case insensitive.
+func NameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
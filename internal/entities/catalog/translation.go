@@ -0,0 +1,78 @@
+package catalog
+
+import (
+	"errors"
+	"time"
+)
+
+// TranslationOption is the translation kind of product options; departments,
+// categories and products use their slug kinds.
+const TranslationOption = "option"
+
+// ErrInvalidTranslation rejects a translation of an unknown kind or field,
+// or into a locale that is not offered.
+var ErrInvalidTranslation = errors.New("invalid translation")
+
+// TranslatableFields lists the text fields of each record kind that can be
+// translated, by json name.
+var TranslatableFields = map[string][]string{
+	SlugDepartment:       {"name", "description", "seo_title", "seo_description"},
+	SlugCategory:         {"name", "description", "seo_title", "seo_description"},
+	SlugProduct:          {"name", "short_description", "long_description", "seo_title", "seo_description"},
+	TranslationOption:    {"option_name"},
+	TranslationAttribute: {"name"},
+}
+
+// Translation is the text of one field of a catalog record in a locale other
+// than the default one, which lives on the record itself.
+type Translation struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Kind      string    `gorm:"size:16;not null;uniqueIndex:idx_catalog_translations_key,priority:1" json:"kind"`
+	RecordID  uint      `gorm:"not null;uniqueIndex:idx_catalog_translations_key,priority:2" json:"record_id"`
+	Locale    string    `gorm:"size:16;not null;uniqueIndex:idx_catalog_translations_key,priority:3" json:"locale"`
+	Field     string    `gorm:"size:32;not null;uniqueIndex:idx_catalog_translations_key,priority:4" json:"field"`
+	Value     string    `gorm:"type:text;not null" json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Translation) TableName() string { return "catalog_translations" }
+
+func (d *Department) TextFields() map[string]*string {
+	return map[string]*string{"name": &d.Name, "description": &d.Description, "seo_title": &d.SEOTitle, "seo_description": &d.SEODescription}
+}
+
+func (c *Category) TextFields() map[string]*string {
+	return map[string]*string{"name": &c.Name, "description": &c.Description, "seo_title": &c.SEOTitle, "seo_description": &c.SEODescription}
+}
+
+func (p *Product) TextFields() map[string]*string {
+	return map[string]*string{
+		"name":              &p.Name,
+		"short_description": &p.ShortDescription,
+		"long_description":  &p.LongDescription,
+		"seo_title":         &p.SEOTitle,
+		"seo_description":   &p.SEODescription,
+	}
+}
+
+func (o *ProductOption) TextFields() map[string]*string {
+	return map[string]*string{"option_name": &o.OptionName}
+}
+
+// Translate overlays the translations of one record on its text fields.
+// Locales are tried in order, so the first one with a value for a field
+// wins; fields without any keep their default-locale text.
+func Translate(fields map[string]*string, ts []Translation, locales []string) {
+	for field, dst := range fields {
+	next:
+		for _, locale := range locales {
+			for _, t := range ts {
+				if t.Locale == locale && t.Field == field && t.Value != "" {
+					*dst = t.Value
+					break next
+				}
+			}
+		}
+	}
+}
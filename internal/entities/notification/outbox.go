@@ -0,0 +1,41 @@
+package notification
+
+import "time"
+
+// Outbox message statuses
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSending = "sending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusDead    = "dead"
+)
+
+// Notification channels
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelPush  = "push"
+)
+
+// Channels lists every supported notification channel.
+var Channels = []string{ChannelEmail, ChannelSMS, ChannelPush}
+
+// OutboxMessage is a rendered notification waiting to be delivered by the
+// background dispatcher. Failed deliveries are retried with exponential backoff
+// until MaxAttempts is reached, after which the message is dead-lettered.
+type OutboxMessage struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	Channel       string     `json:"channel"`
+	Template      string     `json:"template"`
+	Recipient     string     `gorm:"index" json:"recipient"`
+	Subject       string     `json:"subject"`
+	TextBody      string     `json:"text_body"`
+	HTMLBody      string     `json:"html_body"`
+	Status        string     `gorm:"index" json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastError     string     `json:"last_error"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
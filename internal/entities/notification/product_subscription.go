@@ -0,0 +1,42 @@
+package notification
+
+import (
+	"errors"
+	"time"
+)
+
+// Product subscription kinds
+const (
+	SubscriptionBackInStock = "back_in_stock"
+	SubscriptionPriceDrop   = "price_drop"
+)
+
+// ErrInvalidSubscription rejects a subscription that cannot be fulfilled,
+// such as a back-in-stock alert for a product that is in stock.
+var ErrInvalidSubscription = errors.New("invalid subscription")
+
+// ProductSubscription asks for an email when a product, in the option
+// selection of SelectedOptionsJSON, comes back in stock or gets cheaper.
+// Guests subscribe with an email address only, and their subscription is
+// active once confirmed through the link emailed to that address; Token
+// identifies the subscription in confirmation and unsubscribe links.
+// Back-in-stock subscriptions end with their alert, price-drop
+// subscriptions last until cancelled.
+type ProductSubscription struct {
+	ID                  uint   `gorm:"primaryKey" json:"id"`
+	ProductID           uint   `gorm:"not null;uniqueIndex:idx_product_subscription" json:"product_id"`
+	Kind                string `gorm:"size:16;not null;uniqueIndex:idx_product_subscription" json:"kind"`
+	SelectedOptionsJSON string `gorm:"not null;uniqueIndex:idx_product_subscription" json:"selected_options_json"`
+	Email               string `gorm:"size:255;not null;uniqueIndex:idx_product_subscription" json:"email"`
+	UserID              *uint  `gorm:"index" json:"user_id,omitempty"`
+	Locale              string `gorm:"size:8" json:"locale"`
+	Token               string `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	// AlertPrice is the price a price drop is measured against: the price
+	// when subscribing, lowered by every alert.
+	AlertPrice  float64    `json:"alert_price"`
+	ConfirmedAt *time.Time `gorm:"index" json:"confirmed_at"`
+	// ConfirmationSentAt is when the last confirmation email was queued.
+	ConfirmationSentAt *time.Time `json:"-"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
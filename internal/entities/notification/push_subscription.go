@@ -0,0 +1,17 @@
+package notification
+
+import "time"
+
+// PushSubscription is a browser Web Push subscription of a user. Endpoint is
+// the push service URL, P256dh and Auth are the base64url encoded client keys
+// used to encrypt payloads (RFC 8291).
+type PushSubscription struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"index" json:"user_id"`
+	Endpoint  string    `gorm:"uniqueIndex" json:"endpoint"`
+	P256dh    string    `json:"-"`
+	Auth      string    `json:"-"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
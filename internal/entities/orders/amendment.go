@@ -0,0 +1,65 @@
+package orders
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidAmendment rejects an amendment that cannot be applied.
+	ErrInvalidAmendment = errors.New("invalid amendment")
+	// ErrOrderNotAmendable rejects an amendment of an order that has moved
+	// past the statuses in which its lines may be edited.
+	ErrOrderNotAmendable = errors.New("order can no longer be changed")
+	// ErrAmendmentConflict rejects an amendment of an order that was changed
+	// after the amendment was computed.
+	ErrAmendmentConflict = errors.New("order was changed meanwhile; reload it and try again")
+)
+
+// amendableStatuses lists the order statuses in which lines may still be edited,
+// i.e. before production has started. Paid orders stay processing until an
+// admin moves them to in_production.
+var amendableStatuses = map[string]bool{
+	OrderStatusNew:        true,
+	OrderStatusProcessing: true,
+}
+
+// Amendable reports whether the lines of an order in status may still be edited.
+func Amendable(status string) bool {
+	return amendableStatuses[status]
+}
+
+// OrderAmendment records a single edit of an order's lines together with the
+// resulting price/ETA change and how the price difference was settled.
+type OrderAmendment struct {
+	ID                  uint      `gorm:"primaryKey" json:"id"`
+	OrderID             uint      `gorm:"index" json:"order_id"`
+	ActorUserID         uint      `json:"actor_user_id"`
+	ActorRole           string    `json:"actor_role"`
+	Note                string    `json:"note"`
+	ChangesJSON         string    `json:"changes_json"`
+	PreviousTotal       float64   `json:"previous_total"`
+	NewTotal            float64   `json:"new_total"`
+	PriceDifference     float64   `json:"price_difference"`
+	PreviousETADays     int       `json:"previous_eta_days"`
+	NewETADays          int       `json:"new_eta_days"`
+	SettlementType      string    `json:"settlement_type"`
+	SettlementStatus    string    `json:"settlement_status"`
+	SettlementReference string    `json:"settlement_reference"`
+	CheckoutURL         string    `json:"checkout_url"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// OrderAmendmentChange describes one line-level change stored in ChangesJSON.
+type OrderAmendmentChange struct {
+	Action          string  `json:"action"`
+	ItemID          uint    `json:"item_id,omitempty"`
+	ProductID       uint    `json:"product_id"`
+	BeforeQuantity  int     `json:"before_quantity,omitempty"`
+	AfterQuantity   int     `json:"after_quantity,omitempty"`
+	BeforeOptions   string  `json:"before_options,omitempty"`
+	AfterOptions    string  `json:"after_options,omitempty"`
+	BeforeLineTotal float64 `json:"before_line_total,omitempty"`
+	AfterLineTotal  float64 `json:"after_line_total,omitempty"`
+}
@@ -18,7 +18,43 @@ const (
 	PaymentStatusDeclined  = "declined"
 )
 
+// Order listing sort keys
+const (
+	OrderSortNewest    = "newest"
+	OrderSortOldest    = "oldest"
+	OrderSortTotalDesc = "total_desc"
+	OrderSortTotalAsc  = "total_asc"
+)
+
 // Payment methods
 const (
 	PaymentMethodCard = "card"
 )
+
+// Order amendment line actions
+const (
+	AmendmentActionAdd    = "add"
+	AmendmentActionUpdate = "update"
+	AmendmentActionRemove = "remove"
+)
+
+// Order amendment settlement types
+const (
+	SettlementTypeNone   = "none"
+	SettlementTypeCharge = "charge"
+	SettlementTypeRefund = "refund"
+)
+
+// Order amendment settlement statuses
+const (
+	SettlementStatusNotRequired = "not_required"
+	SettlementStatusPending     = "pending"
+	SettlementStatusSettled     = "settled"
+	SettlementStatusFailed      = "failed"
+)
+
+// Invoice kinds
+const (
+	InvoiceKindInvoice    = "invoice"
+	InvoiceKindCreditNote = "credit_note"
+)
@@ -0,0 +1,14 @@
+package orders
+
+import "time"
+
+// OrderFilter narrows and orders order listings. From and To bound the
+// creation time; To is exclusive.
+type OrderFilter struct {
+	Status        string
+	PaymentStatus string
+	UserID        uint
+	From          *time.Time
+	To            *time.Time
+	Sort          string
+}
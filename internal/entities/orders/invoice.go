@@ -0,0 +1,48 @@
+package orders
+
+import "time"
+
+// Invoice is an immutable fiscal document issued for an order. Credit notes use
+// the same table with Kind set to InvoiceKindCreditNote; their amounts are stored
+// as positive values.
+type Invoice struct {
+	ID               uint          `gorm:"primaryKey" json:"id"`
+	Number           string        `gorm:"uniqueIndex" json:"number"`
+	Kind             string        `gorm:"index" json:"kind"`
+	Year             int           `json:"year"`
+	Sequence         int           `json:"sequence"`
+	OrderID          uint          `gorm:"index;index:idx_invoices_order_invoice,unique,where:kind = 'invoice' AND amendment_id IS NULL" json:"order_id"`
+	UserID           uint          `gorm:"index" json:"user_id"`
+	AmendmentID      *uint         `gorm:"uniqueIndex" json:"amendment_id"`
+	RelatedInvoiceID *uint         `json:"related_invoice_id"`
+	BuyerName        string        `json:"buyer_name"`
+	BuyerEmail       string        `json:"buyer_email"`
+	BuyerAddress     string        `json:"buyer_address"`
+	Currency         string        `json:"currency"`
+	Subtotal         float64       `json:"subtotal"`
+	TaxRate          float64       `json:"tax_rate"`
+	TaxAmount        float64       `json:"tax_amount"`
+	Total            float64       `json:"total"`
+	IssuedAt         time.Time     `gorm:"index" json:"issued_at"`
+	Lines            []InvoiceLine `json:"lines"`
+	CreatedAt        time.Time     `json:"created_at"`
+}
+
+type InvoiceLine struct {
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	InvoiceID   uint    `gorm:"index" json:"invoice_id"`
+	ProductID   uint    `json:"product_id"`
+	Description string  `json:"description"`
+	Options     string  `json:"options"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	LineTotal   float64 `json:"line_total"`
+}
+
+// InvoiceSequence holds the last number handed out per document kind and year.
+// Rows are locked while issuing so numbering stays sequential and gap-free.
+type InvoiceSequence struct {
+	Kind       string `gorm:"primaryKey"`
+	Year       int    `gorm:"primaryKey;autoIncrement:false"`
+	LastNumber int
+}
@@ -10,20 +10,27 @@ type Order struct {
 	EstimatedProductionTimeDays int         `json:"estimated_production_time_days"`
 	PaymentMethod               string      `json:"payment_method"`
 	PaymentStatus               string      `json:"payment_status"`
+	PaymentReference            string      `json:"payment_reference"`
+	DeliveryDate                *time.Time  `json:"delivery_date"`
+	DeliveryReminderSentAt      *time.Time  `json:"delivery_reminder_sent_at"`
 	CreatedAt                   time.Time   `json:"created_at"`
 	UpdatedAt                   time.Time   `json:"updated_at"`
 	Items                       []OrderItem `json:"items"`
 }
 
 type OrderItem struct {
-	ID                           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
-	OrderID                      uint      `json:"order_id"`
-	ProductID                    uint      `json:"product_id"`
-	Quantity                     int       `json:"quantity"`
-	UnitPrice                    float64   `json:"unit_price"`
-	LineTotal                    float64   `json:"line_total"`
-	CalculatedProductionTimeDays int       `json:"calculated_production_time_days"`
-	SelectedOptionsJSON          string    `json:"selected_options_json"`
-	CreatedAt                    time.Time `json:"created_at"`
-	UpdatedAt                    time.Time `json:"updated_at"`
+	ID                           uint    `gorm:"primaryKey;autoIncrement" json:"id"`
+	OrderID                      uint    `json:"order_id"`
+	ProductID                    uint    `json:"product_id"`
+	Quantity                     int     `json:"quantity"`
+	UnitPrice                    float64 `json:"unit_price"`
+	LineTotal                    float64 `json:"line_total"`
+	CalculatedProductionTimeDays int     `json:"calculated_production_time_days"`
+	SelectedOptionsJSON          string  `json:"selected_options_json"`
+	// BundleProductID is set on the component lines of an ordered bundle;
+	// BundleGroup tells the bundles of an order apart.
+	BundleProductID *uint     `gorm:"index" json:"bundle_product_id,omitempty"`
+	BundleGroup     int       `json:"bundle_group,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
@@ -0,0 +1,44 @@
+package orders
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInvalidWishlist rejects a wishlist without a usable name or an item of
+// a product that cannot be ordered.
+var ErrInvalidWishlist = errors.New("invalid wishlist")
+
+// Wishlist is a named list of saved product configurations. Users keep any
+// number of them; a wishlist with a ShareToken can be read by anyone with
+// the link.
+type Wishlist struct {
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	UserID     uint           `gorm:"not null;index" json:"user_id"`
+	Name       string         `gorm:"size:120;not null" json:"name"`
+	ShareToken *string        `gorm:"size:64;uniqueIndex" json:"share_token,omitempty"`
+	Items      []WishlistItem `gorm:"constraint:OnDelete:CASCADE" json:"items"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+// WishlistItem is a product with its option selection, stored in the same
+// normalized form as cart items.
+type WishlistItem struct {
+	ID                  uint   `gorm:"primaryKey" json:"id"`
+	WishlistID          uint   `gorm:"not null;index" json:"wishlist_id"`
+	ProductID           uint   `gorm:"not null;index" json:"product_id"`
+	Quantity            int    `gorm:"not null;default:1" json:"quantity"`
+	SelectedOptionsJSON string `json:"selected_options_json"`
+	// AlertPrice is the lowest configured price the owner has seen: the
+	// price when the item was saved, lowered by every price-drop alert.
+	AlertPrice float64   `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	// UnitPrice and Available describe the configuration at the current
+	// catalog price; they are filled in when the wishlist is read.
+	UnitPrice float64 `gorm:"-" json:"unit_price"`
+	Available bool    `gorm:"-" json:"available"`
+	// Wishlist is loaded when alerting about a product.
+	Wishlist *Wishlist `gorm:"constraint:OnDelete:CASCADE" json:"wishlist,omitempty"`
+}
@@ -1,21 +1,26 @@
 package user
 
 import (
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type User struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	Role         string    `json:"role"`
-	Name         string    `json:"name"`
-	Email        string    `gorm:"uniqueIndex" json:"email"`
-	PasswordHash string    `json:"-"`
-	Address      string    `json:"address"`
-	Phone        string    `json:"phone"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	Role         string `json:"role"`
+	Name         string `json:"name"`
+	Email        string `gorm:"uniqueIndex" json:"email"`
+	PasswordHash string `json:"-"`
+	Address      string `json:"address"`
+	Phone        string `json:"phone"`
+	Locale       string `gorm:"default:en" json:"locale"`
+	// NotifyChannels is the comma separated list of channels the user wants
+	// to be notified on; see Channels.
+	NotifyChannels string    `gorm:"default:email" json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
 
 func (u *User) SetPassword(plain string) error {
@@ -30,3 +35,27 @@ func (u *User) SetPassword(plain string) error {
 func (u *User) CheckPassword(plain string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(plain)) == nil
 }
+
+// Channels returns the notification channels the user opted into.
+func (u *User) Channels() []string {
+	var out []string
+	for _, c := range strings.Split(u.NotifyChannels, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (u *User) SetChannels(channels []string) {
+	u.NotifyChannels = strings.Join(channels, ",")
+}
+
+func (u *User) HasChannel(channel string) bool {
+	for _, c := range u.Channels() {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+// Package jobs runs periodic background work (outbox dispatch, schedulers, ...)
+// next to the HTTP server.
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a unit of periodic work. Run is called every Interval until the
+// runner's context is cancelled; an error is logged and the job keeps running.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Runner struct {
+	jobs []Job
+}
+
+func NewRunner(jobs ...Job) *Runner {
+	return &Runner{jobs: jobs}
+}
+
+// Add registers another job; it must be called before Start.
+func (r *Runner) Add(j Job) {
+	r.jobs = append(r.jobs, j)
+}
+
+// Start launches every job in its own goroutine and returns immediately.
+func (r *Runner) Start(ctx context.Context) {
+	for _, j := range r.jobs {
+		go run(ctx, j)
+	}
+}
+
+func run(ctx context.Context, j Job) {
+	t := time.NewTicker(j.Interval)
+	defer t.Stop()
+	for {
+		if err := j.Run(ctx); err != nil {
+			log.Printf("job %s: %v", j.Name, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
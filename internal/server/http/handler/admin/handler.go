@@ -1,44 +1,38 @@
 package admin
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 
 	admin_dto "furniture-shop/internal/dtos/admin"
 	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/server/http/params"
 	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/blob"
+	"furniture-shop/internal/storage/query"
 	vld "furniture-shop/internal/validation"
 )
 
-func normalizeImageURL(u string) string {
-	s := strings.TrimSpace(u)
-	if s == "" {
-		return s
-	}
-	if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
-		if i := strings.Index(s, "/uploads/"); i >= 0 {
-			return s[i:]
-		}
-	}
-	return s
-}
-
 type Handler struct {
-	svc service.AdminService
+	svc   service.AdminService
+	blobs blob.Store
 }
 
-func NewAdminHandler(svc service.AdminService) *Handler {
-	return &Handler{svc: svc}
+func NewAdminHandler(svc service.AdminService, blobs blob.Store) *Handler {
+	return &Handler{svc: svc, blobs: blobs}
 }
 
 func (h *Handler) ListDepartments() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		items, err := h.svc.ListDepartments(c.Context())
+		list := h.svc.ListDepartments
+		if c.QueryBool("archived") {
+			list = h.svc.ListArchivedDepartments
+		}
+		items, err := list(c.Context())
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
@@ -56,7 +50,7 @@ func (h *Handler) CreateDepartment() fiber.Handler {
 		if err := vld.ValidateStruct(in); err != nil {
 			return err
 		}
-		dep := ec.Department{Name: in.Name, Description: in.Description, ImageURL: in.ImageURL}
+		dep := ec.Department{Name: in.Name, Description: in.Description, ImageURL: in.ImageURL, Slug: in.Slug, SEOTitle: in.SEOTitle, SEODescription: in.SEODescription}
 		if err := h.svc.CreateDepartment(c.Context(), &dep); err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
@@ -75,29 +69,50 @@ func (h *Handler) UpdateDepartment() fiber.Handler {
 		}
 		var id uint
 		_, _ = fmt.Sscan(c.Params("id"), &id)
-		if err := h.svc.UpdateDepartment(c.Context(), id, ec.Department{Name: in.Name, Description: in.Description, ImageURL: in.ImageURL}); err != nil {
+		if err := h.svc.UpdateDepartment(c.Context(), id, ec.Department{Name: in.Name, Description: in.Description, ImageURL: in.ImageURL, Slug: in.Slug, SEOTitle: in.SEOTitle, SEODescription: in.SEODescription}); err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
 		return c.JSON(fiber.Map{"message": "updated"})
 	}
 }
 
+// DeleteDepartment archives a department. One with categories answers 409
+// unless ?cascade=true confirms archiving its categories and products too.
 func (h *Handler) DeleteDepartment() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var id uint
 		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
 			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
 		}
-		if err := h.svc.DeleteDepartment(c.Context(), id); err != nil {
-			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		if err := h.svc.DeleteDepartment(c.Context(), id, c.QueryBool("cascade")); err != nil {
+			return archiveError(c, err)
 		}
-		return c.JSON(fiber.Map{"message": "deleted"})
+		return c.JSON(fiber.Map{"message": "archived"})
+	}
+}
+
+// RestoreDepartment brings an archived department back; with ?cascade=true
+// also the categories and products archived together with it.
+func (h *Handler) RestoreDepartment() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.RestoreDepartment(c.Context(), id, c.QueryBool("cascade")); err != nil {
+			return archiveError(c, err)
+		}
+		return c.JSON(fiber.Map{"message": "restored"})
 	}
 }
 
 func (h *Handler) ListCategories() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		items, err := h.svc.ListCategories(c.Context())
+		list := h.svc.ListCategories
+		if c.QueryBool("archived") {
+			list = h.svc.ListArchivedCategories
+		}
+		items, err := list(c.Context())
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
@@ -115,7 +130,7 @@ func (h *Handler) CreateCategory() fiber.Handler {
 		if err := vld.ValidateStruct(in); err != nil {
 			return err
 		}
-		cat := ec.Category{DepartmentID: in.DepartmentID, Name: in.Name, Description: in.Description}
+		cat := ec.Category{DepartmentID: in.DepartmentID, Name: in.Name, Description: in.Description, Slug: in.Slug, SEOTitle: in.SEOTitle, SEODescription: in.SEODescription}
 		if err := h.svc.CreateCategory(c.Context(), &cat); err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
@@ -136,29 +151,68 @@ func (h *Handler) UpdateCategory() fiber.Handler {
 		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
 			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
 		}
-		if err := h.svc.UpdateCategory(c.Context(), id, ec.Category{DepartmentID: in.DepartmentID, Name: in.Name, Description: in.Description}); err != nil {
+		if err := h.svc.UpdateCategory(c.Context(), id, ec.Category{DepartmentID: in.DepartmentID, Name: in.Name, Description: in.Description, Slug: in.Slug, SEOTitle: in.SEOTitle, SEODescription: in.SEODescription}); err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
 		return c.JSON(fiber.Map{"message": "updated"})
 	}
 }
 
+// DeleteCategory archives a category. One with products answers 409 unless
+// ?cascade=true confirms archiving them too.
 func (h *Handler) DeleteCategory() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var id uint
 		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
 			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
 		}
-		if err := h.svc.DeleteCategory(c.Context(), id); err != nil {
-			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		if err := h.svc.DeleteCategory(c.Context(), id, c.QueryBool("cascade")); err != nil {
+			return archiveError(c, err)
 		}
-		return c.JSON(fiber.Map{"message": "deleted"})
+		return c.JSON(fiber.Map{"message": "archived"})
 	}
 }
 
+func (h *Handler) RestoreCategory() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.RestoreCategory(c.Context(), id, c.QueryBool("cascade")); err != nil {
+			return archiveError(c, err)
+		}
+		return c.JSON(fiber.Map{"message": "restored"})
+	}
+}
+
+var productStatuses = map[string]bool{
+	ec.ProductDraft:        true,
+	ec.ProductScheduled:    true,
+	ec.ProductPublished:    true,
+	ec.ProductDiscontinued: true,
+}
+
+// ListProducts takes the catalog product filters plus ?category_id= and
+// ?status=.
 func (h *Handler) ListProducts() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		items, err := h.svc.ListProducts(c.Context())
+		f, err := params.ProductFilter(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		if f.CategoryID, err = params.Uint(c, "category_id"); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		f.Archived = c.QueryBool("archived")
+		f.Status = c.Query("status")
+		if f.Status != "" && !productStatuses[f.Status] {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid status"})
+		}
+		items, err := h.svc.ListProducts(c.Context(), f, params.Page(c))
+		if errors.Is(err, query.ErrInvalidSort) {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
@@ -176,9 +230,10 @@ func (h *Handler) CreateProduct() fiber.Handler {
 		if err := vld.ValidateStruct(in); err != nil {
 			return err
 		}
-		img := normalizeImageURL(in.ImageURL)
+		img := blob.Canonical(h.blobs, in.ImageURL)
 		p := ec.Product{
 			CategoryID:             in.CategoryID,
+			SKU:                    strings.TrimSpace(in.SKU),
 			Name:                   in.Name,
 			ShortDescription:       in.ShortDescription,
 			LongDescription:        in.LongDescription,
@@ -190,8 +245,17 @@ func (h *Handler) CreateProduct() fiber.Handler {
 			DefaultDepth:           in.DefaultDepth,
 			BaseMaterial:           in.BaseMaterial,
 			Quantity:               in.Quantity,
-		}
-		if err := h.svc.CreateProduct(c.Context(), &p); err != nil {
+			Slug:                   in.Slug,
+			SEOTitle:               in.SEOTitle,
+			SEODescription:         in.SEODescription,
+			Status:                 in.Status,
+			PublishAt:              in.PublishAt,
+			UnpublishAt:            in.UnpublishAt,
+		}
+		if err := h.svc.CreateProduct(c.Context(), &p, in.Attributes); err != nil {
+			if errors.Is(err, ec.ErrInvalidSchedule) || errors.Is(err, ec.ErrInvalidAttribute) {
+				return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+			}
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
 
@@ -212,9 +276,10 @@ func (h *Handler) UpdateProduct() fiber.Handler {
 		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
 			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
 		}
-		img := normalizeImageURL(in.ImageURL)
+		img := blob.Canonical(h.blobs, in.ImageURL)
 		if err := h.svc.UpdateProduct(c.Context(), id, ec.Product{
 			CategoryID:             in.CategoryID,
+			SKU:                    strings.TrimSpace(in.SKU),
 			Name:                   in.Name,
 			ShortDescription:       in.ShortDescription,
 			LongDescription:        in.LongDescription,
@@ -226,7 +291,16 @@ func (h *Handler) UpdateProduct() fiber.Handler {
 			DefaultDepth:           in.DefaultDepth,
 			BaseMaterial:           in.BaseMaterial,
 			Quantity:               in.Quantity,
-		}); err != nil {
+			Slug:                   in.Slug,
+			SEOTitle:               in.SEOTitle,
+			SEODescription:         in.SEODescription,
+			Status:                 in.Status,
+			PublishAt:              in.PublishAt,
+			UnpublishAt:            in.UnpublishAt,
+		}, in.Attributes); err != nil {
+			if errors.Is(err, ec.ErrInvalidSchedule) || errors.Is(err, ec.ErrInvalidAttribute) {
+				return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+			}
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
 		return c.JSON(fiber.Map{"message": "updated"})
@@ -240,12 +314,259 @@ func (h *Handler) DeleteProduct() fiber.Handler {
 			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
 		}
 		if err := h.svc.DeleteProduct(c.Context(), id); err != nil {
+			return archiveError(c, err)
+		}
+		return c.JSON(fiber.Map{"message": "archived"})
+	}
+}
+
+func (h *Handler) RestoreProduct() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.RestoreProduct(c.Context(), id); err != nil {
+			return archiveError(c, err)
+		}
+		return c.JSON(fiber.Map{"message": "restored"})
+	}
+}
+
+func (h *Handler) ListAttributes() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		items, err := h.svc.ListAttributes(c.Context(), id)
+		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
+		return c.JSON(items)
+	}
+}
+
+func (h *Handler) CreateAttribute() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var in admin_dto.AttributeDTO
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		d := attributeFromDTO(in)
+		d.CategoryID = id
+		if err := h.svc.CreateAttribute(c.Context(), &d); err != nil {
+			if errors.Is(err, ec.ErrInvalidAttribute) {
+				return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+			}
+			return storeError(c, err)
+		}
+		return c.JSON(d)
+	}
+}
+
+func (h *Handler) UpdateAttribute() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var in admin_dto.AttributeDTO
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.UpdateAttribute(c.Context(), id, attributeFromDTO(in)); err != nil {
+			if errors.Is(err, ec.ErrInvalidAttribute) {
+				return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+			}
+			return storeError(c, err)
+		}
+		return c.JSON(fiber.Map{"message": "updated"})
+	}
+}
+
+func (h *Handler) DeleteAttribute() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.DeleteAttribute(c.Context(), id); err != nil {
+			return storeError(c, err)
+		}
 		return c.JSON(fiber.Map{"message": "deleted"})
 	}
 }
 
+func attributeFromDTO(in admin_dto.AttributeDTO) ec.AttributeDefinition {
+	return ec.AttributeDefinition{
+		Code:       strings.TrimSpace(in.Code),
+		Name:       strings.TrimSpace(in.Name),
+		Type:       in.Type,
+		Unit:       strings.TrimSpace(in.Unit),
+		Choices:    in.Choices,
+		Required:   in.Required,
+		Filterable: in.Filterable,
+		Position:   in.Position,
+	}
+}
+
+func (h *Handler) GetBundle() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		b, err := h.svc.GetBundle(c.Context(), id)
+		if err != nil {
+			return storeError(c, err)
+		}
+		return c.JSON(b)
+	}
+}
+
+func (h *Handler) SaveBundle() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var in admin_dto.BundleDTO
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		b := ec.Bundle{Pricing: in.Pricing, DiscountPercent: in.DiscountPercent}
+		for _, comp := range in.Components {
+			b.Components = append(b.Components, ec.BundleComponent{ProductID: comp.ProductID, Quantity: comp.Quantity, OptionIDs: comp.OptionIDs})
+		}
+		if err := h.svc.SaveBundle(c.Context(), id, &b); err != nil {
+			if errors.Is(err, ec.ErrInvalidBundle) {
+				return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+			}
+			return storeError(c, err)
+		}
+		return c.JSON(b)
+	}
+}
+
+func (h *Handler) DeleteBundle() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.DeleteBundle(c.Context(), id); err != nil {
+			return storeError(c, err)
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+func (h *Handler) ListPriceHistory() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		items, err := h.svc.ListPriceHistory(c.Context(), id, params.Page(c))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(items)
+	}
+}
+
+func (h *Handler) ListPriceSchedules() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		items, err := h.svc.ListPriceSchedules(c.Context(), id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(items)
+	}
+}
+
+func (h *Handler) CreatePriceSchedule() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var in admin_dto.PriceScheduleDTO
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		ps := ec.PriceSchedule{ProductID: id, OptionID: in.OptionID, Kind: in.Kind, Price: in.Price, EndsAt: in.EndsAt}
+		if in.StartsAt != nil {
+			ps.StartsAt = *in.StartsAt
+		}
+		if err := h.svc.CreatePriceSchedule(c.Context(), &ps); err != nil {
+			if errors.Is(err, ec.ErrInvalidPriceSchedule) {
+				return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+			}
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		return c.Status(201).JSON(ps)
+	}
+}
+
+// CancelPriceSchedule drops a planned change or sale, or ends a running
+// sale early.
+func (h *Handler) CancelPriceSchedule() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.CancelPriceSchedule(c.Context(), id); err != nil {
+			if errors.Is(err, ec.ErrInvalidPriceSchedule) {
+				return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+			}
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		return c.JSON(fiber.Map{"message": "cancelled"})
+	}
+}
+
+func archiveError(c *fiber.Ctx, err error) error {
+	var blocked *ec.ArchiveBlockedError
+	switch {
+	case errors.As(err, &blocked):
+		return c.Status(409).JSON(fiber.Map{"message": err.Error(), "children": blocked.Children})
+	case errors.Is(err, ec.ErrParentArchived):
+		return c.Status(409).JSON(fiber.Map{"message": err.Error()})
+	}
+	return storeError(c, err)
+}
+
+// storeError answers 404 for a missing record and 500 for any other failure.
+func storeError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(404).JSON(fiber.Map{"message": "not found"})
+	}
+	return c.Status(500).JSON(fiber.Map{"message": "server error"})
+}
+
 func (h *Handler) ListProductOptions() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var pid *uint
@@ -312,6 +633,9 @@ func (h *Handler) UpdateProductOption() fiber.Handler {
 			ProductionTimeModifierDays:    in.ProductionTimeModifierDays,
 			ProductionTimeModifierPercent: in.ProductionTimeModifierPercent,
 		}); err != nil {
+			if errors.Is(err, ec.ErrInvalidSchedule) {
+				return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+			}
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
 		return c.JSON(fiber.Map{"message": "updated"})
@@ -331,24 +655,13 @@ func (h *Handler) DeleteProductOption() fiber.Handler {
 	}
 }
 
-func (h *Handler) UploadImage() fiber.Handler {
+// ReindexSearch rebuilds the product search index from the catalog.
+func (h *Handler) ReindexSearch() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		fileHeader, err := c.FormFile("file")
+		n, err := h.svc.ReindexSearch(c.Context())
 		if err != nil {
-			return c.Status(400).JSON(fiber.Map{"message": "file is required"})
-		}
-		if err := os.MkdirAll("uploads", 0o755); err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
-		ext := filepath.Ext(fileHeader.Filename)
-		if ext == "" {
-			ext = ".bin"
-		}
-		name := fmt.Sprintf("%d_%s%s", time.Now().UnixNano(), "upload", ext)
-		dst := filepath.Join("uploads", name)
-		if err := c.SaveFile(fileHeader, dst); err != nil {
-			return c.Status(500).JSON(fiber.Map{"message": "save failed"})
-		}
-		return c.JSON(fiber.Map{"url": "/uploads/" + name})
+		return c.JSON(fiber.Map{"indexed": n})
 	}
 }
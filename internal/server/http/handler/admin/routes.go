@@ -12,23 +12,40 @@ func RegisterAdminRoutes(admin fiber.Router, h *Handler, orders *ho.Handler) {
 	admin.Post("/departments", h.CreateDepartment())
 	admin.Put("/departments/:id", h.UpdateDepartment())
 	admin.Delete("/departments/:id", h.DeleteDepartment())
+	admin.Post("/departments/:id/restore", h.RestoreDepartment())
 
 	admin.Get("/categories", h.ListCategories())
 	admin.Post("/categories", h.CreateCategory())
 	admin.Put("/categories/:id", h.UpdateCategory())
 	admin.Delete("/categories/:id", h.DeleteCategory())
+	admin.Post("/categories/:id/restore", h.RestoreCategory())
+	admin.Get("/categories/:id/attributes", h.ListAttributes())
+	admin.Post("/categories/:id/attributes", h.CreateAttribute())
+	admin.Put("/attributes/:id", h.UpdateAttribute())
+	admin.Delete("/attributes/:id", h.DeleteAttribute())
 
 	admin.Get("/products", h.ListProducts())
 	admin.Post("/products", h.CreateProduct())
 	admin.Put("/products/:id", h.UpdateProduct())
 	admin.Delete("/products/:id", h.DeleteProduct())
+	admin.Post("/products/:id/restore", h.RestoreProduct())
+	admin.Get("/products/:id/bundle", h.GetBundle())
+	admin.Put("/products/:id/bundle", h.SaveBundle())
+	admin.Delete("/products/:id/bundle", h.DeleteBundle())
+	admin.Get("/products/:id/price-history", h.ListPriceHistory())
+	admin.Get("/products/:id/price-schedules", h.ListPriceSchedules())
+	admin.Post("/products/:id/price-schedules", h.CreatePriceSchedule())
+	admin.Delete("/price-schedules/:id", h.CancelPriceSchedule())
 
 	admin.Get("/product_options", h.ListProductOptions())
 	admin.Post("/product_options", h.CreateProductOption())
 	admin.Put("/product_options/:id", h.UpdateProductOption())
 	admin.Delete("/product_options/:id", h.DeleteProductOption())
-	admin.Post("/upload", h.UploadImage())
+	admin.Post("/search/reindex", h.ReindexSearch())
 
 	admin.Get("/orders", orders.AdminListOrders())
 	admin.Patch("/orders/:id/status", orders.AdminUpdateOrderStatus())
+	admin.Put("/orders/:id/delivery-date", orders.AdminScheduleDelivery())
+	admin.Patch("/orders/:id/items", orders.AdminAmendOrder())
+	admin.Get("/orders/:id/amendments", orders.AdminOrderAmendments())
 }
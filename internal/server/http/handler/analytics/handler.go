@@ -0,0 +1,87 @@
+package analytics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	ea "furniture-shop/internal/entities/analytics"
+	"furniture-shop/internal/server/http/params"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/storage/query"
+)
+
+// defaultDays is the period reported when ?from= is not given.
+const defaultDays = 30
+
+type Handler struct {
+	svc service.AnalyticsService
+}
+
+func NewAnalyticsHandler(svc service.AnalyticsService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ProductStats returns the per-product funnel over [from, to).
+func (h *Handler) ProductStats() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		from, to, err := period(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		pid, err := params.Uint(c, "product_id")
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		f := ea.StatsFilter{From: from, To: to, ProductID: pid, Sort: c.Query("sort")}
+		res, err := h.svc.ProductStats(c.Context(), f, params.Page(c))
+		if errors.Is(err, query.ErrInvalidSort) {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(res)
+	}
+}
+
+// DailyTotals returns one row per day over [from, to).
+func (h *Handler) DailyTotals() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		from, to, err := period(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		pid, err := params.Uint(c, "product_id")
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		items, err := h.svc.DailyTotals(c.Context(), from, to, pid)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(items)
+	}
+}
+
+// period reads ?from= and ?to=, defaulting to the last 30 days including today.
+func period(c *fiber.Ctx) (time.Time, time.Time, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	to := today.AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -defaultDays)
+	if v, err := params.Date(c, "to"); err != nil {
+		return from, to, err
+	} else if v != nil {
+		to = *v
+	}
+	if v, err := params.Date(c, "from"); err != nil {
+		return from, to, err
+	} else if v != nil {
+		from = *v
+	}
+	if !from.Before(to) {
+		return from, to, errors.New("from must be before to")
+	}
+	return from, to, nil
+}
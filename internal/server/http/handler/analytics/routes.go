@@ -0,0 +1,8 @@
+package analytics
+
+import "github.com/gofiber/fiber/v2"
+
+func RegisterAdminRoutes(admin fiber.Router, h *Handler) {
+	admin.Get("/analytics/products", h.ProductStats())
+	admin.Get("/analytics/daily", h.DailyTotals())
+}
@@ -28,7 +28,7 @@ func (h *Handler) Register() fiber.Handler {
 		if err := vld.ValidateStruct(in); err != nil {
 			return err
 		}
-		user := eu.User{Role: "client", Name: in.Name, Email: in.Email, Address: in.Address, Phone: in.Phone}
+		user := eu.User{Role: "client", Name: in.Name, Email: in.Email, Address: in.Address, Phone: in.Phone, Locale: in.Locale}
 		if err := user.SetPassword(in.Password); err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "password hashing failed"})
 		}
@@ -0,0 +1,43 @@
+package blobs
+
+import (
+	"errors"
+	"mime"
+	"path"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"furniture-shop/internal/service/blob"
+)
+
+// Handler serves the signed download URLs of the local blob store; S3
+// signed URLs point at the bucket directly.
+type Handler struct {
+	store *blob.LocalStore
+}
+
+func NewBlobsHandler(store *blob.LocalStore) *Handler {
+	return &Handler{store: store}
+}
+
+func (h *Handler) Download() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Params("*")
+		if !h.store.Verify(key, c.Query("expires"), c.Query("signature"), time.Now()) {
+			return c.Status(403).JSON(fiber.Map{"message": "invalid or expired link"})
+		}
+		data, err := h.store.Get(c.Context(), key)
+		if errors.Is(err, blob.ErrNotFound) {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+			c.Set(fiber.HeaderContentType, ct)
+		}
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+		return c.Send(data)
+	}
+}
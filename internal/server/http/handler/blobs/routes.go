@@ -0,0 +1,7 @@
+package blobs
+
+import "github.com/gofiber/fiber/v2"
+
+func Register(api fiber.Router, h *Handler) {
+	api.Get("/blobs/*", h.Download())
+}
@@ -1,25 +1,44 @@
 package catalog
 
 import (
+	"encoding/xml"
+	"errors"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 
+	ea "furniture-shop/internal/entities/analytics"
 	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/server/http/middleware"
+	"furniture-shop/internal/server/http/params"
 	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/i18n"
+	"furniture-shop/internal/storage/query"
 )
 
 type Handler struct {
-	svc service.CatalogService
+	svc       service.CatalogService
+	recs      service.RecommendationService
+	analytics service.AnalyticsService
 }
 
-func NewCatalogHandler(svc service.CatalogService) *Handler {
-	return &Handler{svc: svc}
+func NewCatalogHandler(svc service.CatalogService, recs service.RecommendationService, analytics service.AnalyticsService) *Handler {
+	return &Handler{svc: svc, recs: recs, analytics: analytics}
+}
+
+var recommendationKinds = map[string]bool{
+	"":                         true,
+	ec.RecommendBoughtTogether: true,
+	ec.RecommendViewedTogether: true,
+	ec.RecommendSimilar:        true,
 }
 
 func (h *Handler) GetDepartments() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		depts, err := h.svc.ListDepartments(c.Context())
+		depts, err := h.svc.ListDepartments(c.Context(), locale(c))
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
@@ -29,8 +48,14 @@ func (h *Handler) GetDepartments() fiber.Handler {
 
 func (h *Handler) GetCategoriesByDepartment() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		id, _ := strconv.ParseUint(c.Params("id"), 10, 64)
-		cats, err := h.svc.ListCategoriesByDepartment(c.Context(), uint(id))
+		ref, err := h.svc.Resolve(c.Context(), ec.SlugDepartment, c.Params("id"))
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		if ref.Moved {
+			return moved(c, ref.Slug)
+		}
+		cats, err := h.svc.ListCategoriesByDepartment(c.Context(), ref.ID, locale(c))
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
@@ -38,10 +63,26 @@ func (h *Handler) GetCategoriesByDepartment() fiber.Handler {
 	}
 }
 
+// GetProductsByCategory lists the published products of a category; admins
+// see drafts and scheduled products too with ?preview=true.
 func (h *Handler) GetProductsByCategory() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		id, _ := strconv.ParseUint(c.Params("id"), 10, 64)
-		products, err := h.svc.ListProductsByCategory(c.Context(), uint(id))
+		ref, err := h.svc.Resolve(c.Context(), ec.SlugCategory, c.Params("id"))
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		if ref.Moved {
+			return moved(c, ref.Slug)
+		}
+		f, err := params.ProductFilter(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		f.Preview = middleware.IsPreview(c)
+		products, err := h.svc.ListProductsByCategory(c.Context(), ref.ID, f, params.Page(c), locale(c))
+		if errors.Is(err, query.ErrInvalidSort) || errors.Is(err, ec.ErrInvalidAttribute) {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
@@ -49,38 +90,237 @@ func (h *Handler) GetProductsByCategory() fiber.Handler {
 	}
 }
 
+// GetCategoryAttributes lists the attribute filters of a category with the
+// values and number ranges of its published products.
+func (h *Handler) GetCategoryAttributes() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ref, err := h.svc.Resolve(c.Context(), ec.SlugCategory, c.Params("id"))
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		if ref.Moved {
+			return moved(c, ref.Slug)
+		}
+		items, err := h.svc.ListAttributeFacets(c.Context(), ref.ID, locale(c))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(items)
+	}
+}
+
+// GetProductDetails serves a product page; drafts and scheduled products
+// are only shown to admins with ?preview=true, and admin previews are not
+// tracked as views.
 func (h *Handler) GetProductDetails() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		id, _ := strconv.ParseUint(c.Params("id"), 10, 64)
-		p, err := h.svc.GetProduct(c.Context(), uint(id))
+		ref, err := h.svc.Resolve(c.Context(), ec.SlugProduct, c.Params("id"))
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		if ref.Moved {
+			return moved(c, ref.Slug)
+		}
+		preview := middleware.IsPreview(c)
+		p, err := h.svc.GetProduct(c.Context(), ref.ID, preview, locale(c))
 		if err != nil {
 			return c.Status(404).JSON(fiber.Map{"message": "not found"})
 		}
+		if preview {
+			return c.JSON(p)
+		}
+		h.analytics.Track(ea.Event{Type: ea.EventProductViewed, ProductID: p.ID, SessionID: middleware.SessionKey(c)})
 		return c.JSON(p)
 	}
 }
 
+// CompareProducts lines up the products of ?ids=1,2,3 side by side: price,
+// dimensions, material, production time, options and attributes, with the
+// rows that differ marked.
+func (h *Handler) CompareProducts() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ids, err := params.Uints(c, "ids")
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		res, err := h.svc.CompareProducts(c.Context(), ids, locale(c))
+		if errors.Is(err, ec.ErrInvalidComparison) {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		if errors.Is(err, ec.ErrNotPublished) {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(res)
+	}
+}
+
+// SearchProducts runs a ranked full-text search. Filters: department_id,
+// category_id, material, price_min, price_max; paging: limit, offset. Like
+// every catalog endpoint it answers in the language picked from ?lang= or
+// Accept-Language.
 func (h *Handler) SearchProducts() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		q := c.Query("query")
-		if q == "" {
-			return c.JSON([]ec.Product{})
+		params := ec.ProductSearchParams{
+			Query:    c.Query("q", c.Query("query")),
+			Material: c.Query("material"),
+			Limit:    c.QueryInt("limit", 20),
+			Offset:   c.QueryInt("offset", 0),
+			Locale:   locale(c),
+		}
+		if v := c.Query("department_id"); v != "" {
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return c.Status(400).JSON(fiber.Map{"message": "invalid department_id"})
+			}
+			params.DepartmentID = uint(id)
+		}
+		if v := c.Query("category_id"); v != "" {
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return c.Status(400).JSON(fiber.Map{"message": "invalid category_id"})
+			}
+			params.CategoryID = uint(id)
+		}
+		for key, dst := range map[string]**float64{"price_min": &params.PriceMin, "price_max": &params.PriceMax} {
+			if v := c.Query(key); v != "" {
+				f, err := strconv.ParseFloat(v, 64)
+				if err != nil || f < 0 {
+					return c.Status(400).JSON(fiber.Map{"message": "invalid " + key})
+				}
+				*dst = &f
+			}
 		}
-		items, err := h.svc.SearchProducts(c.Context(), q, 50)
+		res, err := h.svc.SearchProducts(c.Context(), params)
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
-		return c.JSON(items)
+		return c.JSON(res)
 	}
 }
 
+// GetProductRecommendations lists products to show next to a product. kind
+// selects bought_together, viewed_together or similar; by default all sources
+// are blended. limit defaults to 4 (max 20).
 func (h *Handler) GetProductRecommendations() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		id, _ := strconv.ParseUint(c.Params("id"), 10, 64)
-		rec, err := h.svc.RecommendProducts(c.Context(), uint(id), 4)
+		ref, err := h.svc.Resolve(c.Context(), ec.SlugProduct, c.Params("id"))
+		if err != nil {
+			return recommendationError(c, err)
+		}
+		if ref.Moved {
+			return moved(c, ref.Slug)
+		}
+		kind := c.Query("kind")
+		if !recommendationKinds[kind] {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid kind"})
+		}
+		limit := c.QueryInt("limit", 4)
+		if limit <= 0 || limit > 20 {
+			limit = 4
+		}
+		rec, err := h.recs.Recommend(c.Context(), ref.ID, kind, limit)
 		if err != nil {
+			return recommendationError(c, err)
+		}
+		if err := h.svc.TranslateProducts(c.Context(), locale(c), rec); err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
 		return c.JSON(rec)
 	}
 }
+
+// recommendationError answers 404 for a missing product and 500 for any
+// other failure.
+func recommendationError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(404).JSON(fiber.Map{"message": "not found"})
+	}
+	return c.Status(500).JSON(fiber.Map{"message": "server error"})
+}
+
+// GetProductStructuredData returns schema.org Product JSON-LD for the
+// storefront to embed in the product page.
+func (h *Handler) GetProductStructuredData() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ref, err := h.svc.Resolve(c.Context(), ec.SlugProduct, c.Params("id"))
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		if ref.Moved {
+			return moved(c, ref.Slug)
+		}
+		ld, err := h.svc.ProductStructuredData(c.Context(), ref.ID, c.BaseURL())
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		if err := c.JSON(ld); err != nil {
+			return err
+		}
+		c.Set(fiber.HeaderContentType, "application/ld+json")
+		return nil
+	}
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// Sitemap serves sitemap.xml with the storefront URLs of the catalog.
+func (h *Handler) Sitemap() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		urls, err := h.svc.Sitemap(c.Context())
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		set := sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9", URLs: make([]sitemapURL, 0, len(urls))}
+		for _, u := range urls {
+			entry := sitemapURL{Loc: u.Loc}
+			if !u.LastMod.IsZero() {
+				entry.LastMod = u.LastMod.UTC().Format("2006-01-02")
+			}
+			set.URLs = append(set.URLs, entry)
+		}
+		out, err := xml.Marshal(set)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		c.Set(fiber.HeaderContentType, "application/xml; charset=utf-8")
+		c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+		return c.Send(append([]byte(xml.Header), out...))
+	}
+}
+
+// locale negotiates the catalog language from ?lang= and Accept-Language
+// and reports it in the Content-Language header.
+func locale(c *fiber.Ctx) string {
+	l := i18n.Negotiate(c.Query("lang"), c.Get(fiber.HeaderAcceptLanguage))
+	c.Set(fiber.HeaderContentLanguage, l)
+	c.Vary(fiber.HeaderAcceptLanguage)
+	return l
+}
+
+// moved answers a request made with a former slug with a permanent
+// redirect to the same route under the current slug.
+func moved(c *fiber.Ctx, slug string) error {
+	segments := strings.Split(c.Path(), "/")
+	for i, part := range strings.Split(c.Route().Path, "/") {
+		if part == ":id" && i < len(segments) {
+			segments[i] = url.PathEscape(slug)
+		}
+	}
+	target := strings.Join(segments, "/")
+	if q := c.Request().URI().QueryString(); len(q) > 0 {
+		target += "?" + string(q)
+	}
+	return c.Redirect(target, fiber.StatusMovedPermanently)
+}
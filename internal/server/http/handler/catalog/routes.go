@@ -1,12 +1,24 @@
 package catalog
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"furniture-shop/internal/server/http/middleware"
+)
 
 func Register(api fiber.Router, h *Handler) {
 	api.Get("/departments", h.GetDepartments())
 	api.Get("/departments/:id/categories", h.GetCategoriesByDepartment())
-	api.Get("/categories/:id/products", h.GetProductsByCategory())
-	api.Get("/products/:id", h.GetProductDetails())
-	api.Get("/products/:id/recommendations", h.GetProductRecommendations())
+	api.Get("/categories/:id/products", middleware.Preview(), h.GetProductsByCategory())
+	api.Get("/categories/:id/attributes", h.GetCategoryAttributes())
 	api.Get("/products/search", h.SearchProducts())
+	api.Get("/products/compare", h.CompareProducts())
+	api.Get("/products/:id", middleware.Preview(), h.GetProductDetails())
+	api.Get("/products/:id/recommendations", h.GetProductRecommendations())
+	api.Get("/products/:id/structured-data", h.GetProductStructuredData())
+}
+
+// RegisterSitemap serves sitemap.xml at the root of app.
+func RegisterSitemap(app fiber.Router, h *Handler) {
+	app.Get("/sitemap.xml", h.Sitemap())
 }
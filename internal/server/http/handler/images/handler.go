@@ -0,0 +1,178 @@
+package images
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+
+	admin_dto "furniture-shop/internal/dtos/admin"
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service"
+	ip "furniture-shop/internal/service/images"
+	vld "furniture-shop/internal/validation"
+)
+
+type Handler struct {
+	svc      service.ImageService
+	maxBytes int64
+}
+
+func NewImagesHandler(svc service.ImageService, maxUploadMB int) *Handler {
+	return &Handler{svc: svc, maxBytes: int64(maxUploadMB) << 20}
+}
+
+// Upload stores a single validated image and returns its URL.
+func (h *Handler) Upload() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		data, err := h.readFile(c)
+		if err != nil {
+			return uploadError(c, err)
+		}
+		url, err := h.svc.Upload(c.Context(), data)
+		if err != nil {
+			return uploadError(c, err)
+		}
+		return c.JSON(fiber.Map{"url": url})
+	}
+}
+
+func (h *Handler) ListProductImages() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		items, err := h.svc.ListProductImages(c.Context(), id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(items)
+	}
+}
+
+// AddProductImage takes a multipart form with the file and optional
+// alt_text and option_id fields.
+func (h *Handler) AddProductImage() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in admin_dto.ProductImageDTO
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		data, err := h.readFile(c)
+		if err != nil {
+			return uploadError(c, err)
+		}
+		img, err := h.svc.AddProductImage(c.Context(), id, data, ec.ProductImage{AltText: in.AltText, OptionID: in.OptionID})
+		if err != nil {
+			return uploadError(c, err)
+		}
+		return c.Status(201).JSON(img)
+	}
+}
+
+func (h *Handler) ReorderProductImages() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in admin_dto.ReorderImagesDTO
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		if err := h.svc.ReorderProductImages(c.Context(), id, in.IDs); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		return c.JSON(fiber.Map{"message": "updated"})
+	}
+}
+
+func (h *Handler) UpdateProductImage() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in admin_dto.ProductImageDTO
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		if err := h.svc.UpdateProductImage(c.Context(), id, ec.ProductImage{AltText: in.AltText, OptionID: in.OptionID}); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		return c.JSON(fiber.Map{"message": "updated"})
+	}
+}
+
+// Original returns a temporary link to the uploaded original.
+func (h *Handler) Original() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		url, err := h.svc.OriginalURL(c.Context(), id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		return c.JSON(fiber.Map{"url": url})
+	}
+}
+
+func (h *Handler) DeleteProductImage() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.DeleteProductImage(c.Context(), id); err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+var errFileRequired = errors.New("file is required")
+
+// readFile reads the "file" form field, refusing oversized files before
+// reading them.
+func (h *Handler) readFile(c *fiber.Ctx) ([]byte, error) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return nil, errFileRequired
+	}
+	if fh.Size > h.maxBytes {
+		return nil, fmt.Errorf("%w: at most %d MB", ip.ErrTooLarge, h.maxBytes>>20)
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, h.maxBytes+1))
+}
+
+func uploadError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ip.ErrTooLarge):
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"message": err.Error()})
+	case errors.Is(err, ip.ErrUnsupportedType):
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+}
@@ -0,0 +1,13 @@
+package images
+
+import "github.com/gofiber/fiber/v2"
+
+func RegisterAdminRoutes(admin fiber.Router, h *Handler) {
+	admin.Post("/upload", h.Upload())
+	admin.Get("/products/:id/images", h.ListProductImages())
+	admin.Post("/products/:id/images", h.AddProductImage())
+	admin.Put("/products/:id/images/order", h.ReorderProductImages())
+	admin.Get("/product_images/:id/original", h.Original())
+	admin.Patch("/product_images/:id", h.UpdateProductImage())
+	admin.Delete("/product_images/:id", h.DeleteProductImage())
+}
@@ -0,0 +1,132 @@
+package invoices
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	eo "furniture-shop/internal/entities/orders"
+	"furniture-shop/internal/service"
+	si "furniture-shop/internal/service/domain/invoices"
+)
+
+type Handler struct {
+	svc service.InvoiceService
+}
+
+func NewInvoicesHandler(svc service.InvoiceService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func sendPDF(c *fiber.Ctx, inv *eo.Invoice, body []byte) error {
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", si.FileName(inv)))
+	return c.Send(body)
+}
+
+func (h *Handler) UserOrderInvoice() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		inv, body, err := h.svc.GetUserOrderInvoicePDF(c.Context(), uid, id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "invoice not found"})
+		}
+		return sendPDF(c, inv, body)
+	}
+}
+
+func (h *Handler) UserInvoice() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		inv, body, err := h.svc.GetUserInvoicePDF(c.Context(), uid, id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "invoice not found"})
+		}
+		return sendPDF(c, inv, body)
+	}
+}
+
+func (h *Handler) AdminListInvoices() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		from, to, err := parsePeriod(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		items, err := h.svc.ListInvoices(c.Context(), from, to, c.Query("kind"))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(items)
+	}
+}
+
+func (h *Handler) AdminInvoicePDF() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		inv, body, err := h.svc.GetInvoicePDF(c.Context(), id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "invoice not found"})
+		}
+		return sendPDF(c, inv, body)
+	}
+}
+
+func (h *Handler) AdminExportInvoices() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		from, to, err := parsePeriod(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		body, err := h.svc.ExportArchive(c.Context(), from, to, c.Query("kind"))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		c.Set(fiber.HeaderContentType, "application/zip")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"invoices_%s_%s.zip\"", from.Format("20060102"), to.AddDate(0, 0, -1).Format("20060102")))
+		return c.Send(body)
+	}
+}
+
+// parsePeriod reads the inclusive ?from=YYYY-MM-DD&to=YYYY-MM-DD range,
+// defaulting to the current calendar month.
+func parsePeriod(c *fiber.Ctx) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	if s := c.Query("from"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid from date")
+		}
+		from = t
+	}
+	if s := c.Query("to"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid to date")
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		return from, to, fmt.Errorf("invalid period")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,14 @@
+package invoices
+
+import "github.com/gofiber/fiber/v2"
+
+func RegisterUserRoutes(r fiber.Router, h *Handler) {
+	r.Get("/orders/:id/invoice", h.UserOrderInvoice())
+	r.Get("/invoices/:id", h.UserInvoice())
+}
+
+func RegisterAdminRoutes(admin fiber.Router, h *Handler) {
+	admin.Get("/invoices", h.AdminListInvoices())
+	admin.Get("/invoices/export", h.AdminExportInvoices())
+	admin.Get("/invoices/:id/pdf", h.AdminInvoicePDF())
+}
@@ -0,0 +1,143 @@
+package notifications
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"furniture-shop/internal/config"
+	notifications_dto "furniture-shop/internal/dtos/notifications"
+	en "furniture-shop/internal/entities/notification"
+	"furniture-shop/internal/service"
+	vld "furniture-shop/internal/validation"
+)
+
+type Handler struct {
+	svc service.NotificationService
+}
+
+func NewNotificationsHandler(svc service.NotificationService) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) ListTemplates() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(h.svc.ListTemplates())
+	}
+}
+
+// PreviewTemplate renders a template with sample data. ?format=html or
+// ?format=text return the raw body, anything else returns all parts as JSON.
+func (h *Handler) PreviewTemplate() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		version := 0
+		if s := c.Query("version"); s != "" {
+			v, err := strconv.Atoi(s)
+			if err != nil || v < 0 {
+				return c.Status(400).JSON(fiber.Map{"message": "invalid version"})
+			}
+			version = v
+		}
+		out, err := h.svc.PreviewTemplate(c.Params("name"), c.Query("locale"), version)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": err.Error()})
+		}
+		switch c.Query("format") {
+		case "html":
+			c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+			return c.SendString(out.HTML)
+		case "text":
+			c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+			return c.SendString(out.Text)
+		}
+		return c.JSON(out)
+	}
+}
+
+// Preferences returns the user's channels together with what the client needs
+// to offer the others (the VAPID key for subscribing to push).
+func (h *Handler) Preferences() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		channels, subs, err := h.svc.GetPreferences(c.Context(), userID)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		if channels == nil {
+			channels = []string{}
+		}
+		return c.JSON(fiber.Map{
+			"channels":           channels,
+			"available_channels": en.Channels,
+			"push_subscriptions": subs,
+			"vapid_public_key":   config.Configurations.Notify.Push.VAPIDPublicKey,
+		})
+	}
+}
+
+func (h *Handler) UpdatePreferences() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var in notifications_dto.UpdatePreferencesRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		userID, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		if err := h.svc.UpdatePreferences(c.Context(), userID, in.Channels); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		return c.JSON(fiber.Map{"message": "updated"})
+	}
+}
+
+func (h *Handler) Subscribe() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var in notifications_dto.PushSubscriptionRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		sub := en.PushSubscription{
+			Endpoint:  in.Endpoint,
+			P256dh:    in.Keys.P256dh,
+			Auth:      in.Keys.Auth,
+			UserAgent: c.Get(fiber.HeaderUserAgent),
+		}
+		if err := h.svc.AddPushSubscription(c.Context(), userID, &sub); err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.Status(201).JSON(sub)
+	}
+}
+
+func (h *Handler) Unsubscribe() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.RemovePushSubscription(c.Context(), userID, id); err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
@@ -0,0 +1,15 @@
+package notifications
+
+import "github.com/gofiber/fiber/v2"
+
+func RegisterAdminRoutes(admin fiber.Router, h *Handler) {
+	admin.Get("/notifications/templates", h.ListTemplates())
+	admin.Get("/notifications/templates/:name/preview", h.PreviewTemplate())
+}
+
+func RegisterUserRoutes(user fiber.Router, h *Handler) {
+	user.Get("/notifications/preferences", h.Preferences())
+	user.Put("/notifications/preferences", h.UpdatePreferences())
+	user.Post("/notifications/push-subscriptions", h.Subscribe())
+	user.Delete("/notifications/push-subscriptions/:id", h.Unsubscribe())
+}
@@ -4,17 +4,20 @@ import (
 	"fmt"
 
 	cartdto "furniture-shop/internal/dtos/cart"
+	ea "furniture-shop/internal/entities/analytics"
+	"furniture-shop/internal/server/http/middleware"
 	"furniture-shop/internal/service"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type CartHandler struct {
-	svc service.CartService
+	svc       service.CartService
+	analytics service.AnalyticsService
 }
 
-func NewCartHandler(svc service.CartService) *CartHandler {
-	return &CartHandler{svc: svc}
+func NewCartHandler(svc service.CartService, analytics service.AnalyticsService) *CartHandler {
+	return &CartHandler{svc: svc, analytics: analytics}
 }
 
 func userID(c *fiber.Ctx) (uint, bool) {
@@ -68,6 +71,7 @@ func (h *CartHandler) AddItem() fiber.Handler {
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": fmt.Sprintf("%v", err)})
 		}
+		h.analytics.Track(ea.Event{Type: ea.EventAddedToCart, ProductID: item.ProductID, UserID: &uid, SessionID: middleware.SessionKey(c), Quantity: item.Quantity})
 		return c.JSON(item)
 	}
 }
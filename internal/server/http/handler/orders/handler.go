@@ -1,6 +1,7 @@
 package orders
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"strconv"
@@ -9,21 +10,27 @@ import (
 	"github.com/gofiber/fiber/v2"
 	stripe "github.com/stripe/stripe-go/v84"
 	session "github.com/stripe/stripe-go/v84/checkout/session"
+	"gorm.io/gorm"
 
 	"furniture-shop/internal/config"
 	order_dto "furniture-shop/internal/dtos/orders"
+	ea "furniture-shop/internal/entities/analytics"
+	ec "furniture-shop/internal/entities/catalog"
 	"furniture-shop/internal/entities/orders"
+	"furniture-shop/internal/server/http/middleware"
+	"furniture-shop/internal/server/http/params"
 	"furniture-shop/internal/service"
-	"furniture-shop/internal/service/mailer"
+	"furniture-shop/internal/storage/query"
 	vld "furniture-shop/internal/validation"
 )
 
 type Handler struct {
-	svc service.OrdersService
+	svc       service.OrdersService
+	analytics service.AnalyticsService
 }
 
-func NewOrdersHandler(svc service.OrdersService) *Handler {
-	return &Handler{svc: svc}
+func NewOrdersHandler(svc service.OrdersService, analytics service.AnalyticsService) *Handler {
+	return &Handler{svc: svc, analytics: analytics}
 }
 
 func (h *Handler) CreateOrder() fiber.Handler {
@@ -44,13 +51,7 @@ func (h *Handler) CreateOrder() fiber.Handler {
 		if err != nil {
 			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
 		}
-
-		to := c.Locals("user_email")
-		if s, ok := to.(string); ok && s != "" {
-			mailer.NewSender().Send(s, "Order created", fmt.Sprintf("Your order #%d has been created and is pending.", order.ID))
-		} else if in.Email != "" {
-			mailer.NewSender().Send(in.Email, "Order created", fmt.Sprintf("Your order #%d has been created and is pending.", order.ID))
-		}
+		h.trackCheckout(c, order)
 
 		if in.PaymentMethod == "card" {
 			url, err := h.createStripeSession(order)
@@ -80,6 +81,7 @@ func (h *Handler) PayExistingOrder() fiber.Handler {
 		if err != nil {
 			return c.Status(404).JSON(fiber.Map{"message": "order not found"})
 		}
+		h.trackCheckout(c, order)
 
 		url, err := h.createStripeSession(order)
 		if err != nil {
@@ -124,8 +126,14 @@ func (h *Handler) UserOrderDetails() fiber.Handler {
 
 func (h *Handler) AdminListOrders() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		status := c.Query("status")
-		orders, err := h.svc.AdminListOrders(c.Context(), status)
+		f, err := params.OrderFilter(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		orders, err := h.svc.AdminListOrders(c.Context(), f, params.Page(c))
+		if errors.Is(err, query.ErrInvalidSort) {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{"message": "server error"})
 		}
@@ -150,6 +158,52 @@ func (h *Handler) AdminUpdateOrderStatus() fiber.Handler {
 	}
 }
 
+func (h *Handler) AdminScheduleDelivery() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var in order_dto.ScheduleDeliveryRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		id, err := h.getID(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var date *time.Time
+		if in.Date != "" {
+			d, err := time.ParseInLocation("2006-01-02", in.Date, time.Local)
+			if err != nil {
+				return c.Status(400).JSON(fiber.Map{"message": "invalid date"})
+			}
+			date = &d
+		}
+		if err := h.svc.AdminScheduleDelivery(c.Context(), id, date); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		return c.JSON(fiber.Map{"message": "updated"})
+	}
+}
+
+// trackCheckout records checkout_started for every line of the order; paying
+// an existing order again is not counted twice.
+func (h *Handler) trackCheckout(c *fiber.Ctx, order *orders.Order) {
+	session := middleware.SessionKey(c)
+	for _, it := range order.Items {
+		h.analytics.Track(ea.Event{
+			Type:        ea.EventCheckoutStarted,
+			ProductID:   it.ProductID,
+			OrderID:     &order.ID,
+			OrderItemID: &it.ID,
+			UserID:      &order.UserID,
+			SessionID:   session,
+			Quantity:    it.Quantity,
+			Value:       it.LineTotal,
+		})
+	}
+}
+
 func (h *Handler) getID(c *fiber.Ctx) (uint, error) {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
 	if err != nil {
@@ -160,7 +214,7 @@ func (h *Handler) getID(c *fiber.Ctx) (uint, error) {
 
 func (h *Handler) createStripeSession(order *orders.Order) (string, error) {
 	stripe.Key = config.Env.StripeSecretKey
-	fe := "http://localhost:5173"
+	fe := config.Configurations.FrontendURL
 
 	amount := int64(math.Round(order.TotalPrice * 100))
 	expiresAt := time.Now().Add(30 * time.Minute).Unix()
@@ -194,3 +248,88 @@ func (h *Handler) createStripeSession(order *orders.Order) (string, error) {
 	}
 	return sess.URL, nil
 }
+
+func (h *Handler) amendOrder(c *fiber.Ctx, asAdmin bool) error {
+	uid, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+	}
+	id, err := h.getID(c)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+	}
+	var in order_dto.AmendOrderRequest
+	if err := c.BodyParser(&in); err != nil {
+		return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+	}
+	if err := vld.ValidateStruct(in); err != nil {
+		return err
+	}
+	order, amendment, err := h.svc.AmendOrder(c.Context(), id, uid, asAdmin, in)
+	if err != nil {
+		return amendmentError(c, err)
+	}
+	return c.JSON(fiber.Map{"order": order, "amendment": amendment})
+}
+
+// amendmentError maps an AmendOrder error to its response; another user's
+// order is reported as not found.
+func amendmentError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(404).JSON(fiber.Map{"message": "order not found"})
+	case errors.Is(err, orders.ErrInvalidAmendment), errors.Is(err, ec.ErrInvalidBundle):
+		return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+	case errors.Is(err, orders.ErrOrderNotAmendable), errors.Is(err, orders.ErrAmendmentConflict):
+		return c.Status(409).JSON(fiber.Map{"message": err.Error()})
+	default:
+		return c.Status(500).JSON(fiber.Map{"message": "server error"})
+	}
+}
+
+func (h *Handler) UserAmendOrder() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return h.amendOrder(c, false)
+	}
+}
+
+func (h *Handler) UserOrderAmendments() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		id, err := h.getID(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if _, err := h.svc.GetUserOrder(c.Context(), uid, id); err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		items, err := h.svc.ListOrderAmendments(c.Context(), id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(items)
+	}
+}
+
+func (h *Handler) AdminAmendOrder() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return h.amendOrder(c, true)
+	}
+}
+
+func (h *Handler) AdminOrderAmendments() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := h.getID(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		items, err := h.svc.ListOrderAmendments(c.Context(), id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(items)
+	}
+}
@@ -0,0 +1,72 @@
+package outbox
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	en "furniture-shop/internal/entities/notification"
+	"furniture-shop/internal/service"
+)
+
+type Handler struct {
+	svc service.OutboxService
+}
+
+func NewOutboxHandler(svc service.OutboxService) *Handler {
+	return &Handler{svc: svc}
+}
+
+var statuses = map[string]bool{
+	"":                     true,
+	en.OutboxStatusPending: true,
+	en.OutboxStatusSending: true,
+	en.OutboxStatusSent:    true,
+	en.OutboxStatusDead:    true,
+}
+
+func (h *Handler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		status := c.Query("status")
+		if !statuses[status] {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid status"})
+		}
+		limit, _ := strconv.Atoi(c.Query("limit", "50"))
+		offset, _ := strconv.Atoi(c.Query("offset", "0"))
+		items, total, err := h.svc.List(c.Context(), status, limit, offset)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(fiber.Map{"items": items, "total": total})
+	}
+}
+
+func (h *Handler) Get() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		m, err := h.svc.Get(c.Context(), id)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		return c.JSON(m)
+	}
+}
+
+// Resend puts a message (typically a dead-lettered one) back into the queue
+// with a fresh attempt budget.
+func (h *Handler) Resend() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.Resend(c.Context(), id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		return c.JSON(fiber.Map{"message": "queued"})
+	}
+}
@@ -0,0 +1,9 @@
+package outbox
+
+import "github.com/gofiber/fiber/v2"
+
+func RegisterAdminRoutes(admin fiber.Router, h *Handler) {
+	admin.Get("/outbox", h.List())
+	admin.Get("/outbox/:id", h.Get())
+	admin.Post("/outbox/:id/resend", h.Resend())
+}
@@ -35,12 +35,18 @@ func (h *Handler) StripeWebhook() fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "invalid signature"})
 		}
 
-		update := func(orderID uint, paymentStatus, orderStatus string) {
-			if err := h.svc.ProcessPaymentResult(c.Context(), orderID, paymentStatus, orderStatus); err != nil {
+		update := func(orderID uint, paymentStatus, orderStatus, paymentRef string, amount int64) {
+			if err := h.svc.ProcessPaymentResult(c.Context(), orderID, paymentStatus, orderStatus, paymentRef, float64(amount)/100); err != nil {
 				log.Printf("ProcessPaymentResult failed (order=%d paid-status=%v order-status=%v): %v", orderID, paymentStatus, orderStatus, err)
 			}
 		}
 
+		settle := func(amendmentID uint, paid bool, paymentRef string) {
+			if err := h.svc.SettleAmendment(c.Context(), amendmentID, paid, paymentRef); err != nil {
+				log.Printf("SettleAmendment failed (amendment=%d paid=%v): %v", amendmentID, paid, err)
+			}
+		}
+
 		parseOID := func(s string) (uint, bool) {
 			var oid uint
 			if s == "" {
@@ -61,15 +67,19 @@ func (h *Handler) StripeWebhook() fiber.Handler {
 		case "payment_intent.succeeded":
 			var pi stripe.PaymentIntent
 			if err := json.Unmarshal(event.Data.Raw, &pi); err == nil {
-				if oid, ok := parseOID(pi.Metadata["order_id"]); ok {
-					update(oid, eo.PaymentStatusPaid, eo.OrderStatusProcessing)
+				if aid, ok := parseOID(pi.Metadata["amendment_id"]); ok {
+					settle(aid, true, pi.ID)
+				} else if oid, ok := parseOID(pi.Metadata["order_id"]); ok {
+					update(oid, eo.PaymentStatusPaid, eo.OrderStatusProcessing, pi.ID, pi.Amount)
 				}
 			}
 		case "payment_intent.payment_failed":
 			var pi stripe.PaymentIntent
 			if err := json.Unmarshal(event.Data.Raw, &pi); err == nil {
-				if oid, ok := parseOID(pi.Metadata["order_id"]); ok {
-					update(oid, eo.PaymentStatusDeclined, eo.OrderStatusCancelled)
+				if aid, ok := parseOID(pi.Metadata["amendment_id"]); ok {
+					settle(aid, false, pi.ID)
+				} else if oid, ok := parseOID(pi.Metadata["order_id"]); ok {
+					update(oid, eo.PaymentStatusDeclined, eo.OrderStatusCancelled, "", 0)
 				}
 			}
 		case "checkout.session.expired":
@@ -79,8 +89,10 @@ func (h *Handler) StripeWebhook() fiber.Handler {
 				if orderIDStr == "" {
 					orderIDStr = sess.Metadata["order_id"]
 				}
-				if oid, ok := parseOID(orderIDStr); ok {
-					update(oid, eo.PaymentStatusCancelled, eo.OrderStatusCancelled)
+				if aid, ok := parseOID(sess.Metadata["amendment_id"]); ok {
+					settle(aid, false, "")
+				} else if oid, ok := parseOID(orderIDStr); ok {
+					update(oid, eo.PaymentStatusCancelled, eo.OrderStatusCancelled, "", 0)
 				}
 			}
 		}
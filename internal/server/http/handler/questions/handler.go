@@ -0,0 +1,271 @@
+package questions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	question_dto "furniture-shop/internal/dtos/questions"
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/server/http/params"
+	"furniture-shop/internal/service"
+	vld "furniture-shop/internal/validation"
+)
+
+type Handler struct {
+	svc service.QuestionService
+}
+
+func NewQuestionsHandler(svc service.QuestionService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ProductQuestions lists the approved questions of a product with their
+// approved answers, newest first; ?answered=true|false narrows them.
+func (h *Handler) ProductQuestions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		answered, err := params.Bool(c, "answered")
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		res, err := h.svc.ListProductQuestions(c.Context(), id, answered, params.Page(c))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(res)
+	}
+}
+
+// UserQuestions lists the questions the user asked, including those
+// awaiting moderation.
+func (h *Handler) UserQuestions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		res, err := h.svc.ListUserQuestions(c.Context(), uid, params.Page(c))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(res)
+	}
+}
+
+func (h *Handler) Ask() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in question_dto.PostTextRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		q, err := h.svc.Ask(c.Context(), uid, id, in.Body)
+		if err != nil {
+			return questionError(c, err)
+		}
+		return c.Status(201).JSON(q)
+	}
+}
+
+// Answer records the answer of a customer who received the product.
+func (h *Handler) Answer() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in question_dto.PostTextRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		a, err := h.svc.Answer(c.Context(), uid, id, in.Body)
+		if err != nil {
+			return questionError(c, err)
+		}
+		return c.Status(201).JSON(a)
+	}
+}
+
+// AdminListQuestions is the question moderation queue, filtered by
+// ?status= (pending by default, "all" for every status), ?product_id= and
+// ?answered=.
+func (h *Handler) AdminListQuestions() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var f ec.QuestionFilter
+		var err error
+		if f.Status, err = status(c); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		if f.ProductID, err = params.Uint(c, "product_id"); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		if f.Answered, err = params.Bool(c, "answered"); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		res, err := h.svc.ListQuestions(c.Context(), f, params.Page(c))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(res)
+	}
+}
+
+func (h *Handler) AdminModerateQuestion() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in question_dto.ModerateRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		q, err := h.svc.ModerateQuestion(c.Context(), id, in.Status)
+		if err != nil {
+			return questionError(c, err)
+		}
+		return c.JSON(q)
+	}
+}
+
+func (h *Handler) AdminDeleteQuestion() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.DeleteQuestion(c.Context(), id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Status(404).JSON(fiber.Map{"message": "not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+// AdminAnswer publishes an official answer and emails the asker.
+func (h *Handler) AdminAnswer() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in question_dto.PostTextRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		a, err := h.svc.AnswerAsShop(c.Context(), uid, id, in.Body)
+		if err != nil {
+			return questionError(c, err)
+		}
+		return c.Status(201).JSON(a)
+	}
+}
+
+// AdminListAnswers is the answer moderation queue, filtered by ?status=
+// like the question queue.
+func (h *Handler) AdminListAnswers() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		st, err := status(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		res, err := h.svc.ListAnswers(c.Context(), st, params.Page(c))
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(res)
+	}
+}
+
+func (h *Handler) AdminModerateAnswer() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in question_dto.ModerateRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		a, err := h.svc.ModerateAnswer(c.Context(), id, in.Status)
+		if err != nil {
+			return questionError(c, err)
+		}
+		return c.JSON(a)
+	}
+}
+
+func (h *Handler) AdminDeleteAnswer() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.DeleteAnswer(c.Context(), id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Status(404).JSON(fiber.Map{"message": "not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+// status reads ?status=, pending by default and "all" for every status.
+func status(c *fiber.Ctx) (string, error) {
+	switch s := c.Query("status", ec.QuestionPending); s {
+	case "all":
+		return "", nil
+	case ec.QuestionPending, ec.QuestionApproved, ec.QuestionRejected:
+		return s, nil
+	}
+	return "", errors.New("invalid status")
+}
+
+func questionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ec.ErrInvalidQuestion):
+		return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+	case errors.Is(err, ec.ErrNotVerifiedBuyer):
+		return c.Status(403).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(404).JSON(fiber.Map{"message": "not found"})
+}
@@ -0,0 +1,23 @@
+package questions
+
+import "github.com/gofiber/fiber/v2"
+
+func Register(api fiber.Router, h *Handler) {
+	api.Get("/products/:id/questions", h.ProductQuestions())
+}
+
+func RegisterUserRoutes(r fiber.Router, h *Handler) {
+	r.Get("/questions", h.UserQuestions())
+	r.Post("/products/:id/questions", h.Ask())
+	r.Post("/questions/:id/answers", h.Answer())
+}
+
+func RegisterAdminRoutes(admin fiber.Router, h *Handler) {
+	admin.Get("/questions", h.AdminListQuestions())
+	admin.Patch("/questions/:id", h.AdminModerateQuestion())
+	admin.Delete("/questions/:id", h.AdminDeleteQuestion())
+	admin.Post("/questions/:id/answers", h.AdminAnswer())
+	admin.Get("/answers", h.AdminListAnswers())
+	admin.Patch("/answers/:id", h.AdminModerateAnswer())
+	admin.Delete("/answers/:id", h.AdminDeleteAnswer())
+}
@@ -0,0 +1,86 @@
+package recommendations
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+
+	admin_dto "furniture-shop/internal/dtos/admin"
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service"
+	vld "furniture-shop/internal/validation"
+)
+
+type Handler struct {
+	svc service.RecommendationService
+}
+
+func NewRecommendationsHandler(svc service.RecommendationService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// ListOverrides returns every override, or those of ?product_id= together
+// with the global ones.
+func (h *Handler) ListOverrides() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var pid *uint
+		if s := c.Query("product_id"); s != "" {
+			var v uint
+			if _, err := fmt.Sscan(s, &v); err != nil {
+				return c.Status(400).JSON(fiber.Map{"message": "invalid product_id"})
+			}
+			pid = &v
+		}
+		items, err := h.svc.ListOverrides(c.Context(), pid)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(items)
+	}
+}
+
+func (h *Handler) SaveOverride() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var in admin_dto.RecommendationOverrideDTO
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		o := ec.RecommendationOverride{
+			ProductID:        in.ProductID,
+			RelatedProductID: in.RelatedProductID,
+			Action:           in.Action,
+			Position:         in.Position,
+		}
+		if err := h.svc.SaveOverride(c.Context(), &o); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		return c.Status(201).JSON(o)
+	}
+}
+
+func (h *Handler) DeleteOverride() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.DeleteOverride(c.Context(), id); err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+// Refresh recomputes the associations now instead of waiting for the job.
+func (h *Handler) Refresh() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		res, err := h.svc.Refresh(c.Context())
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(res)
+	}
+}
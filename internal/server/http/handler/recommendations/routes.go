@@ -0,0 +1,10 @@
+package recommendations
+
+import "github.com/gofiber/fiber/v2"
+
+func RegisterAdminRoutes(admin fiber.Router, h *Handler) {
+	admin.Get("/recommendations/overrides", h.ListOverrides())
+	admin.Post("/recommendations/overrides", h.SaveOverride())
+	admin.Delete("/recommendations/overrides/:id", h.DeleteOverride())
+	admin.Post("/recommendations/refresh", h.Refresh())
+}
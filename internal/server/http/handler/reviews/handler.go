@@ -0,0 +1,256 @@
+package reviews
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	review_dto "furniture-shop/internal/dtos/reviews"
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/server/http/params"
+	"furniture-shop/internal/service"
+	ip "furniture-shop/internal/service/images"
+	"furniture-shop/internal/storage/query"
+	vld "furniture-shop/internal/validation"
+)
+
+type Handler struct {
+	svc      service.ReviewService
+	maxBytes int64
+}
+
+func NewReviewsHandler(svc service.ReviewService, maxUploadMB int) *Handler {
+	return &Handler{svc: svc, maxBytes: int64(maxUploadMB) << 20}
+}
+
+// ProductReviews returns the rating summary of a product and one page of
+// its approved reviews, filtered by ?rating=, ?verified= and ?photos=true
+// and sorted by ?sort=.
+func (h *Handler) ProductReviews() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		f, err := reviewFilter(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		summary, err := h.svc.RatingSummary(c.Context(), id)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		res, err := h.svc.ListProductReviews(c.Context(), id, f, params.Page(c))
+		if errors.Is(err, query.ErrInvalidSort) {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(fiber.Map{"summary": summary, "items": res.Items, "pagination": res.Pagination})
+	}
+}
+
+// UserReview returns the user's own review of a product, whatever its
+// status, or null.
+func (h *Handler) UserReview() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		r, err := h.svc.GetUserReview(c.Context(), id, uid)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(r)
+	}
+}
+
+// SubmitReview takes a multipart form with rating, title and body and up
+// to four "photos" files. It replaces the user's earlier review of the
+// product, if any.
+func (h *Handler) SubmitReview() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in review_dto.SubmitReviewRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		photos, err := h.readPhotos(c)
+		if err != nil {
+			return reviewError(c, err)
+		}
+		r, err := h.svc.SubmitReview(c.Context(), uid, &ec.Review{ProductID: id, Rating: in.Rating, Title: in.Title, Body: in.Body}, photos)
+		if err != nil {
+			return reviewError(c, err)
+		}
+		return c.Status(201).JSON(r)
+	}
+}
+
+func (h *Handler) DeleteUserReview() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.DeleteUserReview(c.Context(), uid, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Status(404).JSON(fiber.Map{"message": "not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+// AdminListReviews is the moderation queue, filtered by ?status= (pending
+// by default, "all" for every status), ?product_id= and the filters of the
+// public listing.
+func (h *Handler) AdminListReviews() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		f, err := reviewFilter(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		if f.ProductID, err = params.Uint(c, "product_id"); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		switch f.Status = c.Query("status", ec.ReviewPending); f.Status {
+		case "all":
+			f.Status = ""
+		case ec.ReviewPending, ec.ReviewApproved, ec.ReviewRejected:
+		default:
+			return c.Status(400).JSON(fiber.Map{"message": "invalid status"})
+		}
+		res, err := h.svc.ListReviews(c.Context(), f, params.Page(c))
+		if errors.Is(err, query.ErrInvalidSort) {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(res)
+	}
+}
+
+func (h *Handler) AdminModerateReview() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in review_dto.ModerateReviewRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		r, err := h.svc.ModerateReview(c.Context(), id, in.Status, in.Note)
+		if errors.Is(err, ec.ErrInvalidReview) {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		return c.JSON(r)
+	}
+}
+
+func (h *Handler) AdminDeleteReview() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.DeleteReview(c.Context(), id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Status(404).JSON(fiber.Map{"message": "not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+// reviewFilter reads rating, verified, photos and sort.
+func reviewFilter(c *fiber.Ctx) (ec.ReviewFilter, error) {
+	f := ec.ReviewFilter{Sort: c.Query("sort"), WithPhotos: c.QueryBool("photos")}
+	var err error
+	if f.Rating, err = params.Int(c, "rating"); err != nil {
+		return f, err
+	}
+	if f.Rating != nil && (*f.Rating < 1 || *f.Rating > 5) {
+		return f, fmt.Errorf("invalid rating")
+	}
+	if f.Verified, err = params.Bool(c, "verified"); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// readPhotos reads the optional "photos" files of a multipart form,
+// refusing oversized files before reading them.
+func (h *Handler) readPhotos(c *fiber.Ctx) ([][]byte, error) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		// a plain form or JSON body carries no photos
+		return nil, nil
+	}
+	files := form.File["photos"]
+	if len(files) > ec.MaxReviewPhotos {
+		return nil, fmt.Errorf("%w: at most %d photos", ec.ErrInvalidReview, ec.MaxReviewPhotos)
+	}
+	out := make([][]byte, 0, len(files))
+	for _, fh := range files {
+		if fh.Size > h.maxBytes {
+			return nil, fmt.Errorf("%w: at most %d MB", ip.ErrTooLarge, h.maxBytes>>20)
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(io.LimitReader(f, h.maxBytes+1))
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, data)
+	}
+	return out, nil
+}
+
+func reviewError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, ip.ErrTooLarge):
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"message": err.Error()})
+	case errors.Is(err, ip.ErrUnsupportedType):
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{"message": err.Error()})
+	case errors.Is(err, ec.ErrInvalidReview):
+		return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(404).JSON(fiber.Map{"message": "not found"})
+}
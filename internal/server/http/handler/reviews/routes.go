@@ -0,0 +1,19 @@
+package reviews
+
+import "github.com/gofiber/fiber/v2"
+
+func Register(api fiber.Router, h *Handler) {
+	api.Get("/products/:id/reviews", h.ProductReviews())
+}
+
+func RegisterUserRoutes(r fiber.Router, h *Handler) {
+	r.Get("/products/:id/review", h.UserReview())
+	r.Post("/products/:id/review", h.SubmitReview())
+	r.Delete("/reviews/:id", h.DeleteUserReview())
+}
+
+func RegisterAdminRoutes(admin fiber.Router, h *Handler) {
+	admin.Get("/reviews", h.AdminListReviews())
+	admin.Patch("/reviews/:id", h.AdminModerateReview())
+	admin.Delete("/reviews/:id", h.AdminDeleteReview())
+}
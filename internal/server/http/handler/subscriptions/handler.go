@@ -0,0 +1,113 @@
+package subscriptions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	notifications_dto "furniture-shop/internal/dtos/notifications"
+	en "furniture-shop/internal/entities/notification"
+	"furniture-shop/internal/service"
+	vld "furniture-shop/internal/validation"
+)
+
+type Handler struct {
+	svc service.ProductSubscriptionService
+}
+
+func NewSubscriptionsHandler(svc service.ProductSubscriptionService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Subscribe serves both the guest and the user route; signed-in customers
+// are subscribed with their account address.
+func (h *Handler) Subscribe() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, _ := c.Locals("user_id").(uint)
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in notifications_dto.ProductSubscriptionRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		sub, err := h.svc.Subscribe(c.Context(), uid, id, in)
+		if err != nil {
+			if errors.Is(err, en.ErrInvalidSubscription) {
+				return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+			}
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.Status(201).JSON(sub)
+	}
+}
+
+// Confirm activates the guest subscription of a confirmation link; no login
+// is needed.
+func (h *Handler) Confirm() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sub, err := h.svc.Confirm(c.Context(), c.Params("token"))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Status(404).JSON(fiber.Map{"message": "not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(sub)
+	}
+}
+
+// Unsubscribe ends the subscription of an unsubscribe link; no login is
+// needed.
+func (h *Handler) Unsubscribe() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sub, err := h.svc.Unsubscribe(c.Context(), c.Params("token"))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Status(404).JSON(fiber.Map{"message": "not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(sub)
+	}
+}
+
+func (h *Handler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		subs, err := h.svc.ListUser(c.Context(), uid)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(subs)
+	}
+}
+
+func (h *Handler) Delete() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.DeleteUser(c.Context(), uid, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Status(404).JSON(fiber.Map{"message": "not found"})
+			}
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
@@ -0,0 +1,17 @@
+package subscriptions
+
+import "github.com/gofiber/fiber/v2"
+
+// Register adds the guest routes: subscribing by email address and the
+// targets of confirmation and unsubscribe links.
+func Register(api fiber.Router, h *Handler) {
+	api.Post("/products/:id/subscriptions", h.Subscribe())
+	api.Post("/subscriptions/:token/confirm", h.Confirm())
+	api.Delete("/subscriptions/:token", h.Unsubscribe())
+}
+
+func RegisterUserRoutes(r fiber.Router, h *Handler) {
+	r.Post("/products/:id/subscriptions", h.Subscribe())
+	r.Get("/subscriptions", h.List())
+	r.Delete("/subscriptions/:id", h.Delete())
+}
@@ -0,0 +1,83 @@
+package transfer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/catalogio"
+)
+
+type Handler struct {
+	svc service.CatalogTransferService
+}
+
+func NewTransferHandler(svc service.CatalogTransferService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Export downloads the catalog as ?format=json (default), or one ?kind= of
+// records as ?format=csv.
+func (h *Handler) Export() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format, kind := c.Query("format", catalogio.FormatJSON), c.Query("kind")
+		doc, err := h.svc.Export(c.Context())
+		if errors.Is(err, ec.ErrMissingSKU) {
+			return c.Status(409).JSON(fiber.Map{"message": err.Error()})
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		var buf bytes.Buffer
+		if err := catalogio.Encode(&buf, doc, format, kind); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		name := "catalog.json"
+		c.Type("json")
+		if format == catalogio.FormatCSV {
+			name = kind + ".csv"
+			c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+		}
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name))
+		return c.Send(buf.Bytes())
+	}
+}
+
+// Import reads a document from the multipart "file" field or the raw body.
+// With ?dry_run=true nothing is written and the report shows what would be
+// created and updated. Any invalid row rejects the whole import with 422.
+func (h *Handler) Import() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format, kind := c.Query("format", catalogio.FormatJSON), c.Query("kind")
+		dryRun := c.QueryBool("dry_run")
+		var r io.Reader = bytes.NewReader(c.Body())
+		if fh, err := c.FormFile("file"); err == nil {
+			f, err := fh.Open()
+			if err != nil {
+				return c.Status(400).JSON(fiber.Map{"message": "invalid file"})
+			}
+			defer f.Close()
+			r = f
+		}
+		doc, rowErrs, err := catalogio.Decode(r, format, kind)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+		}
+		if len(rowErrs) > 0 {
+			return c.Status(422).JSON(ec.ImportReport{DryRun: dryRun, Errors: rowErrs})
+		}
+		rep, err := h.svc.Import(c.Context(), doc, dryRun)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		if len(rep.Errors) > 0 {
+			return c.Status(422).JSON(rep)
+		}
+		return c.JSON(rep)
+	}
+}
@@ -0,0 +1,8 @@
+package transfer
+
+import "github.com/gofiber/fiber/v2"
+
+func RegisterAdminRoutes(admin fiber.Router, h *Handler) {
+	admin.Get("/catalog/export", h.Export())
+	admin.Post("/catalog/import", h.Import())
+}
@@ -0,0 +1,92 @@
+package translations
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"furniture-shop/internal/config"
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service"
+)
+
+type Handler struct {
+	svc service.TranslationService
+}
+
+func NewTranslationsHandler(svc service.TranslationService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Locales lists the default locale and the locales translations can be
+// written in, with the translatable fields of each record kind.
+func (h *Handler) Locales() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"default":   config.Configurations.Locales.Default,
+			"supported": config.Configurations.Locales.Supported,
+			"fields":    ec.TranslatableFields,
+		})
+	}
+}
+
+// List returns every translation of a record: kind is department,
+// category, product or option.
+func (h *Handler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		items, err := h.svc.List(c.Context(), c.Params("kind"), id)
+		if err != nil {
+			return translationError(c, err)
+		}
+		return c.JSON(items)
+	}
+}
+
+// Save takes a JSON object of field names to translated text; an empty
+// string removes a field's translation.
+func (h *Handler) Save() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var values map[string]string
+		if err := c.BodyParser(&values); err != nil || len(values) == 0 {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		items, err := h.svc.Save(c.Context(), c.Params("kind"), id, c.Params("locale"), values)
+		if err != nil {
+			return translationError(c, err)
+		}
+		return c.JSON(items)
+	}
+}
+
+func (h *Handler) Delete() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.Delete(c.Context(), c.Params("kind"), id, c.Params("locale")); err != nil {
+			return translationError(c, err)
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+func translationError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, ec.ErrInvalidTranslation) {
+		return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(404).JSON(fiber.Map{"message": "not found"})
+	}
+	return c.Status(500).JSON(fiber.Map{"message": "server error"})
+}
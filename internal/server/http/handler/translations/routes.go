@@ -0,0 +1,10 @@
+package translations
+
+import "github.com/gofiber/fiber/v2"
+
+func RegisterAdminRoutes(admin fiber.Router, h *Handler) {
+	admin.Get("/locales", h.Locales())
+	admin.Get("/translations/:kind/:id", h.List())
+	admin.Put("/translations/:kind/:id/:locale", h.Save())
+	admin.Delete("/translations/:kind/:id/:locale", h.Delete())
+}
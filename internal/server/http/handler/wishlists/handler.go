@@ -0,0 +1,250 @@
+package wishlists
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+
+	wishlist_dto "furniture-shop/internal/dtos/wishlists"
+	eo "furniture-shop/internal/entities/orders"
+	"furniture-shop/internal/service"
+	vld "furniture-shop/internal/validation"
+)
+
+type Handler struct {
+	svc service.WishlistService
+}
+
+func NewWishlistsHandler(svc service.WishlistService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Shared returns a wishlist by its share link; no login is needed.
+func (h *Handler) Shared() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		w, err := h.svc.GetShared(c.Context(), c.Params("token"))
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		return c.JSON(w)
+	}
+}
+
+func (h *Handler) List() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		lists, err := h.svc.List(c.Context(), uid)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{"message": "server error"})
+		}
+		return c.JSON(lists)
+	}
+}
+
+func (h *Handler) Create() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var in wishlist_dto.WishlistRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		w, err := h.svc.Create(c.Context(), uid, in.Name)
+		if err != nil {
+			return wishlistError(c, err)
+		}
+		return c.Status(201).JSON(w)
+	}
+}
+
+func (h *Handler) Get() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		w, err := h.svc.Get(c.Context(), uid, id)
+		if err != nil {
+			return wishlistError(c, err)
+		}
+		return c.JSON(w)
+	}
+}
+
+func (h *Handler) Rename() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in wishlist_dto.WishlistRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		w, err := h.svc.Rename(c.Context(), uid, id, in.Name)
+		if err != nil {
+			return wishlistError(c, err)
+		}
+		return c.JSON(w)
+	}
+}
+
+func (h *Handler) Delete() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if err := h.svc.Delete(c.Context(), uid, id); err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+// Share turns the share link of a wishlist on or off.
+func (h *Handler) Share(shared bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		w, err := h.svc.Share(c.Context(), uid, id, shared)
+		if err != nil {
+			return wishlistError(c, err)
+		}
+		return c.JSON(w)
+	}
+}
+
+func (h *Handler) AddItem() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in wishlist_dto.WishlistItemRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		item, err := h.svc.AddItem(c.Context(), uid, id, in)
+		if err != nil {
+			return wishlistError(c, err)
+		}
+		return c.Status(201).JSON(item)
+	}
+}
+
+func (h *Handler) UpdateItem() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id, itemID uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if _, err := fmt.Sscan(c.Params("item"), &itemID); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid item id"})
+		}
+		var in wishlist_dto.WishlistItemRequest
+		if err := c.BodyParser(&in); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+		}
+		if err := vld.ValidateStruct(in); err != nil {
+			return err
+		}
+		if err := h.svc.UpdateItem(c.Context(), uid, id, itemID, in); err != nil {
+			return wishlistError(c, err)
+		}
+		return c.JSON(fiber.Map{"message": "updated"})
+	}
+}
+
+func (h *Handler) RemoveItem() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id, itemID uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		if _, err := fmt.Sscan(c.Params("item"), &itemID); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid item id"})
+		}
+		if err := h.svc.RemoveItem(c.Context(), uid, id, itemID); err != nil {
+			return c.Status(404).JSON(fiber.Map{"message": "not found"})
+		}
+		return c.JSON(fiber.Map{"message": "deleted"})
+	}
+}
+
+// MoveToCart moves wishlist items to the cart and returns the cart.
+func (h *Handler) MoveToCart() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		uid, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(401).JSON(fiber.Map{"message": "unauthorized"})
+		}
+		var id uint
+		if _, err := fmt.Sscan(c.Params("id"), &id); err != nil {
+			return c.Status(400).JSON(fiber.Map{"message": "invalid id"})
+		}
+		var in wishlist_dto.MoveToCartRequest
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&in); err != nil {
+				return c.Status(400).JSON(fiber.Map{"message": "invalid request"})
+			}
+		}
+		cart, err := h.svc.MoveToCart(c.Context(), uid, id, in)
+		if err != nil {
+			return wishlistError(c, err)
+		}
+		return c.JSON(cart)
+	}
+}
+
+func wishlistError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, eo.ErrInvalidWishlist) {
+		return c.Status(400).JSON(fiber.Map{"message": err.Error()})
+	}
+	return c.Status(404).JSON(fiber.Map{"message": "not found"})
+}
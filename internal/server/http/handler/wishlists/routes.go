@@ -0,0 +1,21 @@
+package wishlists
+
+import "github.com/gofiber/fiber/v2"
+
+func Register(api fiber.Router, h *Handler) {
+	api.Get("/wishlists/shared/:token", h.Shared())
+}
+
+func RegisterUserRoutes(r fiber.Router, h *Handler) {
+	r.Get("/wishlists", h.List())
+	r.Post("/wishlists", h.Create())
+	r.Get("/wishlists/:id", h.Get())
+	r.Patch("/wishlists/:id", h.Rename())
+	r.Delete("/wishlists/:id", h.Delete())
+	r.Post("/wishlists/:id/share", h.Share(true))
+	r.Delete("/wishlists/:id/share", h.Share(false))
+	r.Post("/wishlists/:id/items", h.AddItem())
+	r.Patch("/wishlists/:id/items/:item", h.UpdateItem())
+	r.Delete("/wishlists/:id/items/:item", h.RemoveItem())
+	r.Post("/wishlists/:id/move-to-cart", h.MoveToCart())
+}
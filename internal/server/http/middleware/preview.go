@@ -0,0 +1,23 @@
+package middleware
+
+import "github.com/gofiber/fiber/v2"
+
+// Preview lets admins look at unpublished catalog content: a request with
+// ?preview=true must carry a valid token, and is served as a preview when
+// the token belongs to an admin (see IsPreview). Other requests pass through
+// anonymously.
+func Preview() fiber.Handler {
+	auth := JWTAuth()
+	return func(c *fiber.Ctx) error {
+		if !c.QueryBool("preview") {
+			return c.Next()
+		}
+		c.Locals("preview", true)
+		return auth(c)
+	}
+}
+
+// IsPreview reports whether an admin asked to see unpublished content.
+func IsPreview(c *fiber.Ctx) bool {
+	return c.Locals("preview") == true && c.Locals("user_role") == "admin"
+}
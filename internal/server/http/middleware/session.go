@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SessionHeader carries the anonymous browser session id generated by the
+// frontend. It groups a visitor's product views without requiring a login.
+const SessionHeader = "X-Session-ID"
+
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9-]{8,64}$`)
+
+// Session stores a well-formed session id from SessionHeader in
+// c.Locals("session_id"); requests without one are served anonymously.
+func Session() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if id := c.Get(SessionHeader); sessionIDPattern.MatchString(id) {
+			c.Locals("session_id", id)
+		}
+		return c.Next()
+	}
+}
+
+// SessionKey identifies the visitor for event deduplication: the browser
+// session id when sent, else the signed-in user, else the client address.
+func SessionKey(c *fiber.Ctx) string {
+	if id, ok := c.Locals("session_id").(string); ok {
+		return id
+	}
+	if uid, ok := c.Locals("user_id").(uint); ok {
+		return fmt.Sprintf("user:%d", uid)
+	}
+	return "ip:" + c.IP()
+}
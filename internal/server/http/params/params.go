@@ -0,0 +1,196 @@
+// Package params parses the query string parameters shared by listing
+// endpoints: pagination and the product and order filters.
+package params
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	ec "furniture-shop/internal/entities/catalog"
+	eo "furniture-shop/internal/entities/orders"
+	"furniture-shop/internal/storage/query"
+)
+
+// Page reads ?page= (1-based) and ?size=.
+func Page(c *fiber.Ctx) query.Page {
+	return query.NewPage(c.QueryInt("page", 1), c.QueryInt("size", query.DefaultSize))
+}
+
+func Float(c *fiber.Ctx, key string) (*float64, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &f, nil
+}
+
+func Int(c *fiber.Ctx, key string) (*int, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &n, nil
+}
+
+func Uint(c *fiber.Ctx, key string) (uint, error) {
+	v := c.Query(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s", key)
+	}
+	return uint(n), nil
+}
+
+// Uints reads a comma-separated list of ids.
+func Uints(c *fiber.Ctx, key string) ([]uint, error) {
+	var out []uint
+	for _, v := range strings.Split(c.Query(key), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s", key)
+		}
+		out = append(out, uint(n))
+	}
+	return out, nil
+}
+
+func Bool(c *fiber.Ctx, key string) (*bool, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &b, nil
+}
+
+// Date reads a YYYY-MM-DD or RFC 3339 timestamp.
+func Date(c *fiber.Ctx, key string) (*time.Time, error) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid %s", key)
+}
+
+// ProductFilter reads price_min, price_max, material, in_stock, rating_min,
+// {width,height,depth}_{min,max}, repeated option=[type:]name, repeated
+// attr=code:value or attr=code:min..max (either bound may be left out) and
+// sort.
+func ProductFilter(c *fiber.Ctx) (ec.ProductFilter, error) {
+	f := ec.ProductFilter{Material: c.Query("material"), Sort: c.Query("sort")}
+	var err error
+	if f.PriceMin, err = Float(c, "price_min"); err != nil {
+		return f, err
+	}
+	if f.PriceMax, err = Float(c, "price_max"); err != nil {
+		return f, err
+	}
+	if f.InStock, err = Bool(c, "in_stock"); err != nil {
+		return f, err
+	}
+	if f.RatingMin, err = Float(c, "rating_min"); err != nil {
+		return f, err
+	}
+	for _, dim := range []struct {
+		name string
+		rng  *ec.IntRange
+	}{{"width", &f.Width}, {"height", &f.Height}, {"depth", &f.Depth}} {
+		if dim.rng.Min, err = Int(c, dim.name+"_min"); err != nil {
+			return f, err
+		}
+		if dim.rng.Max, err = Int(c, dim.name+"_max"); err != nil {
+			return f, err
+		}
+	}
+	for _, raw := range c.Context().QueryArgs().PeekMulti("option") {
+		o := ec.OptionFilter{Name: strings.TrimSpace(string(raw))}
+		if typ, name, ok := strings.Cut(o.Name, ":"); ok {
+			o.Type, o.Name = strings.TrimSpace(typ), strings.TrimSpace(name)
+		}
+		if o.Name == "" {
+			return f, fmt.Errorf("invalid option")
+		}
+		f.Options = append(f.Options, o)
+	}
+	for _, raw := range c.Context().QueryArgs().PeekMulti("attr") {
+		a, err := attributeFilter(string(raw))
+		if err != nil {
+			return f, err
+		}
+		f.Attributes = append(f.Attributes, a)
+	}
+	return f, nil
+}
+
+func attributeFilter(raw string) (ec.AttributeFilter, error) {
+	code, value, ok := strings.Cut(raw, ":")
+	a := ec.AttributeFilter{Code: strings.TrimSpace(code), Value: strings.TrimSpace(value)}
+	if !ok || a.Code == "" || a.Value == "" {
+		return a, fmt.Errorf("invalid attr")
+	}
+	lo, hi, ok := strings.Cut(a.Value, "..")
+	if !ok {
+		return a, nil
+	}
+	for _, b := range []struct {
+		raw string
+		dst **float64
+	}{{lo, &a.Min}, {hi, &a.Max}} {
+		if b.raw = strings.TrimSpace(b.raw); b.raw == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(b.raw, 64)
+		if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+			return a, fmt.Errorf("invalid attr")
+		}
+		*b.dst = &n
+	}
+	if a.Min == nil && a.Max == nil {
+		return a, fmt.Errorf("invalid attr")
+	}
+	a.Value = ""
+	return a, nil
+}
+
+// OrderFilter reads status, payment_status, user_id, from, to and sort.
+func OrderFilter(c *fiber.Ctx) (eo.OrderFilter, error) {
+	f := eo.OrderFilter{Status: c.Query("status"), PaymentStatus: c.Query("payment_status"), Sort: c.Query("sort")}
+	var err error
+	if f.UserID, err = Uint(c, "user_id"); err != nil {
+		return f, err
+	}
+	if f.From, err = Date(c, "from"); err != nil {
+		return f, err
+	}
+	if f.To, err = Date(c, "to"); err != nil {
+		return f, err
+	}
+	return f, nil
+}
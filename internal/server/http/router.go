@@ -3,45 +3,108 @@ package http
 import (
 	"github.com/gofiber/fiber/v2"
 
+	"furniture-shop/internal/config"
 	ha "furniture-shop/internal/server/http/handler/admin"
+	han "furniture-shop/internal/server/http/handler/analytics"
 	hau "furniture-shop/internal/server/http/handler/auth"
+	hb "furniture-shop/internal/server/http/handler/blobs"
 	hc "furniture-shop/internal/server/http/handler/catalog"
+	him "furniture-shop/internal/server/http/handler/images"
+	hi "furniture-shop/internal/server/http/handler/invoices"
+	hn "furniture-shop/internal/server/http/handler/notifications"
 	ho "furniture-shop/internal/server/http/handler/orders"
+	hob "furniture-shop/internal/server/http/handler/outbox"
 	hp "furniture-shop/internal/server/http/handler/payments"
+	hq "furniture-shop/internal/server/http/handler/questions"
+	hr "furniture-shop/internal/server/http/handler/recommendations"
+	hrv "furniture-shop/internal/server/http/handler/reviews"
+	hs "furniture-shop/internal/server/http/handler/subscriptions"
+	ht "furniture-shop/internal/server/http/handler/transfer"
+	htr "furniture-shop/internal/server/http/handler/translations"
+	hw "furniture-shop/internal/server/http/handler/wishlists"
 	"furniture-shop/internal/server/http/middleware"
+	"furniture-shop/internal/service/blob"
 )
 
 func buildRoutes(s *Server) {
 	s.app.Get("/health", func(c *fiber.Ctx) error { return c.SendString("ok") })
-	api := s.app.Group("/api")
+	api := s.app.Group("/api", middleware.Session())
 
 	authH := hau.NewAuthHandler(s.svc.Auth)
-	catalogH := hc.NewCatalogHandler(s.svc.Catalog)
-	ordersH := ho.NewOrdersHandler(s.svc.Orders)
-	cartH := ho.NewCartHandler(s.svc.Cart)
-	adminH := ha.NewAdminHandler(s.svc.Admin)
+	catalogH := hc.NewCatalogHandler(s.svc.Catalog, s.svc.Recommend, s.svc.Analytics)
+	ordersH := ho.NewOrdersHandler(s.svc.Orders, s.svc.Analytics)
+	cartH := ho.NewCartHandler(s.svc.Cart, s.svc.Analytics)
+	wishlistsH := hw.NewWishlistsHandler(s.svc.Wishlists)
+	adminH := ha.NewAdminHandler(s.svc.Admin, s.svc.Blobs)
+	imagesH := him.NewImagesHandler(s.svc.Images, config.Configurations.Images.MaxUploadMB)
 	paymentsH := hp.NewPaymentsHandler(s.svc.Payment)
+	invoicesH := hi.NewInvoicesHandler(s.svc.Invoice)
+	notificationsH := hn.NewNotificationsHandler(s.svc.Notification)
+	subscriptionsH := hs.NewSubscriptionsHandler(s.svc.ProductSubs)
+	outboxH := hob.NewOutboxHandler(s.svc.Outbox)
+	recommendationsH := hr.NewRecommendationsHandler(s.svc.Recommend)
+	reviewsH := hrv.NewReviewsHandler(s.svc.Reviews, config.Configurations.Images.MaxUploadMB)
+	questionsH := hq.NewQuestionsHandler(s.svc.Questions)
+	analyticsH := han.NewAnalyticsHandler(s.svc.Analytics)
+	transferH := ht.NewTransferHandler(s.svc.Transfer)
+	translationsH := htr.NewTranslationsHandler(s.svc.Translations)
 
 	// Auth
 	hau.Register(api, authH)
 
 	// Catalog
 	hc.Register(api, catalogH)
+	hc.RegisterSitemap(s.app, catalogH)
+	hrv.Register(api, reviewsH)
+	hq.Register(api, questionsH)
+
+	// Signed downloads of the local blob store
+	if local, ok := s.svc.Blobs.(*blob.LocalStore); ok {
+		hb.Register(api, hb.NewBlobsHandler(local))
+	}
 
 	// Orders and payments
 	api.Post("/orders", middleware.JWTAuth(), ordersH.CreateOrder())
 	hp.Register(api, paymentsH)
 
+	// Shared wishlists
+	hw.Register(api, wishlistsH)
+
+	// Stock and price alerts for guests
+	hs.Register(api, subscriptionsH)
+
 	// Authenticated user routes
 	authGroup := api.Group("/user", middleware.JWTAuth())
 	authGroup.Get("/me", authH.Me())
 	authGroup.Get("/orders", ordersH.UserOrders())
 	authGroup.Get("/orders/:id", ordersH.UserOrderDetails())
 	authGroup.Post("/orders/:id/pay", ordersH.PayExistingOrder())
+	authGroup.Patch("/orders/:id/items", ordersH.UserAmendOrder())
+	authGroup.Get("/orders/:id/amendments", ordersH.UserOrderAmendments())
 	// Cart
 	ho.RegisterCartRoutes(authGroup, cartH)
+	// Wishlists
+	hw.RegisterUserRoutes(authGroup, wishlistsH)
+	// Invoices
+	hi.RegisterUserRoutes(authGroup, invoicesH)
+	// Notification preferences and product alerts
+	hn.RegisterUserRoutes(authGroup, notificationsH)
+	hs.RegisterUserRoutes(authGroup, subscriptionsH)
+	// Reviews and questions
+	hrv.RegisterUserRoutes(authGroup, reviewsH)
+	hq.RegisterUserRoutes(authGroup, questionsH)
 
 	// Admin routes
 	adminGroup := api.Group("/admin", middleware.JWTAuth(), middleware.RequireAdmin)
 	ha.RegisterAdminRoutes(adminGroup, adminH, ordersH)
+	him.RegisterAdminRoutes(adminGroup, imagesH)
+	hi.RegisterAdminRoutes(adminGroup, invoicesH)
+	hn.RegisterAdminRoutes(adminGroup, notificationsH)
+	hob.RegisterAdminRoutes(adminGroup, outboxH)
+	hr.RegisterAdminRoutes(adminGroup, recommendationsH)
+	hrv.RegisterAdminRoutes(adminGroup, reviewsH)
+	hq.RegisterAdminRoutes(adminGroup, questionsH)
+	han.RegisterAdminRoutes(adminGroup, analyticsH)
+	ht.RegisterAdminRoutes(adminGroup, transferH)
+	htr.RegisterAdminRoutes(adminGroup, translationsH)
 }
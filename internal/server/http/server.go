@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -11,8 +12,11 @@ import (
 
 	"furniture-shop/internal/config"
 	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/blob"
 )
 
+const shutdownTimeout = 10 * time.Second
+
 type Server struct {
 	app *fiber.App
 	svc *service.Service
@@ -20,6 +24,8 @@ type Server struct {
 
 func NewServer(svc *service.Service) *Server {
 	app := fiber.New(fiber.Config{
+		// room for an image upload plus the multipart framing
+		BodyLimit: (config.Configurations.Images.MaxUploadMB + 1) << 20,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			if verrs, ok := err.(validator.ValidationErrors); ok {
 				out := make([]fiber.Map, 0, len(verrs))
@@ -45,9 +51,14 @@ func NewServer(svc *service.Service) *Server {
 		AllowOrigins:     strings.Join(config.Configurations.CORSOrigins, ","),
 		AllowCredentials: true,
 		AllowMethods:     "GET,POST,PATCH,DELETE,PUT",
-		AllowHeaders:     "Authorization,Content-Type",
+		AllowHeaders:     "Authorization,Content-Type,Accept-Language,X-Session-ID",
 	}))
-	app.Static("/uploads", "./uploads")
+	if local, ok := svc.Blobs.(*blob.LocalStore); ok {
+		// originals are private and only reachable through signed URLs
+		app.Static("/uploads", local.Dir(), fiber.Static{
+			Next: func(c *fiber.Ctx) bool { return strings.HasPrefix(c.Path(), blob.LocalURLPrefix+"originals/") },
+		})
+	}
 	s := &Server{app: app, svc: svc}
 	buildRoutes(s)
 	return s
@@ -61,3 +72,9 @@ func (s *Server) Run() error {
 	log.Printf("Server listening on :%s", port)
 	return s.app.Listen(":" + port)
 }
+
+// Shutdown stops taking requests and waits for the ones in flight, up to
+// shutdownTimeout.
+func (s *Server) Shutdown() error {
+	return s.app.ShutdownWithTimeout(shutdownTimeout)
+}
@@ -0,0 +1,110 @@
+// Package blob stores uploaded files behind the Store interface, on the
+// local disk or in an S3-compatible bucket, so that several server
+// instances can share them.
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"furniture-shop/internal/config"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// Object describes a stored file.
+type Object struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Store keeps files under slash-separated keys.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+	// List calls fn for every object whose key starts with prefix.
+	List(ctx context.Context, prefix string, fn func(Object) error) error
+	// URL is the public address of a key; KeyFromURL reverses it and also
+	// accepts absolute forms of relative URLs.
+	URL(key string) string
+	KeyFromURL(url string) (string, bool)
+	// SignedURL grants temporary read access to a key that is not public.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// NewFromConfig opens the store selected in appconfig.json. The local store
+// signs its URLs with BLOB_SIGNING_KEY, which must differ from JWT_SECRET so
+// that neither key can be used to forge what the other signs.
+func NewFromConfig() (Store, error) {
+	cfg := config.Configurations.Blobs
+	switch cfg.Driver {
+	case "local":
+		secret := config.Env.BlobSigningKey
+		if secret == "" {
+			return nil, fmt.Errorf("BLOB_SIGNING_KEY is required by the local blob store")
+		}
+		if secret == config.Env.JWTSecret {
+			return nil, fmt.Errorf("BLOB_SIGNING_KEY must differ from JWT_SECRET")
+		}
+		return NewLocalStore(cfg.LocalDir, secret), nil
+	case "s3":
+		return NewS3Store(cfg.S3, config.Env.S3AccessKey, config.Env.S3SecretKey)
+	}
+	return nil, fmt.Errorf("unknown blob driver %q", cfg.Driver)
+}
+
+// ContentKey derives the key of data from its SHA-256, so identical uploads
+// share one object: <prefix>/ab/abcdef…<ext>.
+func ContentKey(prefix string, data []byte, ext string) string {
+	sum := sha256.Sum256(data)
+	h := hex.EncodeToString(sum[:])
+	return prefix + "/" + h[:2] + "/" + h + ext
+}
+
+// PutContent stores data under its content key unless an identical object
+// is already there, and returns the key.
+func PutContent(ctx context.Context, s Store, prefix string, data []byte, ext, contentType string) (string, error) {
+	key := ContentKey(prefix, data, ext)
+	ok, err := s.Exists(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		if err := s.Put(ctx, key, data, contentType); err != nil {
+			return "", err
+		}
+	}
+	return key, nil
+}
+
+// Canonical keeps links to files of s in their canonical form, e.g. without
+// the API origin the admin UI prefixes to local uploads, so that the admin
+// form and catalog imports store them alike. Other URLs are kept as given.
+func Canonical(s Store, u string) string {
+	u = strings.TrimSpace(u)
+	if key, ok := s.KeyFromURL(u); ok {
+		return s.URL(key)
+	}
+	return u
+}
+
+// validKey rejects keys that could escape the store root.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
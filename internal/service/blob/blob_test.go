@@ -0,0 +1,20 @@
+package blob
+
+import "testing"
+
+func TestCanonical(t *testing.T) {
+	s := NewLocalStore(t.TempDir(), "signing-key")
+	for _, tc := range []struct {
+		url, want string
+	}{
+		{"/uploads/images/a.jpg", "/uploads/images/a.jpg"},
+		{" http://localhost:8080/uploads/images/a.jpg ", "/uploads/images/a.jpg"},
+		{"https://cdn.example.com/a.jpg", "https://cdn.example.com/a.jpg"},
+		{"/uploads/../secret", "/uploads/../secret"},
+		{"", ""},
+	} {
+		if got := Canonical(s, tc.url); got != tc.want {
+			t.Errorf("Canonical(%q) = %q, want %q", tc.url, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalURLPrefix is where the local store directory is served.
+const LocalURLPrefix = "/uploads/"
+
+// localSignedPrefix serves keys through signed URLs, see LocalStore.Verify.
+const localSignedPrefix = "/api/blobs/"
+
+// LocalStore keeps files in a directory of the server. It only suits a
+// single instance, or several sharing the directory over a network mount.
+type LocalStore struct {
+	dir    string
+	secret []byte
+}
+
+func NewLocalStore(dir, secret string) *LocalStore {
+	return &LocalStore{dir: dir, secret: []byte(secret)}
+}
+
+// Dir is the directory holding the files.
+func (s *LocalStore) Dir() string { return s.dir }
+
+func (s *LocalStore) path(key string) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes through a temporary file, so readers never see a partial file
+// when two instances store the same content at once.
+func (s *LocalStore) Put(_ context.Context, key string, data []byte, _ string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *LocalStore) Get(_ context.Context, key string) ([]byte, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (s *LocalStore) Exists(_ context.Context, key string) (bool, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Delete removes the file and its directory once empty.
+func (s *LocalStore) Delete(_ context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	_ = os.Remove(filepath.Dir(p))
+	return nil
+}
+
+func (s *LocalStore) List(ctx context.Context, prefix string, fn func(Object) error) error {
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if d.IsDir() {
+			// skip directories that cannot contain a match
+			if key != "." && !strings.HasPrefix(key+"/", prefix) && !strings.HasPrefix(prefix, key+"/") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasPrefix(key, prefix) || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(Object{Key: key, Size: info.Size(), ModTime: info.ModTime()})
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *LocalStore) URL(key string) string {
+	return LocalURLPrefix + key
+}
+
+// KeyFromURL accepts /uploads/<key>, also behind any origin.
+func (s *LocalStore) KeyFromURL(u string) (string, bool) {
+	if strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://") {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			return "", false
+		}
+		u = parsed.Path
+	}
+	key, ok := strings.CutPrefix(u, LocalURLPrefix)
+	if !ok || !validKey(key) {
+		return "", false
+	}
+	return key, true
+}
+
+// SignedURL points at the signed download route, whose handler checks the
+// signature with Verify.
+func (s *LocalStore) SignedURL(_ context.Context, key string, ttl time.Duration) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	q := url.Values{"expires": {expires}, "signature": {s.sign(key, expires)}}
+	return localSignedPrefix + key + "?" + q.Encode(), nil
+}
+
+// Verify checks a signed URL's signature and expiry.
+func (s *LocalStore) Verify(key, expires, signature string, now time.Time) bool {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > exp || !validKey(key) {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.sign(key, expires)))
+}
+
+func (s *LocalStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,57 @@
+package blob
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalStoreVerify(t *testing.T) {
+	s := NewLocalStore(t.TempDir(), "signing-key")
+	raw, err := s.SignedURL(context.Background(), "images/a.jpg", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := strings.TrimPrefix(u.Path, localSignedPrefix)
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+	now := time.Now()
+	tampered := []byte(signature)
+	if tampered[0] == '0' {
+		tampered[0] = '1'
+	} else {
+		tampered[0] = '0'
+	}
+
+	for _, tc := range []struct {
+		name                    string
+		key, expires, signature string
+		store                   *LocalStore
+		now                     time.Time
+		want                    bool
+	}{
+		{name: "valid", key: key, expires: expires, signature: signature, now: now, want: true},
+		{name: "expired", key: key, expires: expires, signature: signature, now: now.Add(time.Hour + time.Minute)},
+		{name: "tampered signature", key: key, expires: expires, signature: string(tampered), now: now},
+		{name: "other key", key: "images/b.jpg", expires: expires, signature: signature, now: now},
+		{name: "extended expiry", key: key, expires: expires + "0", signature: signature, now: now},
+		{name: "other signing key", key: key, expires: expires, signature: signature, store: NewLocalStore(t.TempDir(), "other-key"), now: now},
+		{name: "invalid key", key: "../a.jpg", expires: expires, signature: signature, now: now},
+		{name: "invalid expiry", key: key, expires: "soon", signature: signature, now: now},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := s
+			if tc.store != nil {
+				store = tc.store
+			}
+			if got := store.Verify(tc.key, tc.expires, tc.signature, tc.now); got != tc.want {
+				t.Errorf("Verify = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
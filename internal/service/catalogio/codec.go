@@ -0,0 +1,80 @@
+// Package catalogio reads and writes catalog documents as JSON or as CSV
+// files of one record kind each.
+package catalogio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	ec "furniture-shop/internal/entities/catalog"
+)
+
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// records returns a pointer to the slice of doc holding kind.
+func records(doc *ec.CatalogDocument, kind string) (any, error) {
+	switch kind {
+	case ec.TransferDepartments:
+		return &doc.Departments, nil
+	case ec.TransferCategories:
+		return &doc.Categories, nil
+	case ec.TransferProducts:
+		return &doc.Products, nil
+	case ec.TransferOptions:
+		return &doc.Options, nil
+	}
+	return nil, errors.New("kind must be one of departments, categories, products, options")
+}
+
+// Decode reads a JSON document, or a CSV file of the given kind. Values that
+// cannot be read are returned as import errors rather than failing the call.
+func Decode(r io.Reader, format, kind string) (*ec.CatalogDocument, []ec.ImportError, error) {
+	doc := &ec.CatalogDocument{}
+	switch format {
+	case FormatJSON:
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(doc); err != nil {
+			return nil, nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return doc, nil, nil
+	case FormatCSV:
+		out, err := records(doc, kind)
+		if err != nil {
+			return nil, nil, err
+		}
+		rowErrs, err := ReadCSV(r, out)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		var errs []ec.ImportError
+		for _, e := range rowErrs {
+			errs = append(errs, ec.ImportError{Kind: kind, Row: e.Row, Field: e.Field, Message: e.Message})
+		}
+		return doc, errs, nil
+	}
+	return nil, nil, errors.New("format must be json or csv")
+}
+
+// Encode writes the whole document as JSON, or one kind of it as CSV.
+func Encode(w io.Writer, doc *ec.CatalogDocument, format, kind string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	case FormatCSV:
+		rows, err := records(doc, kind)
+		if err != nil {
+			return err
+		}
+		return WriteCSV(w, reflect.ValueOf(rows).Elem().Interface())
+	}
+	return errors.New("format must be json or csv")
+}
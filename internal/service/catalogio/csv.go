@@ -0,0 +1,148 @@
+package catalogio
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// RowError is a value of a CSV record that cannot be read.
+type RowError struct {
+	Row     int
+	Field   string
+	Message string
+}
+
+// columns returns the json names of the fields of a record struct.
+func columns(t reflect.Type) []string {
+	out := make([]string, t.NumField())
+	for i := range out {
+		out[i] = strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+	}
+	return out
+}
+
+// WriteCSV writes a slice of record structs with a header of their json
+// field names.
+func WriteCSV(w io.Writer, rows any) error {
+	v := reflect.ValueOf(rows)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns(v.Type().Elem())); err != nil {
+		return err
+	}
+	for i := 0; i < v.Len(); i++ {
+		rec := v.Index(i)
+		out := make([]string, rec.NumField())
+		for j := range out {
+			out[j] = format(rec.Field(j))
+		}
+		if err := cw.Write(out); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadCSV reads records into out, a pointer to a slice of record structs.
+// Columns are matched by header name; missing columns keep zero values,
+// unknown ones are an error. Unreadable values are reported per row so that
+// a dry run lists every problem at once.
+func ReadCSV(r io.Reader, out any) ([]RowError, error) {
+	slice := reflect.ValueOf(out).Elem()
+	t := slice.Type().Elem()
+	index := map[string]int{}
+	for i, name := range columns(t) {
+		index[name] = i
+	}
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]int, len(header))
+	for i, h := range header {
+		h = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+		f, ok := index[h]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", h)
+		}
+		fields[i] = f
+	}
+	var errs []RowError
+	for row := 1; ; row++ {
+		values, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rec := reflect.New(t).Elem()
+		for i, raw := range values {
+			if err := parse(rec.Field(fields[i]), strings.TrimSpace(raw)); err != nil {
+				errs = append(errs, RowError{Row: row, Field: header[i], Message: err.Error()})
+			}
+		}
+		slice.Set(reflect.Append(slice, rec))
+	}
+	return errs, nil
+}
+
+func format(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return ""
+		}
+		return format(v.Elem())
+	case reflect.String:
+		return v.String()
+	case reflect.Int:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// parse sets a record field from its CSV text; an empty cell leaves the
+// zero value, i.e. nil for optional numbers.
+func parse(v reflect.Value, raw string) error {
+	if raw == "" {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		if err := parse(p.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(p)
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return errors.New("must be a whole number")
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported column type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage/query"
+)
+
+func (s *adminService) ListPriceHistory(ctx context.Context, productID uint, page query.Page) (*query.Result[ec.PriceChange], error) {
+	items, total, err := s.prices.ListHistory(ctx, productID, page)
+	if err != nil {
+		return nil, err
+	}
+	return query.NewResult(items, total, page), nil
+}
+
+func (s *adminService) ListPriceSchedules(ctx context.Context, productID uint) ([]ec.PriceSchedule, error) {
+	return s.prices.ListSchedules(ctx, productID)
+}
+
+// CreatePriceSchedule plans a price change or sale; one that starts now is
+// applied right away. Sales of a product may not overlap and must be below
+// its base price.
+func (s *adminService) CreatePriceSchedule(ctx context.Context, ps *ec.PriceSchedule) error {
+	now := time.Now()
+	if err := ps.Validate(now); err != nil {
+		return err
+	}
+	p, err := s.prods.FindByID(ctx, ps.ProductID)
+	if err != nil {
+		return err
+	}
+	if ps.OptionID != nil && !slices.ContainsFunc(p.Options, func(o ec.ProductOption) bool { return o.ID == *ps.OptionID }) {
+		return fmt.Errorf("%w: product %d has no option %d", ec.ErrInvalidPriceSchedule, p.ID, *ps.OptionID)
+	}
+	if ps.Kind == ec.PriceScheduleSale {
+		if p.Bundle != nil && p.Bundle.Pricing == ec.BundleDiscount {
+			return fmt.Errorf("%w: a discounted bundle is priced from its components", ec.ErrInvalidPriceSchedule)
+		}
+		if ps.Price >= p.BasePrice {
+			return fmt.Errorf("%w: the sale price must be below the base price", ec.ErrInvalidPriceSchedule)
+		}
+		planned, err := s.prices.ListSchedules(ctx, p.ID)
+		if err != nil {
+			return err
+		}
+		for _, o := range planned {
+			if o.Status != ec.PriceScheduleDone && ps.Overlaps(&o) {
+				return fmt.Errorf("%w: overlaps sale %d", ec.ErrInvalidPriceSchedule, o.ID)
+			}
+		}
+	}
+	if err := s.prices.CreateSchedule(ctx, ps); err != nil {
+		return err
+	}
+	if !ps.StartsAt.After(now) {
+		return s.applyPriceSchedule(ctx, ps, now)
+	}
+	return nil
+}
+
+func (s *adminService) CancelPriceSchedule(ctx context.Context, id uint) error {
+	ps, err := s.prices.FindSchedule(ctx, id)
+	if err != nil {
+		return err
+	}
+	switch ps.Status {
+	case ec.PriceSchedulePending:
+		return s.prices.DeleteSchedule(ctx, id)
+	case ec.PriceScheduleActive:
+		return s.applyPriceSchedule(ctx, ps, time.Now())
+	}
+	return fmt.Errorf("%w: schedule %d is already done", ec.ErrInvalidPriceSchedule, id)
+}
+
+// ApplyPriceSchedules starts and ends the price schedules due at now and
+// returns how many it applied. A schedule that fails is logged and retried
+// on the next run without holding up the others.
+func (s *adminService) ApplyPriceSchedules(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.prices.ListDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	applied := 0
+	for i := range due {
+		if err := s.applyPriceSchedule(ctx, &due[i], now); err != nil {
+			log.Printf("prices: apply schedule %d: %v", due[i].ID, err)
+			continue
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// applyPriceSchedule applies the next step of a schedule and lets the
+// search index and product watchers know about the new price.
+func (s *adminService) applyPriceSchedule(ctx context.Context, ps *ec.PriceSchedule, now time.Time) error {
+	before, _ := s.prods.FindByID(ctx, ps.ProductID)
+	if err := s.prices.ApplySchedule(ctx, ps.ID, now); err != nil {
+		return err
+	}
+	s.reindex(ctx, ps.ProductID)
+	s.watch(ctx, before)
+	return nil
+}
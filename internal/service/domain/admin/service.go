@@ -2,10 +2,18 @@ package admin
 
 import (
 	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
 
 	ec "furniture-shop/internal/entities/catalog"
 	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/search"
 	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
 )
 
 type adminService struct {
@@ -13,10 +21,16 @@ type adminService struct {
 	cats    storage.CategoryRepository
 	prods   storage.ProductRepository
 	options storage.ProductOptionRepository
+	archive storage.CatalogArchiveRepository
+	attrs   storage.AttributeRepository
+	bundles storage.BundleRepository
+	prices  storage.PriceRepository
+	indexer *search.Indexer
+	watcher service.ProductWatcher
 }
 
-func NewAdminService(depts storage.DepartmentRepository, cats storage.CategoryRepository, prods storage.ProductRepository, options storage.ProductOptionRepository) service.AdminService {
-	return &adminService{depts: depts, cats: cats, prods: prods, options: options}
+func NewAdminService(depts storage.DepartmentRepository, cats storage.CategoryRepository, prods storage.ProductRepository, options storage.ProductOptionRepository, archive storage.CatalogArchiveRepository, attrs storage.AttributeRepository, bundles storage.BundleRepository, prices storage.PriceRepository, indexer *search.Indexer, watcher service.ProductWatcher) service.AdminService {
+	return &adminService{depts: depts, cats: cats, prods: prods, options: options, archive: archive, attrs: attrs, bundles: bundles, prices: prices, indexer: indexer, watcher: watcher}
 }
 
 func (s *adminService) ListDepartments(ctx context.Context) ([]ec.Department, error) {
@@ -28,11 +42,39 @@ func (s *adminService) CreateDepartment(ctx context.Context, d *ec.Department) e
 }
 
 func (s *adminService) UpdateDepartment(ctx context.Context, id uint, d ec.Department) error {
-	return s.depts.Update(ctx, id, d)
+	if err := s.depts.Update(ctx, id, d); err != nil {
+		return err
+	}
+	if err := s.indexer.Department(ctx, id); err != nil {
+		log.Printf("search: index department %d: %v", id, err)
+	}
+	return nil
+}
+
+// DeleteDepartment archives a department; with cascade its categories and
+// products are archived too, otherwise a department with categories is kept.
+func (s *adminService) DeleteDepartment(ctx context.Context, id uint, cascade bool) error {
+	ids, err := s.archive.Archive(ctx, ec.SlugDepartment, id, cascade)
+	if err != nil {
+		return err
+	}
+	s.unindex(ctx, ids)
+	return nil
+}
+
+func (s *adminService) ListArchivedDepartments(ctx context.Context) ([]ec.Department, error) {
+	return s.depts.ListArchived(ctx)
 }
 
-func (s *adminService) DeleteDepartment(ctx context.Context, id uint) error {
-	return s.depts.Delete(ctx, id)
+func (s *adminService) RestoreDepartment(ctx context.Context, id uint, cascade bool) error {
+	ids, err := s.archive.Restore(ctx, ec.SlugDepartment, id, cascade)
+	if err != nil {
+		return err
+	}
+	for _, pid := range ids {
+		s.reindex(ctx, pid)
+	}
+	return nil
 }
 
 func (s *adminService) ListCategories(ctx context.Context) ([]ec.Category, error) {
@@ -44,27 +86,169 @@ func (s *adminService) CreateCategory(ctx context.Context, c *ec.Category) error
 }
 
 func (s *adminService) UpdateCategory(ctx context.Context, id uint, c ec.Category) error {
-	return s.cats.Update(ctx, id, c)
+	if err := s.cats.Update(ctx, id, c); err != nil {
+		return err
+	}
+	if err := s.indexer.Categories(ctx, id); err != nil {
+		log.Printf("search: index category %d: %v", id, err)
+	}
+	return nil
 }
 
-func (s *adminService) DeleteCategory(ctx context.Context, id uint) error {
-	return s.cats.Delete(ctx, id)
+// DeleteCategory archives a category; with cascade its products are
+// archived too, otherwise a category with products is kept.
+func (s *adminService) DeleteCategory(ctx context.Context, id uint, cascade bool) error {
+	ids, err := s.archive.Archive(ctx, ec.SlugCategory, id, cascade)
+	if err != nil {
+		return err
+	}
+	s.unindex(ctx, ids)
+	return nil
 }
 
-func (s *adminService) ListProducts(ctx context.Context) ([]ec.Product, error) {
-	return s.prods.ListAll(ctx)
+func (s *adminService) ListArchivedCategories(ctx context.Context) ([]ec.Category, error) {
+	return s.cats.ListArchived(ctx)
 }
 
-func (s *adminService) CreateProduct(ctx context.Context, p *ec.Product) error {
-	return s.prods.Create(ctx, p)
+func (s *adminService) RestoreCategory(ctx context.Context, id uint, cascade bool) error {
+	ids, err := s.archive.Restore(ctx, ec.SlugCategory, id, cascade)
+	if err != nil {
+		return err
+	}
+	for _, pid := range ids {
+		s.reindex(ctx, pid)
+	}
+	return nil
 }
 
-func (s *adminService) UpdateProduct(ctx context.Context, id uint, p ec.Product) error {
-	return s.prods.Update(ctx, id, p)
+func (s *adminService) ListProducts(ctx context.Context, f ec.ProductFilter, page query.Page) (*query.Result[ec.Product], error) {
+	items, total, err := s.prods.List(ctx, f, page)
+	if err != nil {
+		return nil, err
+	}
+	return query.NewResult(items, total, page), nil
 }
 
+// CreateProduct saves a new product as a draft unless another lifecycle
+// state is given; it stays off the storefront until published. attrs are
+// checked against the attributes of the product's category.
+func (s *adminService) CreateProduct(ctx context.Context, p *ec.Product, attrs map[string]any) error {
+	if err := p.Schedule(time.Now()); err != nil {
+		return err
+	}
+	values, err := s.parseAttributes(ctx, p.CategoryID, attrs)
+	if err != nil {
+		return err
+	}
+	p.Attributes = values
+	if err := s.prods.Create(ctx, p); err != nil {
+		return err
+	}
+	s.reindex(ctx, p.ID)
+	return nil
+}
+
+// UpdateProduct keeps the lifecycle of the product when p has no status,
+// and its attribute values when attrs is nil.
+func (s *adminService) UpdateProduct(ctx context.Context, id uint, p ec.Product, attrs map[string]any) error {
+	if p.Status != "" {
+		if err := p.Schedule(time.Now()); err != nil {
+			return err
+		}
+	}
+	p.Attributes = nil
+	if attrs != nil {
+		values, err := s.parseAttributes(ctx, p.CategoryID, attrs)
+		if err != nil {
+			return err
+		}
+		p.Attributes = values
+	}
+	before, _ := s.prods.FindByID(ctx, id)
+	if err := s.prods.Update(ctx, id, p); err != nil {
+		return err
+	}
+	s.reindex(ctx, id)
+	s.watch(ctx, before)
+	return nil
+}
+
+func (s *adminService) parseAttributes(ctx context.Context, categoryID uint, attrs map[string]any) ([]ec.ProductAttribute, error) {
+	defs, err := s.attrs.ListByCategory(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return ec.ParseAttributes(defs, attrs)
+}
+
+// DeleteProduct archives a product: it leaves the storefront and carts but
+// stays readable from past orders.
 func (s *adminService) DeleteProduct(ctx context.Context, id uint) error {
-	return s.prods.Delete(ctx, id)
+	ids, err := s.archive.Archive(ctx, ec.SlugProduct, id, false)
+	if err != nil {
+		return err
+	}
+	s.unindex(ctx, ids)
+	return nil
+}
+
+func (s *adminService) RestoreProduct(ctx context.Context, id uint) error {
+	if _, err := s.archive.Restore(ctx, ec.SlugProduct, id, false); err != nil {
+		return err
+	}
+	s.reindex(ctx, id)
+	return nil
+}
+
+func (s *adminService) GetBundle(ctx context.Context, productID uint) (*ec.Bundle, error) {
+	return s.bundles.Find(ctx, productID)
+}
+
+// SaveBundle makes a product a bundle of b's components, or replaces its
+// components. Components must be plain products with their own options,
+// and a component of another bundle cannot become a bundle itself.
+func (s *adminService) SaveBundle(ctx context.Context, productID uint, b *ec.Bundle) error {
+	if _, err := s.prods.FindByID(ctx, productID); err != nil {
+		return err
+	}
+	containing, err := s.bundles.ListContaining(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if len(containing) > 0 {
+		return fmt.Errorf("%w: product %d is a component of bundle %d", ec.ErrInvalidBundle, productID, containing[0])
+	}
+	ids := make([]uint, len(b.Components))
+	for i, c := range b.Components {
+		ids[i] = c.ProductID
+	}
+	products, err := s.prods.ListByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range b.Components {
+		c := &b.Components[i]
+		if j := slices.IndexFunc(products, func(p ec.Product) bool { return p.ID == c.ProductID }); j >= 0 {
+			c.Product = &products[j]
+		}
+	}
+	b.ProductID = productID
+	if err := b.Validate(productID); err != nil {
+		return err
+	}
+	if err := s.bundles.Save(ctx, b); err != nil {
+		return err
+	}
+	s.reindex(ctx, productID)
+	return nil
+}
+
+func (s *adminService) DeleteBundle(ctx context.Context, productID uint) error {
+	if err := s.bundles.Delete(ctx, productID); err != nil {
+		return err
+	}
+	s.reindex(ctx, productID)
+	return nil
 }
 
 func (s *adminService) ListProductOptions(ctx context.Context, productID *uint) ([]ec.ProductOption, error) {
@@ -72,13 +256,127 @@ func (s *adminService) ListProductOptions(ctx context.Context, productID *uint)
 }
 
 func (s *adminService) CreateProductOption(ctx context.Context, o *ec.ProductOption) error {
-	return s.options.Create(ctx, o)
+	if err := s.options.Create(ctx, o); err != nil {
+		return err
+	}
+	s.reindex(ctx, o.ProductID)
+	return nil
 }
 
 func (s *adminService) UpdateProductOption(ctx context.Context, id uint, o ec.ProductOption) error {
-	return s.options.Update(ctx, id, o)
+	prev, err := s.options.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	before, _ := s.prods.FindByID(ctx, prev.ProductID)
+	if err := s.options.Update(ctx, id, o); err != nil {
+		return err
+	}
+	s.reindex(ctx, prev.ProductID)
+	if o.ProductID != prev.ProductID {
+		s.reindex(ctx, o.ProductID)
+	}
+	s.watch(ctx, before)
+	return nil
 }
 
 func (s *adminService) DeleteProductOption(ctx context.Context, id uint) error {
-	return s.options.Delete(ctx, id)
+	prev, err := s.options.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.options.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.reindex(ctx, prev.ProductID)
+	return nil
+}
+
+func (s *adminService) ListAttributes(ctx context.Context, categoryID uint) ([]ec.AttributeDefinition, error) {
+	return s.attrs.ListByCategory(ctx, categoryID)
+}
+
+func (s *adminService) CreateAttribute(ctx context.Context, d *ec.AttributeDefinition) error {
+	cats, err := s.cats.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(cats, func(c ec.Category) bool { return c.ID == d.CategoryID }) {
+		return fmt.Errorf("category %d: %w", d.CategoryID, gorm.ErrRecordNotFound)
+	}
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	return s.attrs.Create(ctx, d)
+}
+
+// UpdateAttribute keeps the category of a definition. Its type can only
+// change while no product has a value for it.
+func (s *adminService) UpdateAttribute(ctx context.Context, id uint, d ec.AttributeDefinition) error {
+	prev, err := s.attrs.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	if d.Type != prev.Type {
+		n, err := s.attrs.CountValues(ctx, id)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("%w: cannot change the type of an attribute used by %d products", ec.ErrInvalidAttribute, n)
+		}
+	}
+	return s.attrs.Update(ctx, id, d)
+}
+
+func (s *adminService) DeleteAttribute(ctx context.Context, id uint) error {
+	if _, err := s.attrs.FindByID(ctx, id); err != nil {
+		return err
+	}
+	return s.attrs.Delete(ctx, id)
+}
+
+func (s *adminService) ReindexSearch(ctx context.Context) (int, error) {
+	return s.indexer.Rebuild(ctx)
+}
+
+// PublishDue applies the publishing schedule at now and returns how many
+// products changed state.
+func (s *adminService) PublishDue(ctx context.Context, now time.Time) (int, error) {
+	ids, err := s.prods.ApplySchedule(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		s.reindex(ctx, id)
+	}
+	return len(ids), nil
+}
+
+// reindex refreshes a product in the search index after a catalog write. The
+// database stays the source of truth, so a failure is only logged; a
+// rebuild brings the index back in sync.
+func (s *adminService) reindex(ctx context.Context, productID uint) {
+	if err := s.indexer.Product(ctx, productID); err != nil {
+		log.Printf("search: index product %d: %v", productID, err)
+	}
+}
+
+func (s *adminService) unindex(ctx context.Context, productIDs []uint) {
+	for _, id := range productIDs {
+		if err := s.indexer.Remove(ctx, id); err != nil {
+			log.Printf("search: remove product %d: %v", id, err)
+		}
+	}
+}
+
+// watch tells the product watcher about a change to a product, given as it
+// was before the change; nil when it could not be loaded.
+func (s *adminService) watch(ctx context.Context, before *ec.Product) {
+	if before != nil {
+		s.watcher.ProductChanged(ctx, before)
+	}
 }
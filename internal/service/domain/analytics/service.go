@@ -0,0 +1,143 @@
+package analytics
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"furniture-shop/internal/config"
+	ea "furniture-shop/internal/entities/analytics"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
+)
+
+// flushTimeout bounds the final write when Run is stopped.
+const flushTimeout = 10 * time.Second
+
+type analyticsService struct {
+	events storage.EventRepository
+	cfg    config.EventsConfig
+	window time.Duration
+	queue  chan ea.Event
+
+	mu sync.Mutex
+	// seen holds the dedup keys queued within the current window, so that
+	// repeats are dropped before reaching the queue. The unique dedup_key
+	// column catches the rest, e.g. across server instances.
+	seen    map[string]time.Time
+	dropped int
+}
+
+func NewAnalyticsService(events storage.EventRepository, cfg config.EventsConfig) service.AnalyticsService {
+	return &analyticsService{
+		events: events,
+		cfg:    cfg,
+		window: time.Duration(cfg.DedupWindowMinutes) * time.Minute,
+		queue:  make(chan ea.Event, cfg.QueueSize),
+		seen:   map[string]time.Time{},
+	}
+}
+
+func (s *analyticsService) Track(e ea.Event) {
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now().UTC()
+	}
+	e.DedupKey = e.Key(s.window)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.seen[e.DedupKey]; ok {
+		return
+	}
+	select {
+	case s.queue <- e:
+		s.seen[e.DedupKey] = e.OccurredAt
+	default:
+		s.dropped++
+	}
+}
+
+// Run writes queued events in batches until ctx is cancelled, then flushes
+// what is left.
+func (s *analyticsService) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(s.cfg.FlushIntervalSeconds) * time.Second)
+	defer ticker.Stop()
+	batch := make([]ea.Event, 0, s.cfg.BatchSize)
+	for {
+		select {
+		case e := <-s.queue:
+			batch = append(batch, e)
+			if len(batch) >= s.cfg.BatchSize {
+				batch = s.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch = s.flush(ctx, batch)
+			s.forget(time.Now().UTC())
+		case <-ctx.Done():
+			for len(s.queue) > 0 {
+				batch = append(batch, <-s.queue)
+			}
+			fctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+			s.flush(fctx, batch)
+			cancel()
+			return
+		}
+	}
+}
+
+// flush writes the batch and returns it emptied. Analytics are best effort:
+// a failed batch is logged and dropped rather than retried.
+func (s *analyticsService) flush(ctx context.Context, batch []ea.Event) []ea.Event {
+	if len(batch) > 0 {
+		if _, err := s.events.InsertBatch(ctx, batch); err != nil {
+			log.Printf("analytics: writing %d events: %v", len(batch), err)
+		}
+	}
+	s.mu.Lock()
+	if s.dropped > 0 {
+		log.Printf("analytics: queue full, dropped %d events", s.dropped)
+		s.dropped = 0
+	}
+	s.mu.Unlock()
+	return batch[:0]
+}
+
+// forget drops dedup keys older than the window.
+func (s *analyticsService) forget(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, at := range s.seen {
+		if now.Sub(at) > s.window {
+			delete(s.seen, key)
+		}
+	}
+}
+
+// Rollup refreshes the daily stats of yesterday (for late events) and today,
+// recomputes product popularity and prunes raw events past their retention.
+func (s *analyticsService) Rollup(ctx context.Context, now time.Time) error {
+	now = now.UTC()
+	for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+		if err := s.events.RollupDay(ctx, day); err != nil {
+			return err
+		}
+	}
+	if err := s.events.RecomputePopularity(ctx, now.AddDate(0, 0, -s.cfg.PopularityDays)); err != nil {
+		return err
+	}
+	_, err := s.events.PruneEvents(ctx, now.AddDate(0, 0, -s.cfg.RetentionDays))
+	return err
+}
+
+func (s *analyticsService) ProductStats(ctx context.Context, f ea.StatsFilter, page query.Page) (*query.Result[ea.ProductStats], error) {
+	items, total, err := s.events.ProductStats(ctx, f, page)
+	if err != nil {
+		return nil, err
+	}
+	return query.NewResult(items, total, page), nil
+}
+
+func (s *analyticsService) DailyTotals(ctx context.Context, from, to time.Time, productID uint) ([]ea.DailyTotals, error) {
+	return s.events.DailyTotals(ctx, from, to, productID)
+}
@@ -0,0 +1,50 @@
+package catalog
+
+import (
+	"context"
+	"log"
+	"slices"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/storage"
+)
+
+// bundleWatcher passes product changes on, and as changes of the bundles
+// the product is a component of too, since their stock and price follow
+// their components.
+type bundleWatcher struct {
+	bundles  storage.BundleRepository
+	products storage.ProductRepository
+	next     service.ProductWatcher
+}
+
+func NewBundleWatcher(bundles storage.BundleRepository, products storage.ProductRepository, next service.ProductWatcher) service.ProductWatcher {
+	return &bundleWatcher{bundles: bundles, products: products, next: next}
+}
+
+func (w *bundleWatcher) ProductChanged(ctx context.Context, prev *ec.Product) {
+	w.next.ProductChanged(ctx, prev)
+	ids, err := w.bundles.ListContaining(ctx, prev.ID)
+	if err != nil {
+		log.Printf("bundles: list bundles containing product %d: %v", prev.ID, err)
+		return
+	}
+	for _, id := range ids {
+		p, err := w.products.FindByID(ctx, id)
+		if err != nil || p.Bundle == nil {
+			continue
+		}
+		// the bundle as it was, made up of the component before the change
+		before, b := *p, *p.Bundle
+		b.Components = slices.Clone(b.Components)
+		for i := range b.Components {
+			if b.Components[i].ProductID == prev.ID {
+				b.Components[i].Product = prev
+			}
+		}
+		before.Bundle = &b
+		before.ApplyBundle()
+		w.next.ProductChanged(ctx, &before)
+	}
+}
@@ -0,0 +1,121 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"furniture-shop/internal/config"
+	ec "furniture-shop/internal/entities/catalog"
+)
+
+// Sitemap lists the storefront pages worth indexing: the catalog of each
+// department and category and every product page, by slug.
+func (s *catalogService) Sitemap(ctx context.Context) ([]ec.SitemapURL, error) {
+	depts, err := s.departments.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cats, err := s.categories.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	products, err := s.products.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	base := config.Configurations.FrontendURL
+	out := make([]ec.SitemapURL, 0, 2+len(depts)+len(cats)+len(products))
+	out = append(out, ec.SitemapURL{Loc: base + "/"}, ec.SitemapURL{Loc: base + "/catalog"})
+	deptRefs := make(map[uint]string, len(depts))
+	for _, d := range depts {
+		deptRefs[d.ID] = slugOrID(d.Slug, d.ID)
+		out = append(out, ec.SitemapURL{Loc: fmt.Sprintf("%s/catalog?dept=%s", base, url.QueryEscape(deptRefs[d.ID])), LastMod: d.UpdatedAt})
+	}
+	for _, c := range cats {
+		dept, ok := deptRefs[c.DepartmentID]
+		if !ok {
+			continue
+		}
+		out = append(out, ec.SitemapURL{Loc: fmt.Sprintf("%s/catalog?dept=%s&cat=%s", base, url.QueryEscape(dept), url.QueryEscape(slugOrID(c.Slug, c.ID))), LastMod: c.UpdatedAt})
+	}
+	for _, p := range products {
+		if !p.Published() {
+			continue
+		}
+		out = append(out, ec.SitemapURL{Loc: productURL(p), LastMod: p.UpdatedAt})
+	}
+	return out, nil
+}
+
+// ProductStructuredData describes a product for search engines. Relative
+// image URLs are made absolute with assetBase, the origin serving uploads.
+func (s *catalogService) ProductStructuredData(ctx context.Context, id uint, assetBase string) (*ec.ProductLD, error) {
+	p, err := s.products.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Published() {
+		return nil, ec.ErrNotPublished
+	}
+	abs := func(u string) string {
+		if strings.HasPrefix(u, "/") {
+			return strings.TrimRight(assetBase, "/") + u
+		}
+		return u
+	}
+	var images []string
+	for _, img := range p.Images {
+		if u := img.URL(ec.ImageLarge, ec.ImageJPEG); u != "" {
+			images = append(images, abs(u))
+		}
+	}
+	if len(images) == 0 && p.ImageURL != "" {
+		images = []string{abs(p.ImageURL)}
+	}
+	description := p.SEODescription
+	if description == "" {
+		description = p.ShortDescription
+	}
+	currency := config.Configurations.Invoice.Currency
+	if currency == "" {
+		currency = "EUR"
+	}
+	// products out of stock are still sold, made to order
+	availability := "https://schema.org/MadeToOrder"
+	if p.Quantity > 0 {
+		availability = "https://schema.org/InStock"
+	}
+	url := productURL(*p)
+	return &ec.ProductLD{
+		Context:     "https://schema.org",
+		Type:        "Product",
+		Name:        p.Name,
+		Description: description,
+		SKU:         p.SKU,
+		Image:       images,
+		URL:         url,
+		Material:    p.BaseMaterial,
+		Offers: ec.OfferLD{
+			Type:          "Offer",
+			URL:           url,
+			Price:         fmt.Sprintf("%.2f", p.Price()),
+			PriceCurrency: currency,
+			Availability:  availability,
+		},
+	}, nil
+}
+
+func productURL(p ec.Product) string {
+	return config.Configurations.FrontendURL + "/product/" + slugOrID(p.Slug, p.ID)
+}
+
+// slugOrID is how storefront links refer to a record: by slug, or by id
+// while it has none.
+func slugOrID(slug string, id uint) string {
+	if slug == "" {
+		return fmt.Sprint(id)
+	}
+	return slug
+}
@@ -2,57 +2,216 @@ package catalog
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
 
 	ec "furniture-shop/internal/entities/catalog"
 	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/i18n"
+	"furniture-shop/internal/service/search"
 	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
 )
 
 type catalogService struct {
 	departments storage.DepartmentRepository
 	categories  storage.CategoryRepository
 	products    storage.ProductRepository
+	slugs       storage.SlugRepository
+	attributes  storage.AttributeRepository
+	search      search.Index
+	translator  *i18n.Translator
 }
 
-func NewCatalogService(departments storage.DepartmentRepository, categories storage.CategoryRepository, products storage.ProductRepository) service.CatalogService {
-	return &catalogService{departments: departments, categories: categories, products: products}
+func NewCatalogService(departments storage.DepartmentRepository, categories storage.CategoryRepository, products storage.ProductRepository, slugs storage.SlugRepository, attributes storage.AttributeRepository, index search.Index, translator *i18n.Translator) service.CatalogService {
+	return &catalogService{departments: departments, categories: categories, products: products, slugs: slugs, attributes: attributes, search: index, translator: translator}
 }
 
-func (s *catalogService) ListDepartments(ctx context.Context) ([]ec.Department, error) {
-	return s.departments.List(ctx)
+// Resolve accepts a numeric id as is and looks anything else up as a slug.
+func (s *catalogService) Resolve(ctx context.Context, kind, ref string) (*ec.SlugRef, error) {
+	if id, err := strconv.ParseUint(ref, 10, 64); err == nil {
+		return &ec.SlugRef{ID: uint(id)}, nil
+	}
+	return s.slugs.Resolve(ctx, kind, ref)
 }
 
-func (s *catalogService) ListCategoriesByDepartment(ctx context.Context, departmentID uint) ([]ec.Category, error) {
-	return s.categories.ListByDepartment(ctx, departmentID)
+func (s *catalogService) ListDepartments(ctx context.Context, locale string) ([]ec.Department, error) {
+	depts, err := s.departments.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return depts, s.translator.Departments(ctx, locale, depts)
 }
 
-func (s *catalogService) ListProductsByCategory(ctx context.Context, categoryID uint) ([]ec.Product, error) {
-	return s.products.ListByCategory(ctx, categoryID)
+func (s *catalogService) ListCategoriesByDepartment(ctx context.Context, departmentID uint, locale string) ([]ec.Category, error) {
+	cats, err := s.categories.ListByDepartment(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	return cats, s.translator.Categories(ctx, locale, cats)
 }
 
-func (s *catalogService) GetProduct(ctx context.Context, id uint) (*ec.Product, error) {
-	p, err := s.products.FindByID(ctx, id)
+func (s *catalogService) ListProductsByCategory(ctx context.Context, categoryID uint, f ec.ProductFilter, page query.Page, locale string) (*query.Result[ec.Product], error) {
+	f.CategoryID = categoryID
+	if err := s.checkAttributeFilters(ctx, categoryID, f.Attributes); err != nil {
+		return nil, err
+	}
+	if !f.Preview {
+		f.Status = ec.ProductPublished
+	}
+	items, total, err := s.products.List(ctx, f, page)
 	if err != nil {
 		return nil, err
 	}
-	_ = s.products.IncrementRecommendation(ctx, id)
-	return p, nil
+	if err := s.translator.Products(ctx, locale, items); err != nil {
+		return nil, err
+	}
+	return query.NewResult(items, total, page), nil
 }
 
-func (s *catalogService) SearchProducts(ctx context.Context, query string, limit int) ([]ec.Product, error) {
-	if limit <= 0 {
-		limit = 50
+// checkAttributeFilters only allows the filterable attributes of the
+// category. An exact number is matched as a range of one value, so that
+// 45 finds 45.0.
+func (s *catalogService) checkAttributeFilters(ctx context.Context, categoryID uint, filters []ec.AttributeFilter) error {
+	if len(filters) == 0 {
+		return nil
+	}
+	defs, err := s.attributes.ListByCategory(ctx, categoryID)
+	if err != nil {
+		return err
 	}
-	return s.products.Search(ctx, query, limit)
+	for i := range filters {
+		a := &filters[i]
+		j := slices.IndexFunc(defs, func(d ec.AttributeDefinition) bool { return d.Code == a.Code && d.Filterable })
+		if j < 0 {
+			return fmt.Errorf("%w: %s is not a filter of this category", ec.ErrInvalidAttribute, a.Code)
+		}
+		ranged := a.Min != nil || a.Max != nil
+		switch {
+		case defs[j].Type == ec.AttributeNumber && !ranged:
+			n, err := strconv.ParseFloat(a.Value, 64)
+			if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+				return fmt.Errorf("%w: %s must be a number", ec.ErrInvalidAttribute, a.Code)
+			}
+			a.Min, a.Max = &n, &n
+		case defs[j].Type != ec.AttributeNumber && ranged:
+			return fmt.Errorf("%w: %s is not a number", ec.ErrInvalidAttribute, a.Code)
+		}
+	}
+	return nil
 }
 
-func (s *catalogService) RecommendProducts(ctx context.Context, productID uint, limit int) ([]ec.Product, error) {
-	p, err := s.products.FindByID(ctx, productID)
+// CompareProducts lines up two to MaxCompared published products, loaded in
+// one batch, in the order of ids; repeated ids count once.
+func (s *catalogService) CompareProducts(ctx context.Context, ids []uint, locale string) (*ec.ProductComparison, error) {
+	seen := map[uint]bool{}
+	ids = slices.DeleteFunc(slices.Clone(ids), func(id uint) bool {
+		dup := seen[id]
+		seen[id] = true
+		return dup
+	})
+	if len(ids) < 2 || len(ids) > ec.MaxCompared {
+		return nil, fmt.Errorf("%w: compare 2 to %d products", ec.ErrInvalidComparison, ec.MaxCompared)
+	}
+	items, err := s.products.ListByIDs(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
-	if limit <= 0 {
-		limit = 4
+	if len(items) != len(ids) || slices.ContainsFunc(items, func(p ec.Product) bool { return !p.Published() }) {
+		return nil, ec.ErrNotPublished
+	}
+	if err := s.translator.Products(ctx, locale, items); err != nil {
+		return nil, err
 	}
-	return s.products.ListRecommendations(ctx, p, limit)
+	return ec.Compare(items), nil
+}
+
+// ListAttributeFacets returns the filterable attributes of a category with
+// the values its published products have.
+func (s *catalogService) ListAttributeFacets(ctx context.Context, categoryID uint, locale string) ([]ec.AttributeFacet, error) {
+	items, err := s.attributes.Facets(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return items, s.translator.AttributeFacets(ctx, locale, items)
+}
+
+// GetProduct also returns archived and discontinued products, marked by
+// deleted_at and status, so that links from past orders keep working; they
+// cannot be ordered. Drafts and scheduled products are only shown in preview.
+func (s *catalogService) GetProduct(ctx context.Context, id uint, preview bool, locale string) (*ec.Product, error) {
+	p, err := s.products.FindIncludingArchived(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !preview && !p.Visible() {
+		return nil, ec.ErrNotPublished
+	}
+	return p, s.translator.Product(ctx, locale, p)
+}
+
+func (s *catalogService) SearchProducts(ctx context.Context, p ec.ProductSearchParams) (*ec.ProductSearchResult, error) {
+	if p.Limit <= 0 || p.Limit > 100 {
+		p.Limit = 20
+	}
+	if p.Offset < 0 {
+		p.Offset = 0
+	}
+	res, err := s.search.Query(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return res, s.translateSearch(ctx, p.Locale, res)
+}
+
+func (s *catalogService) TranslateProducts(ctx context.Context, locale string, items []ec.Product) error {
+	return s.translator.Products(ctx, locale, items)
+}
+
+// translateSearch translates the hits and the department and category facet
+// labels of a search result. Highlights are computed by the index on the
+// text of the same locale.
+func (s *catalogService) translateSearch(ctx context.Context, locale string, res *ec.ProductSearchResult) error {
+	if len(i18n.Fallbacks(locale)) == 0 {
+		return nil
+	}
+	products := make([]ec.Product, len(res.Hits))
+	for i, h := range res.Hits {
+		products[i] = h.Product
+	}
+	if err := s.translator.Products(ctx, locale, products); err != nil {
+		return err
+	}
+	for i := range res.Hits {
+		res.Hits[i].Product = products[i]
+	}
+
+	depts := make([]ec.Department, len(res.Facets.Departments))
+	for i, f := range res.Facets.Departments {
+		depts[i] = ec.Department{ID: facetID(f), Name: f.Label}
+	}
+	if err := s.translator.Departments(ctx, locale, depts); err != nil {
+		return err
+	}
+	for i := range depts {
+		res.Facets.Departments[i].Label = depts[i].Name
+	}
+	cats := make([]ec.Category, len(res.Facets.Categories))
+	for i, f := range res.Facets.Categories {
+		cats[i] = ec.Category{ID: facetID(f), Name: f.Label}
+	}
+	if err := s.translator.Categories(ctx, locale, cats); err != nil {
+		return err
+	}
+	for i := range cats {
+		res.Facets.Categories[i].Label = cats[i].Name
+	}
+	return nil
+}
+
+func facetID(f ec.FacetCount) uint {
+	id, _ := strconv.ParseUint(f.Value, 10, 64)
+	return uint(id)
 }
@@ -0,0 +1,267 @@
+package images
+
+import (
+	"context"
+	"errors"
+	"log"
+	"mime"
+	"time"
+
+	"furniture-shop/internal/config"
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/blob"
+	ip "furniture-shop/internal/service/images"
+	"furniture-shop/internal/service/search"
+	"furniture-shop/internal/storage"
+)
+
+// Stored files are keyed by their content, public images under images/ and
+// the uploaded originals, reachable only through signed URLs, under
+// originals/. The orphan cleanup only looks at these prefixes, so files put
+// into the store by other means (e.g. seed images) are kept.
+const (
+	publicPrefix   = "images"
+	originalPrefix = "originals"
+)
+
+type imageService struct {
+	images    storage.ProductImageRepository
+	products  storage.ProductRepository
+	options   storage.ProductOptionRepository
+	indexer   *search.Indexer
+	blobs     blob.Store
+	processor *ip.Processor
+	cfg       config.ImagesConfig
+	signedTTL time.Duration
+}
+
+func NewImageService(imgs storage.ProductImageRepository, products storage.ProductRepository, options storage.ProductOptionRepository, indexer *search.Indexer, blobs blob.Store, cfg config.ImagesConfig, signedTTL time.Duration) service.ImageService {
+	return &imageService{
+		images:    imgs,
+		products:  products,
+		options:   options,
+		indexer:   indexer,
+		blobs:     blobs,
+		processor: ip.NewProcessor(cfg),
+		cfg:       cfg,
+		signedTTL: signedTTL,
+	}
+}
+
+// Upload stores a validated image as is, e.g. for department pictures, and
+// returns its URL.
+func (s *imageService) Upload(ctx context.Context, data []byte) (string, error) {
+	format, _, err := s.processor.Validate(data)
+	if err != nil {
+		return "", err
+	}
+	return s.put(ctx, publicPrefix, data, format)
+}
+
+func (s *imageService) ListProductImages(ctx context.Context, productID uint) ([]ec.ProductImage, error) {
+	return s.images.ListByProduct(ctx, productID)
+}
+
+// AddProductImage renders the upload and appends it to the product gallery.
+func (s *imageService) AddProductImage(ctx context.Context, productID uint, data []byte, img ec.ProductImage) (*ec.ProductImage, error) {
+	if _, err := s.products.FindByID(ctx, productID); err != nil {
+		return nil, errors.New("product not found")
+	}
+	if err := s.checkOption(ctx, productID, img.OptionID); err != nil {
+		return nil, err
+	}
+	processed, err := s.processor.Process(data)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.images.ListByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	img.ID = 0
+	img.ProductID = productID
+	img.Position = len(existing) + 1
+	img.Width, img.Height = processed.Width, processed.Height
+	img.Renditions = nil
+	if img.OriginalURL, err = s.put(ctx, originalPrefix, data, processed.Format); err != nil {
+		return nil, err
+	}
+	for _, r := range processed.Renditions {
+		url, err := s.put(ctx, publicPrefix, r.Data, r.Format)
+		if err != nil {
+			return nil, err
+		}
+		img.Renditions = append(img.Renditions, ec.ImageRendition{
+			Size: r.Size, Format: r.Format, Width: r.Width, Height: r.Height, Bytes: len(r.Data), URL: url,
+		})
+	}
+	// files already stored for a failed insert are left to the orphan cleanup
+	if err := s.images.Create(ctx, &img); err != nil {
+		return nil, err
+	}
+	s.syncCover(ctx, productID, nil)
+	return &img, nil
+}
+
+func (s *imageService) UpdateProductImage(ctx context.Context, id uint, img ec.ProductImage) error {
+	prev, err := s.images.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.checkOption(ctx, prev.ProductID, img.OptionID); err != nil {
+		return err
+	}
+	return s.images.Update(ctx, id, img)
+}
+
+// ReorderProductImages takes every image id of the product in the new order;
+// the first image becomes the cover.
+func (s *imageService) ReorderProductImages(ctx context.Context, productID uint, ids []uint) error {
+	existing, err := s.images.ListByProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+	known := make(map[uint]bool, len(existing))
+	for _, img := range existing {
+		known[img.ID] = true
+	}
+	if len(ids) != len(existing) {
+		return errors.New("ids must list every image of the product")
+	}
+	for _, id := range ids {
+		if !known[id] {
+			return errors.New("ids must list every image of the product")
+		}
+		delete(known, id)
+	}
+	if err := s.images.Reorder(ctx, productID, ids); err != nil {
+		return err
+	}
+	s.syncCover(ctx, productID, nil)
+	return nil
+}
+
+// OriginalURL returns a temporary link to the uploaded original.
+func (s *imageService) OriginalURL(ctx context.Context, id uint) (string, error) {
+	img, err := s.images.FindByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	key, ok := s.blobs.KeyFromURL(img.OriginalURL)
+	if !ok {
+		return "", errors.New("original is not in the blob store")
+	}
+	return s.blobs.SignedURL(ctx, key, s.signedTTL)
+}
+
+// DeleteProductImage removes the image from the gallery. Its files may be
+// shared with identical uploads, so they are left to the orphan cleanup.
+func (s *imageService) DeleteProductImage(ctx context.Context, id uint) error {
+	img, err := s.images.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.images.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.syncCover(ctx, img.ProductID, img)
+	return nil
+}
+
+// CleanupOrphans removes uploaded files that nothing in the catalog refers
+// to, once they are older than the grace period; younger files may belong
+// to a form that is still being filled in.
+func (s *imageService) CleanupOrphans(ctx context.Context, now time.Time) (int, error) {
+	urls, err := s.images.ReferencedURLs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	referenced := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		referenced[u] = true
+	}
+	cutoff := now.Add(-time.Duration(s.cfg.OrphanGraceHours) * time.Hour)
+	removed := 0
+	for _, prefix := range []string{publicPrefix, originalPrefix} {
+		var orphans []string
+		err := s.blobs.List(ctx, prefix+"/", func(o blob.Object) error {
+			if !referenced[s.blobs.URL(o.Key)] && o.ModTime.Before(cutoff) {
+				orphans = append(orphans, o.Key)
+			}
+			return nil
+		})
+		if err != nil {
+			return removed, err
+		}
+		for _, key := range orphans {
+			if err := s.blobs.Delete(ctx, key); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (s *imageService) checkOption(ctx context.Context, productID uint, optionID *uint) error {
+	if optionID == nil {
+		return nil
+	}
+	o, err := s.options.FindByID(ctx, *optionID)
+	if err != nil || o.ProductID != productID {
+		return errors.New("option does not belong to the product")
+	}
+	return nil
+}
+
+// syncCover makes the first gallery image the product's listing image. When
+// the gallery becomes empty the cover is cleared only if it showed the
+// removed image, so a cover set by hand is kept.
+func (s *imageService) syncCover(ctx context.Context, productID uint, removed *ec.ProductImage) {
+	gallery, err := s.images.ListByProduct(ctx, productID)
+	if err != nil {
+		log.Printf("images: load gallery of product %d: %v", productID, err)
+		return
+	}
+	p, err := s.products.FindByID(ctx, productID)
+	if err != nil {
+		return
+	}
+	cover := p.ImageURL
+	if len(gallery) > 0 {
+		cover = gallery[0].URL(ec.ImageMedium, ec.ImageJPEG)
+	} else if removed != nil && showsImage(cover, removed) {
+		cover = ""
+	}
+	if cover == p.ImageURL {
+		return
+	}
+	if err := s.images.SetCover(ctx, productID, cover); err != nil {
+		log.Printf("images: set cover of product %d: %v", productID, err)
+		return
+	}
+	if err := s.indexer.Product(ctx, productID); err != nil {
+		log.Printf("search: index product %d: %v", productID, err)
+	}
+}
+
+// put stores data under its content key and returns its URL.
+func (s *imageService) put(ctx context.Context, prefix string, data []byte, format string) (string, error) {
+	ext := ip.Ext(format)
+	key, err := blob.PutContent(ctx, s.blobs, prefix, data, ext, mime.TypeByExtension(ext))
+	if err != nil {
+		return "", err
+	}
+	return s.blobs.URL(key), nil
+}
+
+func showsImage(url string, img *ec.ProductImage) bool {
+	for _, r := range img.Renditions {
+		if r.URL == url {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package invoices
+
+import (
+	"fmt"
+	"strings"
+
+	"furniture-shop/internal/config"
+	eo "furniture-shop/internal/entities/orders"
+	"furniture-shop/internal/service/pdf"
+)
+
+const (
+	marginLeft   = 50.0
+	marginRight  = pdf.PageWidth - 50.0
+	marginBottom = 80.0
+)
+
+// FileName is the download name of an invoice PDF.
+func FileName(inv *eo.Invoice) string {
+	return inv.Number + ".pdf"
+}
+
+// Render lays out an invoice or credit note as a PDF document.
+func Render(inv *eo.Invoice, seller config.InvoiceConfig) []byte {
+	doc := pdf.New()
+	title := "INVOICE"
+	if inv.Kind == eo.InvoiceKindCreditNote {
+		title = "CREDIT NOTE"
+	}
+
+	y := pdf.PageHeight - 60
+	doc.Text(marginLeft, y, pdf.Bold, 20, title)
+	doc.TextRight(marginRight, y, pdf.Bold, 12, inv.Number)
+	y -= 18
+	doc.TextRight(marginRight, y, pdf.Regular, 10, "Issued: "+inv.IssuedAt.Format("2006-01-02"))
+	y -= 14
+	doc.TextRight(marginRight, y, pdf.Regular, 10, fmt.Sprintf("Order: #%d", inv.OrderID))
+	if inv.RelatedInvoiceID != nil {
+		y -= 14
+		doc.TextRight(marginRight, y, pdf.Regular, 10, fmt.Sprintf("Related document: #%d", *inv.RelatedInvoiceID))
+	}
+
+	y = pdf.PageHeight - 110
+	doc.Text(marginLeft, y, pdf.Bold, 10, "Seller")
+	doc.Text(320, y, pdf.Bold, 10, "Buyer")
+	sellerLines := []string{seller.SellerName, seller.SellerAddress}
+	if seller.SellerVATID != "" {
+		sellerLines = append(sellerLines, "VAT ID: "+seller.SellerVATID)
+	}
+	buyerLines := []string{inv.BuyerName, inv.BuyerAddress, inv.BuyerEmail}
+	for i := 0; i < len(sellerLines) || i < len(buyerLines); i++ {
+		y -= 14
+		if i < len(sellerLines) {
+			doc.Text(marginLeft, y, pdf.Regular, 10, sellerLines[i])
+		}
+		if i < len(buyerLines) {
+			doc.Text(320, y, pdf.Regular, 10, buyerLines[i])
+		}
+	}
+
+	y -= 36
+	header := func() {
+		doc.Text(marginLeft, y, pdf.Bold, 10, "Description")
+		doc.TextRight(390, y, pdf.Bold, 10, "Qty")
+		doc.TextRight(470, y, pdf.Bold, 10, "Unit price")
+		doc.TextRight(marginRight, y, pdf.Bold, 10, "Total")
+		y -= 6
+		doc.Line(marginLeft, y, marginRight, y, 0.8)
+		y -= 16
+	}
+	header()
+	for _, l := range inv.Lines {
+		if y < marginBottom+60 {
+			doc.AddPage()
+			y = pdf.PageHeight - 60
+			header()
+		}
+		doc.Text(marginLeft, y, pdf.Regular, 10, truncate(l.Description, 55))
+		doc.TextRight(390, y, pdf.Regular, 10, fmt.Sprintf("%d", l.Quantity))
+		doc.TextRight(470, y, pdf.Regular, 10, money(l.UnitPrice, inv.Currency))
+		doc.TextRight(marginRight, y, pdf.Regular, 10, money(l.LineTotal, inv.Currency))
+		if l.Options != "" {
+			y -= 12
+			doc.Text(marginLeft+10, y, pdf.Regular, 8, truncate(l.Options, 80))
+		}
+		y -= 18
+	}
+
+	if y < marginBottom+70 {
+		doc.AddPage()
+		y = pdf.PageHeight - 60
+	}
+	doc.Line(320, y+8, marginRight, y+8, 0.5)
+	y -= 6
+	doc.Text(320, y, pdf.Regular, 10, "Net amount")
+	doc.TextRight(marginRight, y, pdf.Regular, 10, money(inv.Subtotal, inv.Currency))
+	y -= 16
+	doc.Text(320, y, pdf.Regular, 10, fmt.Sprintf("VAT %s%%", trimZeros(inv.TaxRate)))
+	doc.TextRight(marginRight, y, pdf.Regular, 10, money(inv.TaxAmount, inv.Currency))
+	y -= 18
+	total := "Total"
+	if inv.Kind == eo.InvoiceKindCreditNote {
+		total = "Total credited"
+	}
+	doc.Text(320, y, pdf.Bold, 12, total)
+	doc.TextRight(marginRight, y, pdf.Bold, 12, money(inv.Total, inv.Currency))
+
+	doc.Text(marginLeft, 50, pdf.Regular, 8, "Prices include VAT. Thank you for your purchase.")
+	return doc.Bytes()
+}
+
+func money(v float64, currency string) string {
+	return fmt.Sprintf("%.2f %s", v, currency)
+}
+
+func trimZeros(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
+}
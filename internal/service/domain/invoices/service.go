@@ -0,0 +1,214 @@
+package invoices
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"furniture-shop/internal/config"
+	eo "furniture-shop/internal/entities/orders"
+	"furniture-shop/internal/service"
+	so "furniture-shop/internal/service/domain/orders"
+	"furniture-shop/internal/storage"
+)
+
+type invoiceService struct {
+	invoices storage.InvoiceRepository
+	orders   storage.OrderRepository
+	products storage.ProductRepository
+	users    storage.UserRepository
+}
+
+func NewInvoiceService(invoices storage.InvoiceRepository, orders storage.OrderRepository, products storage.ProductRepository, users storage.UserRepository) service.InvoiceService {
+	return &invoiceService{invoices: invoices, orders: orders, products: products, users: users}
+}
+
+// IssueForOrder creates the invoice for a paid order. It is idempotent: an
+// already issued invoice is returned unchanged, also when a concurrent call
+// issued it first (an order has at most one invoice, so that insert fails).
+func (s *invoiceService) IssueForOrder(ctx context.Context, orderID uint) (*eo.Invoice, error) {
+	if inv, err := s.invoices.FindOrderInvoice(ctx, orderID); err == nil {
+		return inv, nil
+	}
+	o, err := s.orders.FindWithItems(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if o.PaymentStatus != eo.PaymentStatusPaid {
+		return nil, errors.New("order is not paid")
+	}
+	inv, err := s.newDocument(ctx, o, eo.InvoiceKindInvoice, o.TotalPrice)
+	if err != nil {
+		return nil, err
+	}
+	for _, it := range o.Items {
+		name, options := s.describeItem(ctx, it)
+		inv.Lines = append(inv.Lines, eo.InvoiceLine{
+			ProductID:   it.ProductID,
+			Description: name,
+			Options:     options,
+			Quantity:    it.Quantity,
+			UnitPrice:   it.UnitPrice,
+			LineTotal:   it.LineTotal,
+		})
+	}
+	if err := s.invoices.Issue(ctx, inv); err != nil {
+		if issued, ferr := s.invoices.FindOrderInvoice(ctx, orderID); ferr == nil {
+			return issued, nil
+		}
+		return nil, err
+	}
+	return inv, nil
+}
+
+// IssueForAmendment documents a settled amendment price difference: an extra
+// invoice when the customer paid more, a credit note when money was refunded.
+func (s *invoiceService) IssueForAmendment(ctx context.Context, amendmentID uint) (*eo.Invoice, error) {
+	if inv, err := s.invoices.FindByAmendment(ctx, amendmentID); err == nil {
+		return inv, nil
+	}
+	a, err := s.orders.FindAmendment(ctx, amendmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a.SettlementStatus != eo.SettlementStatusSettled {
+		return nil, errors.New("amendment is not settled")
+	}
+	o, err := s.orders.FindByID(ctx, a.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	kind := eo.InvoiceKindInvoice
+	if a.SettlementType == eo.SettlementTypeRefund {
+		kind = eo.InvoiceKindCreditNote
+	}
+	amount := math.Abs(a.PriceDifference)
+	inv, err := s.newDocument(ctx, o, kind, amount)
+	if err != nil {
+		return nil, err
+	}
+	inv.AmendmentID = &a.ID
+	if base, err := s.invoices.FindOrderInvoice(ctx, o.ID); err == nil {
+		inv.RelatedInvoiceID = &base.ID
+	}
+	inv.Lines = []eo.InvoiceLine{{
+		Description: fmt.Sprintf("Amendment #%d to order #%d", a.ID, o.ID),
+		Options:     a.Note,
+		Quantity:    1,
+		UnitPrice:   amount,
+		LineTotal:   amount,
+	}}
+	if err := s.invoices.Issue(ctx, inv); err != nil {
+		if issued, ferr := s.invoices.FindByAmendment(ctx, amendmentID); ferr == nil {
+			return issued, nil
+		}
+		return nil, err
+	}
+	return inv, nil
+}
+
+func (s *invoiceService) ListOrderInvoices(ctx context.Context, orderID uint) ([]eo.Invoice, error) {
+	return s.invoices.ListByOrder(ctx, orderID)
+}
+
+func (s *invoiceService) GetUserOrderInvoicePDF(ctx context.Context, userID, orderID uint) (*eo.Invoice, []byte, error) {
+	inv, err := s.invoices.FindOrderInvoice(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if inv.UserID != userID {
+		return nil, nil, errors.New("forbidden")
+	}
+	return inv, Render(inv, config.Configurations.Invoice), nil
+}
+
+func (s *invoiceService) GetUserInvoicePDF(ctx context.Context, userID, invoiceID uint) (*eo.Invoice, []byte, error) {
+	inv, err := s.invoices.FindByID(ctx, invoiceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if inv.UserID != userID {
+		return nil, nil, errors.New("forbidden")
+	}
+	return inv, Render(inv, config.Configurations.Invoice), nil
+}
+
+func (s *invoiceService) GetInvoicePDF(ctx context.Context, invoiceID uint) (*eo.Invoice, []byte, error) {
+	inv, err := s.invoices.FindByID(ctx, invoiceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return inv, Render(inv, config.Configurations.Invoice), nil
+}
+
+func (s *invoiceService) ListInvoices(ctx context.Context, from, to time.Time, kind string) ([]eo.Invoice, error) {
+	return s.invoices.ListIssuedBetween(ctx, from, to, kind)
+}
+
+// ExportArchive renders every document issued in [from, to) into a zip archive.
+func (s *invoiceService) ExportArchive(ctx context.Context, from, to time.Time, kind string) ([]byte, error) {
+	items, err := s.invoices.ListIssuedBetween(ctx, from, to, kind)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for i := range items {
+		w, err := zw.Create(FileName(&items[i]))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(Render(&items[i], config.Configurations.Invoice)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *invoiceService) newDocument(ctx context.Context, o *eo.Order, kind string, gross float64) (*eo.Invoice, error) {
+	u, err := s.users.FindByID(ctx, o.UserID)
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.Configurations.Invoice
+	gross = roundCents(gross)
+	net := roundCents(gross / (1 + cfg.TaxRate/100))
+	currency := cfg.Currency
+	if currency == "" {
+		currency = "EUR"
+	}
+	return &eo.Invoice{
+		Kind:         kind,
+		OrderID:      o.ID,
+		UserID:       o.UserID,
+		BuyerName:    u.Name,
+		BuyerEmail:   u.Email,
+		BuyerAddress: u.Address,
+		Currency:     currency,
+		Subtotal:     net,
+		TaxRate:      cfg.TaxRate,
+		TaxAmount:    roundCents(gross - net),
+		Total:        gross,
+		IssuedAt:     time.Now().UTC(),
+	}, nil
+}
+
+// describeItem resolves the product name and the human readable option list of an order line.
+func (s *invoiceService) describeItem(ctx context.Context, it eo.OrderItem) (string, string) {
+	p, err := s.products.FindIncludingArchived(ctx, it.ProductID)
+	if err != nil {
+		return fmt.Sprintf("Product #%d", it.ProductID), ""
+	}
+	return p.Name, so.DescribeSelectedOptions(*p, it.SelectedOptionsJSON)
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
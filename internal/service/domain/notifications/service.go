@@ -0,0 +1,376 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"furniture-shop/internal/config"
+	ec "furniture-shop/internal/entities/catalog"
+	en "furniture-shop/internal/entities/notification"
+	eo "furniture-shop/internal/entities/orders"
+	eu "furniture-shop/internal/entities/user"
+	"furniture-shop/internal/service"
+	so "furniture-shop/internal/service/domain/orders"
+	"furniture-shop/internal/service/templates"
+	"furniture-shop/internal/storage"
+)
+
+// statusTemplates maps order statuses that customers are told about to their template.
+var statusTemplates = map[string]string{
+	eo.OrderStatusShipped:   templates.OrderShipped,
+	eo.OrderStatusDelivered: templates.OrderDelivered,
+}
+
+// subscriptionTemplates maps product subscription kinds to their template.
+var subscriptionTemplates = map[string]string{
+	en.SubscriptionBackInStock: templates.BackInStock,
+	en.SubscriptionPriceDrop:   templates.PriceDrop,
+}
+
+type notificationService struct {
+	users     storage.UserRepository
+	orders    storage.OrderRepository
+	products  storage.ProductRepository
+	pushSubs  storage.PushSubscriptionRepository
+	outbox    service.OutboxService
+	templates *templates.Registry
+}
+
+func NewNotificationService(users storage.UserRepository, orders storage.OrderRepository, products storage.ProductRepository, pushSubs storage.PushSubscriptionRepository, outbox service.OutboxService, reg *templates.Registry) service.NotificationService {
+	return &notificationService{users: users, orders: orders, products: products, pushSubs: pushSubs, outbox: outbox, templates: reg}
+}
+
+func (s *notificationService) OrderCreated(ctx context.Context, orderID uint) error {
+	return s.notifyOrder(ctx, orderID, templates.OrderCreated)
+}
+
+func (s *notificationService) PaymentSucceeded(ctx context.Context, orderID uint) error {
+	return s.notifyOrder(ctx, orderID, templates.PaymentSucceeded)
+}
+
+func (s *notificationService) PaymentFailed(ctx context.Context, orderID uint) error {
+	return s.notifyOrder(ctx, orderID, templates.PaymentFailed)
+}
+
+func (s *notificationService) OrderStatusChanged(ctx context.Context, orderID uint, status string) error {
+	name, ok := statusTemplates[status]
+	if !ok {
+		return nil
+	}
+	return s.notifyOrder(ctx, orderID, name)
+}
+
+func (s *notificationService) PasswordReset(ctx context.Context, userID uint, resetURL string) error {
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, u, templates.PasswordReset, templates.PasswordResetData{CustomerName: u.Name, ResetURL: resetURL, ExpiresIn: "1 hour"})
+}
+
+// QuestionAnswered tells the asker of a product question about an answer.
+func (s *notificationService) QuestionAnswered(ctx context.Context, q *ec.Question, a *ec.Answer) error {
+	u, err := s.users.FindByID(ctx, q.UserID)
+	if err != nil {
+		return err
+	}
+	p, err := s.products.FindIncludingArchived(ctx, q.ProductID)
+	if err != nil {
+		return err
+	}
+	by := a.AuthorName
+	if a.Official || by == "" {
+		by = "Furniture Shop"
+	}
+	return s.send(ctx, u, templates.QuestionAnswered, templates.QuestionAnsweredData{
+		CustomerName: u.Name,
+		ProductName:  p.Name,
+		Question:     q.Body,
+		Answer:       a.Body,
+		AnsweredBy:   by,
+		ProductURL:   productURL(p) + "#questions",
+	})
+}
+
+func (s *notificationService) PriceDropped(ctx context.Context, userID uint, p *ec.Product, options, listName string, was float64) error {
+	return s.productAlert(ctx, userID, templates.PriceDrop, p, options, listName, was)
+}
+
+func (s *notificationService) BackInStock(ctx context.Context, userID uint, p *ec.Product, options, listName string) error {
+	return s.productAlert(ctx, userID, templates.BackInStock, p, options, listName, 0)
+}
+
+func (s *notificationService) productAlert(ctx context.Context, userID uint, name string, p *ec.Product, options, listName string, was float64) error {
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	data := alertData(p, options, was)
+	data.CustomerName = u.Name
+	data.ListName = listName
+	return s.send(ctx, u, name, data)
+}
+
+// SubscriptionAlert goes out by email only, to the subscribed address, in
+// the language of the subscription; subscribers opted into it per product
+// regardless of their channel preferences.
+func (s *notificationService) SubscriptionAlert(ctx context.Context, sub *en.ProductSubscription, p *ec.Product, was float64) error {
+	name, ok := subscriptionTemplates[sub.Kind]
+	if !ok {
+		return fmt.Errorf("unknown subscription kind %q", sub.Kind)
+	}
+	data := alertData(p, sub.SelectedOptionsJSON, was)
+	data.UnsubscribeURL = fmt.Sprintf("%s/unsubscribe/%s", config.Configurations.FrontendURL, sub.Token)
+	if sub.UserID != nil {
+		if u, err := s.users.FindByID(ctx, *sub.UserID); err == nil {
+			data.CustomerName = u.Name
+		}
+	}
+	msg, err := s.templates.Render(name, sub.Locale, 0, data)
+	if err != nil {
+		return err
+	}
+	return s.outbox.Enqueue(ctx, &en.OutboxMessage{
+		Channel:   en.ChannelEmail,
+		Recipient: sub.Email,
+		Subject:   msg.Subject,
+		TextBody:  msg.Text,
+		HTMLBody:  msg.HTML,
+		Template:  fmt.Sprintf("%s/%s/v%d", msg.Name, msg.Locale, msg.Version),
+	})
+}
+
+// SubscriptionConfirm emails a guest the link that activates a product
+// subscription.
+func (s *notificationService) SubscriptionConfirm(ctx context.Context, sub *en.ProductSubscription, p *ec.Product) error {
+	msg, err := s.templates.Render(templates.SubscriptionConfirm, sub.Locale, 0, templates.SubscriptionConfirmData{
+		ProductName:    p.Name,
+		Options:        so.DescribeSelectedOptions(*p, sub.SelectedOptionsJSON),
+		PriceDrop:      sub.Kind == en.SubscriptionPriceDrop,
+		ConfirmURL:     fmt.Sprintf("%s/subscriptions/confirm/%s", config.Configurations.FrontendURL, sub.Token),
+		ProductURL:     productURL(p),
+		UnsubscribeURL: fmt.Sprintf("%s/unsubscribe/%s", config.Configurations.FrontendURL, sub.Token),
+	})
+	if err != nil {
+		return err
+	}
+	return s.outbox.Enqueue(ctx, &en.OutboxMessage{
+		Channel:   en.ChannelEmail,
+		Recipient: sub.Email,
+		Subject:   msg.Subject,
+		TextBody:  msg.Text,
+		HTMLBody:  msg.HTML,
+		Template:  fmt.Sprintf("%s/%s/v%d", msg.Name, msg.Locale, msg.Version),
+	})
+}
+
+// alertData describes a product in an option selection at its current price.
+func alertData(p *ec.Product, options string, was float64) templates.ProductAlertData {
+	return templates.ProductAlertData{
+		ProductName: p.Name,
+		Options:     so.DescribeSelectedOptions(*p, options),
+		OldPrice:    was,
+		NewPrice:    so.ConfiguredPrice(p, options),
+		Currency:    currency(),
+		ProductURL:  productURL(p),
+	}
+}
+
+// deliveryReminderHour is the local hour from which delivery-day reminders go out.
+const deliveryReminderHour = 8
+
+// SendDeliveryReminders notifies customers whose shipped order is scheduled for
+// delivery today. Each order is reminded once per scheduled date.
+func (s *notificationService) SendDeliveryReminders(ctx context.Context, now time.Time) (int, error) {
+	if now.Hour() < deliveryReminderHour {
+		return 0, nil
+	}
+	due, err := s.orders.ListDeliveryRemindersDue(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, o := range due {
+		if err := s.notifyOrder(ctx, o.ID, templates.DeliveryReminder); err != nil {
+			log.Printf("delivery reminder for order %d: %v", o.ID, err)
+			continue
+		}
+		if err := s.orders.MarkDeliveryReminderSent(ctx, o.ID, now); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *notificationService) GetPreferences(ctx context.Context, userID uint) ([]string, []en.PushSubscription, error) {
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	subs, err := s.pushSubs.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return u.Channels(), subs, nil
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, userID uint, channels []string) error {
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	seen := map[string]bool{}
+	var out []string
+	for _, c := range channels {
+		if seen[c] {
+			continue
+		}
+		if c == en.ChannelSMS && u.Phone == "" {
+			return errors.New("a phone number is required for sms notifications")
+		}
+		seen[c] = true
+		out = append(out, c)
+	}
+	u.SetChannels(out)
+	return s.users.UpdateNotifyChannels(ctx, userID, u.NotifyChannels)
+}
+
+func (s *notificationService) AddPushSubscription(ctx context.Context, userID uint, sub *en.PushSubscription) error {
+	sub.UserID = userID
+	return s.pushSubs.Save(ctx, sub)
+}
+
+func (s *notificationService) RemovePushSubscription(ctx context.Context, userID, id uint) error {
+	return s.pushSubs.Delete(ctx, userID, id)
+}
+
+func (s *notificationService) ListTemplates() []templates.Info {
+	return s.templates.List()
+}
+
+func (s *notificationService) PreviewTemplate(name, locale string, version int) (*templates.Rendered, error) {
+	return s.templates.Render(name, locale, version, templates.SampleData(name))
+}
+
+func (s *notificationService) notifyOrder(ctx context.Context, orderID uint, name string) error {
+	o, err := s.orders.FindWithItems(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	u, err := s.users.FindByID(ctx, o.UserID)
+	if err != nil {
+		return err
+	}
+	return s.send(ctx, u, name, s.orderData(ctx, o, u))
+}
+
+// send renders the template for the user's locale and queues one outbox
+// message per recipient on every channel the event is routed to; delivery
+// happens asynchronously in the outbox dispatcher.
+func (s *notificationService) send(ctx context.Context, u *eu.User, name string, data any) error {
+	msg, err := s.templates.Render(name, u.Locale, 0, data)
+	if err != nil {
+		return err
+	}
+	tmpl := fmt.Sprintf("%s/%s/v%d", msg.Name, msg.Locale, msg.Version)
+	var subs []en.PushSubscription
+	if u.HasChannel(en.ChannelPush) {
+		subs, _ = s.pushSubs.ListByUser(ctx, u.ID)
+	}
+	for _, ch := range s.channelsFor(name, u, len(subs) > 0) {
+		var out []en.OutboxMessage
+		switch ch {
+		case en.ChannelEmail:
+			out = append(out, en.OutboxMessage{Recipient: u.Email, Subject: msg.Subject, TextBody: msg.Text, HTMLBody: msg.HTML})
+		case en.ChannelSMS:
+			out = append(out, en.OutboxMessage{Recipient: u.Phone, TextBody: msg.Short})
+		case en.ChannelPush:
+			for _, sub := range subs {
+				out = append(out, en.OutboxMessage{Recipient: strconv.FormatUint(uint64(sub.ID), 10), Subject: msg.Subject, TextBody: msg.Short})
+			}
+		}
+		for i := range out {
+			out[i].Channel = ch
+			out[i].Template = tmpl
+			if err := s.outbox.Enqueue(ctx, &out[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// channelsFor picks the channels an event is delivered on: those routed for it
+// in the configuration that the user opted into and can be reached on. When
+// none is left the event still goes out by email if it is routed to email, so
+// transactional messages are never silently dropped.
+func (s *notificationService) channelsFor(name string, u *eu.User, hasPush bool) []string {
+	routes, ok := config.Configurations.Notify.Routes[name]
+	if !ok {
+		routes = []string{en.ChannelEmail}
+	}
+	var out []string
+	routedEmail := false
+	for _, ch := range routes {
+		if ch == en.ChannelEmail {
+			routedEmail = true
+		}
+		if !u.HasChannel(ch) {
+			continue
+		}
+		if (ch == en.ChannelSMS && u.Phone == "") || (ch == en.ChannelPush && !hasPush) {
+			continue
+		}
+		out = append(out, ch)
+	}
+	if len(out) == 0 && routedEmail {
+		out = []string{en.ChannelEmail}
+	}
+	return out
+}
+
+func (s *notificationService) orderData(ctx context.Context, o *eo.Order, u *eu.User) templates.OrderData {
+	data := templates.OrderData{
+		CustomerName: u.Name,
+		OrderID:      o.ID,
+		Status:       o.Status,
+		Total:        o.TotalPrice,
+		Currency:     currency(),
+		ETADays:      o.EstimatedProductionTimeDays,
+		OrderURL:     fmt.Sprintf("%s/orders?open=%d", config.Configurations.FrontendURL, o.ID),
+	}
+	if o.DeliveryDate != nil {
+		data.DeliveryDate = o.DeliveryDate.Format("2006-01-02")
+	}
+	for _, it := range o.Items {
+		line := templates.OrderLine{Name: fmt.Sprintf("Product #%d", it.ProductID), Quantity: it.Quantity, LineTotal: it.LineTotal}
+		if p, err := s.products.FindIncludingArchived(ctx, it.ProductID); err == nil {
+			line.Name = p.Name
+			line.Options = so.DescribeSelectedOptions(*p, it.SelectedOptionsJSON)
+		}
+		data.Items = append(data.Items, line)
+	}
+	return data
+}
+
+func currency() string {
+	if c := config.Configurations.Invoice.Currency; c != "" {
+		return c
+	}
+	return "EUR"
+}
+
+// productURL links to a product page on the storefront, by slug when it
+// has one.
+func productURL(p *ec.Product) string {
+	ref := p.Slug
+	if ref == "" {
+		ref = strconv.FormatUint(uint64(p.ID), 10)
+	}
+	return fmt.Sprintf("%s/product/%s", config.Configurations.FrontendURL, ref)
+}
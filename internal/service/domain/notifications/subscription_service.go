@@ -0,0 +1,211 @@
+package notifications
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	notif_dto "furniture-shop/internal/dtos/notifications"
+	ec "furniture-shop/internal/entities/catalog"
+	en "furniture-shop/internal/entities/notification"
+	"furniture-shop/internal/service"
+	so "furniture-shop/internal/service/domain/orders"
+	"furniture-shop/internal/storage"
+)
+
+type subscriptionService struct {
+	subs     storage.ProductSubscriptionRepository
+	products storage.ProductRepository
+	users    storage.UserRepository
+	notifier service.NotificationService
+}
+
+func NewSubscriptionService(subs storage.ProductSubscriptionRepository, products storage.ProductRepository, users storage.UserRepository, notifier service.NotificationService) service.ProductSubscriptionService {
+	return &subscriptionService{subs: subs, products: products, users: users, notifier: notifier}
+}
+
+// confirmationResendAfter is how long a guest waits before subscribing
+// again sends another confirmation email.
+const confirmationResendAfter = time.Hour
+
+// Subscribe records a subscription to a published product in the given
+// option selection. Back-in-stock subscriptions are only taken while the
+// product is out of stock; price drops are measured from the current price.
+// Subscriptions of signed-in users are active at once; guests are emailed a
+// link that activates theirs.
+func (s *subscriptionService) Subscribe(ctx context.Context, userID, productID uint, in notif_dto.ProductSubscriptionRequest) (*en.ProductSubscription, error) {
+	p, err := s.products.FindByID(ctx, productID)
+	if err != nil || !p.Published() {
+		return nil, fmt.Errorf("%w: product %d is not available", en.ErrInvalidSubscription, productID)
+	}
+	if in.Kind == en.SubscriptionBackInStock && p.Quantity > 0 {
+		return nil, fmt.Errorf("%w: product %d is in stock", en.ErrInvalidSubscription, productID)
+	}
+	for _, o := range in.Options {
+		if !slices.ContainsFunc(p.Options, func(po ec.ProductOption) bool { return po.ID == o.ID }) {
+			return nil, fmt.Errorf("%w: product %d has no option %d", en.ErrInvalidSubscription, productID, o.ID)
+		}
+	}
+	options := so.NormalizeOptions(in.Options)
+	sub := &en.ProductSubscription{
+		ProductID:           productID,
+		Kind:                in.Kind,
+		SelectedOptionsJSON: options,
+		Email:               strings.ToLower(strings.TrimSpace(in.Email)),
+		Locale:              in.Locale,
+		AlertPrice:          so.ConfiguredPrice(p, options),
+	}
+	if userID != 0 {
+		u, err := s.users.FindByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		sub.UserID = &userID
+		sub.Email = strings.ToLower(u.Email)
+		sub.Locale = u.Locale
+	}
+	if sub.Email == "" {
+		return nil, fmt.Errorf("%w: an email address is required", en.ErrInvalidSubscription)
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	sub.Token = hex.EncodeToString(b)
+	if err := s.subs.Save(ctx, sub); err != nil {
+		return nil, err
+	}
+	if sub.ConfirmedAt != nil {
+		return sub, nil
+	}
+	now := time.Now()
+	if userID != 0 {
+		if err := s.subs.Confirm(ctx, sub.ID, now); err != nil {
+			return nil, err
+		}
+		sub.ConfirmedAt = &now
+		return sub, nil
+	}
+	if sub.ConfirmationSentAt != nil && now.Sub(*sub.ConfirmationSentAt) < confirmationResendAfter {
+		return sub, nil
+	}
+	if err := s.notifier.SubscriptionConfirm(ctx, sub, p); err != nil {
+		return nil, err
+	}
+	if err := s.subs.SetConfirmationSent(ctx, sub.ID, now); err != nil {
+		log.Printf("subscriptions: record confirmation of subscription %d: %v", sub.ID, err)
+	}
+	return sub, nil
+}
+
+func (s *subscriptionService) Confirm(ctx context.Context, token string) (*en.ProductSubscription, error) {
+	sub, err := s.subs.FindByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if sub.ConfirmedAt == nil {
+		now := time.Now()
+		if err := s.subs.Confirm(ctx, sub.ID, now); err != nil {
+			return nil, err
+		}
+		sub.ConfirmedAt = &now
+	}
+	return sub, nil
+}
+
+func (s *subscriptionService) ListUser(ctx context.Context, userID uint) ([]en.ProductSubscription, error) {
+	return s.subs.ListByUser(ctx, userID)
+}
+
+func (s *subscriptionService) DeleteUser(ctx context.Context, userID, id uint) error {
+	sub, err := s.subs.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if sub.UserID == nil || *sub.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	return s.subs.Delete(ctx, id)
+}
+
+func (s *subscriptionService) Unsubscribe(ctx context.Context, token string) (*en.ProductSubscription, error) {
+	sub, err := s.subs.FindByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.subs.Delete(ctx, sub.ID); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// ProductChanged emails the subscribers of a product that came back in stock
+// or got cheaper than the price they were last told about. Back-in-stock
+// subscriptions end once their alert is queued; price-drop subscriptions
+// carry on from the new price. A subscriber already alerted about the change,
+// e.g. through a wishlist, is not emailed again, but the subscription moves
+// on as if they had been. Failures are logged.
+func (s *subscriptionService) ProductChanged(ctx context.Context, prev *ec.Product) {
+	p, err := s.products.FindByID(ctx, prev.ID)
+	if err != nil || !p.Published() {
+		return
+	}
+	subs, err := s.subs.ListByProduct(ctx, p.ID)
+	if err != nil {
+		log.Printf("subscriptions: list subscriptions of product %d: %v", p.ID, err)
+		return
+	}
+	restocked := prev.Quantity <= 0 && p.Quantity > 0
+	for i := range subs {
+		sub := &subs[i]
+		switch sub.Kind {
+		case en.SubscriptionBackInStock:
+			if !restocked {
+				continue
+			}
+			if s.firstAlert(ctx, sub) {
+				if err := s.notifier.SubscriptionAlert(ctx, sub, p, 0); err != nil {
+					log.Printf("subscriptions: alert subscription %d: %v", sub.ID, err)
+					continue
+				}
+			}
+			if err := s.subs.Delete(ctx, sub.ID); err != nil {
+				log.Printf("subscriptions: end subscription %d: %v", sub.ID, err)
+			}
+		case en.SubscriptionPriceDrop:
+			price := so.ConfiguredPrice(p, sub.SelectedOptionsJSON)
+			if price >= sub.AlertPrice-0.005 {
+				continue
+			}
+			if s.firstAlert(ctx, sub) {
+				if err := s.notifier.SubscriptionAlert(ctx, sub, p, sub.AlertPrice); err != nil {
+					log.Printf("subscriptions: alert subscription %d: %v", sub.ID, err)
+					continue
+				}
+			}
+			if err := s.subs.SetAlertPrice(ctx, sub.ID, price); err != nil {
+				log.Printf("subscriptions: lower alert price of subscription %d: %v", sub.ID, err)
+			}
+		}
+	}
+}
+
+// firstAlert reports whether the subscriber of sub is yet to be alerted of
+// its kind about the current change. A guest with an account is recognized
+// by email.
+func (s *subscriptionService) firstAlert(ctx context.Context, sub *en.ProductSubscription) bool {
+	var userID uint
+	if sub.UserID != nil {
+		userID = *sub.UserID
+	} else if u, err := s.users.FindByEmail(ctx, sub.Email); err == nil {
+		userID = u.ID
+	}
+	return service.FirstAlert(ctx, sub.Kind, userID, sub.Email)
+}
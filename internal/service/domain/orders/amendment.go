@@ -0,0 +1,318 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strconv"
+
+	"gorm.io/gorm"
+
+	order_dto "furniture-shop/internal/dtos/orders"
+	ec "furniture-shop/internal/entities/catalog"
+	eo "furniture-shop/internal/entities/orders"
+	"furniture-shop/internal/service/gateway"
+)
+
+func (s *ordersService) AmendOrder(ctx context.Context, orderID, actorID uint, asAdmin bool, in order_dto.AmendOrderRequest) (*eo.Order, *eo.OrderAmendment, error) {
+	if len(in.Add) == 0 && len(in.Update) == 0 && len(in.Remove) == 0 {
+		return nil, nil, fmt.Errorf("%w: no changes requested", eo.ErrInvalidAmendment)
+	}
+	o, err := s.orders.FindWithItems(ctx, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !asAdmin && o.UserID != actorID {
+		return nil, nil, fmt.Errorf("order %d: %w", orderID, gorm.ErrRecordNotFound)
+	}
+	if !eo.Amendable(o.Status) {
+		return nil, nil, fmt.Errorf("%w: order in status %q", eo.ErrOrderNotAmendable, o.Status)
+	}
+	// the order as the amendment is computed from; the lines below are
+	// edited in place
+	base := *o
+	base.Items = slices.Clone(o.Items)
+
+	products := map[uint]*ec.Product{}
+	loadProduct := func(id uint) (*ec.Product, error) {
+		if p, ok := products[id]; ok {
+			return p, nil
+		}
+		p, err := s.product.FindByID(ctx, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: product %d not found", eo.ErrInvalidAmendment, id)
+		}
+		if err != nil {
+			return nil, err
+		}
+		products[id] = p
+		return p, nil
+	}
+
+	byID := map[uint]int{}
+	for i, it := range o.Items {
+		byID[it.ID] = i
+	}
+	prevQty := map[uint]int{}
+	for _, it := range o.Items {
+		prevQty[it.ProductID] += it.Quantity
+	}
+
+	// removing a line of a bundle removes the whole bundle
+	remove := in.Remove
+	for _, id := range in.Remove {
+		if idx, ok := byID[id]; ok && o.Items[idx].BundleGroup != 0 {
+			for _, it := range o.Items {
+				if it.BundleGroup == o.Items[idx].BundleGroup && !slices.Contains(remove, it.ID) {
+					remove = append(remove, it.ID)
+				}
+			}
+		}
+	}
+
+	var changes []eo.OrderAmendmentChange
+	removed := map[uint]bool{}
+	for _, id := range remove {
+		idx, ok := byID[id]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: order item %d not found", eo.ErrInvalidAmendment, id)
+		}
+		it := o.Items[idx]
+		removed[id] = true
+		changes = append(changes, eo.OrderAmendmentChange{
+			Action:          eo.AmendmentActionRemove,
+			ItemID:          it.ID,
+			ProductID:       it.ProductID,
+			BeforeQuantity:  it.Quantity,
+			BeforeOptions:   it.SelectedOptionsJSON,
+			BeforeLineTotal: it.LineTotal,
+		})
+	}
+
+	for _, up := range in.Update {
+		idx, ok := byID[up.ItemID]
+		if !ok || removed[up.ItemID] {
+			return nil, nil, fmt.Errorf("%w: order item %d not found", eo.ErrInvalidAmendment, up.ItemID)
+		}
+		it := &o.Items[idx]
+		if it.BundleGroup != 0 {
+			return nil, nil, fmt.Errorf("%w: order item %d is part of a bundle; remove the bundle and add it again", ec.ErrInvalidBundle, it.ID)
+		}
+		p, err := loadProduct(it.ProductID)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts := up.Options
+		if opts == nil {
+			_ = json.Unmarshal([]byte(it.SelectedOptionsJSON), &opts)
+		}
+		change := eo.OrderAmendmentChange{
+			Action:          eo.AmendmentActionUpdate,
+			ItemID:          it.ID,
+			ProductID:       it.ProductID,
+			BeforeQuantity:  it.Quantity,
+			BeforeOptions:   it.SelectedOptionsJSON,
+			BeforeLineTotal: it.LineTotal,
+		}
+		// the line keeps the price it was ordered at; only a different
+		// configuration is priced at the current catalog price
+		options := MarshalSelectedOptions(opts)
+		if options != it.SelectedOptionsJSON {
+			it.UnitPrice = CalculateUnitPrice(*p, opts)
+		}
+		it.Quantity = up.Quantity
+		it.LineTotal = it.UnitPrice * float64(up.Quantity)
+		it.CalculatedProductionTimeDays = CalculateItemProductionTime(*p, opts)
+		it.SelectedOptionsJSON = options
+		change.AfterQuantity = it.Quantity
+		change.AfterOptions = it.SelectedOptionsJSON
+		change.AfterLineTotal = it.LineTotal
+		changes = append(changes, change)
+	}
+
+	items := make([]eo.OrderItem, 0, len(o.Items)+len(in.Add))
+	for _, it := range o.Items {
+		if !removed[it.ID] {
+			items = append(items, it)
+		}
+	}
+	for _, add := range in.Add {
+		p, err := loadProduct(add.ProductID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !p.Published() {
+			return nil, nil, fmt.Errorf("%w: product %d is not available", eo.ErrInvalidAmendment, add.ProductID)
+		}
+		if add.Quantity <= 0 {
+			add.Quantity = 1
+		}
+		lines, err := orderLines(p, add.Quantity, add.Options, max(nextBundleGroup(o.Items), nextBundleGroup(items)))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, it := range lines {
+			items = append(items, it)
+			changes = append(changes, eo.OrderAmendmentChange{
+				Action:         eo.AmendmentActionAdd,
+				ProductID:      it.ProductID,
+				AfterQuantity:  it.Quantity,
+				AfterOptions:   it.SelectedOptionsJSON,
+				AfterLineTotal: it.LineTotal,
+			})
+		}
+	}
+	if len(items) == 0 {
+		return nil, nil, fmt.Errorf("%w: an order must keep at least one item", eo.ErrInvalidAmendment)
+	}
+
+	var total float64
+	allInStock := true
+	newQty := map[uint]int{}
+	for _, it := range items {
+		total += it.LineTotal
+		newQty[it.ProductID] += it.Quantity
+	}
+	paid := o.PaymentStatus == eo.PaymentStatusPaid
+	for pid, qty := range newQty {
+		p, err := loadProduct(pid)
+		if err != nil {
+			return nil, nil, err
+		}
+		available := p.Quantity
+		if paid {
+			// stock for the previous lines was already taken when the order was paid
+			available += prevQty[pid]
+		}
+		if available < qty {
+			allInStock = false
+		}
+	}
+
+	changesJSON, _ := json.Marshal(changes)
+	role := "client"
+	if asAdmin {
+		role = "admin"
+	}
+	prevTotal := o.TotalPrice
+	prevETA := o.EstimatedProductionTimeDays
+	o.Items = items
+	o.TotalPrice = total
+	o.EstimatedProductionTimeDays = s.estimateProductionTime(ctx, items, allInStock)
+	amendment := &eo.OrderAmendment{
+		ActorUserID:      actorID,
+		ActorRole:        role,
+		Note:             in.Note,
+		ChangesJSON:      string(changesJSON),
+		PreviousTotal:    prevTotal,
+		NewTotal:         total,
+		PriceDifference:  roundCents(total - prevTotal),
+		PreviousETADays:  prevETA,
+		NewETADays:       o.EstimatedProductionTimeDays,
+		SettlementType:   eo.SettlementTypeNone,
+		SettlementStatus: eo.SettlementStatusNotRequired,
+	}
+	if paid && amendment.PriceDifference > 0 {
+		amendment.SettlementType = eo.SettlementTypeCharge
+		amendment.SettlementStatus = eo.SettlementStatusPending
+	} else if paid && amendment.PriceDifference < 0 {
+		amendment.SettlementType = eo.SettlementTypeRefund
+		amendment.SettlementStatus = eo.SettlementStatusPending
+	}
+
+	removedIDs := make([]uint, 0, len(removed))
+	for id := range removed {
+		removedIDs = append(removedIDs, id)
+	}
+	// a paid order already holds its stock: the amendment takes or hands
+	// back the difference, and stock handed back may be awaited
+	stock := map[uint]int{}
+	var restocked []*ec.Product
+	if paid {
+		for pid := range mergeKeys(prevQty, newQty) {
+			delta := prevQty[pid] - newQty[pid]
+			if delta == 0 {
+				continue
+			}
+			stock[pid] = delta
+			if delta > 0 {
+				if before, err := s.product.FindByID(ctx, pid); err == nil {
+					restocked = append(restocked, before)
+				}
+			}
+		}
+	}
+	if err := s.orders.ApplyAmendment(ctx, &base, o, removedIDs, stock, amendment); err != nil {
+		return nil, nil, err
+	}
+	for _, before := range restocked {
+		s.watcher.ProductChanged(ctx, before)
+	}
+
+	s.settleAmendment(o, amendment)
+	if err := s.orders.UpdateAmendmentSettlement(ctx, amendment.ID, amendment.SettlementStatus, amendment.SettlementReference, amendment.CheckoutURL); err != nil {
+		log.Printf("orders: record settlement of amendment %d (%s %s): %v", amendment.ID, amendment.SettlementStatus, amendment.SettlementReference, err)
+	}
+	if amendment.SettlementType == eo.SettlementTypeRefund && amendment.SettlementStatus == eo.SettlementStatusSettled {
+		if _, err := s.invoices.IssueForAmendment(ctx, amendment.ID); err != nil {
+			log.Printf("orders: issue credit note for amendment %d: %v", amendment.ID, err)
+		}
+	}
+
+	updated, err := s.orders.FindWithItems(ctx, o.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, amendment, nil
+}
+
+func (s *ordersService) ListOrderAmendments(ctx context.Context, orderID uint) ([]eo.OrderAmendment, error) {
+	return s.orders.ListAmendments(ctx, orderID)
+}
+
+// settleAmendment collects or refunds the price difference of an amendment on an
+// already paid order. Failures are recorded on the amendment instead of rolling
+// back the line changes so that an admin can follow up manually.
+func (s *ordersService) settleAmendment(o *eo.Order, a *eo.OrderAmendment) {
+	switch a.SettlementType {
+	case eo.SettlementTypeCharge:
+		url, err := s.payments.CreateCheckout(gateway.CheckoutRequest{
+			OrderID:     o.ID,
+			Description: fmt.Sprintf("Order #%d amendment #%d", o.ID, a.ID),
+			Amount:      a.PriceDifference,
+			Metadata:    map[string]string{"amendment_id": strconv.Itoa(int(a.ID))},
+		})
+		if err != nil {
+			a.SettlementStatus = eo.SettlementStatusFailed
+			return
+		}
+		a.CheckoutURL = url
+	case eo.SettlementTypeRefund:
+		ref, err := s.payments.Refund(o.PaymentReference, -a.PriceDifference)
+		if err != nil {
+			a.SettlementStatus = eo.SettlementStatusFailed
+			return
+		}
+		a.SettlementStatus = eo.SettlementStatusSettled
+		a.SettlementReference = ref
+	}
+}
+
+func mergeKeys(a, b map[uint]int) map[uint]struct{} {
+	out := make(map[uint]struct{}, len(a)+len(b))
+	for k := range a {
+		out[k] = struct{}{}
+	}
+	for k := range b {
+		out[k] = struct{}{}
+	}
+	return out
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,90 @@
+package orders
+
+import (
+	"fmt"
+
+	order_dto "furniture-shop/internal/dtos/orders"
+	ec "furniture-shop/internal/entities/catalog"
+	eo "furniture-shop/internal/entities/orders"
+)
+
+// orderLines prices qty of a product with the selected options. A bundle
+// becomes one line per component, marked with the bundle and group, so that
+// production, stock and invoices deal with the products actually made. The
+// bundle price is shared between the lines in proportion to the components'
+// own prices.
+func orderLines(p *ec.Product, qty int, selected []order_dto.SelectedOption, group int) ([]eo.OrderItem, error) {
+	if p.Bundle == nil {
+		unit := CalculateUnitPrice(*p, selected)
+		return []eo.OrderItem{{
+			ProductID:                    p.ID,
+			Quantity:                     qty,
+			UnitPrice:                    unit,
+			LineTotal:                    unit * float64(qty),
+			CalculatedProductionTimeDays: CalculateItemProductionTime(*p, selected),
+			SelectedOptionsJSON:          MarshalSelectedOptions(selected),
+		}}, nil
+	}
+	b := p.Bundle
+	if !b.Sellable() {
+		return nil, fmt.Errorf("%w: a component of product %d is not available", ec.ErrInvalidBundle, p.ID)
+	}
+	ids := optionIDs(selected)
+	total := roundCents(b.Price(p.Price(), ids) * float64(qty))
+	own := make([]float64, len(b.Components))
+	var sum float64
+	for i := range b.Components {
+		c := &b.Components[i]
+		own[i] = c.Product.PriceWith(c.Options(ids)) * float64(c.Quantity*qty)
+		sum += own[i]
+	}
+	lines := make([]eo.OrderItem, len(b.Components))
+	rest := total
+	for i := range b.Components {
+		c := &b.Components[i]
+		opts := componentOptions(c, ids)
+		line := rest
+		if i < len(b.Components)-1 {
+			switch {
+			case sum > 0:
+				line = roundCents(total * own[i] / sum)
+			default:
+				line = roundCents(total / float64(len(b.Components)))
+			}
+		}
+		rest -= line
+		n := c.Quantity * qty
+		lines[i] = eo.OrderItem{
+			ProductID:                    c.ProductID,
+			Quantity:                     n,
+			UnitPrice:                    line / float64(n),
+			LineTotal:                    line,
+			CalculatedProductionTimeDays: CalculateItemProductionTime(*c.Product, opts),
+			SelectedOptionsJSON:          MarshalSelectedOptions(opts),
+			BundleProductID:              &p.ID,
+			BundleGroup:                  group,
+		}
+	}
+	return lines, nil
+}
+
+func componentOptions(c *ec.BundleComponent, selected []uint) []order_dto.SelectedOption {
+	var out []order_dto.SelectedOption
+	for _, id := range c.Options(selected) {
+		for _, o := range c.Product.Options {
+			if o.ID == id {
+				out = append(out, order_dto.SelectedOption{ID: id, Type: o.OptionType})
+			}
+		}
+	}
+	return out
+}
+
+// nextBundleGroup numbers the next bundle ordered in items.
+func nextBundleGroup(items []eo.OrderItem) int {
+	group := 0
+	for _, it := range items {
+		group = max(group, it.BundleGroup)
+	}
+	return group + 1
+}
@@ -29,14 +29,7 @@ func (s *cartService) Replace(ctx context.Context, userID uint, in cartdto.Repla
 		if it.Quantity <= 0 {
 			it.Quantity = 1
 		}
-		sort.Slice(it.Options, func(i, j int) bool {
-			if it.Options[i].ID == it.Options[j].ID {
-				return it.Options[i].Type < it.Options[j].Type
-			}
-			return it.Options[i].ID < it.Options[j].ID
-		})
-		b, _ := json.Marshal(it.Options)
-		items = append(items, eo.CartItem{ProductID: it.ProductID, Quantity: it.Quantity, SelectedOptionsJSON: string(b)})
+		items = append(items, eo.CartItem{ProductID: it.ProductID, Quantity: it.Quantity, SelectedOptionsJSON: NormalizeOptions(it.Options)})
 	}
 	return s.carts.ReplaceItems(ctx, userID, items)
 }
@@ -45,14 +38,7 @@ func (s *cartService) AddItem(ctx context.Context, userID uint, in cartdto.AddCa
 	if in.Quantity <= 0 {
 		in.Quantity = 1
 	}
-	sort.Slice(in.Options, func(i, j int) bool {
-		if in.Options[i].ID == in.Options[j].ID {
-			return in.Options[i].Type < in.Options[j].Type
-		}
-		return in.Options[i].ID < in.Options[j].ID
-	})
-	b, _ := json.Marshal(in.Options)
-	item := &eo.CartItem{ProductID: in.ProductID, Quantity: in.Quantity, SelectedOptionsJSON: string(b)}
+	item := &eo.CartItem{ProductID: in.ProductID, Quantity: in.Quantity, SelectedOptionsJSON: NormalizeOptions(in.Options)}
 	return s.carts.AddItem(ctx, userID, item)
 }
 
@@ -60,14 +46,7 @@ func (s *cartService) UpdateItem(ctx context.Context, userID uint, itemID uint,
 	if in.Quantity <= 0 {
 		in.Quantity = 1
 	}
-	sort.Slice(in.Options, func(i, j int) bool {
-		if in.Options[i].ID == in.Options[j].ID {
-			return in.Options[i].Type < in.Options[j].Type
-		}
-		return in.Options[i].ID < in.Options[j].ID
-	})
-	b, _ := json.Marshal(in.Options)
-	return s.carts.UpdateItem(ctx, userID, itemID, eo.CartItem{Quantity: in.Quantity, SelectedOptionsJSON: string(b)})
+	return s.carts.UpdateItem(ctx, userID, itemID, eo.CartItem{Quantity: in.Quantity, SelectedOptionsJSON: NormalizeOptions(in.Options)})
 }
 
 func (s *cartService) RemoveItem(ctx context.Context, userID uint, itemID uint) error {
@@ -77,3 +56,16 @@ func (s *cartService) RemoveItem(ctx context.Context, userID uint, itemID uint)
 func (s *cartService) Clear(ctx context.Context, userID uint) error {
 	return s.carts.Clear(ctx, userID)
 }
+
+// NormalizeOptions renders an option selection in the stored form used to
+// tell configurations apart: sorted by id and type, as JSON.
+func NormalizeOptions(opts []cartdto.SelectedOption) string {
+	sort.Slice(opts, func(i, j int) bool {
+		if opts[i].ID == opts[j].ID {
+			return opts[i].Type < opts[j].Type
+		}
+		return opts[i].ID < opts[j].ID
+	})
+	b, _ := json.Marshal(opts)
+	return string(b)
+}
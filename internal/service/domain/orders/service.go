@@ -6,23 +6,31 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	order_dto "furniture-shop/internal/dtos/orders"
 	ec "furniture-shop/internal/entities/catalog"
 	eo "furniture-shop/internal/entities/orders"
 	eu "furniture-shop/internal/entities/user"
 	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/gateway"
 	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
 )
 
 type ordersService struct {
-	users   storage.UserRepository
-	orders  storage.OrderRepository
-	product storage.ProductRepository
+	users    storage.UserRepository
+	orders   storage.OrderRepository
+	product  storage.ProductRepository
+	payments gateway.PaymentGateway
+	invoices service.InvoiceService
+	notifier service.NotificationService
+	watcher  service.ProductWatcher
 }
 
-func NewOrdersService(users storage.UserRepository, orders storage.OrderRepository, product storage.ProductRepository) service.OrdersService {
-	return &ordersService{users: users, orders: orders, product: product}
+func NewOrdersService(users storage.UserRepository, orders storage.OrderRepository, product storage.ProductRepository, payments gateway.PaymentGateway, invoices service.InvoiceService, notifier service.NotificationService, watcher service.ProductWatcher) service.OrdersService {
+	return &ordersService{users: users, orders: orders, product: product, payments: payments, invoices: invoices, notifier: notifier, watcher: watcher}
 }
 
 func (s *ordersService) CreateOrder(ctx context.Context, in order_dto.CreateOrderInput) (*eo.Order, error) {
@@ -67,21 +75,20 @@ func (s *ordersService) CreateOrder(ctx context.Context, in order_dto.CreateOrde
 		if err != nil {
 			return nil, fmt.Errorf("product %d not found", it.ProductID)
 		}
+		if !p.Published() {
+			return nil, fmt.Errorf("product %d is not available", it.ProductID)
+		}
 		if it.Quantity <= 0 {
 			it.Quantity = 1
 		}
-		unit := CalculateUnitPrice(*p, it.Options)
-		line := unit * float64(it.Quantity)
-		pt := CalculateItemProductionTime(*p, it.Options)
-		items = append(items, eo.OrderItem{
-			ProductID:                    p.ID,
-			Quantity:                     it.Quantity,
-			UnitPrice:                    unit,
-			LineTotal:                    line,
-			CalculatedProductionTimeDays: pt,
-			SelectedOptionsJSON:          MarshalSelectedOptions(it.Options),
-		})
-		total += line
+		lines, err := orderLines(p, it.Quantity, it.Options, nextBundleGroup(items))
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines {
+			total += line.LineTotal
+		}
+		items = append(items, lines...)
 		available := p.Quantity
 		if available < it.Quantity {
 			allInStock = false
@@ -89,22 +96,15 @@ func (s *ordersService) CreateOrder(ctx context.Context, in order_dto.CreateOrde
 			}
 		} else {
 		}
-		for q := 0; q < it.Quantity; q++ {
-			_ = s.product.IncrementRecommendation(ctx, p.ID)
-		}
 	}
 	order.TotalPrice = total
-	if allInStock {
-		order.EstimatedProductionTimeDays = 1
-	} else {
-		workload, _ := s.orders.CountByStatus(ctx, eo.OrderStatusInProduction)
-		order.EstimatedProductionTimeDays = CalculateOrderProductionTimeWithWorkload(items, workload)
-	}
+	order.EstimatedProductionTimeDays = s.estimateProductionTime(ctx, items, allInStock)
 	order.Items = items
 
 	if err := s.orders.CreateWithItems(ctx, order); err != nil {
 		return nil, err
 	}
+	_ = s.notifier.OrderCreated(ctx, order.ID)
 	return order, nil
 }
 
@@ -123,8 +123,12 @@ func (s *ordersService) GetUserOrder(ctx context.Context, userID, orderID uint)
 	return o, nil
 }
 
-func (s *ordersService) AdminListOrders(ctx context.Context, status string) ([]eo.Order, error) {
-	return s.orders.ListAll(ctx, status)
+func (s *ordersService) AdminListOrders(ctx context.Context, f eo.OrderFilter, page query.Page) (*query.Result[eo.Order], error) {
+	items, total, err := s.orders.List(ctx, f, page)
+	if err != nil {
+		return nil, err
+	}
+	return query.NewResult(items, total, page), nil
 }
 
 func (s *ordersService) AdminUpdateOrderStatus(ctx context.Context, orderID uint, status string) error {
@@ -139,26 +143,44 @@ func (s *ordersService) AdminUpdateOrderStatus(ctx context.Context, orderID uint
 	if !allowed[status] {
 		return errors.New("invalid status")
 	}
-	return s.orders.UpdateStatus(ctx, orderID, status)
+	if err := s.orders.UpdateStatus(ctx, orderID, status); err != nil {
+		return err
+	}
+	_ = s.notifier.OrderStatusChanged(ctx, orderID, status)
+	return nil
 }
 
-func CalculateUnitPrice(product ec.Product, selected []order_dto.SelectedOption) float64 {
-	price := product.BasePrice
-	byID := map[uint]ec.ProductOption{}
-	for _, o := range product.Options {
-		byID[o.ID] = o
+// AdminScheduleDelivery sets the day an order will be delivered; nil clears it.
+// Customers are reminded on that day once the order has shipped.
+func (s *ordersService) AdminScheduleDelivery(ctx context.Context, orderID uint, date *time.Time) error {
+	o, err := s.orders.FindByID(ctx, orderID)
+	if err != nil {
+		return errors.New("order not found")
 	}
-	for _, so := range selected {
-		if opt, ok := byID[so.ID]; ok {
-			switch opt.PriceModifierType {
-			case "absolute":
-				price += opt.PriceModifierValue
-			case "percent":
-				price = price * (1.0 + opt.PriceModifierValue/100.0)
-			}
-		}
+	if o.Status == eo.OrderStatusDelivered || o.Status == eo.OrderStatusCancelled {
+		return fmt.Errorf("order in status %q can no longer be scheduled", o.Status)
+	}
+	return s.orders.UpdateDeliveryDate(ctx, orderID, date)
+}
+
+func (s *ordersService) estimateProductionTime(ctx context.Context, items []eo.OrderItem, allInStock bool) int {
+	if allInStock {
+		return 1
 	}
-	return price
+	workload, _ := s.orders.CountByStatus(ctx, eo.OrderStatusInProduction)
+	return CalculateOrderProductionTimeWithWorkload(items, workload)
+}
+
+func CalculateUnitPrice(product ec.Product, selected []order_dto.SelectedOption) float64 {
+	return product.PriceWith(optionIDs(selected))
+}
+
+func optionIDs(selected []order_dto.SelectedOption) []uint {
+	ids := make([]uint, len(selected))
+	for i, so := range selected {
+		ids[i] = so.ID
+	}
+	return ids
 }
 
 func CalculateItemProductionTime(product ec.Product, selected []order_dto.SelectedOption) int {
@@ -181,6 +203,21 @@ func CalculateItemProductionTime(product ec.Product, selected []order_dto.Select
 	return days
 }
 
+// DescribeSelectedOptions renders the stored option selection of a line as "type: name" pairs.
+func DescribeSelectedOptions(product ec.Product, selectedJSON string) string {
+	var selected []order_dto.SelectedOption
+	_ = json.Unmarshal([]byte(selectedJSON), &selected)
+	names := make([]string, 0, len(selected))
+	for _, so := range selected {
+		for _, opt := range product.Options {
+			if opt.ID == so.ID {
+				names = append(names, fmt.Sprintf("%s: %s", opt.OptionType, opt.OptionName))
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
 func MarshalSelectedOptions(selected []order_dto.SelectedOption) string {
 	b, _ := json.Marshal(selected)
 	return string(b)
@@ -0,0 +1,337 @@
+package orders
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	order_dto "furniture-shop/internal/dtos/orders"
+	wishlist_dto "furniture-shop/internal/dtos/wishlists"
+	ec "furniture-shop/internal/entities/catalog"
+	en "furniture-shop/internal/entities/notification"
+	eo "furniture-shop/internal/entities/orders"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/storage"
+)
+
+type wishlistService struct {
+	wishlists storage.WishlistRepository
+	carts     storage.CartRepository
+	products  storage.ProductRepository
+	notifier  service.NotificationService
+}
+
+func NewWishlistService(wishlists storage.WishlistRepository, carts storage.CartRepository, products storage.ProductRepository, notifier service.NotificationService) service.WishlistService {
+	return &wishlistService{wishlists: wishlists, carts: carts, products: products, notifier: notifier}
+}
+
+func (s *wishlistService) List(ctx context.Context, userID uint) ([]eo.Wishlist, error) {
+	lists, err := s.wishlists.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range lists {
+		if err := s.price(ctx, &lists[i]); err != nil {
+			return nil, err
+		}
+	}
+	return lists, nil
+}
+
+func (s *wishlistService) Get(ctx context.Context, userID, id uint) (*eo.Wishlist, error) {
+	w, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return w, s.price(ctx, w)
+}
+
+func (s *wishlistService) Create(ctx context.Context, userID uint, name string) (*eo.Wishlist, error) {
+	name, err := checkName(name)
+	if err != nil {
+		return nil, err
+	}
+	w := &eo.Wishlist{UserID: userID, Name: name, Items: []eo.WishlistItem{}}
+	if err := s.wishlists.Create(ctx, w); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (s *wishlistService) Rename(ctx context.Context, userID, id uint, name string) (*eo.Wishlist, error) {
+	name, err := checkName(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if err := s.wishlists.Rename(ctx, id, name); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, id)
+}
+
+func (s *wishlistService) Delete(ctx context.Context, userID, id uint) error {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.wishlists.Delete(ctx, id)
+}
+
+// Share gives the wishlist a random token that anyone with the link can
+// read it by; sharing an already shared wishlist keeps its link.
+func (s *wishlistService) Share(ctx context.Context, userID, id uint, shared bool) (*eo.Wishlist, error) {
+	w, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	var token *string
+	if shared {
+		if w.ShareToken != nil {
+			return s.Get(ctx, userID, id)
+		}
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		t := hex.EncodeToString(b)
+		token = &t
+	}
+	if err := s.wishlists.SetShareToken(ctx, id, token); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, userID, id)
+}
+
+// GetShared returns a shared wishlist without its owner and share token.
+func (s *wishlistService) GetShared(ctx context.Context, token string) (*eo.Wishlist, error) {
+	w, err := s.wishlists.FindByShareToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	w.UserID = 0
+	w.ShareToken = nil
+	return w, s.price(ctx, w)
+}
+
+// AddItem saves a configuration of a published product, adding to the
+// quantity of the same configuration if it is already in the wishlist.
+func (s *wishlistService) AddItem(ctx context.Context, userID, id uint, in wishlist_dto.WishlistItemRequest) (*eo.WishlistItem, error) {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	p, err := s.products.FindByID(ctx, in.ProductID)
+	if err != nil || !p.Published() {
+		return nil, fmt.Errorf("%w: product %d is not available", eo.ErrInvalidWishlist, in.ProductID)
+	}
+	item, err := configure(p, in)
+	if err != nil {
+		return nil, err
+	}
+	item.WishlistID = id
+	if err := s.wishlists.AddItem(ctx, item); err != nil {
+		return nil, err
+	}
+	item.UnitPrice = item.AlertPrice
+	item.Available = true
+	return item, nil
+}
+
+// UpdateItem changes the quantity and options of an item; a new
+// configuration starts price-drop alerts from its current price.
+func (s *wishlistService) UpdateItem(ctx context.Context, userID, id, itemID uint, in wishlist_dto.WishlistItemRequest) error {
+	w, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(w.Items, func(it eo.WishlistItem) bool { return it.ID == itemID })
+	if i < 0 {
+		return errors.New("wishlist item not found")
+	}
+	p, err := s.products.FindIncludingArchived(ctx, w.Items[i].ProductID)
+	if err != nil {
+		return err
+	}
+	item, err := configure(p, in)
+	if err != nil {
+		return err
+	}
+	return s.wishlists.UpdateItem(ctx, id, itemID, *item)
+}
+
+func (s *wishlistService) RemoveItem(ctx context.Context, userID, id, itemID uint) error {
+	if _, err := s.owned(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.wishlists.RemoveItems(ctx, id, []uint{itemID})
+}
+
+// MoveToCart adds the selected items to the user's cart, merging them into
+// cart lines of the same configuration, and takes them off the wishlist
+// unless asked to keep them. Items of products that can no longer be
+// ordered stay where they are.
+func (s *wishlistService) MoveToCart(ctx context.Context, userID, id uint, in wishlist_dto.MoveToCartRequest) (*eo.Cart, error) {
+	w, err := s.owned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.price(ctx, w); err != nil {
+		return nil, err
+	}
+	for _, itemID := range in.ItemIDs {
+		if !slices.ContainsFunc(w.Items, func(it eo.WishlistItem) bool { return it.ID == itemID }) {
+			return nil, errors.New("wishlist item not found")
+		}
+	}
+	var moved []uint
+	for _, it := range w.Items {
+		if !it.Available || (len(in.ItemIDs) > 0 && !slices.Contains(in.ItemIDs, it.ID)) {
+			continue
+		}
+		line := &eo.CartItem{ProductID: it.ProductID, Quantity: it.Quantity, SelectedOptionsJSON: it.SelectedOptionsJSON}
+		if _, err := s.carts.AddItem(ctx, userID, line); err != nil {
+			return nil, err
+		}
+		moved = append(moved, it.ID)
+	}
+	if !in.Keep {
+		if err := s.wishlists.RemoveItems(ctx, id, moved); err != nil {
+			return nil, err
+		}
+	}
+	return s.carts.GetOrCreateByUser(ctx, userID)
+}
+
+// ProductChanged alerts the owners of wishlists holding the product when it
+// comes back in stock or when a saved configuration gets cheaper than the
+// lowest price its owner has seen. Each user gets at most one message of
+// each kind per change, also counting product subscriptions, so a restock
+// that also lowers the price sends both; failures are logged.
+func (s *wishlistService) ProductChanged(ctx context.Context, prev *ec.Product) {
+	p, err := s.products.FindByID(ctx, prev.ID)
+	if err != nil || !p.Published() {
+		return
+	}
+	items, err := s.wishlists.ListItemsByProduct(ctx, p.ID)
+	if err != nil {
+		log.Printf("wishlists: list items of product %d: %v", p.ID, err)
+		return
+	}
+	restocked := prev.Quantity <= 0 && p.Quantity > 0
+	type alert struct {
+		item    eo.WishlistItem
+		dropped []eo.WishlistItem
+	}
+	var users []uint
+	alerts := map[uint]*alert{}
+	for _, it := range items {
+		if it.Wishlist == nil {
+			continue
+		}
+		uid := it.Wishlist.UserID
+		a, ok := alerts[uid]
+		if !ok {
+			a = &alert{item: it}
+			alerts[uid] = a
+			users = append(users, uid)
+		}
+		if it.UnitPrice = ConfiguredPrice(p, it.SelectedOptionsJSON); it.UnitPrice < it.AlertPrice-0.005 {
+			if len(a.dropped) == 0 {
+				a.item = it
+			}
+			a.dropped = append(a.dropped, it)
+		}
+	}
+	for _, uid := range users {
+		a := alerts[uid]
+		it := a.item
+		if restocked && service.FirstAlert(ctx, en.SubscriptionBackInStock, uid, "") {
+			if err := s.notifier.BackInStock(ctx, uid, p, it.SelectedOptionsJSON, it.Wishlist.Name); err != nil {
+				log.Printf("wishlists: alert user %d about product %d: %v", uid, p.ID, err)
+			}
+		}
+		if len(a.dropped) == 0 {
+			continue
+		}
+		if service.FirstAlert(ctx, en.SubscriptionPriceDrop, uid, "") {
+			if err := s.notifier.PriceDropped(ctx, uid, p, it.SelectedOptionsJSON, it.Wishlist.Name, it.AlertPrice); err != nil {
+				log.Printf("wishlists: alert user %d about product %d: %v", uid, p.ID, err)
+				continue
+			}
+		}
+		for _, d := range a.dropped {
+			if err := s.wishlists.SetAlertPrice(ctx, d.ID, d.UnitPrice); err != nil {
+				log.Printf("wishlists: lower alert price of item %d: %v", d.ID, err)
+			}
+		}
+	}
+}
+
+// owned loads a wishlist of the user.
+func (s *wishlistService) owned(ctx context.Context, userID, id uint) (*eo.Wishlist, error) {
+	w, err := s.wishlists.FindByID(ctx, id)
+	if err != nil || w.UserID != userID {
+		return nil, errors.New("wishlist not found")
+	}
+	return w, nil
+}
+
+// price fills in the current price and availability of the items.
+func (s *wishlistService) price(ctx context.Context, w *eo.Wishlist) error {
+	ids := make([]uint, len(w.Items))
+	for i, it := range w.Items {
+		ids[i] = it.ProductID
+	}
+	products, err := s.products.ListByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range w.Items {
+		it := &w.Items[i]
+		j := slices.IndexFunc(products, func(p ec.Product) bool { return p.ID == it.ProductID })
+		if j < 0 {
+			continue
+		}
+		it.UnitPrice = ConfiguredPrice(&products[j], it.SelectedOptionsJSON)
+		it.Available = products[j].Published()
+	}
+	return nil
+}
+
+// configure builds an item from a request, rejecting options the product
+// does not have.
+func configure(p *ec.Product, in wishlist_dto.WishlistItemRequest) (*eo.WishlistItem, error) {
+	for _, o := range in.Options {
+		if !slices.ContainsFunc(p.Options, func(po ec.ProductOption) bool { return po.ID == o.ID }) {
+			return nil, fmt.Errorf("%w: product %d has no option %d", eo.ErrInvalidWishlist, p.ID, o.ID)
+		}
+	}
+	if in.Quantity <= 0 {
+		in.Quantity = 1
+	}
+	options := NormalizeOptions(in.Options)
+	return &eo.WishlistItem{ProductID: p.ID, Quantity: in.Quantity, SelectedOptionsJSON: options, AlertPrice: ConfiguredPrice(p, options)}, nil
+}
+
+// ConfiguredPrice is the unit price, rounded to cents, of a product in an
+// option selection stored as cart items store it.
+func ConfiguredPrice(p *ec.Product, options string) float64 {
+	var selected []order_dto.SelectedOption
+	_ = json.Unmarshal([]byte(options), &selected)
+	return roundCents(CalculateUnitPrice(*p, selected))
+}
+
+func checkName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len([]rune(name)) > 120 {
+		return "", fmt.Errorf("%w: name must have 1 to 120 characters", eo.ErrInvalidWishlist)
+	}
+	return name, nil
+}
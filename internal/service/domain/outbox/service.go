@@ -0,0 +1,105 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"furniture-shop/internal/config"
+	en "furniture-shop/internal/entities/notification"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/notifier"
+	"furniture-shop/internal/storage"
+)
+
+// claimLease is how long a claimed message stays reserved for one worker.
+const claimLease = 5 * time.Minute
+
+type outboxService struct {
+	repo     storage.OutboxRepository
+	notifier *notifier.Notifier
+	cfg      config.OutboxConfig
+}
+
+func NewOutboxService(repo storage.OutboxRepository, n *notifier.Notifier, cfg config.OutboxConfig) service.OutboxService {
+	return &outboxService{repo: repo, notifier: n, cfg: cfg}
+}
+
+func (s *outboxService) Enqueue(ctx context.Context, m *en.OutboxMessage) error {
+	if m.Channel == "" {
+		m.Channel = en.ChannelEmail
+	}
+	return s.repo.Enqueue(ctx, m)
+}
+
+// DispatchDue delivers one batch of due messages and returns how many were sent.
+func (s *outboxService) DispatchDue(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	batch, err := s.repo.ClaimDue(ctx, now, claimLease, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, m := range batch {
+		attempts := m.Attempts + 1
+		if err := s.deliver(ctx, m); err != nil {
+			dead := attempts >= s.cfg.MaxAttempts
+			next := time.Now().UTC().Add(s.backoff(attempts))
+			if dead {
+				log.Printf("outbox: message %d to %s dead-lettered after %d attempts: %v", m.ID, m.Recipient, attempts, err)
+			}
+			if uerr := s.repo.MarkFailed(ctx, m.ID, attempts, next, err.Error(), dead); uerr != nil {
+				return sent, uerr
+			}
+			continue
+		}
+		if err := s.repo.MarkSent(ctx, m.ID, attempts, time.Now().UTC()); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *outboxService) List(ctx context.Context, status string, limit, offset int) ([]en.OutboxMessage, int64, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.List(ctx, status, limit, offset)
+}
+
+func (s *outboxService) Get(ctx context.Context, id uint) (*en.OutboxMessage, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *outboxService) Resend(ctx context.Context, id uint) error {
+	m, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if m.Status == en.OutboxStatusSending {
+		return errors.New("message is being delivered")
+	}
+	return s.repo.Requeue(ctx, id)
+}
+
+func (s *outboxService) deliver(ctx context.Context, m en.OutboxMessage) error {
+	return s.notifier.Send(ctx, notifier.Message{Channel: m.Channel, To: m.Recipient, Subject: m.Subject, Text: m.TextBody, HTML: m.HTMLBody})
+}
+
+// backoff doubles the retry delay with each attempt: base, 2*base, 4*base ... capped at the configured maximum.
+func (s *outboxService) backoff(attempts int) time.Duration {
+	d := time.Duration(s.cfg.BaseBackoffSeconds) * time.Second
+	max := time.Duration(s.cfg.MaxBackoffSeconds) * time.Second
+	for i := 1; i < attempts && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
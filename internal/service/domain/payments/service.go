@@ -3,31 +3,48 @@ package payments
 import (
 	"context"
 	"fmt"
+	"math"
 
+	ea "furniture-shop/internal/entities/analytics"
 	eo "furniture-shop/internal/entities/orders"
 	"furniture-shop/internal/service"
-	"furniture-shop/internal/service/mailer"
+	"furniture-shop/internal/service/gateway"
 	"furniture-shop/internal/storage"
 )
 
 type paymentService struct {
-	orders   storage.OrderRepository
-	products storage.ProductRepository
-	users    storage.UserRepository
-	mailer   mailer.Sender
+	payments  gateway.PaymentGateway
+	orders    storage.OrderRepository
+	products  storage.ProductRepository
+	users     storage.UserRepository
+	invoices  service.InvoiceService
+	notifier  service.NotificationService
+	analytics service.AnalyticsService
 }
 
-func NewPaymentService(orders storage.OrderRepository, products storage.ProductRepository, users storage.UserRepository, m mailer.Sender) service.PaymentService {
-	return &paymentService{orders: orders, products: products, users: users, mailer: m}
+func NewPaymentService(payments gateway.PaymentGateway, orders storage.OrderRepository, products storage.ProductRepository, users storage.UserRepository, invoices service.InvoiceService, notifier service.NotificationService, analytics service.AnalyticsService) service.PaymentService {
+	return &paymentService{payments: payments, orders: orders, products: products, users: users, invoices: invoices, notifier: notifier, analytics: analytics}
 }
 
-func (s *paymentService) ProcessPaymentResult(ctx context.Context, orderID uint, paymentStatus, orderStatus string) error {
+// ProcessPaymentResult records the outcome of an order's payment; amount is
+// what was paid. A payment that does not match the order total, made through
+// a checkout session opened before the order was amended, is refunded and
+// the order stays unpaid.
+func (s *paymentService) ProcessPaymentResult(ctx context.Context, orderID uint, paymentStatus, orderStatus, paymentRef string, amount float64) error {
 	var prevPayment string
 	var withItems *eo.Order
 	if o, err := s.orders.FindWithItems(ctx, orderID); err == nil {
 		prevPayment = o.PaymentStatus
 		withItems = o
 	}
+	if paymentStatus == eo.PaymentStatusPaid && prevPayment != eo.PaymentStatusPaid && withItems != nil &&
+		math.Round(amount*100) != math.Round(withItems.TotalPrice*100) {
+		if _, err := s.payments.Refund(paymentRef, amount); err != nil {
+			return fmt.Errorf("order %d was paid %.2f instead of %.2f and the refund failed: %w", orderID, amount, withItems.TotalPrice, err)
+		}
+		_ = s.notifier.PaymentFailed(ctx, orderID)
+		return fmt.Errorf("order %d was paid %.2f instead of %.2f; the payment was refunded", orderID, amount, withItems.TotalPrice)
+	}
 
 	if err := s.orders.UpdatePaymentStatus(ctx, orderID, paymentStatus); err != nil {
 		return err
@@ -35,6 +52,11 @@ func (s *paymentService) ProcessPaymentResult(ctx context.Context, orderID uint,
 	if err := s.orders.UpdateStatus(ctx, orderID, orderStatus); err != nil {
 		return err
 	}
+	if paymentRef != "" {
+		if err := s.orders.UpdatePaymentReference(ctx, orderID, paymentRef); err != nil {
+			return err
+		}
+	}
 
 	if paymentStatus == "paid" {
 		if prevPayment != "paid" && withItems != nil {
@@ -42,19 +64,47 @@ func (s *paymentService) ProcessPaymentResult(ctx context.Context, orderID uint,
 				if derr := s.products.AdjustQuantity(ctx, it.ProductID, -it.Quantity); derr != nil {
 					return derr
 				}
+				s.analytics.Track(ea.Event{
+					Type:        ea.EventOrderPaid,
+					ProductID:   it.ProductID,
+					OrderID:     &withItems.ID,
+					OrderItemID: &it.ID,
+					UserID:      &withItems.UserID,
+					SessionID:   fmt.Sprintf("order:%d", withItems.ID),
+					Quantity:    it.Quantity,
+					Value:       it.LineTotal,
+				})
 			}
 		}
-		_ = s.orders.UpdateStatus(ctx, orderID, string(eo.OrderStatusInProduction))
-		if ord, err := s.orders.FindByID(ctx, orderID); err == nil {
-			if u, err := s.users.FindByID(ctx, ord.UserID); err == nil {
-				_ = s.mailer.Send(u.Email, "Payment succeeded", fmt.Sprintf("Your payment was successful. Order #%d", orderID))
-			}
+		if _, err := s.invoices.IssueForOrder(ctx, orderID); err != nil {
+			return err
 		}
+		_ = s.notifier.PaymentSucceeded(ctx, orderID)
 	} else if paymentStatus == "declined" || paymentStatus == "cancelled" {
-		if o, err := s.orders.FindByID(ctx, orderID); err == nil {
-			if u, err := s.users.FindByID(ctx, o.UserID); err == nil {
-				_ = s.mailer.Send(u.Email, "Payment failed", fmt.Sprintf("Your payment failed or was cancelled. Order #%d", orderID))
-			}
+		_ = s.notifier.PaymentFailed(ctx, orderID)
+	}
+	return nil
+}
+
+// SettleAmendment records the outcome of the extra payment collected for an order amendment.
+func (s *paymentService) SettleAmendment(ctx context.Context, amendmentID uint, paid bool, paymentRef string) error {
+	a, err := s.orders.FindAmendment(ctx, amendmentID)
+	if err != nil {
+		return err
+	}
+	if a.SettlementType != eo.SettlementTypeCharge || a.SettlementStatus == eo.SettlementStatusSettled {
+		return nil
+	}
+	status := eo.SettlementStatusFailed
+	if paid {
+		status = eo.SettlementStatusSettled
+	}
+	if err := s.orders.UpdateAmendmentSettlement(ctx, amendmentID, status, paymentRef, a.CheckoutURL); err != nil {
+		return err
+	}
+	if paid {
+		if _, err := s.invoices.IssueForAmendment(ctx, amendmentID); err != nil {
+			return err
 		}
 	}
 	return nil
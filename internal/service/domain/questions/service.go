@@ -0,0 +1,218 @@
+package questions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
+)
+
+var statuses = map[string]bool{ec.QuestionPending: true, ec.QuestionApproved: true, ec.QuestionRejected: true}
+
+type questionService struct {
+	questions storage.QuestionRepository
+	products  storage.ProductRepository
+	orders    storage.OrderRepository
+	users     storage.UserRepository
+	notifier  service.NotificationService
+}
+
+func NewQuestionService(questions storage.QuestionRepository, products storage.ProductRepository, orders storage.OrderRepository, users storage.UserRepository, notifier service.NotificationService) service.QuestionService {
+	return &questionService{questions: questions, products: products, orders: orders, users: users, notifier: notifier}
+}
+
+// ListProductQuestions returns the approved questions of a product with
+// their approved answers.
+func (s *questionService) ListProductQuestions(ctx context.Context, productID uint, answered *bool, page query.Page) (*query.Result[ec.Question], error) {
+	f := ec.QuestionFilter{ProductID: productID, Status: ec.QuestionApproved, AnswerStatus: ec.QuestionApproved, Answered: answered}
+	return s.list(ctx, f, page)
+}
+
+// ListUserQuestions returns the questions a user asked, in any status, with
+// their approved answers.
+func (s *questionService) ListUserQuestions(ctx context.Context, userID uint, page query.Page) (*query.Result[ec.Question], error) {
+	return s.list(ctx, ec.QuestionFilter{UserID: userID, AnswerStatus: ec.QuestionApproved}, page)
+}
+
+// Ask records a question about a published product; it is public once
+// approved.
+func (s *questionService) Ask(ctx context.Context, userID, productID uint, body string) (*ec.Question, error) {
+	body, err := ec.CheckQuestionText(body)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.products.FindByID(ctx, productID)
+	if err != nil || !p.Published() {
+		return nil, errors.New("product not found")
+	}
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	q := &ec.Question{ProductID: productID, UserID: userID, AuthorName: u.Name, Body: body, Status: ec.QuestionPending}
+	if err := s.questions.Create(ctx, q); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Answer records a customer's answer to an approved question. Only
+// customers who received the product in a delivered order may answer, and
+// their answers await moderation.
+func (s *questionService) Answer(ctx context.Context, userID, questionID uint, body string) (*ec.Answer, error) {
+	body, err := ec.CheckQuestionText(body)
+	if err != nil {
+		return nil, err
+	}
+	q, err := s.questions.FindByID(ctx, questionID)
+	if err != nil || q.Status != ec.QuestionApproved {
+		return nil, errors.New("question not found")
+	}
+	if q.UserID == userID {
+		return nil, fmt.Errorf("%w: you cannot answer your own question", ec.ErrInvalidQuestion)
+	}
+	delivered, err := s.orders.HasDelivered(ctx, userID, q.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if !delivered {
+		return nil, ec.ErrNotVerifiedBuyer
+	}
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	a := &ec.Answer{QuestionID: questionID, UserID: userID, AuthorName: u.Name, Body: body, Verified: true, Status: ec.QuestionPending}
+	if err := s.questions.CreateAnswer(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// ListQuestions is the question moderation queue.
+func (s *questionService) ListQuestions(ctx context.Context, f ec.QuestionFilter, page query.Page) (*query.Result[ec.Question], error) {
+	return s.list(ctx, f, page)
+}
+
+func (s *questionService) ModerateQuestion(ctx context.Context, id uint, status string) (*ec.Question, error) {
+	if !statuses[status] {
+		return nil, fmt.Errorf("%w: unknown status %q", ec.ErrInvalidQuestion, status)
+	}
+	if err := s.questions.SetStatus(ctx, id, status, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	q, err := s.questions.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// answers approved while the question was hidden are announced now
+	for i := range q.Answers {
+		s.notify(ctx, q, &q.Answers[i])
+	}
+	return q, nil
+}
+
+func (s *questionService) DeleteQuestion(ctx context.Context, id uint) error {
+	return s.questions.Delete(ctx, id)
+}
+
+// AnswerAsShop publishes an official answer, approving the question if it
+// was still pending, and tells the asker. Rejected questions cannot be
+// answered; approve them first.
+func (s *questionService) AnswerAsShop(ctx context.Context, adminID, questionID uint, body string) (*ec.Answer, error) {
+	body, err := ec.CheckQuestionText(body)
+	if err != nil {
+		return nil, err
+	}
+	q, err := s.questions.FindByID(ctx, questionID)
+	if err != nil {
+		return nil, err
+	}
+	if q.Status == ec.QuestionRejected {
+		return nil, fmt.Errorf("%w: the question was rejected", ec.ErrInvalidQuestion)
+	}
+	u, err := s.users.FindByID(ctx, adminID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if q.Status == ec.QuestionPending {
+		if err := s.questions.SetStatus(ctx, q.ID, ec.QuestionApproved, now); err != nil {
+			return nil, err
+		}
+		q.Status = ec.QuestionApproved
+	}
+	a := &ec.Answer{QuestionID: questionID, UserID: adminID, AuthorName: u.Name, Body: body, Official: true, Status: ec.QuestionApproved, ModeratedAt: &now}
+	if err := s.questions.CreateAnswer(ctx, a); err != nil {
+		return nil, err
+	}
+	s.notify(ctx, q, a)
+	return a, nil
+}
+
+// ListAnswers is the answer moderation queue.
+func (s *questionService) ListAnswers(ctx context.Context, status string, page query.Page) (*query.Result[ec.Answer], error) {
+	items, total, err := s.questions.ListAnswers(ctx, status, page)
+	if err != nil {
+		return nil, err
+	}
+	return query.NewResult(items, total, page), nil
+}
+
+// ModerateAnswer approves, rejects or requeues an answer; the asker is told
+// about an answer the first time it is approved.
+func (s *questionService) ModerateAnswer(ctx context.Context, id uint, status string) (*ec.Answer, error) {
+	if !statuses[status] {
+		return nil, fmt.Errorf("%w: unknown status %q", ec.ErrInvalidQuestion, status)
+	}
+	if err := s.questions.SetAnswerStatus(ctx, id, status, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	a, err := s.questions.FindAnswer(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	q, err := s.questions.FindByID(ctx, a.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+	s.notify(ctx, q, a)
+	return a, nil
+}
+
+func (s *questionService) DeleteAnswer(ctx context.Context, id uint) error {
+	return s.questions.DeleteAnswer(ctx, id)
+}
+
+func (s *questionService) list(ctx context.Context, f ec.QuestionFilter, page query.Page) (*query.Result[ec.Question], error) {
+	items, total, err := s.questions.List(ctx, f, page)
+	if err != nil {
+		return nil, err
+	}
+	return query.NewResult(items, total, page), nil
+}
+
+// notify tells the asker about a published answer to their published
+// question, once per answer. Failures are logged: the answer stands either
+// way.
+func (s *questionService) notify(ctx context.Context, q *ec.Question, a *ec.Answer) {
+	if q.Status != ec.QuestionApproved || a.Status != ec.QuestionApproved || a.NotifiedAt != nil || a.UserID == q.UserID {
+		return
+	}
+	if err := s.notifier.QuestionAnswered(ctx, q, a); err != nil {
+		log.Printf("questions: notify asker of answer %d: %v", a.ID, err)
+		return
+	}
+	now := time.Now().UTC()
+	if err := s.questions.MarkAnswerNotified(ctx, a.ID, now); err != nil {
+		log.Printf("questions: mark answer %d notified: %v", a.ID, err)
+		return
+	}
+	a.NotifiedAt = &now
+}
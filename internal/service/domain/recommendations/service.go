@@ -0,0 +1,150 @@
+package recommendations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"furniture-shop/internal/config"
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/storage"
+)
+
+var ErrInvalidKind = errors.New("invalid recommendation kind")
+
+// kindSources lists the association kinds consulted per requested kind, in
+// order; the empty kind blends all of them.
+var kindSources = map[string][]string{
+	"":                         {ec.RecommendBoughtTogether, ec.RecommendViewedTogether},
+	ec.RecommendBoughtTogether: {ec.RecommendBoughtTogether},
+	ec.RecommendViewedTogether: {ec.RecommendViewedTogether},
+	ec.RecommendSimilar:        nil,
+}
+
+type recommendationService struct {
+	recs     storage.RecommendationRepository
+	products storage.ProductRepository
+	cfg      config.RecommendConfig
+}
+
+func NewRecommendationService(recs storage.RecommendationRepository, products storage.ProductRepository, cfg config.RecommendConfig) service.RecommendationService {
+	return &recommendationService{recs: recs, products: products, cfg: cfg}
+}
+
+// Recommend fills up to limit slots with the product's pins, then the
+// requested associations, then same-category products by popularity, never
+// repeating a product and skipping excluded ones.
+func (s *recommendationService) Recommend(ctx context.Context, productID uint, kind string, limit int) ([]ec.Product, error) {
+	sources, ok := kindSources[kind]
+	if !ok {
+		return nil, ErrInvalidKind
+	}
+	p, err := s.products.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := s.recs.ListOverrides(ctx, &productID)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[uint]bool{productID: true}
+	var pins []uint
+	for _, o := range overrides {
+		switch o.Action {
+		case ec.OverrideExclude:
+			seen[o.RelatedProductID] = true
+		case ec.OverridePin:
+			pins = append(pins, o.RelatedProductID)
+		}
+	}
+
+	out := make([]ec.Product, 0, limit)
+	add := func(items []ec.Product) {
+		for _, it := range items {
+			if len(out) == limit {
+				return
+			}
+			if !seen[it.ID] {
+				seen[it.ID] = true
+				out = append(out, it)
+			}
+		}
+	}
+	var pinned []uint
+	for _, id := range pins {
+		if !seen[id] {
+			pinned = append(pinned, id)
+		}
+	}
+	if len(pinned) > 0 {
+		items, err := s.products.ListByIDs(ctx, pinned)
+		if err != nil {
+			return nil, err
+		}
+		published := items[:0]
+		for _, it := range items {
+			if it.Published() {
+				published = append(published, it)
+			}
+		}
+		add(published)
+	}
+	// ask for enough extra rows to make up for duplicates and exclusions
+	fetch := limit + len(seen)
+	for _, k := range sources {
+		if len(out) == limit {
+			break
+		}
+		items, err := s.recs.ListAssociated(ctx, productID, k, fetch)
+		if err != nil {
+			return nil, err
+		}
+		add(items)
+	}
+	if len(out) < limit {
+		items, err := s.products.ListRecommendations(ctx, p, fetch)
+		if err != nil {
+			return nil, err
+		}
+		add(items)
+	}
+	return out, nil
+}
+
+// Refresh recomputes the associations from recent orders and product views.
+func (s *recommendationService) Refresh(ctx context.Context) (*ec.RecommendationRefresh, error) {
+	since := time.Now().UTC().AddDate(0, 0, -s.cfg.LookbackDays)
+	out := &ec.RecommendationRefresh{}
+	var err error
+	if out.BoughtTogether, err = s.recs.RebuildBoughtTogether(ctx, since, s.cfg.MinSupport, s.cfg.PerProduct); err != nil {
+		return nil, err
+	}
+	if out.ViewedTogether, err = s.recs.RebuildViewedTogether(ctx, since, s.cfg.MinSupport, s.cfg.PerProduct); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *recommendationService) ListOverrides(ctx context.Context, productID *uint) ([]ec.RecommendationOverride, error) {
+	return s.recs.ListOverrides(ctx, productID)
+}
+
+func (s *recommendationService) SaveOverride(ctx context.Context, o *ec.RecommendationOverride) error {
+	if o.ProductID != 0 && o.ProductID == o.RelatedProductID {
+		return errors.New("a product cannot be recommended for itself")
+	}
+	if _, err := s.products.FindByID(ctx, o.RelatedProductID); err != nil {
+		return errors.New("related product not found")
+	}
+	if o.ProductID != 0 {
+		if _, err := s.products.FindByID(ctx, o.ProductID); err != nil {
+			return errors.New("product not found")
+		}
+	}
+	return s.recs.SaveOverride(ctx, o)
+}
+
+func (s *recommendationService) DeleteOverride(ctx context.Context, id uint) error {
+	return s.recs.DeleteOverride(ctx, id)
+}
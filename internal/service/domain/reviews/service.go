@@ -0,0 +1,143 @@
+package reviews
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
+)
+
+type reviewService struct {
+	reviews  storage.ReviewRepository
+	products storage.ProductRepository
+	orders   storage.OrderRepository
+	users    storage.UserRepository
+	images   service.ImageService
+}
+
+func NewReviewService(reviews storage.ReviewRepository, products storage.ProductRepository, orders storage.OrderRepository, users storage.UserRepository, images service.ImageService) service.ReviewService {
+	return &reviewService{reviews: reviews, products: products, orders: orders, users: users, images: images}
+}
+
+// ListProductReviews returns the approved reviews of a product.
+func (s *reviewService) ListProductReviews(ctx context.Context, productID uint, f ec.ReviewFilter, page query.Page) (*query.Result[ec.Review], error) {
+	f.ProductID, f.UserID, f.Status = productID, 0, ec.ReviewApproved
+	items, total, err := s.reviews.List(ctx, f, page)
+	if err != nil {
+		return nil, err
+	}
+	return query.NewResult(items, total, page), nil
+}
+
+func (s *reviewService) RatingSummary(ctx context.Context, productID uint) (*ec.RatingSummary, error) {
+	return s.reviews.Summary(ctx, productID)
+}
+
+// GetUserReview returns the user's own review of a product in any status,
+// or nil.
+func (s *reviewService) GetUserReview(ctx context.Context, productID, userID uint) (*ec.Review, error) {
+	return s.reviews.FindByUser(ctx, productID, userID)
+}
+
+// SubmitReview creates the user's review of a published product or
+// replaces their earlier one, which then awaits moderation again: an
+// approved review is unpublished until the edit is approved. New photos
+// replace the attached ones; without any the attached ones are kept.
+func (s *reviewService) SubmitReview(ctx context.Context, userID uint, r *ec.Review, photos [][]byte) (*ec.Review, error) {
+	p, err := s.products.FindByID(ctx, r.ProductID)
+	if err != nil || !p.Published() {
+		return nil, errors.New("product not found")
+	}
+	r.Photos = nil
+	if len(photos) > 0 {
+		r.Photos = make([]ec.ReviewPhoto, len(photos))
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	u, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.reviews.FindByUser(ctx, r.ProductID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		r.ID = existing.ID
+	}
+	if r.Verified, err = s.orders.HasDelivered(ctx, userID, r.ProductID); err != nil {
+		return nil, err
+	}
+	for i, data := range photos {
+		url, err := s.images.Upload(ctx, data)
+		if err != nil {
+			return nil, fmt.Errorf("photo %d: %w", i+1, err)
+		}
+		r.Photos[i].URL = url
+	}
+	r.UserID = userID
+	r.AuthorName = u.Name
+	r.Status = ec.ReviewPending
+	r.ModerationNote, r.ModeratedAt = "", nil
+	if err := s.reviews.Save(ctx, r); err != nil {
+		return nil, err
+	}
+	return s.reviews.FindByID(ctx, r.ID)
+}
+
+// DeleteUserReview deletes a review written by the user.
+func (s *reviewService) DeleteUserReview(ctx context.Context, userID, id uint) error {
+	r, err := s.reviews.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if r.UserID != userID {
+		return gorm.ErrRecordNotFound
+	}
+	return s.reviews.Delete(ctx, id)
+}
+
+// ListReviews is the moderation queue: reviews of every product, filtered
+// by status.
+func (s *reviewService) ListReviews(ctx context.Context, f ec.ReviewFilter, page query.Page) (*query.Result[ec.Review], error) {
+	items, total, err := s.reviews.List(ctx, f, page)
+	if err != nil {
+		return nil, err
+	}
+	return query.NewResult(items, total, page), nil
+}
+
+// ModerateReview approves, rejects or requeues a review. The verified flag
+// is derived again, since the author's order may have been delivered since
+// they wrote it.
+func (s *reviewService) ModerateReview(ctx context.Context, id uint, status, note string) (*ec.Review, error) {
+	switch status {
+	case ec.ReviewPending, ec.ReviewApproved, ec.ReviewRejected:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", ec.ErrInvalidReview, status)
+	}
+	r, err := s.reviews.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	verified, err := s.orders.HasDelivered(ctx, r.UserID, r.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.reviews.Moderate(ctx, id, status, note, verified, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.reviews.FindByID(ctx, id)
+}
+
+func (s *reviewService) DeleteReview(ctx context.Context, id uint) error {
+	return s.reviews.Delete(ctx, id)
+}
@@ -1,24 +1,78 @@
 package domain
 
 import (
+	"time"
+
+	"furniture-shop/internal/config"
 	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/blob"
 	sadm "furniture-shop/internal/service/domain/admin"
+	san "furniture-shop/internal/service/domain/analytics"
 	sa "furniture-shop/internal/service/domain/auth"
 	sc "furniture-shop/internal/service/domain/catalog"
+	simg "furniture-shop/internal/service/domain/images"
+	si "furniture-shop/internal/service/domain/invoices"
+	sn "furniture-shop/internal/service/domain/notifications"
 	so "furniture-shop/internal/service/domain/orders"
+	sob "furniture-shop/internal/service/domain/outbox"
 	sp "furniture-shop/internal/service/domain/payments"
-	"furniture-shop/internal/service/mailer"
+	sq "furniture-shop/internal/service/domain/questions"
+	sr "furniture-shop/internal/service/domain/recommendations"
+	srv "furniture-shop/internal/service/domain/reviews"
+	st "furniture-shop/internal/service/domain/transfer"
+	stl "furniture-shop/internal/service/domain/translations"
+	"furniture-shop/internal/service/gateway"
+	"furniture-shop/internal/service/i18n"
+	"furniture-shop/internal/service/notifier"
+	"furniture-shop/internal/service/search"
+	"furniture-shop/internal/service/templates"
 	"furniture-shop/internal/storage"
 )
 
 // NewService wires concrete domain services from repositories
-func NewService(repos *storage.Repository, jwtSecret string) *service.Service {
-	return &service.Service{
-		Auth:    sa.NewAuthService(repos.Users, jwtSecret),
-		Catalog: sc.NewCatalogService(repos.Departments, repos.Categories, repos.Products),
-		Orders:  so.NewOrdersService(repos.Users, repos.Orders, repos.Products),
-		Admin:   sadm.NewAdminService(repos.Departments, repos.Categories, repos.Products, repos.ProductOptions),
-		Payment: sp.NewPaymentService(repos.Orders, repos.Products, repos.Users, mailer.NewSender()),
-		Cart:    so.NewCartService(repos.Carts),
+func NewService(repos *storage.Repository, jwtSecret string) (*service.Service, error) {
+	payments := gateway.NewStripeGateway()
+	invoices := si.NewInvoiceService(repos.Invoices, repos.Orders, repos.Products, repos.Users)
+	channels, err := notifier.NewFromConfig(repos.PushSubs)
+	if err != nil {
+		return nil, err
+	}
+	outbox := sob.NewOutboxService(repos.Outbox, channels, config.Configurations.Outbox)
+	notifications := sn.NewNotificationService(repos.Users, repos.Orders, repos.Products, repos.PushSubs, outbox, templates.MustLoad())
+	index, err := search.NewFromConfig(repos.Products)
+	if err != nil {
+		return nil, err
 	}
+	blobs, err := blob.NewFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	analytics := san.NewAnalyticsService(repos.Events, config.Configurations.Events)
+	indexer := search.NewIndexer(index, repos.Departments, repos.Categories, repos.Products, repos.ProductOptions, repos.Translations)
+	signedTTL := time.Duration(config.Configurations.Blobs.SignedURLTTLMinutes) * time.Minute
+	wishlists := so.NewWishlistService(repos.Wishlists, repos.Carts, repos.Products, notifications)
+	productSubs := sn.NewSubscriptionService(repos.ProductSubs, repos.Products, repos.Users, notifications)
+	watchers := sc.NewBundleWatcher(repos.Bundles, repos.Products, service.ProductWatchers{wishlists, productSubs})
+	images := simg.NewImageService(repos.ProductImages, repos.Products, repos.ProductOptions, indexer, blobs, config.Configurations.Images, signedTTL)
+	return &service.Service{
+		Auth:         sa.NewAuthService(repos.Users, jwtSecret),
+		Catalog:      sc.NewCatalogService(repos.Departments, repos.Categories, repos.Products, repos.Slugs, repos.Attributes, index, i18n.NewTranslator(repos.Translations)),
+		Orders:       so.NewOrdersService(repos.Users, repos.Orders, repos.Products, payments, invoices, notifications, watchers),
+		Admin:        sadm.NewAdminService(repos.Departments, repos.Categories, repos.Products, repos.ProductOptions, repos.Archive, repos.Attributes, repos.Bundles, repos.Prices, indexer, watchers),
+		Images:       images,
+		Transfer:     st.NewTransferService(repos.Departments, repos.Categories, repos.Products, repos.ProductOptions, repos.CatalogTransfer, indexer, blobs, watchers),
+		Translations: stl.NewTranslationService(repos.Departments, repos.Categories, repos.Products, repos.ProductOptions, repos.Attributes, repos.Translations, indexer),
+		Payment:      sp.NewPaymentService(payments, repos.Orders, repos.Products, repos.Users, invoices, notifications, analytics),
+		Cart:         so.NewCartService(repos.Carts),
+		Wishlists:    wishlists,
+		ProductSubs:  productSubs,
+		Invoice:      invoices,
+		Notification: notifications,
+		Outbox:       outbox,
+		Recommend:    sr.NewRecommendationService(repos.Recommendations, repos.Products, config.Configurations.Recommend),
+		Reviews:      srv.NewReviewService(repos.Reviews, repos.Products, repos.Orders, repos.Users, images),
+		Questions:    sq.NewQuestionService(repos.Questions, repos.Products, repos.Orders, repos.Users, notifications),
+		Analytics:    analytics,
+		Blobs:        blobs,
+	}, nil
 }
@@ -0,0 +1,474 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	admin_dto "furniture-shop/internal/dtos/admin"
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/blob"
+	"furniture-shop/internal/service/search"
+	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
+	vld "furniture-shop/internal/validation"
+)
+
+// pendingID stands in for the id of a record created by the same import
+// while its referrers are validated.
+const pendingID uint = math.MaxUint32
+
+type transferService struct {
+	depts    storage.DepartmentRepository
+	cats     storage.CategoryRepository
+	products storage.ProductRepository
+	options  storage.ProductOptionRepository
+	transfer storage.CatalogTransferRepository
+	indexer  *search.Indexer
+	blobs    blob.Store
+	watcher  service.ProductWatcher
+}
+
+func NewTransferService(depts storage.DepartmentRepository, cats storage.CategoryRepository, products storage.ProductRepository, options storage.ProductOptionRepository, transfer storage.CatalogTransferRepository, indexer *search.Indexer, blobs blob.Store, watcher service.ProductWatcher) service.CatalogTransferService {
+	return &transferService{depts: depts, cats: cats, products: products, options: options, transfer: transfer, indexer: indexer, blobs: blobs, watcher: watcher}
+}
+
+// catalog is the current catalog indexed the way imports refer to it.
+type catalog struct {
+	depts      []ec.Department
+	cats       []ec.Category
+	products   []ec.Product
+	options    []ec.ProductOption
+	deptByName map[string]ec.Department
+	deptByID   map[uint]ec.Department
+	catByKey   map[string]ec.Category
+	catByID    map[uint]ec.Category
+	prodBySKU  map[string]ec.Product
+	prodByID   map[uint]ec.Product
+	optByKey   map[string]ec.ProductOption
+	// archivedSKUs are taken by archived products, which imports leave alone.
+	archivedSKUs map[string]bool
+}
+
+func (s *transferService) load(ctx context.Context) (*catalog, error) {
+	c := &catalog{
+		deptByName: map[string]ec.Department{},
+		deptByID:   map[uint]ec.Department{},
+		catByKey:   map[string]ec.Category{},
+		catByID:    map[uint]ec.Category{},
+		prodBySKU:  map[string]ec.Product{},
+		prodByID:   map[uint]ec.Product{},
+		optByKey:   map[string]ec.ProductOption{},
+
+		archivedSKUs: map[string]bool{},
+	}
+	var err error
+	if c.depts, err = s.depts.List(ctx); err != nil {
+		return nil, err
+	}
+	if c.cats, err = s.cats.ListAll(ctx); err != nil {
+		return nil, err
+	}
+	if c.products, err = s.products.ListAll(ctx); err != nil {
+		return nil, err
+	}
+	if c.options, err = s.options.List(ctx, nil); err != nil {
+		return nil, err
+	}
+	for page := query.NewPage(1, query.MaxSize); ; page.Number++ {
+		archived, _, err := s.products.List(ctx, ec.ProductFilter{Archived: true}, page)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range archived {
+			if p.SKU != "" {
+				c.archivedSKUs[p.SKU] = true
+			}
+		}
+		if len(archived) < page.Size {
+			break
+		}
+	}
+	sort.Slice(c.depts, func(i, j int) bool { return c.depts[i].ID < c.depts[j].ID })
+	sort.Slice(c.cats, func(i, j int) bool { return c.cats[i].ID < c.cats[j].ID })
+	sort.Slice(c.products, func(i, j int) bool { return c.products[i].ID < c.products[j].ID })
+	sort.Slice(c.options, func(i, j int) bool { return c.options[i].ID < c.options[j].ID })
+	for _, d := range c.depts {
+		c.deptByID[d.ID] = d
+		if _, dup := c.deptByName[ec.NameKey(d.Name)]; !dup {
+			c.deptByName[ec.NameKey(d.Name)] = d
+		}
+	}
+	for _, cat := range c.cats {
+		c.catByID[cat.ID] = cat
+		key := categoryKey(c.deptByID[cat.DepartmentID].Name, cat.Name)
+		if _, dup := c.catByKey[key]; !dup {
+			c.catByKey[key] = cat
+		}
+	}
+	for _, p := range c.products {
+		c.prodByID[p.ID] = p
+		if p.SKU != "" {
+			c.prodBySKU[p.SKU] = p
+		}
+	}
+	for _, o := range c.options {
+		key := optionKey(c.prodByID[o.ProductID].SKU, o.OptionType, o.OptionName)
+		if _, dup := c.optByKey[key]; !dup {
+			c.optByKey[key] = o
+		}
+	}
+	return c, nil
+}
+
+// Export returns the whole catalog. Every product needs a SKU, so that
+// importing the file updates the products instead of failing on them.
+func (s *transferService) Export(ctx context.Context) (*ec.CatalogDocument, error) {
+	c, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, p := range c.products {
+		if p.SKU == "" {
+			missing = append(missing, fmt.Sprint(p.ID))
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: give products %s a SKU first", ec.ErrMissingSKU, strings.Join(missing, ", "))
+	}
+	doc := &ec.CatalogDocument{
+		Departments: []ec.DepartmentRecord{},
+		Categories:  []ec.CategoryRecord{},
+		Products:    []ec.ProductRecord{},
+		Options:     []ec.OptionRecord{},
+	}
+	for _, d := range c.depts {
+		doc.Departments = append(doc.Departments, ec.DepartmentRecord{Name: d.Name, Description: d.Description, ImageURL: d.ImageURL, Slug: d.Slug, SEOTitle: d.SEOTitle, SEODescription: d.SEODescription})
+	}
+	for _, cat := range c.cats {
+		doc.Categories = append(doc.Categories, ec.CategoryRecord{Department: c.deptByID[cat.DepartmentID].Name, Name: cat.Name, Description: cat.Description, Slug: cat.Slug, SEOTitle: cat.SEOTitle, SEODescription: cat.SEODescription})
+	}
+	for _, p := range c.products {
+		cat := c.catByID[p.CategoryID]
+		doc.Products = append(doc.Products, ec.ProductRecord{
+			SKU:                    p.SKU,
+			Department:             c.deptByID[cat.DepartmentID].Name,
+			Category:               cat.Name,
+			Name:                   p.Name,
+			ShortDescription:       p.ShortDescription,
+			LongDescription:        p.LongDescription,
+			BasePrice:              p.BasePrice,
+			BaseProductionTimeDays: p.BaseProductionTimeDays,
+			ImageURL:               p.ImageURL,
+			BaseMaterial:           p.BaseMaterial,
+			Quantity:               p.Quantity,
+			DefaultWidth:           p.DefaultWidth,
+			DefaultHeight:          p.DefaultHeight,
+			DefaultDepth:           p.DefaultDepth,
+			Slug:                   p.Slug,
+			SEOTitle:               p.SEOTitle,
+			SEODescription:         p.SEODescription,
+		})
+	}
+	for _, o := range c.options {
+		doc.Options = append(doc.Options, ec.OptionRecord{
+			ProductSKU:                    c.prodByID[o.ProductID].SKU,
+			OptionType:                    o.OptionType,
+			OptionName:                    o.OptionName,
+			PriceModifierType:             o.PriceModifierType,
+			PriceModifierValue:            o.PriceModifierValue,
+			ProductionTimeModifierDays:    o.ProductionTimeModifierDays,
+			ProductionTimeModifierPercent: o.ProductionTimeModifierPercent,
+		})
+	}
+	return doc, nil
+}
+
+// Import validates every record with the admin DTO rules and resolves its
+// references against the catalog and the document itself. Records update
+// the department or category of the same name, the product of the same SKU
+// and the option of the same product, type and name, and create the rest.
+// The plan is applied only when there are no errors and dryRun is false.
+func (s *transferService) Import(ctx context.Context, doc *ec.CatalogDocument, dryRun bool) (*ec.ImportReport, error) {
+	c, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rep := &ec.ImportReport{DryRun: dryRun, Errors: []ec.ImportError{}}
+	plan := &ec.ImportPlan{}
+	fail := func(kind string, row int, field, msg string) {
+		rep.Errors = append(rep.Errors, ec.ImportError{Kind: kind, Row: row, Field: field, Message: msg})
+	}
+	check := func(kind string, row int, dto any) {
+		for _, e := range validationErrors(dto) {
+			fail(kind, row, e[0], e[1])
+		}
+	}
+
+	newDepts := map[string]bool{}
+	for i, r := range doc.Departments {
+		row, key := i+1, ec.NameKey(r.Name)
+		if newDepts[key] {
+			fail(ec.TransferDepartments, row, "name", "duplicate department")
+			continue
+		}
+		newDepts[key] = true
+		img := blob.Canonical(s.blobs, r.ImageURL)
+		check(ec.TransferDepartments, row, admin_dto.DepartmentDTO{Name: strings.TrimSpace(r.Name), Description: r.Description, ImageURL: s.checkedURL(img), Slug: r.Slug, SEOTitle: r.SEOTitle, SEODescription: r.SEODescription})
+		d := ec.Department{Name: strings.TrimSpace(r.Name), Description: r.Description, ImageURL: img, Slug: r.Slug, SEOTitle: r.SEOTitle, SEODescription: r.SEODescription}
+		if prev, ok := c.deptByName[key]; ok {
+			d.ID = prev.ID
+			rep.Departments.Updated++
+		} else {
+			rep.Departments.Created++
+		}
+		plan.Departments = append(plan.Departments, d)
+	}
+	deptID := func(name string) (uint, bool) {
+		if d, ok := c.deptByName[ec.NameKey(name)]; ok {
+			return d.ID, true
+		}
+		return pendingID, newDepts[ec.NameKey(name)]
+	}
+
+	newCats := map[string]bool{}
+	for i, r := range doc.Categories {
+		row, key := i+1, categoryKey(r.Department, r.Name)
+		did, ok := deptID(r.Department)
+		if !ok {
+			fail(ec.TransferCategories, row, "department", fmt.Sprintf("unknown department %q", r.Department))
+			continue
+		}
+		if newCats[key] {
+			fail(ec.TransferCategories, row, "name", "duplicate category")
+			continue
+		}
+		newCats[key] = true
+		check(ec.TransferCategories, row, admin_dto.CategoryDTO{DepartmentID: did, Name: strings.TrimSpace(r.Name), Description: r.Description, Slug: r.Slug, SEOTitle: r.SEOTitle, SEODescription: r.SEODescription})
+		cat := ec.PlannedCategory{Category: ec.Category{Name: strings.TrimSpace(r.Name), Description: r.Description, Slug: r.Slug, SEOTitle: r.SEOTitle, SEODescription: r.SEODescription}, Department: r.Department}
+		if prev, ok := c.catByKey[key]; ok {
+			cat.ID = prev.ID
+			rep.Categories.Updated++
+		} else {
+			rep.Categories.Created++
+		}
+		plan.Categories = append(plan.Categories, cat)
+	}
+
+	newProducts := map[string]bool{}
+	for i, r := range doc.Products {
+		row, sku := i+1, strings.TrimSpace(r.SKU)
+		if sku == "" {
+			fail(ec.TransferProducts, row, "sku", "sku is required to match products")
+			continue
+		}
+		if newProducts[sku] {
+			fail(ec.TransferProducts, row, "sku", "duplicate sku")
+			continue
+		}
+		if c.archivedSKUs[sku] {
+			fail(ec.TransferProducts, row, "sku", "sku belongs to an archived product; restore it first")
+			continue
+		}
+		newProducts[sku] = true
+		key := categoryKey(r.Department, r.Category)
+		catID := pendingID
+		if cat, ok := c.catByKey[key]; ok {
+			catID = cat.ID
+		} else if !newCats[key] {
+			fail(ec.TransferProducts, row, "category", fmt.Sprintf("unknown category %q in department %q", r.Category, r.Department))
+			continue
+		}
+		img := blob.Canonical(s.blobs, r.ImageURL)
+		dto := admin_dto.ProductDTO{
+			CategoryID:             catID,
+			SKU:                    sku,
+			Name:                   r.Name,
+			ShortDescription:       r.ShortDescription,
+			LongDescription:        r.LongDescription,
+			BasePrice:              r.BasePrice,
+			BaseProductionTimeDays: r.BaseProductionTimeDays,
+			ImageURL:               s.checkedURL(img),
+			DefaultWidth:           r.DefaultWidth,
+			DefaultHeight:          r.DefaultHeight,
+			DefaultDepth:           r.DefaultDepth,
+			BaseMaterial:           r.BaseMaterial,
+			Quantity:               r.Quantity,
+			Slug:                   r.Slug,
+			SEOTitle:               r.SEOTitle,
+			SEODescription:         r.SEODescription,
+		}
+		check(ec.TransferProducts, row, dto)
+		p := ec.PlannedProduct{
+			Product: ec.Product{
+				SKU:                    sku,
+				Name:                   r.Name,
+				ShortDescription:       r.ShortDescription,
+				LongDescription:        r.LongDescription,
+				BasePrice:              r.BasePrice,
+				BaseProductionTimeDays: r.BaseProductionTimeDays,
+				ImageURL:               img,
+				DefaultWidth:           r.DefaultWidth,
+				DefaultHeight:          r.DefaultHeight,
+				DefaultDepth:           r.DefaultDepth,
+				BaseMaterial:           r.BaseMaterial,
+				Quantity:               r.Quantity,
+				Slug:                   r.Slug,
+				SEOTitle:               r.SEOTitle,
+				SEODescription:         r.SEODescription,
+			},
+			Department: r.Department,
+			Category:   r.Category,
+		}
+		if prev, ok := c.prodBySKU[sku]; ok {
+			p.ID = prev.ID
+			rep.Products.Updated++
+		} else {
+			// a new product starts as a draft, like one created in the admin;
+			// updated ones keep their lifecycle
+			if err := p.Schedule(time.Now()); err != nil {
+				return nil, err
+			}
+			rep.Products.Created++
+		}
+		plan.Products = append(plan.Products, p)
+	}
+
+	newOptions := map[string]bool{}
+	for i, r := range doc.Options {
+		row, sku := i+1, strings.TrimSpace(r.ProductSKU)
+		productID := pendingID
+		if p, ok := c.prodBySKU[sku]; ok {
+			productID = p.ID
+		} else if !newProducts[sku] {
+			fail(ec.TransferOptions, row, "product_sku", fmt.Sprintf("unknown product %q", r.ProductSKU))
+			continue
+		}
+		key := optionKey(sku, r.OptionType, r.OptionName)
+		if newOptions[key] {
+			fail(ec.TransferOptions, row, "option_name", "duplicate option")
+			continue
+		}
+		newOptions[key] = true
+		check(ec.TransferOptions, row, admin_dto.ProductOptionDTO{
+			ProductID:                     productID,
+			OptionType:                    r.OptionType,
+			OptionName:                    r.OptionName,
+			PriceModifierType:             r.PriceModifierType,
+			PriceModifierValue:            r.PriceModifierValue,
+			ProductionTimeModifierDays:    r.ProductionTimeModifierDays,
+			ProductionTimeModifierPercent: r.ProductionTimeModifierPercent,
+		})
+		o := ec.PlannedOption{
+			ProductOption: ec.ProductOption{
+				OptionType:                    r.OptionType,
+				OptionName:                    r.OptionName,
+				PriceModifierType:             r.PriceModifierType,
+				PriceModifierValue:            r.PriceModifierValue,
+				ProductionTimeModifierDays:    r.ProductionTimeModifierDays,
+				ProductionTimeModifierPercent: r.ProductionTimeModifierPercent,
+			},
+			ProductSKU: sku,
+		}
+		if productID != pendingID {
+			o.ProductID = productID
+		}
+		if prev, ok := c.optByKey[key]; ok {
+			o.ID = prev.ID
+			rep.Options.Updated++
+		} else {
+			rep.Options.Created++
+		}
+		plan.Options = append(plan.Options, o)
+	}
+
+	if len(rep.Errors) > 0 || dryRun {
+		return rep, nil
+	}
+	if err := s.transfer.Apply(ctx, plan); err != nil {
+		return nil, err
+	}
+	rep.Applied = true
+	if _, err := s.indexer.Rebuild(ctx); err != nil {
+		log.Printf("search: rebuild after import: %v", err)
+	}
+	s.watch(ctx, c, plan)
+	return rep, nil
+}
+
+// watch tells the product watchers about the existing products an applied
+// import updated, directly or through their options.
+func (s *transferService) watch(ctx context.Context, c *catalog, plan *ec.ImportPlan) {
+	var ids []uint
+	for _, p := range plan.Products {
+		if p.ID != 0 {
+			ids = append(ids, p.ID)
+		}
+	}
+	for _, o := range plan.Options {
+		if o.ProductID != 0 {
+			ids = append(ids, o.ProductID)
+		}
+	}
+	slices.Sort(ids)
+	for _, id := range slices.Compact(ids) {
+		if before, ok := c.prodByID[id]; ok {
+			s.watcher.ProductChanged(ctx, &before)
+		}
+	}
+}
+
+// checkedURL is the image URL as validated: links to stored files are
+// relative for the local store and need no check.
+func (s *transferService) checkedURL(u string) string {
+	if _, ok := s.blobs.KeyFromURL(u); ok {
+		return ""
+	}
+	return u
+}
+
+func categoryKey(department, name string) string {
+	return ec.NameKey(department) + "/" + ec.NameKey(name)
+}
+
+func optionKey(sku, optionType, name string) string {
+	return sku + "/" + optionType + "/" + ec.NameKey(name)
+}
+
+// validationErrors lists the failed rules of dto as json field name and
+// message pairs.
+func validationErrors(dto any) [][2]string {
+	err := vld.ValidateStruct(dto)
+	if err == nil {
+		return nil
+	}
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return [][2]string{{"", err.Error()}}
+	}
+	t := reflect.TypeOf(dto)
+	out := make([][2]string, 0, len(verrs))
+	for _, fe := range verrs {
+		name := fe.Field()
+		if f, ok := t.FieldByName(fe.StructField()); ok {
+			name = strings.Split(f.Tag.Get("json"), ",")[0]
+		}
+		rule := fe.Tag()
+		if fe.Param() != "" {
+			rule += "=" + fe.Param()
+		}
+		out = append(out, [2]string{name, "fails " + rule})
+	}
+	return out
+}
@@ -0,0 +1,133 @@
+package translations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"gorm.io/gorm"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service"
+	"furniture-shop/internal/service/i18n"
+	"furniture-shop/internal/service/search"
+	"furniture-shop/internal/storage"
+)
+
+type translationService struct {
+	depts        storage.DepartmentRepository
+	cats         storage.CategoryRepository
+	products     storage.ProductRepository
+	options      storage.ProductOptionRepository
+	attrs        storage.AttributeRepository
+	translations storage.TranslationRepository
+	indexer      *search.Indexer
+}
+
+func NewTranslationService(depts storage.DepartmentRepository, cats storage.CategoryRepository, products storage.ProductRepository, options storage.ProductOptionRepository, attrs storage.AttributeRepository, translations storage.TranslationRepository, indexer *search.Indexer) service.TranslationService {
+	return &translationService{depts: depts, cats: cats, products: products, options: options, attrs: attrs, translations: translations, indexer: indexer}
+}
+
+func (s *translationService) List(ctx context.Context, kind string, id uint) ([]ec.Translation, error) {
+	if _, ok := ec.TranslatableFields[kind]; !ok {
+		return nil, fmt.Errorf("%w: unknown kind %q", ec.ErrInvalidTranslation, kind)
+	}
+	if _, err := s.productOf(ctx, kind, id); err != nil {
+		return nil, err
+	}
+	return s.translations.List(ctx, kind, []uint{id}, nil)
+}
+
+func (s *translationService) Save(ctx context.Context, kind string, id uint, locale string, values map[string]string) ([]ec.Translation, error) {
+	if err := checkLocale(kind, locale); err != nil {
+		return nil, err
+	}
+	for field := range values {
+		if !slices.Contains(ec.TranslatableFields[kind], field) {
+			return nil, fmt.Errorf("%w: %s has no translatable field %q", ec.ErrInvalidTranslation, kind, field)
+		}
+	}
+	productID, err := s.productOf(ctx, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.translations.Save(ctx, kind, id, locale, values); err != nil {
+		return nil, err
+	}
+	s.reindex(ctx, productID)
+	return s.translations.List(ctx, kind, []uint{id}, []string{locale})
+}
+
+func (s *translationService) Delete(ctx context.Context, kind string, id uint, locale string) error {
+	if err := checkLocale(kind, locale); err != nil {
+		return err
+	}
+	productID, err := s.productOf(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	if err := s.translations.Delete(ctx, kind, id, locale); err != nil {
+		return err
+	}
+	s.reindex(ctx, productID)
+	return nil
+}
+
+func checkLocale(kind, locale string) error {
+	if _, ok := ec.TranslatableFields[kind]; !ok {
+		return fmt.Errorf("%w: unknown kind %q", ec.ErrInvalidTranslation, kind)
+	}
+	if !i18n.Supported(locale) || locale == i18n.Default() {
+		return fmt.Errorf("%w: %q is not a translation locale", ec.ErrInvalidTranslation, locale)
+	}
+	return nil
+}
+
+// productOf checks that the record exists and returns the product whose
+// search document includes its text, or 0 for departments and categories.
+func (s *translationService) productOf(ctx context.Context, kind string, id uint) (uint, error) {
+	switch kind {
+	case ec.SlugDepartment:
+		depts, err := s.depts.List(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if !slices.ContainsFunc(depts, func(d ec.Department) bool { return d.ID == id }) {
+			return 0, fmt.Errorf("department %d: %w", id, gorm.ErrRecordNotFound)
+		}
+	case ec.SlugCategory:
+		cats, err := s.cats.ListAll(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if !slices.ContainsFunc(cats, func(c ec.Category) bool { return c.ID == id }) {
+			return 0, fmt.Errorf("category %d: %w", id, gorm.ErrRecordNotFound)
+		}
+	case ec.SlugProduct:
+		if _, err := s.products.FindIncludingArchived(ctx, id); err != nil {
+			return 0, err
+		}
+		return id, nil
+	case ec.TranslationOption:
+		o, err := s.options.FindByID(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+		return o.ProductID, nil
+	case ec.TranslationAttribute:
+		if _, err := s.attrs.FindByID(ctx, id); err != nil {
+			return 0, err
+		}
+	}
+	return 0, nil
+}
+
+func (s *translationService) reindex(ctx context.Context, productID uint) {
+	if productID == 0 {
+		return
+	}
+	if err := s.indexer.Product(ctx, productID); err != nil {
+		log.Printf("search: index product %d: %v", productID, err)
+	}
+}
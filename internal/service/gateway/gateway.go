@@ -0,0 +1,92 @@
+package gateway
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	stripe "github.com/stripe/stripe-go/v84"
+	session "github.com/stripe/stripe-go/v84/checkout/session"
+	"github.com/stripe/stripe-go/v84/refund"
+
+	"furniture-shop/internal/config"
+)
+
+// CheckoutRequest describes a one-off payment for an arbitrary amount tied to an order.
+type CheckoutRequest struct {
+	OrderID     uint
+	Description string
+	Amount      float64
+	Metadata    map[string]string
+}
+
+type PaymentGateway interface {
+	CreateCheckout(in CheckoutRequest) (string, error)
+	Refund(paymentReference string, amount float64) (string, error)
+}
+
+type stripeGateway struct {
+	secretKey   string
+	frontendURL string
+}
+
+func NewStripeGateway() PaymentGateway {
+	return &stripeGateway{secretKey: config.Env.StripeSecretKey, frontendURL: config.Configurations.FrontendURL}
+}
+
+func (g *stripeGateway) CreateCheckout(in CheckoutRequest) (string, error) {
+	stripe.Key = g.secretKey
+	orderIDStr := strconv.Itoa(int(in.OrderID))
+	metadata := map[string]string{"order_id": orderIDStr}
+	for k, v := range in.Metadata {
+		metadata[k] = v
+	}
+
+	params := &stripe.CheckoutSessionParams{
+		Mode:              stripe.String(string(stripe.CheckoutSessionModePayment)),
+		SuccessURL:        stripe.String(fmt.Sprintf("%s/payment/success?session_id={CHECKOUT_SESSION_ID}&order_id=%d", g.frontendURL, in.OrderID)),
+		CancelURL:         stripe.String(fmt.Sprintf("%s/payment/cancel?order_id=%d", g.frontendURL, in.OrderID)),
+		ClientReferenceID: stripe.String(orderIDStr),
+		ExpiresAt:         stripe.Int64(time.Now().Add(30 * time.Minute).Unix()),
+		Metadata:          metadata,
+		PaymentIntentData: &stripe.CheckoutSessionPaymentIntentDataParams{
+			Metadata: metadata,
+		},
+		LineItems: []*stripe.CheckoutSessionLineItemParams{{
+			PriceData: &stripe.CheckoutSessionLineItemPriceDataParams{
+				Currency: stripe.String("eur"),
+				ProductData: &stripe.CheckoutSessionLineItemPriceDataProductDataParams{
+					Name: stripe.String(in.Description),
+				},
+				UnitAmount: stripe.Int64(toCents(in.Amount)),
+			},
+			Quantity: stripe.Int64(1),
+		}},
+	}
+
+	sess, err := session.New(params)
+	if err != nil {
+		return "", err
+	}
+	return sess.URL, nil
+}
+
+func (g *stripeGateway) Refund(paymentReference string, amount float64) (string, error) {
+	if paymentReference == "" {
+		return "", fmt.Errorf("no payment reference to refund against")
+	}
+	stripe.Key = g.secretKey
+	r, err := refund.New(&stripe.RefundParams{
+		PaymentIntent: stripe.String(paymentReference),
+		Amount:        stripe.Int64(toCents(amount)),
+	})
+	if err != nil {
+		return "", err
+	}
+	return r.ID, nil
+}
+
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
@@ -0,0 +1,175 @@
+// Package i18n picks the catalog locale of a request and overlays stored
+// translations on catalog records. Records hold their text in the default
+// locale (LOCALES.DEFAULT); a field without a translation in the requested
+// locale falls back to its base language (bg-BG to bg) and then to the
+// default text.
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"furniture-shop/internal/config"
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+)
+
+// Default is the locale the catalog records are written in.
+func Default() string {
+	return config.Configurations.Locales.Default
+}
+
+// Supported reports whether locale is one of the configured locales.
+func Supported(locale string) bool {
+	for _, l := range config.Configurations.Locales.Supported {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate picks the locale of a request: lang (the ?lang= parameter) wins
+// over the Accept-Language header, whose tags are tried by quality. A tag
+// matches a supported locale exactly or through its base language; when
+// nothing matches the default locale is used.
+func Negotiate(lang, acceptLanguage string) string {
+	if l := match(lang); l != "" {
+		return l
+	}
+	type tag struct {
+		name string
+		q    float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if name != "" && name != "*" && q > 0 {
+			tags = append(tags, tag{name, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	for _, t := range tags {
+		if l := match(t.name); l != "" {
+			return l
+		}
+	}
+	return Default()
+}
+
+func match(tag string) string {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if tag == "" {
+		return ""
+	}
+	if Supported(tag) {
+		return tag
+	}
+	if base, _, ok := strings.Cut(tag, "-"); ok && Supported(base) {
+		return base
+	}
+	return ""
+}
+
+// Fallbacks returns the locales whose translations apply to locale, most
+// specific first. It is empty for the default locale, whose text is stored
+// on the records themselves.
+func Fallbacks(locale string) []string {
+	var out []string
+	if locale != "" && locale != Default() {
+		out = append(out, locale)
+	}
+	if base, _, ok := strings.Cut(locale, "-"); ok && base != Default() && Supported(base) {
+		out = append(out, base)
+	}
+	return out
+}
+
+// Translator overlays the translations of a locale on catalog records.
+type Translator struct {
+	translations storage.TranslationRepository
+}
+
+func NewTranslator(translations storage.TranslationRepository) *Translator {
+	return &Translator{translations: translations}
+}
+
+func (t *Translator) Departments(ctx context.Context, locale string, items []ec.Department) error {
+	return translate(ctx, t, ec.SlugDepartment, locale, items, func(d *ec.Department) (uint, map[string]*string) { return d.ID, d.TextFields() })
+}
+
+func (t *Translator) Categories(ctx context.Context, locale string, items []ec.Category) error {
+	return translate(ctx, t, ec.SlugCategory, locale, items, func(c *ec.Category) (uint, map[string]*string) { return c.ID, c.TextFields() })
+}
+
+// Products translates products together with their loaded options and
+// attribute names.
+func (t *Translator) Products(ctx context.Context, locale string, items []ec.Product) error {
+	if err := translate(ctx, t, ec.SlugProduct, locale, items, func(p *ec.Product) (uint, map[string]*string) { return p.ID, p.TextFields() }); err != nil {
+		return err
+	}
+	var options []*ec.ProductOption
+	var attrs []*ec.AttributeDefinition
+	for i := range items {
+		for j := range items[i].Options {
+			options = append(options, &items[i].Options[j])
+		}
+		for j := range items[i].Attributes {
+			if a := items[i].Attributes[j].Attribute; a != nil {
+				attrs = append(attrs, a)
+			}
+		}
+	}
+	if err := translate(ctx, t, ec.TranslationOption, locale, options, func(o **ec.ProductOption) (uint, map[string]*string) { return (*o).ID, (*o).TextFields() }); err != nil {
+		return err
+	}
+	return translate(ctx, t, ec.TranslationAttribute, locale, attrs, func(a **ec.AttributeDefinition) (uint, map[string]*string) { return (*a).ID, (*a).TextFields() })
+}
+
+func (t *Translator) AttributeFacets(ctx context.Context, locale string, items []ec.AttributeFacet) error {
+	return translate(ctx, t, ec.TranslationAttribute, locale, items, func(f *ec.AttributeFacet) (uint, map[string]*string) { return f.ID, f.TextFields() })
+}
+
+func (t *Translator) Product(ctx context.Context, locale string, p *ec.Product) error {
+	items := []ec.Product{*p}
+	if err := t.Products(ctx, locale, items); err != nil {
+		return err
+	}
+	*p = items[0]
+	return nil
+}
+
+// translate loads the translations of items in one query and applies them.
+func translate[T any](ctx context.Context, t *Translator, kind, locale string, items []T, fields func(*T) (uint, map[string]*string)) error {
+	locales := Fallbacks(locale)
+	if len(locales) == 0 || len(items) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(items))
+	for i := range items {
+		ids[i], _ = fields(&items[i])
+	}
+	ts, err := t.translations.List(ctx, kind, ids, locales)
+	if err != nil {
+		return err
+	}
+	byRecord := map[uint][]ec.Translation{}
+	for _, tr := range ts {
+		byRecord[tr.RecordID] = append(byRecord[tr.RecordID], tr)
+	}
+	for i := range items {
+		id, f := fields(&items[i])
+		if len(byRecord[id]) > 0 {
+			ec.Translate(f, byRecord[id], locales)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package images
+
+import (
+	"bytes"
+
+	ec "furniture-shop/internal/entities/catalog"
+)
+
+// Detect identifies the image format from the leading magic bytes, returning
+// "" for anything else.
+func Detect(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return ec.ImageJPEG
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return ec.ImagePNG
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return ec.ImageGIF
+	case len(data) >= 16 && bytes.Equal(data[:4], []byte("RIFF")) && bytes.Equal(data[8:12], []byte("WEBP")):
+		return ec.ImageWebP
+	}
+	return ""
+}
+
+// Ext returns the file extension of a format.
+func Ext(format string) string {
+	if format == ec.ImageJPEG {
+		return ".jpg"
+	}
+	return "." + format
+}
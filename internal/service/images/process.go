@@ -0,0 +1,158 @@
+// Package images validates uploaded pictures and renders the resized copies
+// shown by the shop, in pure Go.
+package images
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+
+	"furniture-shop/internal/config"
+	ec "furniture-shop/internal/entities/catalog"
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type, use JPEG, PNG, GIF or WebP")
+	ErrTooLarge        = errors.New("image too large")
+)
+
+// Size is a rendition size, scaled so that its longest edge fits MaxEdge.
+// The WebP encoder is lossless, so only the small sizes also get a WebP
+// copy; every size gets a JPEG.
+type Size struct {
+	Name    string
+	MaxEdge int
+	WebP    bool
+}
+
+var Sizes = []Size{
+	{Name: ec.ImageThumbnail, MaxEdge: 200, WebP: true},
+	{Name: ec.ImageMedium, MaxEdge: 600, WebP: true},
+	{Name: ec.ImageLarge, MaxEdge: 1200},
+}
+
+// Rendition is an encoded resized copy.
+type Rendition struct {
+	Size   string
+	Format string
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// Processed describes a validated upload and its renditions.
+type Processed struct {
+	Format     string
+	Width      int
+	Height     int
+	Renditions []Rendition
+}
+
+type Processor struct {
+	maxBytes  int
+	maxPixels int
+	quality   int
+}
+
+func NewProcessor(cfg config.ImagesConfig) *Processor {
+	return &Processor{maxBytes: cfg.MaxUploadMB << 20, maxPixels: cfg.MaxPixels, quality: cfg.JPEGQuality}
+}
+
+// Validate checks the size of the upload and that its content, not its file
+// name, is a supported image, and returns the detected format and dimensions.
+func (p *Processor) Validate(data []byte) (string, image.Config, error) {
+	if len(data) > p.maxBytes {
+		return "", image.Config{}, fmt.Errorf("%w: at most %d MB", ErrTooLarge, p.maxBytes>>20)
+	}
+	format := Detect(data)
+	if format == "" {
+		return "", image.Config{}, ErrUnsupportedType
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", image.Config{}, ErrUnsupportedType
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return "", image.Config{}, ErrUnsupportedType
+	}
+	// checked before decoding so that a small file cannot expand to an
+	// enormous bitmap
+	if cfg.Width*cfg.Height > p.maxPixels {
+		return "", image.Config{}, fmt.Errorf("%w: at most %d pixels", ErrTooLarge, p.maxPixels)
+	}
+	return format, cfg, nil
+}
+
+// Process validates the upload and renders every size. Images are never
+// scaled up, so a small upload yields renditions of its own size.
+func (p *Processor) Process(data []byte) (*Processed, error) {
+	format, cfg, err := p.Validate(data)
+	if err != nil {
+		return nil, err
+	}
+	src, err := decode(data)
+	if err != nil {
+		return nil, ErrUnsupportedType
+	}
+	out := &Processed{Format: format, Width: cfg.Width, Height: cfg.Height}
+	for _, size := range Sizes {
+		scaled := resize(src, size.MaxEdge)
+		w, h := scaled.Bounds().Dx(), scaled.Bounds().Dy()
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, flatten(scaled), &jpeg.Options{Quality: p.quality}); err != nil {
+			return nil, err
+		}
+		out.Renditions = append(out.Renditions, Rendition{Size: size.Name, Format: ec.ImageJPEG, Width: w, Height: h, Data: buf.Bytes()})
+		if size.WebP {
+			var buf bytes.Buffer
+			if err := nativewebp.Encode(&buf, scaled, nil); err != nil {
+				return nil, err
+			}
+			out.Renditions = append(out.Renditions, Rendition{Size: size.Name, Format: ec.ImageWebP, Width: w, Height: h, Data: buf.Bytes()})
+		}
+	}
+	return out, nil
+}
+
+// decode guards against decoders panicking on malformed input.
+func decode(data []byte) (img image.Image, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("decode image: %v", r)
+		}
+	}()
+	img, _, err = image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// resize scales src to fit maxEdge, keeping the aspect ratio.
+func resize(src image.Image, maxEdge int) *image.NRGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > maxEdge || h > maxEdge {
+		if w >= h {
+			w, h = maxEdge, max(1, h*maxEdge/w)
+		} else {
+			w, h = max(1, w*maxEdge/h), maxEdge
+		}
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst
+}
+
+// flatten puts the image on a white background, as JPEG has no transparency.
+func flatten(src image.Image) *image.RGBA {
+	dst := image.NewRGBA(src.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, src.Bounds().Min, draw.Over)
+	return dst
+}
@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+var maildirSeq atomic.Uint64
+
+// maildirSender delivers messages into a local Maildir (tmp/new/cur) so that
+// development setups can inspect outgoing mail with any mail client.
+type maildirSender struct {
+	dir  string
+	from string
+}
+
+func NewMaildirSender(dir, from string) Sender {
+	return &maildirSender{dir: dir, from: from}
+}
+
+func (s *maildirSender) Send(msg Message) error {
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(s.dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+	body, err := Compose(s.from, msg)
+	if err != nil {
+		return err
+	}
+	host, _ := os.Hostname()
+	name := fmt.Sprintf("%d.%d_%d.%s", time.Now().Unix(), os.Getpid(), maildirSeq.Add(1), host)
+	tmp := filepath.Join(s.dir, "tmp", name)
+	if err := os.WriteFile(tmp, body, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.dir, "new", name))
+}
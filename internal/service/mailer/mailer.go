@@ -1,13 +1,28 @@
 package mailer
 
 import (
+	"bytes"
 	"fmt"
 	"furniture-shop/internal/config"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
 	"net/smtp"
+	"net/textproto"
 )
 
+// Message is a rendered email. HTML is optional; when present the message is
+// sent as multipart/alternative with Text as the plain fallback.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
 type Sender interface {
-	Send(to, subject, body string) error
+	Send(msg Message) error
 }
 
 type smtpSender struct {
@@ -18,7 +33,11 @@ type smtpSender struct {
 	from string
 }
 
+// NewSender returns the transport selected by the MAIL.DRIVER configuration.
 func NewSender() Sender {
+	if config.Configurations.Mail.Driver == "maildir" {
+		return NewMaildirSender(config.Configurations.Mail.Maildir, config.Env.EmailSenderFrom)
+	}
 	return &smtpSender{
 		host: config.Env.EmailSenderHost,
 		port: config.Env.EmailSenderPort,
@@ -28,9 +47,56 @@ func NewSender() Sender {
 	}
 }
 
-func (s *smtpSender) Send(to, subject, body string) error {
+func (s *smtpSender) Send(msg Message) error {
 	addr := fmt.Sprintf("%s:%s", s.host, s.port)
 	auth := smtp.PlainAuth("", s.user, s.pass, s.host)
-	msg := []byte(fmt.Sprintf("To: %s\r\nSubject: %s\r\nFrom: %s\r\n\r\n%s\r\n", to, subject, s.from, body))
-	return smtp.SendMail(addr, auth, s.from, []string{to}, msg)
+	body, err := Compose(s.from, msg)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(addr, auth, s.from, []string{msg.To}, body)
+}
+
+// Compose builds the RFC 5322 representation of msg.
+func Compose(from string, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "To: %s\r\nFrom: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n", msg.To, from, mime.QEncoding.Encode("utf-8", msg.Subject))
+	if msg.HTML == "" {
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\nContent-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQP(&buf, msg.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", mw.Boundary())
+	parts := []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+	for _, p := range parts {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQP(w, p.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQP(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(s)); err != nil {
+		return err
+	}
+	return qp.Close()
 }
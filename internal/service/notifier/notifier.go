@@ -0,0 +1,79 @@
+// Package notifier delivers rendered notifications over email, SMS and Web
+// Push. Each transport implements Channel; Notifier dispatches a message to
+// the channel it is addressed to.
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"furniture-shop/internal/config"
+	en "furniture-shop/internal/entities/notification"
+	"furniture-shop/internal/service/mailer"
+	"furniture-shop/internal/storage"
+)
+
+// Message is a rendered notification addressed to one recipient of one
+// channel: an email address, a phone number or a push subscription ID.
+type Message struct {
+	Channel string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type Channel interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type Notifier struct {
+	channels map[string]Channel
+}
+
+func New(channels map[string]Channel) *Notifier {
+	return &Notifier{channels: channels}
+}
+
+// NewFromConfig wires the transports selected in appconfig.json.
+func NewFromConfig(subs storage.PushSubscriptionRepository) (*Notifier, error) {
+	cfg := config.Configurations.Notify
+	var sms SMSGateway = LogSMSGateway{}
+	if cfg.SMS.Driver == "http" {
+		sms = NewHTTPSMSGateway(cfg.SMS.URL, config.Env.SMSAPIToken, cfg.SMS.Sender)
+	}
+	var push PushSender = LogPushSender{}
+	if cfg.Push.Driver == "webpush" {
+		wp, err := NewWebPushSender(cfg.Push.VAPIDPublicKey, config.Env.VAPIDPrivateKey, cfg.Push.Subject)
+		if err != nil {
+			return nil, err
+		}
+		push = wp
+	}
+	return New(map[string]Channel{
+		en.ChannelEmail: NewEmailChannel(mailer.NewSender()),
+		en.ChannelSMS:   NewSMSChannel(sms),
+		en.ChannelPush:  NewPushChannel(subs, push),
+	}), nil
+}
+
+func (n *Notifier) Send(ctx context.Context, msg Message) error {
+	ch, ok := n.channels[msg.Channel]
+	if !ok {
+		return fmt.Errorf("unsupported channel %q", msg.Channel)
+	}
+	return ch.Send(ctx, msg)
+}
+
+type emailChannel struct {
+	sender mailer.Sender
+}
+
+// NewEmailChannel adapts a mailer transport to the Channel interface.
+func NewEmailChannel(sender mailer.Sender) Channel {
+	return &emailChannel{sender: sender}
+}
+
+func (c *emailChannel) Send(_ context.Context, msg Message) error {
+	return c.sender.Send(mailer.Message{To: msg.To, Subject: msg.Subject, Text: msg.Text, HTML: msg.HTML})
+}
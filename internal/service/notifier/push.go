@@ -0,0 +1,230 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+
+	en "furniture-shop/internal/entities/notification"
+	"furniture-shop/internal/storage"
+)
+
+// ErrSubscriptionGone is returned by a PushSender when the push service
+// reports that the subscription no longer exists.
+var ErrSubscriptionGone = errors.New("push subscription expired")
+
+// PushSender delivers an already serialised payload to one subscription.
+type PushSender interface {
+	Push(ctx context.Context, sub *en.PushSubscription, payload []byte) error
+}
+
+// LogPushSender is the local stub: it only logs the payload.
+type LogPushSender struct{}
+
+func (LogPushSender) Push(_ context.Context, sub *en.PushSubscription, payload []byte) error {
+	log.Printf("push to subscription %d: %s", sub.ID, payload)
+	return nil
+}
+
+type pushChannel struct {
+	subs   storage.PushSubscriptionRepository
+	sender PushSender
+}
+
+// NewPushChannel delivers messages whose recipient is a push subscription ID.
+// Subscriptions the push service reports as gone are deleted.
+func NewPushChannel(subs storage.PushSubscriptionRepository, sender PushSender) Channel {
+	return &pushChannel{subs: subs, sender: sender}
+}
+
+func (c *pushChannel) Send(ctx context.Context, msg Message) error {
+	id, err := strconv.ParseUint(msg.To, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid push recipient %q", msg.To)
+	}
+	sub, err := c.subs.FindByID(ctx, uint(id))
+	if err != nil {
+		// unsubscribed since the message was queued; nothing to deliver
+		return nil
+	}
+	payload, _ := json.Marshal(map[string]string{"title": msg.Subject, "body": msg.Text})
+	err = c.sender.Push(ctx, sub, payload)
+	if errors.Is(err, ErrSubscriptionGone) {
+		return c.subs.Delete(ctx, sub.UserID, sub.ID)
+	}
+	return err
+}
+
+type webPushSender struct {
+	publicKey  string
+	privateKey *ecdsa.PrivateKey
+	subject    string
+	client     *http.Client
+}
+
+// NewWebPushSender creates a Web Push sender authenticated with the VAPID key
+// pair (base64url, uncompressed P-256 public key and raw 32 byte private key).
+func NewWebPushSender(publicKey, privateKey, subject string) (PushSender, error) {
+	d, err := base64.RawURLEncoding.DecodeString(privateKey)
+	if err != nil || len(d) != 32 {
+		return nil, errors.New("invalid VAPID private key")
+	}
+	priv, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		return nil, errors.New("invalid VAPID private key")
+	}
+	pub := priv.PublicKey().Bytes()
+	if publicKey != base64.RawURLEncoding.EncodeToString(pub) {
+		return nil, errors.New("VAPID public key does not match the private key")
+	}
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(pub[1:33]), Y: new(big.Int).SetBytes(pub[33:])},
+		D:         new(big.Int).SetBytes(d),
+	}
+	return &webPushSender{publicKey: publicKey, privateKey: key, subject: subject, client: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func (s *webPushSender) Push(ctx context.Context, sub *en.PushSubscription, payload []byte) error {
+	body, err := encryptPayload(sub, payload)
+	if err != nil {
+		return err
+	}
+	token, err := s.vapidToken(sub.Endpoint)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", "86400")
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", token, s.publicKey))
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("push service responded %s", resp.Status)
+	}
+	return nil
+}
+
+// vapidToken signs the ES256 JWT identifying the application server to the
+// push service of endpoint (RFC 8292).
+func (s *webPushSender) vapidToken(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, _ := json.Marshal(map[string]any{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": s.subject,
+	})
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	r, sig, err := ecdsa.Sign(rand.Reader, s.privateKey, digest[:])
+	if err != nil {
+		return "", err
+	}
+	raw := make([]byte, 64)
+	r.FillBytes(raw[:32])
+	sig.FillBytes(raw[32:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// encryptPayload encrypts payload for the subscription using the aes128gcm
+// content encoding of RFC 8291 / RFC 8188 as a single record.
+func encryptPayload(sub *en.PushSubscription, payload []byte) ([]byte, error) {
+	uaPubBytes, err := base64.RawURLEncoding.DecodeString(sub.P256dh)
+	if err != nil {
+		return nil, errors.New("invalid subscription key")
+	}
+	authSecret, err := base64.RawURLEncoding.DecodeString(sub.Auth)
+	if err != nil {
+		return nil, errors.New("invalid subscription auth secret")
+	}
+	uaPub, err := ecdh.P256().NewPublicKey(uaPubBytes)
+	if err != nil {
+		return nil, errors.New("invalid subscription key")
+	}
+	asPriv, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPub := asPriv.PublicKey().Bytes()
+	shared, err := asPriv.ECDH(uaPub)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := append([]byte("WebPush: info\x00"), uaPubBytes...)
+	keyInfo = append(keyInfo, asPub...)
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, authSecret, keyInfo), ikm); err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek := make([]byte, 16)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: aes128gcm\x00")), cek); err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 12)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: nonce\x00")), nonce); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// 0x02 marks the last (and only) record
+	plaintext := append(append([]byte{}, payload...), 0x02)
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+	if len(ciphertext) > 4096 {
+		return nil, errors.New("push payload too large")
+	}
+
+	var out bytes.Buffer
+	out.Write(salt)
+	_ = binary.Write(&out, binary.BigEndian, uint32(4096))
+	out.WriteByte(byte(len(asPub)))
+	out.Write(asPub)
+	out.Write(ciphertext)
+	return out.Bytes(), nil
+}
@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// SMSGateway sends a text message to a phone number.
+type SMSGateway interface {
+	SendSMS(ctx context.Context, to, body string) error
+}
+
+// LogSMSGateway is the local stub: it only logs the message.
+type LogSMSGateway struct{}
+
+func (LogSMSGateway) SendSMS(_ context.Context, to, body string) error {
+	log.Printf("sms to %s: %s", to, body)
+	return nil
+}
+
+type httpSMSGateway struct {
+	url    string
+	token  string
+	sender string
+	client *http.Client
+}
+
+// NewHTTPSMSGateway posts {"from","to","text"} as JSON to url with a bearer
+// token, which fits most HTTP SMS providers or a small relay in front of them.
+func NewHTTPSMSGateway(url, token, sender string) SMSGateway {
+	return &httpSMSGateway{url: url, token: token, sender: sender, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (g *httpSMSGateway) SendSMS(ctx context.Context, to, body string) error {
+	if g.url == "" {
+		return errors.New("sms gateway url is not configured")
+	}
+	payload, _ := json.Marshal(map[string]string{"from": g.sender, "to": to, "text": body})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway responded %s", resp.Status)
+	}
+	return nil
+}
+
+type smsChannel struct {
+	gateway SMSGateway
+}
+
+func NewSMSChannel(gateway SMSGateway) Channel {
+	return &smsChannel{gateway: gateway}
+}
+
+func (c *smsChannel) Send(ctx context.Context, msg Message) error {
+	if msg.To == "" {
+		return errors.New("recipient has no phone number")
+	}
+	return c.gateway.SendSMS(ctx, msg.To, msg.Text)
+}
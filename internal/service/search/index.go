@@ -0,0 +1,49 @@
+// Package search decouples product search from the storage layer. An Index
+// is kept in sync with catalog writes by the admin service and answers the
+// storefront search; the backend is chosen with SEARCH.BACKEND in the
+// configuration: "postgres" (default) uses the full-text search maintained by
+// database triggers, "memory" an embedded inverted index that needs no
+// database and is rebuilt on startup. The memory index only sees the catalog
+// writes of its own process, so it suits a single API instance; deployments
+// running several must use postgres.
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"furniture-shop/internal/config"
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+)
+
+// Document is a product as seen by the index: the product with its options,
+// the department and category it is listed under and the translations of
+// the product and its options, which are searchable too.
+type Document struct {
+	Product        ec.Product
+	DepartmentID   uint
+	DepartmentName string
+	CategoryName   string
+	Translations   []ec.Translation
+}
+
+// Index stores product documents and answers search queries.
+type Index interface {
+	// Index adds the documents, replacing earlier versions of the same products.
+	Index(ctx context.Context, docs ...Document) error
+	Delete(ctx context.Context, ids ...uint) error
+	Query(ctx context.Context, p ec.ProductSearchParams) (*ec.ProductSearchResult, error)
+}
+
+// NewFromConfig builds the index selected by the SEARCH configuration.
+func NewFromConfig(products storage.ProductRepository) (Index, error) {
+	switch config.Configurations.Search.Backend {
+	case "postgres":
+		return NewPostgresIndex(products), nil
+	case "memory":
+		return NewMemoryIndex(), nil
+	default:
+		return nil, fmt.Errorf("unknown search backend %q", config.Configurations.Search.Backend)
+	}
+}
@@ -0,0 +1,180 @@
+package search
+
+import (
+	"context"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+)
+
+// reindexBatch is the number of documents sent to the index at once when
+// reindexing many products.
+const reindexBatch = 200
+
+// Indexer builds documents from the catalog repositories and feeds them to an Index.
+type Indexer struct {
+	index        Index
+	departments  storage.DepartmentRepository
+	categories   storage.CategoryRepository
+	products     storage.ProductRepository
+	options      storage.ProductOptionRepository
+	translations storage.TranslationRepository
+}
+
+func NewIndexer(index Index, departments storage.DepartmentRepository, categories storage.CategoryRepository, products storage.ProductRepository, options storage.ProductOptionRepository, translations storage.TranslationRepository) *Indexer {
+	return &Indexer{index: index, departments: departments, categories: categories, products: products, options: options, translations: translations}
+}
+
+// Product (re)indexes a single product, or removes it from the index when
+// it is not published.
+func (x *Indexer) Product(ctx context.Context, id uint) error {
+	p, err := x.products.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !p.Published() {
+		return x.index.Delete(ctx, id)
+	}
+	describe, err := x.describer(ctx)
+	if err != nil {
+		return err
+	}
+	doc := describe(*p)
+	if doc.Translations, err = x.translations.List(ctx, ec.SlugProduct, []uint{id}, nil); err != nil {
+		return err
+	}
+	optionIDs := make([]uint, len(p.Options))
+	for i, o := range p.Options {
+		optionIDs[i] = o.ID
+	}
+	optionTs, err := x.translations.List(ctx, ec.TranslationOption, optionIDs, nil)
+	if err != nil {
+		return err
+	}
+	doc.Translations = append(doc.Translations, optionTs...)
+	return x.index.Index(ctx, doc)
+}
+
+func (x *Indexer) Remove(ctx context.Context, id uint) error {
+	return x.index.Delete(ctx, id)
+}
+
+// Rebuild indexes every published product and returns how many were indexed.
+func (x *Indexer) Rebuild(ctx context.Context) (int, error) {
+	return x.indexWhere(ctx, func(ec.Product) bool { return true })
+}
+
+// Categories reindexes the products listed under the given categories, e.g.
+// after a category was renamed or moved to another department.
+func (x *Indexer) Categories(ctx context.Context, ids ...uint) error {
+	in := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		in[id] = true
+	}
+	_, err := x.indexWhere(ctx, func(p ec.Product) bool { return in[p.CategoryID] })
+	return err
+}
+
+// Department reindexes the products of every category in a department.
+func (x *Indexer) Department(ctx context.Context, id uint) error {
+	cats, err := x.categories.ListByDepartment(ctx, id)
+	if err != nil {
+		return err
+	}
+	ids := make([]uint, len(cats))
+	for i, c := range cats {
+		ids[i] = c.ID
+	}
+	return x.Categories(ctx, ids...)
+}
+
+func (x *Indexer) indexWhere(ctx context.Context, keep func(ec.Product) bool) (int, error) {
+	products, err := x.products.ListAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	options, err := x.options.List(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	byProduct := map[uint][]ec.ProductOption{}
+	optionProduct := map[uint]uint{}
+	for _, o := range options {
+		byProduct[o.ProductID] = append(byProduct[o.ProductID], o)
+		optionProduct[o.ID] = o.ProductID
+	}
+	translations := map[uint][]ec.Translation{}
+	productTs, err := x.translations.List(ctx, ec.SlugProduct, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	for _, t := range productTs {
+		translations[t.RecordID] = append(translations[t.RecordID], t)
+	}
+	optionTs, err := x.translations.List(ctx, ec.TranslationOption, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	for _, t := range optionTs {
+		if pid, ok := optionProduct[t.RecordID]; ok {
+			translations[pid] = append(translations[pid], t)
+		}
+	}
+	describe, err := x.describer(ctx)
+	if err != nil {
+		return 0, err
+	}
+	indexed := 0
+	batch := make([]Document, 0, reindexBatch)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := x.index.Index(ctx, batch...); err != nil {
+			return err
+		}
+		indexed += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+	for _, p := range products {
+		if !p.Published() || !keep(p) {
+			continue
+		}
+		p.Options = byProduct[p.ID]
+		doc := describe(p)
+		doc.Translations = translations[p.ID]
+		batch = append(batch, doc)
+		if len(batch) == reindexBatch {
+			if err := flush(); err != nil {
+				return indexed, err
+			}
+		}
+	}
+	return indexed, flush()
+}
+
+// describer loads the department and category names once and returns a
+// function that turns products into documents.
+func (x *Indexer) describer(ctx context.Context) (func(ec.Product) Document, error) {
+	depts, err := x.departments.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cats, err := x.categories.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deptNames := make(map[uint]string, len(depts))
+	for _, d := range depts {
+		deptNames[d.ID] = d.Name
+	}
+	catByID := make(map[uint]ec.Category, len(cats))
+	for _, c := range cats {
+		catByID[c.ID] = c
+	}
+	return func(p ec.Product) Document {
+		c := catByID[p.CategoryID]
+		return Document{Product: p, DepartmentID: c.DepartmentID, DepartmentName: deptNames[c.DepartmentID], CategoryName: c.Name}
+	}, nil
+}
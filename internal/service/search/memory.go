@@ -0,0 +1,444 @@
+package search
+
+import (
+	"context"
+	"html"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/service/i18n"
+)
+
+// minTermSimilarity is the trigram similarity above which an indexed word is
+// accepted as the correction of a misspelled query word.
+const minTermSimilarity = 0.3
+
+// Field weights, matching the A-D weights of the Postgres ranking.
+const (
+	weightName        = 1.0
+	weightAttributes  = 0.4
+	weightShort       = 0.2
+	weightDescription = 0.1
+)
+
+// translationWeights weighs translated text like the field it translates.
+var translationWeights = map[string]float64{
+	"name":              weightName,
+	"option_name":       weightAttributes,
+	"short_description": weightShort,
+	"long_description":  weightDescription,
+}
+
+// snippetWords is the length of the description highlight.
+const snippetWords = 30
+
+type memoryDoc struct {
+	Document
+	terms map[string]float64
+}
+
+// memoryIndex is an inverted index from word stems to the weighted term
+// frequency of every product containing them. Stems of translated text are
+// posted under localeTerm, so that a query only matches the translations of
+// its locale. It is safe for concurrent use.
+type memoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[uint]*memoryDoc
+	postings map[string]map[uint]float64
+	// vocab maps every indexed word to its stem; it is the dictionary used to
+	// correct misspellings and to expand prefixes.
+	vocab map[string]string
+}
+
+func NewMemoryIndex() Index {
+	return &memoryIndex{
+		docs:     map[uint]*memoryDoc{},
+		postings: map[string]map[uint]float64{},
+		vocab:    map[string]string{},
+	}
+}
+
+func (x *memoryIndex) Index(ctx context.Context, docs ...Document) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, d := range docs {
+		x.remove(d.Product.ID)
+		doc := &memoryDoc{Document: d, terms: map[string]float64{}}
+		p := d.Product
+		attrs := []string{p.BaseMaterial}
+		for _, o := range p.Options {
+			attrs = append(attrs, o.OptionName)
+		}
+		x.addField(doc, "", p.Name, weightName)
+		x.addField(doc, "", strings.Join(attrs, " "), weightAttributes)
+		x.addField(doc, "", p.ShortDescription, weightShort)
+		x.addField(doc, "", p.LongDescription, weightDescription)
+		for _, t := range d.Translations {
+			if w, ok := translationWeights[t.Field]; ok {
+				x.addField(doc, t.Locale, t.Value, w)
+			}
+		}
+		for term, w := range doc.terms {
+			if x.postings[term] == nil {
+				x.postings[term] = map[uint]float64{}
+			}
+			x.postings[term][p.ID] = w
+		}
+		x.docs[p.ID] = doc
+	}
+	return nil
+}
+
+// localized returns the product with the texts of locale, so that the
+// highlights are made on the text the caller shows.
+func (d *memoryDoc) localized(locale string) ec.Product {
+	p := d.Product
+	locales := i18n.Fallbacks(locale)
+	if len(locales) == 0 || len(d.Translations) == 0 {
+		return p
+	}
+	of := func(kind string, id uint) []ec.Translation {
+		var out []ec.Translation
+		for _, t := range d.Translations {
+			if t.Kind == kind && t.RecordID == id {
+				out = append(out, t)
+			}
+		}
+		return out
+	}
+	ec.Translate(p.TextFields(), of(ec.SlugProduct, p.ID), locales)
+	p.Options = slices.Clone(p.Options)
+	for i := range p.Options {
+		o := &p.Options[i]
+		ec.Translate(o.TextFields(), of(ec.TranslationOption, o.ID), locales)
+	}
+	return p
+}
+
+func (x *memoryIndex) addField(doc *memoryDoc, locale, text string, weight float64) {
+	for _, s := range tokenize(text) {
+		if stopWords[s.word] {
+			continue
+		}
+		st := stem(s.word)
+		doc.terms[localeTerm(locale, st)] += weight
+		if utf8.RuneCountInString(s.word) > 2 {
+			x.vocab[s.word] = st
+		}
+	}
+}
+
+// localeTerm is the posting key of a stem in the text of locale; the default
+// text has no locale.
+func localeTerm(locale, st string) string {
+	if locale == "" {
+		return st
+	}
+	return locale + ":" + st
+}
+
+// termFrequencies sums the postings of a stem in the default text and in the
+// translations of locales.
+func (x *memoryIndex) termFrequencies(st string, locales []string) map[uint]float64 {
+	out := maps.Clone(x.postings[st])
+	if out == nil {
+		out = map[uint]float64{}
+	}
+	for _, l := range locales {
+		for id, tf := range x.postings[localeTerm(l, st)] {
+			out[id] += tf
+		}
+	}
+	return out
+}
+
+func (x *memoryIndex) Delete(ctx context.Context, ids ...uint) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, id := range ids {
+		x.remove(id)
+	}
+	return nil
+}
+
+func (x *memoryIndex) remove(id uint) {
+	doc, ok := x.docs[id]
+	if !ok {
+		return
+	}
+	for term := range doc.terms {
+		delete(x.postings[term], id)
+		if len(x.postings[term]) == 0 {
+			delete(x.postings, term)
+		}
+	}
+	delete(x.docs, id)
+}
+
+// Query ranks documents containing every query word; when none does, any word
+// suffices. Unknown words also match their closest indexed spelling and the
+// last word matches as a prefix.
+func (x *memoryIndex) Query(ctx context.Context, p ec.ProductSearchParams) (*ec.ProductSearchResult, error) {
+	res := &ec.ProductSearchResult{Query: p.Query, Limit: p.Limit, Offset: p.Offset, Hits: []ec.ProductSearchHit{}}
+	words := queryWords(p.Query)
+	if len(words) == 0 {
+		return res, nil
+	}
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	locales := i18n.Fallbacks(p.Locale)
+	corrections := x.correctWords(words, locales)
+	if len(corrections) > 0 {
+		res.Corrections = corrections
+	}
+	groups := x.expand(words, corrections)
+	scores := x.match(groups, locales, true)
+	if len(scores) == 0 && len(words) > 1 {
+		scores = x.match(groups, locales, false)
+	}
+
+	type scored struct {
+		doc   *memoryDoc
+		score float64
+	}
+	var hits []scored
+	for id, score := range scores {
+		if doc := x.docs[id]; doc.matches(p, "") {
+			hits = append(hits, scored{doc, score})
+		}
+	}
+	res.Total = int64(len(hits))
+	if res.Total == 0 {
+		return res, nil
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].doc.Product.ID < hits[j].doc.Product.ID
+	})
+
+	matched := map[string]bool{}
+	for _, g := range groups {
+		for term := range g {
+			matched[term] = true
+		}
+	}
+	start := min(p.Offset, len(hits))
+	end := len(hits)
+	if p.Limit > 0 {
+		end = min(start+p.Limit, len(hits))
+	}
+	for _, h := range hits[start:end] {
+		prod := h.doc.localized(p.Locale)
+		res.Hits = append(res.Hits, ec.ProductSearchHit{
+			Product:              prod,
+			Rank:                 h.score / (h.score + 1),
+			NameHighlight:        highlight(prod.Name, matched, 0),
+			DescriptionHighlight: highlight(strings.TrimSpace(prod.ShortDescription+" "+prod.LongDescription), matched, snippetWords),
+		})
+	}
+	res.Facets = x.facets(scores, p)
+	return res, nil
+}
+
+// correctWords picks the closest indexed word for every query word that is
+// not itself indexed in the default text or the translations of locales.
+func (x *memoryIndex) correctWords(words []string, locales []string) map[string]string {
+	out := map[string]string{}
+	for _, w := range words {
+		if st, ok := x.vocab[w]; ok && len(x.termFrequencies(st, locales)) > 0 {
+			continue
+		}
+		best, bestSim := "", minTermSimilarity
+		for term, st := range x.vocab {
+			if len(x.termFrequencies(st, locales)) == 0 {
+				continue
+			}
+			sim := similarity(w, term)
+			if sim > bestSim || (sim == bestSim && (best == "" || term < best)) {
+				best, bestSim = term, sim
+			}
+		}
+		if best != "" {
+			out[w] = best
+		}
+	}
+	return out
+}
+
+// expand turns every query word into the set of stems it matches.
+func (x *memoryIndex) expand(words []string, corrections map[string]string) []map[string]bool {
+	groups := make([]map[string]bool, len(words))
+	for i, w := range words {
+		g := map[string]bool{stem(w): true}
+		if c, ok := corrections[w]; ok {
+			g[stem(c)] = true
+		}
+		if i == len(words)-1 {
+			for term, st := range x.vocab {
+				if strings.HasPrefix(term, w) {
+					g[st] = true
+				}
+			}
+		}
+		groups[i] = g
+	}
+	return groups
+}
+
+// match scores the documents matching all (or any) of the word groups in the
+// default text or the translations of locales by the sum of the tf-idf of
+// their best term per group.
+func (x *memoryIndex) match(groups []map[string]bool, locales []string, all bool) map[uint]float64 {
+	total := float64(len(x.docs))
+	var scores map[uint]float64
+	for i, g := range groups {
+		best := map[uint]float64{}
+		for term := range g {
+			docs := x.termFrequencies(term, locales)
+			idf := math.Log(1 + total/float64(max(len(docs), 1)))
+			for id, tf := range docs {
+				best[id] = math.Max(best[id], tf*idf)
+			}
+		}
+		if i == 0 {
+			scores = best
+			continue
+		}
+		if all {
+			for id := range scores {
+				if s, ok := best[id]; ok {
+					scores[id] += s
+				} else {
+					delete(scores, id)
+				}
+			}
+		} else {
+			for id, s := range best {
+				scores[id] += s
+			}
+		}
+	}
+	return scores
+}
+
+// facets counts matches per department, category, material and price range,
+// each ignoring its own filter.
+func (x *memoryIndex) facets(scores map[uint]float64, p ec.ProductSearchParams) ec.SearchFacets {
+	counts := map[string]map[string]*ec.FacetCount{}
+	add := func(facet, value, label string) {
+		if counts[facet] == nil {
+			counts[facet] = map[string]*ec.FacetCount{}
+		}
+		fc, ok := counts[facet][value]
+		if !ok {
+			fc = &ec.FacetCount{Value: value, Label: label}
+			counts[facet][value] = fc
+		}
+		fc.Count++
+	}
+	for id := range scores {
+		doc := x.docs[id]
+		prod := doc.Product
+		if doc.matches(p, "department") {
+			add("department", strconv.FormatUint(uint64(doc.DepartmentID), 10), doc.DepartmentName)
+		}
+		if doc.matches(p, "category") {
+			add("category", strconv.FormatUint(uint64(prod.CategoryID), 10), doc.CategoryName)
+		}
+		if doc.matches(p, "material") {
+			add("material", prod.BaseMaterial, prod.BaseMaterial)
+		}
+		if doc.matches(p, "price") {
+			bucket := ec.PriceBucket(prod.Price())
+			add("price", bucket, bucket)
+		}
+	}
+	sorted := func(facet string) []ec.FacetCount {
+		out := []ec.FacetCount{}
+		for _, fc := range counts[facet] {
+			out = append(out, *fc)
+		}
+		sort.Slice(out, func(i, j int) bool {
+			if out[i].Count != out[j].Count {
+				return out[i].Count > out[j].Count
+			}
+			return out[i].Label < out[j].Label
+		})
+		return out
+	}
+	return ec.SearchFacets{
+		Departments: sorted("department"),
+		Categories:  sorted("category"),
+		Materials:   sorted("material"),
+		PriceRanges: sorted("price"),
+	}
+}
+
+// matches applies the search filters except the one named by exclude.
+func (d *memoryDoc) matches(p ec.ProductSearchParams, exclude string) bool {
+	prod := d.Product
+	if p.DepartmentID != 0 && exclude != "department" && d.DepartmentID != p.DepartmentID {
+		return false
+	}
+	if p.CategoryID != 0 && exclude != "category" && prod.CategoryID != p.CategoryID {
+		return false
+	}
+	if p.Material != "" && exclude != "material" && !strings.EqualFold(prod.BaseMaterial, p.Material) {
+		return false
+	}
+	if exclude != "price" {
+		if p.PriceMin != nil && prod.Price() < *p.PriceMin {
+			return false
+		}
+		if p.PriceMax != nil && prod.Price() >= *p.PriceMax {
+			return false
+		}
+	}
+	return true
+}
+
+// highlight HTML-escapes text and wraps the words whose stem matched in
+// <mark> tags. With a positive limit only that many words are returned,
+// starting a few words before the first match.
+func highlight(text string, matched map[string]bool, limit int) string {
+	spans := tokenize(text)
+	if len(spans) == 0 {
+		return html.EscapeString(text)
+	}
+	first, last := 0, len(spans)
+	from, to := 0, len(text)
+	if limit > 0 && len(spans) > limit {
+		for i, s := range spans {
+			if matched[stem(s.word)] {
+				first = max(0, min(i-5, len(spans)-limit))
+				break
+			}
+		}
+		last = first + limit
+		from, to = spans[first].start, spans[last-1].end
+	}
+	var b strings.Builder
+	pos := from
+	for _, s := range spans[first:last] {
+		if !matched[stem(s.word)] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:s.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[s.start:s.end]))
+		b.WriteString("</mark>")
+		pos = s.end
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	return b.String()
+}
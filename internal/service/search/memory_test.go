@@ -0,0 +1,245 @@
+package search
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"testing"
+
+	ec "furniture-shop/internal/entities/catalog"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// testIndex holds four products in two departments:
+//
+//	1 Oak Dining Table     Dining room / Tables   Oak    400
+//	2 Folding Chairs       Dining room / Chairs   Pine   120
+//	3 Oak Bookshelf        Office / Shelves       Oak    900, on sale at 450
+//	4 Glass Coffee Table   Dining room / Tables   Glass  2500, named "Стъклена маса" in bg
+func testIndex(t *testing.T) Index {
+	t.Helper()
+	dining := func(d Document) Document {
+		d.DepartmentID, d.DepartmentName = 1, "Dining room"
+		return d
+	}
+	docs := []Document{
+		dining(Document{
+			Product:      ec.Product{ID: 1, Name: "Oak Dining Table", BaseMaterial: "Oak", CategoryID: 10, BasePrice: 400, ShortDescription: "Seats six around a solid oak top."},
+			CategoryName: "Tables",
+		}),
+		dining(Document{
+			Product:      ec.Product{ID: 2, Name: "Folding Chairs", BaseMaterial: "Pine", CategoryID: 11, BasePrice: 120},
+			CategoryName: "Chairs",
+		}),
+		{
+			Product:        ec.Product{ID: 3, Name: "Oak Bookshelf", BaseMaterial: "Oak", CategoryID: 20, BasePrice: 900, SalePrice: ptr(450.0)},
+			DepartmentID:   2,
+			DepartmentName: "Office",
+			CategoryName:   "Shelves",
+		},
+		dining(Document{
+			Product:      ec.Product{ID: 4, Name: "Glass Coffee Table", BaseMaterial: "Glass", CategoryID: 10, BasePrice: 2500},
+			CategoryName: "Tables",
+			Translations: []ec.Translation{{Kind: ec.SlugProduct, RecordID: 4, Locale: "bg", Field: "name", Value: "Стъклена маса"}},
+		}),
+	}
+	x := NewMemoryIndex()
+	if err := x.Index(context.Background(), docs...); err != nil {
+		t.Fatal(err)
+	}
+	return x
+}
+
+func hitIDs(res *ec.ProductSearchResult) []uint {
+	var ids []uint
+	for _, h := range res.Hits {
+		ids = append(ids, h.Product.ID)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestMemoryQuery(t *testing.T) {
+	x := testIndex(t)
+	tests := []struct {
+		name        string
+		params      ec.ProductSearchParams
+		want        []uint
+		corrections map[string]string
+	}{
+		{name: "empty query", params: ec.ProductSearchParams{Query: "  the "}},
+		{name: "single word", params: ec.ProductSearchParams{Query: "oak"}, want: []uint{1, 3}},
+		// words that are not indexed as typed are reported as corrected, but
+		// match by their stem as well
+		{name: "stemmed plural", params: ec.ProductSearchParams{Query: "tables"}, want: []uint{1, 4}, corrections: map[string]string{"tables": "table"}},
+		{name: "stemmed verb", params: ec.ProductSearchParams{Query: "folded chair"}, want: []uint{2}, corrections: map[string]string{"folded": "folding", "chair": "chairs"}},
+		{name: "all words", params: ec.ProductSearchParams{Query: "oak table"}, want: []uint{1}},
+		{name: "any word when none has all", params: ec.ProductSearchParams{Query: "oak chairs"}, want: []uint{1, 2, 3}},
+		{name: "last word as prefix", params: ec.ProductSearchParams{Query: "dining ta"}, want: []uint{1}},
+		{name: "misspelling", params: ec.ProductSearchParams{Query: "tabel"}, want: []uint{1, 4}, corrections: map[string]string{"tabel": "table"}},
+		{name: "translation", params: ec.ProductSearchParams{Query: "маса", Locale: "bg"}, want: []uint{4}},
+		{name: "translation of another locale", params: ec.ProductSearchParams{Query: "маса", Locale: "de"}},
+		{name: "translation in the default locale", params: ec.ProductSearchParams{Query: "маса"}},
+		{name: "description", params: ec.ProductSearchParams{Query: "seats"}, want: []uint{1}},
+		{name: "department", params: ec.ProductSearchParams{Query: "oak", DepartmentID: 2}, want: []uint{3}},
+		{name: "category", params: ec.ProductSearchParams{Query: "table", CategoryID: 10}, want: []uint{1, 4}},
+		{name: "material ignores case", params: ec.ProductSearchParams{Query: "table", Material: "glass"}, want: []uint{4}},
+		{name: "sale price in range", params: ec.ProductSearchParams{Query: "oak", PriceMin: ptr(300.0), PriceMax: ptr(500.0)}, want: []uint{1, 3}},
+		{name: "upper bound excluded", params: ec.ProductSearchParams{Query: "oak", PriceMax: ptr(450.0)}, want: []uint{1}},
+		{name: "no match", params: ec.ProductSearchParams{Query: "zzzz"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := x.Query(context.Background(), tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hitIDs(res); !slices.Equal(got, tt.want) {
+				t.Errorf("hits = %v, want %v", got, tt.want)
+			}
+			if res.Total != int64(len(tt.want)) {
+				t.Errorf("total = %d, want %d", res.Total, len(tt.want))
+			}
+			if !reflect.DeepEqual(res.Corrections, tt.corrections) {
+				t.Errorf("corrections = %v, want %v", res.Corrections, tt.corrections)
+			}
+		})
+	}
+}
+
+func TestMemoryQueryPaging(t *testing.T) {
+	x := testIndex(t)
+	var pages [][]uint
+	for offset := 0; offset < 3; offset++ {
+		res, err := x.Query(context.Background(), ec.ProductSearchParams{Query: "oak chairs", Limit: 1, Offset: offset})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Total != 3 {
+			t.Fatalf("offset %d: total = %d, want 3", offset, res.Total)
+		}
+		pages = append(pages, hitIDs(res))
+	}
+	var seen []uint
+	for _, p := range pages {
+		if len(p) != 1 {
+			t.Fatalf("pages = %v, want one hit each", pages)
+		}
+		seen = append(seen, p[0])
+	}
+	slices.Sort(seen)
+	if !slices.Equal(seen, []uint{1, 2, 3}) {
+		t.Errorf("pages = %v, want every hit once", pages)
+	}
+}
+
+func TestMemoryQueryFacets(t *testing.T) {
+	x := testIndex(t)
+	tests := []struct {
+		name   string
+		params ec.ProductSearchParams
+		want   ec.SearchFacets
+	}{
+		{
+			name:   "no filter",
+			params: ec.ProductSearchParams{Query: "table"},
+			want: ec.SearchFacets{
+				Departments: []ec.FacetCount{{Value: "1", Label: "Dining room", Count: 2}},
+				Categories:  []ec.FacetCount{{Value: "10", Label: "Tables", Count: 2}},
+				Materials:   []ec.FacetCount{{Value: "Glass", Label: "Glass", Count: 1}, {Value: "Oak", Label: "Oak", Count: 1}},
+				PriceRanges: []ec.FacetCount{{Value: "2000-", Label: "2000-", Count: 1}, {Value: "250-500", Label: "250-500", Count: 1}},
+			},
+		},
+		{
+			// each facet ignores its own filter but applies the others
+			name:   "category filter",
+			params: ec.ProductSearchParams{Query: "oak", CategoryID: 10},
+			want: ec.SearchFacets{
+				Departments: []ec.FacetCount{{Value: "1", Label: "Dining room", Count: 1}},
+				Categories:  []ec.FacetCount{{Value: "20", Label: "Shelves", Count: 1}, {Value: "10", Label: "Tables", Count: 1}},
+				Materials:   []ec.FacetCount{{Value: "Oak", Label: "Oak", Count: 1}},
+				PriceRanges: []ec.FacetCount{{Value: "250-500", Label: "250-500", Count: 1}},
+			},
+		},
+		{
+			name:   "material and price filters",
+			params: ec.ProductSearchParams{Query: "oak chairs", Material: "oak", PriceMax: ptr(500.0)},
+			want: ec.SearchFacets{
+				Departments: []ec.FacetCount{{Value: "1", Label: "Dining room", Count: 1}, {Value: "2", Label: "Office", Count: 1}},
+				Categories:  []ec.FacetCount{{Value: "20", Label: "Shelves", Count: 1}, {Value: "10", Label: "Tables", Count: 1}},
+				Materials:   []ec.FacetCount{{Value: "Oak", Label: "Oak", Count: 2}, {Value: "Pine", Label: "Pine", Count: 1}},
+				PriceRanges: []ec.FacetCount{{Value: "250-500", Label: "250-500", Count: 2}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := x.Query(context.Background(), tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(res.Facets, tt.want) {
+				t.Errorf("facets = %+v, want %+v", res.Facets, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryQueryHighlightsAndLocale(t *testing.T) {
+	x := testIndex(t)
+	res, err := x.Query(context.Background(), ec.ProductSearchParams{Query: "oak dining"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 1 {
+		t.Fatalf("hits = %v, want product 1", hitIDs(res))
+	}
+	h := res.Hits[0]
+	if want := "<mark>Oak</mark> <mark>Dining</mark> Table"; h.NameHighlight != want {
+		t.Errorf("name highlight = %q, want %q", h.NameHighlight, want)
+	}
+	if want := "Seats six around a solid <mark>oak</mark> top."; h.DescriptionHighlight != want {
+		t.Errorf("description highlight = %q, want %q", h.DescriptionHighlight, want)
+	}
+	if h.Rank <= 0 || h.Rank >= 1 {
+		t.Errorf("rank = %v, want within (0, 1)", h.Rank)
+	}
+}
+
+func TestMemoryIndexReplaceAndDelete(t *testing.T) {
+	x := testIndex(t)
+	ctx := context.Background()
+	if err := x.Index(ctx, Document{Product: ec.Product{ID: 1, Name: "Walnut Dining Table", BaseMaterial: "Walnut", CategoryID: 10, BasePrice: 400}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := x.Delete(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	for q, want := range map[string][]uint{"oak": nil, "walnut": {1}, "bookshelf": nil, "table": {1, 4}} {
+		res, err := x.Query(ctx, ec.ProductSearchParams{Query: q})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hitIDs(res); !slices.Equal(got, want) {
+			t.Errorf("%q: hits = %v, want %v", q, got, want)
+		}
+	}
+}
+
+func TestHighlightEscapes(t *testing.T) {
+	matched := map[string]bool{stem("oak"): true}
+	tests := []struct {
+		text, want string
+	}{
+		{"Oak <b>table</b>", "<mark>Oak</mark> &lt;b&gt;table&lt;/b&gt;"},
+		{`<script>alert("oak")</script>`, `&lt;script&gt;alert(&#34;<mark>oak</mark>&#34;)&lt;/script&gt;`},
+		{"Tom & Jerry", "Tom &amp; Jerry"},
+		{"<>", "&lt;&gt;"},
+	}
+	for _, tt := range tests {
+		if got := highlight(tt.text, matched, 0); got != tt.want {
+			t.Errorf("highlight(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
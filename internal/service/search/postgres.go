@@ -0,0 +1,35 @@
+package search
+
+import (
+	"context"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+)
+
+// postgresIndex queries the products.search_vector column. Triggers already
+// refresh it on every catalog write, so indexing only forces a recompute and
+// deleted products leave the index together with their rows.
+type postgresIndex struct {
+	products storage.ProductRepository
+}
+
+func NewPostgresIndex(products storage.ProductRepository) Index {
+	return &postgresIndex{products: products}
+}
+
+func (x *postgresIndex) Index(ctx context.Context, docs ...Document) error {
+	ids := make([]uint, len(docs))
+	for i, d := range docs {
+		ids[i] = d.Product.ID
+	}
+	return x.products.RefreshSearch(ctx, ids)
+}
+
+func (x *postgresIndex) Delete(ctx context.Context, ids ...uint) error {
+	return nil
+}
+
+func (x *postgresIndex) Query(ctx context.Context, p ec.ProductSearchParams) (*ec.ProductSearchResult, error) {
+	return x.products.Search(ctx, p)
+}
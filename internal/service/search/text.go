@@ -0,0 +1,97 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are skipped when indexing and querying, like the Postgres
+// english text search configuration does.
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "into": true, "is": true, "it": true, "of": true, "on": true,
+	"or": true, "the": true, "to": true, "with": true,
+}
+
+// span is a word of a text with its byte offsets.
+type span struct {
+	word       string
+	start, end int
+}
+
+// tokenize splits text into lower-cased letter/digit words.
+func tokenize(text string) []span {
+	var out []span
+	start := -1
+	for i, r := range text {
+		word := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if word && start < 0 {
+			start = i
+		} else if !word && start >= 0 {
+			out = append(out, span{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		out = append(out, span{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return out
+}
+
+// queryWords lower-cases the query and drops punctuation and stop words.
+func queryWords(q string) []string {
+	var out []string
+	for _, s := range tokenize(q) {
+		if !stopWords[s.word] {
+			out = append(out, s.word)
+		}
+	}
+	return out
+}
+
+// stem strips common English inflections so that "tables" finds "table" and
+// "folding" finds "fold". It is deliberately light: both the index and the
+// query go through it, so it only has to be consistent.
+func stem(w string) string {
+	switch {
+	case len(w) > 4 && strings.HasSuffix(w, "ies"):
+		return w[:len(w)-3] + "y"
+	case len(w) > 4 && (strings.HasSuffix(w, "ches") || strings.HasSuffix(w, "shes") || strings.HasSuffix(w, "sses") || strings.HasSuffix(w, "xes")):
+		return w[:len(w)-2]
+	case len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") && !strings.HasSuffix(w, "us"):
+		return w[:len(w)-1]
+	case len(w) > 5 && strings.HasSuffix(w, "ing"):
+		return w[:len(w)-3]
+	case len(w) > 4 && strings.HasSuffix(w, "ed"):
+		return w[:len(w)-2]
+	}
+	return w
+}
+
+// trigrams returns the pg_trgm trigram set of a word, padded with two spaces
+// in front and one behind.
+func trigrams(w string) map[string]struct{} {
+	r := []rune("  " + w + " ")
+	out := make(map[string]struct{}, len(r))
+	for i := 0; i+3 <= len(r); i++ {
+		out[string(r[i:i+3])] = struct{}{}
+	}
+	return out
+}
+
+// similarity is the pg_trgm similarity of two words: shared trigrams over all
+// distinct trigrams.
+func similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	shared := 0
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			shared++
+		}
+	}
+	union := len(ta) + len(tb) - shared
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
@@ -0,0 +1,91 @@
+package search
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []span
+	}{
+		{"", nil},
+		{"Oak", []span{{"oak", 0, 3}}},
+		{"Oak, 3-seat SOFA!", []span{{"oak", 0, 3}, {"3", 5, 6}, {"seat", 7, 11}, {"sofa", 12, 16}}},
+		{"  dining   table  ", []span{{"dining", 2, 8}, {"table", 11, 16}}},
+		{"Маса от дъб", []span{{"маса", 0, 8}, {"от", 9, 13}, {"дъб", 14, 20}}},
+	}
+	for _, tt := range tests {
+		if got := tokenize(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("tokenize(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestQueryWords(t *testing.T) {
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"", nil},
+		{"the table", []string{"table"}},
+		{"Chairs & a Table, for the kitchen", []string{"chairs", "table", "kitchen"}},
+		{"a the of", nil},
+	}
+	for _, tt := range tests {
+		if got := queryWords(tt.q); !slices.Equal(got, tt.want) {
+			t.Errorf("queryWords(%q) = %q, want %q", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word, want string
+	}{
+		{"tables", "table"},
+		{"chairs", "chair"},
+		{"shelves", "shelve"},
+		{"bodies", "body"},
+		{"benches", "bench"},
+		{"dishes", "dish"},
+		{"boxes", "box"},
+		{"glasses", "glass"},
+		{"folding", "fold"},
+		{"painted", "paint"},
+		{"glass", "glass"},
+		{"walnut", "walnut"},
+		{"cactus", "cactus"},
+		{"bus", "bus"},
+		{"ties", "tie"},
+		{"king", "king"},
+		{"red", "red"},
+	}
+	for _, tt := range tests {
+		if got := stem(tt.word); got != tt.want {
+			t.Errorf("stem(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"sofa", "sofa", 1},
+		{"ab", "cd", 0},
+		// "  t", " ta", "tab" shared out of 9 distinct trigrams
+		{"tabel", "table", 3.0 / 9},
+		{"table", "tabel", 3.0 / 9},
+		// "  o", " oa", "oak" shared out of 6 distinct trigrams
+		{"oak", "oaks", 3.0 / 6},
+	}
+	for _, tt := range tests {
+		if got := similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -2,12 +2,23 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"furniture-shop/internal/dtos/cart"
+	notif_dto "furniture-shop/internal/dtos/notifications"
 	order_dto "furniture-shop/internal/dtos/orders"
+	wishlist_dto "furniture-shop/internal/dtos/wishlists"
+	ea "furniture-shop/internal/entities/analytics"
 	ec "furniture-shop/internal/entities/catalog"
+	en "furniture-shop/internal/entities/notification"
 	eo "furniture-shop/internal/entities/orders"
 	eu "furniture-shop/internal/entities/user"
+	"furniture-shop/internal/service/blob"
+	"furniture-shop/internal/service/templates"
+	"furniture-shop/internal/storage/query"
 )
 
 type AuthService interface {
@@ -17,43 +28,200 @@ type AuthService interface {
 }
 
 type CatalogService interface {
-	ListDepartments(ctx context.Context) ([]ec.Department, error)
-	ListCategoriesByDepartment(ctx context.Context, departmentID uint) ([]ec.Category, error)
-	ListProductsByCategory(ctx context.Context, categoryID uint) ([]ec.Product, error)
-	GetProduct(ctx context.Context, id uint) (*ec.Product, error)
-	SearchProducts(ctx context.Context, query string, limit int) ([]ec.Product, error)
-	RecommendProducts(ctx context.Context, productID uint, limit int) ([]ec.Product, error)
+	ListDepartments(ctx context.Context, locale string) ([]ec.Department, error)
+	ListCategoriesByDepartment(ctx context.Context, departmentID uint, locale string) ([]ec.Category, error)
+	ListProductsByCategory(ctx context.Context, categoryID uint, f ec.ProductFilter, page query.Page, locale string) (*query.Result[ec.Product], error)
+	GetProduct(ctx context.Context, id uint, preview bool, locale string) (*ec.Product, error)
+	ListAttributeFacets(ctx context.Context, categoryID uint, locale string) ([]ec.AttributeFacet, error)
+	CompareProducts(ctx context.Context, ids []uint, locale string) (*ec.ProductComparison, error)
+	SearchProducts(ctx context.Context, p ec.ProductSearchParams) (*ec.ProductSearchResult, error)
+	// TranslateProducts overlays the translations of locale on products
+	// loaded elsewhere, e.g. recommendations.
+	TranslateProducts(ctx context.Context, locale string, items []ec.Product) error
+	Resolve(ctx context.Context, kind, ref string) (*ec.SlugRef, error)
+	Sitemap(ctx context.Context) ([]ec.SitemapURL, error)
+	ProductStructuredData(ctx context.Context, id uint, assetBase string) (*ec.ProductLD, error)
 }
 
 type OrdersService interface {
 	CreateOrder(ctx context.Context, in order_dto.CreateOrderInput) (*eo.Order, error)
 	ListUserOrders(ctx context.Context, userID uint) ([]eo.Order, error)
 	GetUserOrder(ctx context.Context, userID, orderID uint) (*eo.Order, error)
-	AdminListOrders(ctx context.Context, status string) ([]eo.Order, error)
+	AdminListOrders(ctx context.Context, f eo.OrderFilter, page query.Page) (*query.Result[eo.Order], error)
 	AdminUpdateOrderStatus(ctx context.Context, orderID uint, status string) error
+	AmendOrder(ctx context.Context, orderID, actorID uint, asAdmin bool, in order_dto.AmendOrderRequest) (*eo.Order, *eo.OrderAmendment, error)
+	ListOrderAmendments(ctx context.Context, orderID uint) ([]eo.OrderAmendment, error)
+	AdminScheduleDelivery(ctx context.Context, orderID uint, date *time.Time) error
 }
 
 type AdminService interface {
 	ListDepartments(ctx context.Context) ([]ec.Department, error)
 	CreateDepartment(ctx context.Context, d *ec.Department) error
 	UpdateDepartment(ctx context.Context, id uint, d ec.Department) error
-	DeleteDepartment(ctx context.Context, id uint) error
+	DeleteDepartment(ctx context.Context, id uint, cascade bool) error
+	ListArchivedDepartments(ctx context.Context) ([]ec.Department, error)
+	RestoreDepartment(ctx context.Context, id uint, cascade bool) error
 	ListCategories(ctx context.Context) ([]ec.Category, error)
 	CreateCategory(ctx context.Context, c *ec.Category) error
 	UpdateCategory(ctx context.Context, id uint, c ec.Category) error
-	DeleteCategory(ctx context.Context, id uint) error
-	ListProducts(ctx context.Context) ([]ec.Product, error)
-	CreateProduct(ctx context.Context, p *ec.Product) error
-	UpdateProduct(ctx context.Context, id uint, p ec.Product) error
+	DeleteCategory(ctx context.Context, id uint, cascade bool) error
+	ListArchivedCategories(ctx context.Context) ([]ec.Category, error)
+	RestoreCategory(ctx context.Context, id uint, cascade bool) error
+	ListProducts(ctx context.Context, f ec.ProductFilter, page query.Page) (*query.Result[ec.Product], error)
+	CreateProduct(ctx context.Context, p *ec.Product, attrs map[string]any) error
+	UpdateProduct(ctx context.Context, id uint, p ec.Product, attrs map[string]any) error
 	DeleteProduct(ctx context.Context, id uint) error
+	RestoreProduct(ctx context.Context, id uint) error
+	GetBundle(ctx context.Context, productID uint) (*ec.Bundle, error)
+	SaveBundle(ctx context.Context, productID uint, b *ec.Bundle) error
+	DeleteBundle(ctx context.Context, productID uint) error
+	ListPriceHistory(ctx context.Context, productID uint, page query.Page) (*query.Result[ec.PriceChange], error)
+	ListPriceSchedules(ctx context.Context, productID uint) ([]ec.PriceSchedule, error)
+	CreatePriceSchedule(ctx context.Context, s *ec.PriceSchedule) error
+	// CancelPriceSchedule drops a schedule that has not started and ends a
+	// running sale.
+	CancelPriceSchedule(ctx context.Context, id uint) error
 	ListProductOptions(ctx context.Context, productID *uint) ([]ec.ProductOption, error)
 	CreateProductOption(ctx context.Context, o *ec.ProductOption) error
 	UpdateProductOption(ctx context.Context, id uint, o ec.ProductOption) error
 	DeleteProductOption(ctx context.Context, id uint) error
+	ListAttributes(ctx context.Context, categoryID uint) ([]ec.AttributeDefinition, error)
+	CreateAttribute(ctx context.Context, d *ec.AttributeDefinition) error
+	UpdateAttribute(ctx context.Context, id uint, d ec.AttributeDefinition) error
+	DeleteAttribute(ctx context.Context, id uint) error
+	ReindexSearch(ctx context.Context) (int, error)
+	PublishDue(ctx context.Context, now time.Time) (int, error)
+	ApplyPriceSchedules(ctx context.Context, now time.Time) (int, error)
+}
+
+// ImageService validates uploads, renders product gallery images and removes
+// files the catalog no longer references.
+type ImageService interface {
+	Upload(ctx context.Context, data []byte) (string, error)
+	ListProductImages(ctx context.Context, productID uint) ([]ec.ProductImage, error)
+	AddProductImage(ctx context.Context, productID uint, data []byte, img ec.ProductImage) (*ec.ProductImage, error)
+	UpdateProductImage(ctx context.Context, id uint, img ec.ProductImage) error
+	ReorderProductImages(ctx context.Context, productID uint, ids []uint) error
+	OriginalURL(ctx context.Context, id uint) (string, error)
+	DeleteProductImage(ctx context.Context, id uint) error
+	CleanupOrphans(ctx context.Context, now time.Time) (int, error)
+}
+
+// CatalogTransferService exports the catalog as a document and imports one,
+// upserting departments and categories by name and products by SKU.
+type CatalogTransferService interface {
+	Export(ctx context.Context) (*ec.CatalogDocument, error)
+	Import(ctx context.Context, doc *ec.CatalogDocument, dryRun bool) (*ec.ImportReport, error)
+}
+
+// TranslationService manages the translations of catalog records into the
+// supported locales other than the default one.
+type TranslationService interface {
+	List(ctx context.Context, kind string, id uint) ([]ec.Translation, error)
+	// Save sets the given fields of a record in a locale; an empty value
+	// removes a field's translation.
+	Save(ctx context.Context, kind string, id uint, locale string, values map[string]string) ([]ec.Translation, error)
+	Delete(ctx context.Context, kind string, id uint, locale string) error
+}
+
+// RecommendationService serves product recommendations: admin pins first,
+// then precomputed associations, then popular products of the same category.
+type RecommendationService interface {
+	Recommend(ctx context.Context, productID uint, kind string, limit int) ([]ec.Product, error)
+	Refresh(ctx context.Context) (*ec.RecommendationRefresh, error)
+	ListOverrides(ctx context.Context, productID *uint) ([]ec.RecommendationOverride, error)
+	SaveOverride(ctx context.Context, o *ec.RecommendationOverride) error
+	DeleteOverride(ctx context.Context, id uint) error
+}
+
+// ReviewService collects customer reviews of products, moderates them and
+// aggregates the approved ones into product ratings.
+type ReviewService interface {
+	ListProductReviews(ctx context.Context, productID uint, f ec.ReviewFilter, page query.Page) (*query.Result[ec.Review], error)
+	RatingSummary(ctx context.Context, productID uint) (*ec.RatingSummary, error)
+	GetUserReview(ctx context.Context, productID, userID uint) (*ec.Review, error)
+	SubmitReview(ctx context.Context, userID uint, r *ec.Review, photos [][]byte) (*ec.Review, error)
+	DeleteUserReview(ctx context.Context, userID, id uint) error
+	ListReviews(ctx context.Context, f ec.ReviewFilter, page query.Page) (*query.Result[ec.Review], error)
+	ModerateReview(ctx context.Context, id uint, status, note string) (*ec.Review, error)
+	DeleteReview(ctx context.Context, id uint) error
+}
+
+// QuestionService runs the product Q&A: customers ask, the shop and
+// customers who received the product answer, and admins moderate both.
+type QuestionService interface {
+	ListProductQuestions(ctx context.Context, productID uint, answered *bool, page query.Page) (*query.Result[ec.Question], error)
+	ListUserQuestions(ctx context.Context, userID uint, page query.Page) (*query.Result[ec.Question], error)
+	Ask(ctx context.Context, userID, productID uint, body string) (*ec.Question, error)
+	Answer(ctx context.Context, userID, questionID uint, body string) (*ec.Answer, error)
+	ListQuestions(ctx context.Context, f ec.QuestionFilter, page query.Page) (*query.Result[ec.Question], error)
+	ModerateQuestion(ctx context.Context, id uint, status string) (*ec.Question, error)
+	DeleteQuestion(ctx context.Context, id uint) error
+	AnswerAsShop(ctx context.Context, adminID, questionID uint, body string) (*ec.Answer, error)
+	ListAnswers(ctx context.Context, status string, page query.Page) (*query.Result[ec.Answer], error)
+	ModerateAnswer(ctx context.Context, id uint, status string) (*ec.Answer, error)
+	DeleteAnswer(ctx context.Context, id uint) error
+}
+
+// AnalyticsService ingests behavior events and reports on their daily
+// aggregates. Track never blocks: events are queued and written in batches
+// by Run.
+type AnalyticsService interface {
+	Track(e ea.Event)
+	Run(ctx context.Context)
+	Rollup(ctx context.Context, now time.Time) error
+	ProductStats(ctx context.Context, f ea.StatsFilter, page query.Page) (*query.Result[ea.ProductStats], error)
+	DailyTotals(ctx context.Context, from, to time.Time, productID uint) ([]ea.DailyTotals, error)
 }
 
 type PaymentService interface {
-	ProcessPaymentResult(ctx context.Context, orderID uint, paymentStatus, orderStatus string) error
+	ProcessPaymentResult(ctx context.Context, orderID uint, paymentStatus, orderStatus, paymentRef string, amount float64) error
+	SettleAmendment(ctx context.Context, amendmentID uint, paid bool, paymentRef string) error
+}
+
+type InvoiceService interface {
+	IssueForOrder(ctx context.Context, orderID uint) (*eo.Invoice, error)
+	IssueForAmendment(ctx context.Context, amendmentID uint) (*eo.Invoice, error)
+	ListOrderInvoices(ctx context.Context, orderID uint) ([]eo.Invoice, error)
+	GetUserOrderInvoicePDF(ctx context.Context, userID, orderID uint) (*eo.Invoice, []byte, error)
+	GetUserInvoicePDF(ctx context.Context, userID, invoiceID uint) (*eo.Invoice, []byte, error)
+	GetInvoicePDF(ctx context.Context, invoiceID uint) (*eo.Invoice, []byte, error)
+	ListInvoices(ctx context.Context, from, to time.Time, kind string) ([]eo.Invoice, error)
+	ExportArchive(ctx context.Context, from, to time.Time, kind string) ([]byte, error)
+}
+
+type NotificationService interface {
+	OrderCreated(ctx context.Context, orderID uint) error
+	PaymentSucceeded(ctx context.Context, orderID uint) error
+	PaymentFailed(ctx context.Context, orderID uint) error
+	OrderStatusChanged(ctx context.Context, orderID uint, status string) error
+	PasswordReset(ctx context.Context, userID uint, resetURL string) error
+	QuestionAnswered(ctx context.Context, q *ec.Question, a *ec.Answer) error
+	// PriceDropped and BackInStock alert a user about a product configuration
+	// they saved: options is its stored option selection and listName the
+	// wishlist it is in.
+	PriceDropped(ctx context.Context, userID uint, p *ec.Product, options, listName string, was float64) error
+	BackInStock(ctx context.Context, userID uint, p *ec.Product, options, listName string) error
+	// SubscriptionAlert emails a product subscriber, with a link to
+	// unsubscribe; was is the price a price drop is measured against.
+	SubscriptionAlert(ctx context.Context, sub *en.ProductSubscription, p *ec.Product, was float64) error
+	// SubscriptionConfirm emails a guest the link confirming a subscription.
+	SubscriptionConfirm(ctx context.Context, sub *en.ProductSubscription, p *ec.Product) error
+	SendDeliveryReminders(ctx context.Context, now time.Time) (int, error)
+	GetPreferences(ctx context.Context, userID uint) ([]string, []en.PushSubscription, error)
+	UpdatePreferences(ctx context.Context, userID uint, channels []string) error
+	AddPushSubscription(ctx context.Context, userID uint, sub *en.PushSubscription) error
+	RemovePushSubscription(ctx context.Context, userID, id uint) error
+	ListTemplates() []templates.Info
+	PreviewTemplate(name, locale string, version int) (*templates.Rendered, error)
+}
+
+type OutboxService interface {
+	Enqueue(ctx context.Context, m *en.OutboxMessage) error
+	DispatchDue(ctx context.Context) (int, error)
+	List(ctx context.Context, status string, limit, offset int) ([]en.OutboxMessage, int64, error)
+	Get(ctx context.Context, id uint) (*en.OutboxMessage, error)
+	Resend(ctx context.Context, id uint) error
 }
 
 type CartService interface {
@@ -65,11 +233,109 @@ type CartService interface {
 	Clear(ctx context.Context, userID uint) error
 }
 
+// WishlistService manages the named wishlists of a user. Wishlist and item
+// methods fail for wishlists owned by someone else.
+type WishlistService interface {
+	ProductWatcher
+	List(ctx context.Context, userID uint) ([]eo.Wishlist, error)
+	Get(ctx context.Context, userID, id uint) (*eo.Wishlist, error)
+	Create(ctx context.Context, userID uint, name string) (*eo.Wishlist, error)
+	Rename(ctx context.Context, userID, id uint, name string) (*eo.Wishlist, error)
+	Delete(ctx context.Context, userID, id uint) error
+	// Share gives a wishlist a share token, or removes it to revoke the link.
+	Share(ctx context.Context, userID, id uint, shared bool) (*eo.Wishlist, error)
+	GetShared(ctx context.Context, token string) (*eo.Wishlist, error)
+	AddItem(ctx context.Context, userID, id uint, in wishlist_dto.WishlistItemRequest) (*eo.WishlistItem, error)
+	UpdateItem(ctx context.Context, userID, id, itemID uint, in wishlist_dto.WishlistItemRequest) error
+	RemoveItem(ctx context.Context, userID, id, itemID uint) error
+	MoveToCart(ctx context.Context, userID, id uint, in wishlist_dto.MoveToCartRequest) (*eo.Cart, error)
+}
+
+// ProductWatcher is told about product changes customers may be waiting
+// for; prev is the product as it was before the change.
+type ProductWatcher interface {
+	ProductChanged(ctx context.Context, prev *ec.Product)
+}
+
+// ProductWatchers passes product changes on to each of its watchers. The
+// watchers share a record of the alerts sent about the change, see
+// FirstAlert.
+type ProductWatchers []ProductWatcher
+
+func (w ProductWatchers) ProductChanged(ctx context.Context, prev *ec.Product) {
+	if _, ok := ctx.Value(alertsKey{}).(*sentAlerts); !ok {
+		ctx = context.WithValue(ctx, alertsKey{}, &sentAlerts{sent: map[string]bool{}})
+	}
+	for _, pw := range w {
+		pw.ProductChanged(ctx, prev)
+	}
+}
+
+type alertsKey struct{}
+
+type sentAlerts struct {
+	mu   sync.Mutex
+	sent map[string]bool
+}
+
+// FirstAlert reports whether a user, or a guest known only by email, is yet
+// to be alerted of kind (en.SubscriptionBackInStock or
+// en.SubscriptionPriceDrop) about the product change being handed to
+// ProductWatchers, and records that they are now. It keeps a customer who
+// both saved and subscribed to a product from getting the same alert twice.
+func FirstAlert(ctx context.Context, kind string, userID uint, email string) bool {
+	alerts, ok := ctx.Value(alertsKey{}).(*sentAlerts)
+	if !ok {
+		return true
+	}
+	key := kind + ":email:" + strings.ToLower(email)
+	if userID != 0 {
+		key = fmt.Sprintf("%s:user:%d", kind, userID)
+	}
+	alerts.mu.Lock()
+	defer alerts.mu.Unlock()
+	if alerts.sent[key] {
+		return false
+	}
+	alerts.sent[key] = true
+	return true
+}
+
+// ProductSubscriptionService manages back-in-stock and price-drop
+// subscriptions of customers and guests, emailing them as products change.
+type ProductSubscriptionService interface {
+	ProductWatcher
+	// Subscribe subscribes a user, or the guest address in the request when
+	// userID is 0. Subscribing twice returns the existing subscription.
+	Subscribe(ctx context.Context, userID, productID uint, in notif_dto.ProductSubscriptionRequest) (*en.ProductSubscription, error)
+	ListUser(ctx context.Context, userID uint) ([]en.ProductSubscription, error)
+	DeleteUser(ctx context.Context, userID, id uint) error
+	// Confirm activates the guest subscription a confirmation link points at.
+	Confirm(ctx context.Context, token string) (*en.ProductSubscription, error)
+	// Unsubscribe ends the subscription an unsubscribe link points at.
+	Unsubscribe(ctx context.Context, token string) (*en.ProductSubscription, error)
+}
+
 type Service struct {
-	Auth    AuthService
-	Catalog CatalogService
-	Orders  OrdersService
-	Admin   AdminService
-	Payment PaymentService
-	Cart    CartService
+	Auth         AuthService
+	Catalog      CatalogService
+	Orders       OrdersService
+	Admin        AdminService
+	Images       ImageService
+	Transfer     CatalogTransferService
+	Translations TranslationService
+	Payment      PaymentService
+	Cart         CartService
+	Wishlists    WishlistService
+	ProductSubs  ProductSubscriptionService
+	Invoice      InvoiceService
+	Notification NotificationService
+	Outbox       OutboxService
+	Recommend    RecommendationService
+	Reviews      ReviewService
+	Questions    QuestionService
+	Analytics    AnalyticsService
+	// Blobs stores uploaded files; handlers use it to recognise URLs of
+	// stored files and to serve signed local downloads.
+	Blobs blob.Store
 }
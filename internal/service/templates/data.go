@@ -0,0 +1,117 @@
+package templates
+
+// OrderLine is a single order line as shown in notifications.
+type OrderLine struct {
+	Name      string
+	Options   string
+	Quantity  int
+	LineTotal float64
+}
+
+// OrderData is the view model shared by the order and payment templates.
+type OrderData struct {
+	CustomerName string
+	OrderID      uint
+	Status       string
+	Total        float64
+	Currency     string
+	ETADays      int
+	Items        []OrderLine
+	OrderURL     string
+	DeliveryDate string
+}
+
+// PasswordResetData is the view model of the password reset template.
+type PasswordResetData struct {
+	CustomerName string
+	ResetURL     string
+	ExpiresIn    string
+}
+
+// QuestionAnsweredData is the view model of the template telling a customer
+// their product question was answered.
+type QuestionAnsweredData struct {
+	CustomerName string
+	ProductName  string
+	Question     string
+	Answer       string
+	AnsweredBy   string
+	ProductURL   string
+}
+
+// ProductAlertData is the view model of the price-drop and back-in-stock
+// templates. ListName names the wishlist the product was saved in, if any;
+// UnsubscribeURL is set for alerts of product subscriptions, which guests
+// receive without a CustomerName.
+type ProductAlertData struct {
+	CustomerName   string
+	ProductName    string
+	Options        string
+	ListName       string
+	OldPrice       float64
+	NewPrice       float64
+	Currency       string
+	ProductURL     string
+	UnsubscribeURL string
+}
+
+// SubscriptionConfirmData is the view model of the email asking a guest to
+// confirm a product subscription. PriceDrop tells the kinds apart.
+type SubscriptionConfirmData struct {
+	ProductName    string
+	Options        string
+	PriceDrop      bool
+	ConfirmURL     string
+	ProductURL     string
+	UnsubscribeURL string
+}
+
+// SampleData returns representative data for previewing a template.
+func SampleData(name string) any {
+	switch name {
+	case PasswordReset:
+		return PasswordResetData{CustomerName: "Maria Ivanova", ResetURL: "http://localhost:5173/reset-password?token=sample", ExpiresIn: "1 hour"}
+	case QuestionAnswered:
+		return QuestionAnsweredData{
+			CustomerName: "Maria Ivanova",
+			ProductName:  "Sofia Sofas 3",
+			Question:     "How well does the fabric hold up with pets?",
+			Answer:       "The upholstery is rated for 60,000 rubs and the covers are removable and washable.",
+			AnsweredBy:   "Furniture Shop",
+			ProductURL:   "http://localhost:5173/product/sofia-sofas-3",
+		}
+	case SubscriptionConfirm:
+		return SubscriptionConfirmData{
+			ProductName:    "Sofia Sofas 3",
+			Options:        "color: Oak, material: Solid Wood",
+			ConfirmURL:     "http://localhost:5173/subscriptions/confirm/sample",
+			ProductURL:     "http://localhost:5173/product/sofia-sofas-3",
+			UnsubscribeURL: "http://localhost:5173/unsubscribe/sample",
+		}
+	case PriceDrop, BackInStock:
+		return ProductAlertData{
+			CustomerName: "Maria Ivanova",
+			ProductName:  "Sofia Sofas 3",
+			Options:      "color: Oak, material: Solid Wood",
+			ListName:     "Living room",
+			OldPrice:     1249.00,
+			NewPrice:     1099.00,
+			Currency:     "EUR",
+			ProductURL:   "http://localhost:5173/product/sofia-sofas-3",
+		}
+	}
+	return OrderData{
+		CustomerName: "Maria Ivanova",
+		OrderID:      1042,
+		Status:       "processing",
+		Total:        1489.00,
+		Currency:     "EUR",
+		ETADays:      14,
+		Items: []OrderLine{
+			{Name: "Sofia Sofas 3", Options: "color: Oak, material: Solid Wood", Quantity: 1, LineTotal: 1249.00},
+			{Name: "Varna Coffee Tables 1", Quantity: 2, LineTotal: 240.00},
+		},
+		OrderURL:     "http://localhost:5173/orders?open=1042",
+		DeliveryDate: "2026-10-20",
+	}
+}
@@ -0,0 +1,227 @@
+// Package templates holds the named, versioned and localized notification
+// templates. Each template lives in files/<name>/<locale>.v<version>.tmpl and
+// defines three blocks: "subject", "text" and "html". The HTML block is wrapped
+// in the shared files/layout.html.tmpl. An optional "short" block holds the
+// one-line body used for SMS and push notifications; it defaults to the subject.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Template names
+const (
+	OrderCreated     = "order_created"
+	PaymentSucceeded = "payment_succeeded"
+	PaymentFailed    = "payment_failed"
+	OrderShipped     = "order_shipped"
+	OrderDelivered   = "order_delivered"
+	PasswordReset    = "password_reset"
+	DeliveryReminder = "delivery_reminder"
+	QuestionAnswered = "question_answered"
+	PriceDrop        = "price_drop"
+	BackInStock      = "back_in_stock"
+
+	SubscriptionConfirm = "subscription_confirm"
+)
+
+const DefaultLocale = "en"
+
+//go:embed files
+var files embed.FS
+
+type Rendered struct {
+	Name    string `json:"name"`
+	Locale  string `json:"locale"`
+	Version int    `json:"version"`
+	Subject string `json:"subject"`
+	Short   string `json:"short"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+type Info struct {
+	Name     string         `json:"name"`
+	Locales  []string       `json:"locales"`
+	Versions map[string]int `json:"latest_versions"`
+}
+
+type variant struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Registry keeps parsed templates indexed by name, locale and version.
+type Registry struct {
+	variants map[string]map[string]map[int]*variant
+}
+
+var funcs = map[string]any{
+	"money": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+}
+
+// Load parses every embedded template.
+func Load() (*Registry, error) {
+	layout, err := fs.ReadFile(files, "files/layout.html.tmpl")
+	if err != nil {
+		return nil, err
+	}
+	r := &Registry{variants: map[string]map[string]map[int]*variant{}}
+	err = fs.WalkDir(files, "files", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Dir(p) == "files" {
+			return err
+		}
+		name := path.Base(path.Dir(p))
+		locale, version, ok := parseFileName(path.Base(p))
+		if !ok {
+			return fmt.Errorf("templates: unexpected file name %s", p)
+		}
+		src, err := fs.ReadFile(files, p)
+		if err != nil {
+			return err
+		}
+		tt, err := texttemplate.New(name).Funcs(funcs).Parse(string(src))
+		if err != nil {
+			return fmt.Errorf("templates: %s: %w", p, err)
+		}
+		ht, err := htmltemplate.New("layout").Funcs(funcs).Parse(string(layout))
+		if err != nil {
+			return err
+		}
+		if _, err := ht.Parse(string(src)); err != nil {
+			return fmt.Errorf("templates: %s: %w", p, err)
+		}
+		if r.variants[name] == nil {
+			r.variants[name] = map[string]map[int]*variant{}
+		}
+		if r.variants[name][locale] == nil {
+			r.variants[name][locale] = map[int]*variant{}
+		}
+		r.variants[name][locale][version] = &variant{text: tt, html: ht}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// MustLoad is Load for package-level initialisation; the templates are
+// embedded, so a failure is a programming error.
+func MustLoad() *Registry {
+	r, err := Load()
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+// parseFileName splits "bg.v2.tmpl" into ("bg", 2).
+func parseFileName(base string) (string, int, bool) {
+	parts := strings.Split(strings.TrimSuffix(base, ".tmpl"), ".")
+	if len(parts) != 2 || !strings.HasPrefix(parts[1], "v") {
+		return "", 0, false
+	}
+	v, err := strconv.Atoi(strings.TrimPrefix(parts[1], "v"))
+	if err != nil || v <= 0 {
+		return "", 0, false
+	}
+	return parts[0], v, true
+}
+
+// Render executes a template. Locale falls back from "bg-BG" to "bg" to the
+// default locale; version 0 selects the latest version of the chosen locale.
+func (r *Registry) Render(name, locale string, version int, data any) (*Rendered, error) {
+	byLocale, ok := r.variants[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown template %q", name)
+	}
+	loc := resolveLocale(byLocale, locale)
+	if loc == "" {
+		return nil, fmt.Errorf("template %q has no %q or default variant", name, locale)
+	}
+	versions := byLocale[loc]
+	if version == 0 {
+		version = latest(versions)
+	}
+	v, ok := versions[version]
+	if !ok {
+		return nil, fmt.Errorf("template %q has no version %d for locale %q", name, version, loc)
+	}
+
+	out := &Rendered{Name: name, Locale: loc, Version: version}
+	var buf bytes.Buffer
+	if err := v.text.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return nil, err
+	}
+	out.Subject = strings.TrimSpace(buf.String())
+	out.Short = out.Subject
+	if v.text.Lookup("short") != nil {
+		buf.Reset()
+		if err := v.text.ExecuteTemplate(&buf, "short", data); err != nil {
+			return nil, err
+		}
+		out.Short = strings.TrimSpace(buf.String())
+	}
+	buf.Reset()
+	if err := v.text.ExecuteTemplate(&buf, "text", data); err != nil {
+		return nil, err
+	}
+	out.Text = strings.TrimSpace(buf.String()) + "\n"
+	buf.Reset()
+	if err := v.html.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return nil, err
+	}
+	out.HTML = buf.String()
+	return out, nil
+}
+
+// List describes the available templates.
+func (r *Registry) List() []Info {
+	out := make([]Info, 0, len(r.variants))
+	for name, byLocale := range r.variants {
+		info := Info{Name: name, Versions: map[string]int{}}
+		for loc, versions := range byLocale {
+			info.Locales = append(info.Locales, loc)
+			info.Versions[loc] = latest(versions)
+		}
+		sort.Strings(info.Locales)
+		out = append(out, info)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func resolveLocale(byLocale map[string]map[int]*variant, locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	candidates := []string{locale}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		candidates = append(candidates, locale[:i])
+	}
+	candidates = append(candidates, DefaultLocale)
+	for _, c := range candidates {
+		if _, ok := byLocale[c]; ok {
+			return c
+		}
+	}
+	return ""
+}
+
+func latest(versions map[int]*variant) int {
+	max := 0
+	for v := range versions {
+		if v > max {
+			max = v
+		}
+	}
+	return max
+}
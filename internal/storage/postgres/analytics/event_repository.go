@@ -0,0 +1,147 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	ea "furniture-shop/internal/entities/analytics"
+	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
+)
+
+type EventRepository struct {
+	db *gorm.DB
+}
+
+func NewEventRepository(db *gorm.DB) storage.EventRepository {
+	return &EventRepository{db: db}
+}
+
+// InsertBatch stores events, skipping those whose dedup key was already
+// recorded, and returns how many were inserted.
+func (r *EventRepository) InsertBatch(ctx context.Context, events []ea.Event) (int64, error) {
+	if len(events) == 0 {
+		return 0, nil
+	}
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dedup_key"}},
+		DoNothing: true,
+	}).CreateInBatches(events, 500)
+	return res.RowsAffected, res.Error
+}
+
+// RollupDay recomputes the daily product stats of the UTC day containing day.
+func (r *EventRepository) RollupDay(ctx context.Context, day time.Time) error {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("day = ?", from).Delete(&ea.DailyProductStat{}).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO daily_product_stats (day, product_id, type, events, sessions, quantity, value)
+SELECT ?::date, product_id, type, COUNT(*), COUNT(DISTINCT session_id), COALESCE(SUM(quantity), 0), COALESCE(SUM(value), 0)
+  FROM events
+ WHERE occurred_at >= ? AND occurred_at < ?
+ GROUP BY product_id, type`, from, from, to).Error
+	})
+}
+
+// RecomputePopularity rewrites recommendation_counters, which drives the
+// popularity sort and the same-category recommendations, from the daily
+// stats since the given day: viewing sessions, cart additions, checkouts and
+// units sold, weighted by how strongly they signal interest.
+func (r *EventRepository) RecomputePopularity(ctx context.Context, since time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM recommendation_counters").Error; err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO recommendation_counters (product_id, count)
+SELECT product_id, ROUND(SUM(CASE type
+    WHEN ? THEN sessions
+    WHEN ? THEN events * 3
+    WHEN ? THEN events * 5
+    WHEN ? THEN quantity * 10
+    ELSE 0 END))::int
+  FROM daily_product_stats
+ WHERE day >= ?
+ GROUP BY product_id`, ea.EventProductViewed, ea.EventAddedToCart, ea.EventCheckoutStarted, ea.EventOrderPaid, since).Error
+	})
+}
+
+func (r *EventRepository) PruneEvents(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).Where("occurred_at < ?", before).Delete(&ea.Event{})
+	return res.RowsAffected, res.Error
+}
+
+var statsSorts = query.Sorts{
+	ea.StatsSortViews:      "views DESC, product_id",
+	ea.StatsSortCart:       "added_to_cart DESC, product_id",
+	ea.StatsSortUnits:      "units DESC, product_id",
+	ea.StatsSortRevenue:    "revenue DESC, product_id",
+	ea.StatsSortConversion: "conversion DESC, views DESC, product_id",
+}
+
+// sumIf sums a daily_product_stats column over the rows of one event type.
+func sumIf(eventType, column, alias string) string {
+	return fmt.Sprintf("COALESCE(SUM(CASE WHEN s.type = '%s' THEN s.%s END), 0) AS %s", eventType, column, alias)
+}
+
+func (r *EventRepository) ProductStats(ctx context.Context, f ea.StatsFilter, page query.Page) ([]ea.ProductStats, int64, error) {
+	order, err := statsSorts.Resolve(f.Sort, ea.StatsSortViews)
+	if err != nil {
+		return nil, 0, err
+	}
+	inner := r.db.Table("daily_product_stats s").
+		Select("s.product_id, p.name, "+
+			sumIf(ea.EventProductViewed, "events", "views")+", "+
+			sumIf(ea.EventProductViewed, "sessions", "view_sessions")+", "+
+			sumIf(ea.EventAddedToCart, "events", "added_to_cart")+", "+
+			sumIf(ea.EventCheckoutStarted, "events", "checkout_started")+", "+
+			sumIf(ea.EventOrderPaid, "sessions", "orders")+", "+
+			sumIf(ea.EventOrderPaid, "quantity", "units")+", "+
+			sumIf(ea.EventOrderPaid, "value", "revenue")).
+		Joins("JOIN products p ON p.id = s.product_id").
+		Where("s.day >= ? AND s.day < ?", f.From, f.To).
+		Group("s.product_id, p.name")
+	if f.ProductID != 0 {
+		inner = inner.Where("s.product_id = ?", f.ProductID)
+	}
+	q := r.db.WithContext(ctx).Table("(?) AS t", inner)
+	var total int64
+	if err := q.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var out []ea.ProductStats
+	err = q.Select("t.*, CASE WHEN view_sessions > 0 THEN orders::float / view_sessions ELSE 0 END AS conversion").
+		Order(order).
+		Scopes(query.Paginate(page)).
+		Scan(&out).Error
+	return out, total, err
+}
+
+// DailyTotals returns one row per day in [from, to), including days without events.
+func (r *EventRepository) DailyTotals(ctx context.Context, from, to time.Time, productID uint) ([]ea.DailyTotals, error) {
+	join := "LEFT JOIN daily_product_stats s ON s.day = d.day"
+	args := []any{from, to}
+	if productID != 0 {
+		join += " AND s.product_id = ?"
+		args = append(args, productID)
+	}
+	sql := `SELECT d.day, ` +
+		sumIf(ea.EventProductViewed, "events", "views") + `, ` +
+		sumIf(ea.EventAddedToCart, "events", "added_to_cart") + `, ` +
+		sumIf(ea.EventCheckoutStarted, "events", "checkout_started") + `, ` +
+		sumIf(ea.EventOrderPaid, "quantity", "units") + `, ` +
+		sumIf(ea.EventOrderPaid, "value", "revenue") + `
+  FROM generate_series(?::date, ?::date - 1, interval '1 day') AS d(day)
+  ` + join + `
+ GROUP BY d.day
+ ORDER BY d.day`
+	var out []ea.DailyTotals
+	err := r.db.WithContext(ctx).Raw(sql, args...).Scan(&out).Error
+	return out, err
+}
@@ -0,0 +1,152 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+)
+
+type ArchiveRepository struct {
+	db *gorm.DB
+}
+
+func NewArchiveRepository(db *gorm.DB) storage.CatalogArchiveRepository {
+	return &ArchiveRepository{db: db}
+}
+
+// Archive sets deleted_at on the record. A department with active
+// categories, or a category with active products, is only archived with
+// cascade, and then together with them under the same timestamp so that a
+// cascading restore can bring back exactly that set. Archived products are
+// taken out of carts; order lines keep pointing at them.
+func (r *ArchiveRepository) Archive(ctx context.Context, kind string, id uint, cascade bool) ([]uint, error) {
+	var productIDs []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		var catIDs []uint
+		switch kind {
+		case ec.SlugDepartment:
+			if err := tx.Model(&ec.Category{}).Where("department_id = ?", id).Pluck("id", &catIDs).Error; err != nil {
+				return err
+			}
+			if len(catIDs) > 0 && !cascade {
+				return &ec.ArchiveBlockedError{Kind: "categories", Children: int64(len(catIDs))}
+			}
+		case ec.SlugCategory:
+			catIDs = []uint{id}
+		case ec.SlugProduct:
+			productIDs = []uint{id}
+		default:
+			return fmt.Errorf("unknown catalog kind %q", kind)
+		}
+		if len(catIDs) > 0 {
+			if err := tx.Model(&ec.Product{}).Where("category_id IN ?", catIDs).Pluck("id", &productIDs).Error; err != nil {
+				return err
+			}
+			if kind == ec.SlugCategory && len(productIDs) > 0 && !cascade {
+				return &ec.ArchiveBlockedError{Kind: "products", Children: int64(len(productIDs))}
+			}
+		}
+		res := tx.Table(slugTables[kind]).Where("id = ? AND deleted_at IS NULL", id).Update("deleted_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if kind != ec.SlugCategory && len(catIDs) > 0 {
+			if err := tx.Model(&ec.Category{}).Where("id IN ?", catIDs).Update("deleted_at", now).Error; err != nil {
+				return err
+			}
+		}
+		if len(productIDs) > 0 {
+			if err := tx.Model(&ec.Product{}).Where("id IN ?", productIDs).Update("deleted_at", now).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("DELETE FROM cart_items WHERE product_id IN ?", productIDs).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return productIDs, err
+}
+
+// Restore clears deleted_at. The parent must be active; with cascade the
+// children archived together with the record come back too.
+func (r *ArchiveRepository) Restore(ctx context.Context, kind string, id uint, cascade bool) ([]uint, error) {
+	var productIDs []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		table, ok := slugTables[kind]
+		if !ok {
+			return fmt.Errorf("unknown catalog kind %q", kind)
+		}
+		var row struct {
+			DeletedAt    time.Time
+			DepartmentID uint
+			CategoryID   uint
+		}
+		cols := "deleted_at"
+		switch kind {
+		case ec.SlugCategory:
+			cols += ", department_id"
+		case ec.SlugProduct:
+			cols += ", category_id"
+		}
+		if err := tx.Table(table).Select(cols).Where("id = ? AND deleted_at IS NOT NULL", id).Take(&row).Error; err != nil {
+			return err
+		}
+		var parentActive int64
+		switch kind {
+		case ec.SlugCategory:
+			if err := tx.Model(&ec.Department{}).Where("id = ?", row.DepartmentID).Count(&parentActive).Error; err != nil {
+				return err
+			}
+		case ec.SlugProduct:
+			if err := tx.Model(&ec.Category{}).Where("id = ?", row.CategoryID).Count(&parentActive).Error; err != nil {
+				return err
+			}
+		default:
+			parentActive = 1
+		}
+		if parentActive == 0 {
+			return ec.ErrParentArchived
+		}
+		if err := tx.Table(table).Where("id = ?", id).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if kind == ec.SlugProduct {
+			productIDs = []uint{id}
+			return nil
+		}
+		if !cascade {
+			return nil
+		}
+		catIDs := []uint{id}
+		if kind == ec.SlugDepartment {
+			catIDs = nil
+			if err := tx.Unscoped().Model(&ec.Category{}).Where("department_id = ? AND deleted_at = ?", id, row.DeletedAt).Pluck("id", &catIDs).Error; err != nil {
+				return err
+			}
+			if len(catIDs) == 0 {
+				return nil
+			}
+			if err := tx.Unscoped().Model(&ec.Category{}).Where("id IN ?", catIDs).Update("deleted_at", nil).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Unscoped().Model(&ec.Product{}).Where("category_id IN ? AND deleted_at = ?", catIDs, row.DeletedAt).Pluck("id", &productIDs).Error; err != nil {
+			return err
+		}
+		if len(productIDs) == 0 {
+			return nil
+		}
+		return tx.Unscoped().Model(&ec.Product{}).Where("id IN ?", productIDs).Update("deleted_at", nil).Error
+	})
+	return productIDs, err
+}
@@ -0,0 +1,109 @@
+package catalog
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+)
+
+type AttributeRepository struct {
+	db *gorm.DB
+}
+
+func NewAttributeRepository(db *gorm.DB) storage.AttributeRepository {
+	return &AttributeRepository{db: db}
+}
+
+func (r *AttributeRepository) ListByCategory(ctx context.Context, categoryID uint) ([]ec.AttributeDefinition, error) {
+	var items []ec.AttributeDefinition
+	err := r.db.WithContext(ctx).Where("category_id = ?", categoryID).Order("position, id").Find(&items).Error
+	return items, err
+}
+
+func (r *AttributeRepository) FindByID(ctx context.Context, id uint) (*ec.AttributeDefinition, error) {
+	var d ec.AttributeDefinition
+	if err := r.db.WithContext(ctx).First(&d, id).Error; err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *AttributeRepository) Create(ctx context.Context, d *ec.AttributeDefinition) error {
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+func (r *AttributeRepository) Update(ctx context.Context, id uint, d ec.AttributeDefinition) error {
+	return r.db.WithContext(ctx).Model(&ec.AttributeDefinition{}).Where("id = ?", id).
+		Select("code", "name", "type", "unit", "choices", "required", "filterable", "position").
+		Updates(&d).Error
+}
+
+// Delete removes a definition together with the product values using it.
+func (r *AttributeRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("attribute_id = ?", id).Delete(&ec.ProductAttribute{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&ec.AttributeDefinition{}, id).Error
+	})
+}
+
+func (r *AttributeRepository) CountValues(ctx context.Context, id uint) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&ec.ProductAttribute{}).Where("attribute_id = ?", id).Count(&n).Error
+	return n, err
+}
+
+// Facets counts the values of the filterable attributes of a category over
+// its published products.
+func (r *AttributeRepository) Facets(ctx context.Context, categoryID uint) ([]ec.AttributeFacet, error) {
+	var defs []ec.AttributeDefinition
+	if err := r.db.WithContext(ctx).Where("category_id = ? AND filterable", categoryID).Order("position, id").Find(&defs).Error; err != nil {
+		return nil, err
+	}
+	if len(defs) == 0 {
+		return []ec.AttributeFacet{}, nil
+	}
+	ids := make([]uint, len(defs))
+	for i, d := range defs {
+		ids[i] = d.ID
+	}
+	var rows []struct {
+		AttributeID uint
+		Value       string
+		Number      *float64
+		Count       int64
+	}
+	err := r.db.WithContext(ctx).Table("product_attributes pa").
+		Select("pa.attribute_id, pa.value, pa.number, COUNT(*) AS count").
+		Joins("JOIN products p ON p.id = pa.product_id AND p.deleted_at IS NULL AND p.status = ?", ec.ProductPublished).
+		Where("pa.attribute_id IN ? AND p.category_id = ?", ids, categoryID).
+		Group("pa.attribute_id, pa.value, pa.number").
+		Order("count DESC, pa.value").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ec.AttributeFacet, len(defs))
+	byID := make(map[uint]*ec.AttributeFacet, len(defs))
+	for i, d := range defs {
+		out[i] = ec.AttributeFacet{AttributeDefinition: d, Values: []ec.FacetCount{}}
+		byID[d.ID] = &out[i]
+	}
+	for _, row := range rows {
+		f := byID[row.AttributeID]
+		f.Values = append(f.Values, ec.FacetCount{Value: row.Value, Label: row.Value, Count: row.Count})
+		if n := row.Number; n != nil {
+			if f.Min == nil || *n < *f.Min {
+				f.Min = n
+			}
+			if f.Max == nil || *n > *f.Max {
+				f.Max = n
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,79 @@
+package catalog
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+)
+
+type BundleRepository struct {
+	db *gorm.DB
+}
+
+func NewBundleRepository(db *gorm.DB) storage.BundleRepository {
+	return &BundleRepository{db: db}
+}
+
+// Find returns the bundle of a product with its components and their
+// options.
+func (r *BundleRepository) Find(ctx context.Context, productID uint) (*ec.Bundle, error) {
+	var b ec.Bundle
+	err := r.db.WithContext(ctx).
+		Preload("Components", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }).
+		Preload("Components.Product.Options").
+		First(&b, "product_id = ?", productID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// ListContaining returns the IDs of the bundles that have the product as
+// a component.
+func (r *BundleRepository) ListContaining(ctx context.Context, productID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&ec.BundleComponent{}).
+		Where("product_id = ?", productID).
+		Distinct().Order("bundle_id").Pluck("bundle_id", &ids).Error
+	return ids, err
+}
+
+// Save creates or replaces the bundle of b.ProductID and its components.
+func (r *BundleRepository) Save(ctx context.Context, b *ec.Bundle) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row := ec.Bundle{ProductID: b.ProductID, Pricing: b.Pricing, DiscountPercent: b.DiscountPercent}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"pricing", "discount_percent", "updated_at"}),
+		}).Create(&row).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("bundle_id = ?", b.ProductID).Delete(&ec.BundleComponent{}).Error; err != nil {
+			return err
+		}
+		components := make([]ec.BundleComponent, len(b.Components))
+		for i, c := range b.Components {
+			components[i] = ec.BundleComponent{BundleID: b.ProductID, ProductID: c.ProductID, Quantity: c.Quantity, OptionIDs: c.OptionIDs, Position: c.Position}
+		}
+		return tx.Create(&components).Error
+	})
+}
+
+// Delete turns a bundle back into a plain product.
+func (r *BundleRepository) Delete(ctx context.Context, productID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("bundle_id = ?", productID).Delete(&ec.BundleComponent{}).Error; err != nil {
+			return err
+		}
+		res := tx.Where("product_id = ?", productID).Delete(&ec.Bundle{})
+		if res.Error == nil && res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return res.Error
+	})
+}
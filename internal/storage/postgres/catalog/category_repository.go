@@ -33,16 +33,47 @@ func (r *CategoryRepository) ListAll(ctx context.Context) ([]ec.Category, error)
 	return out, nil
 }
 
+func (r *CategoryRepository) ListArchived(ctx context.Context) ([]ec.Category, error) {
+	var out []ec.Category
+	if err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (r *CategoryRepository) Create(ctx context.Context, c *ec.Category) error {
-	return r.db.WithContext(ctx).Create(c).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createCategory(tx, c)
+	})
 }
 
 func (r *CategoryRepository) Update(ctx context.Context, id uint, c ec.Category) error {
-	return r.db.WithContext(ctx).Model(&ec.Category{}).Where("id = ?", id).
-		Select("name", "department_id").
-		Updates(c).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateCategory(tx, id, c)
+	})
 }
 
-func (r *CategoryRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&ec.Category{}, id).Error
+func createCategory(tx *gorm.DB, c *ec.Category) error {
+	requested := c.Slug
+	c.Slug = ""
+	if err := tx.Create(c).Error; err != nil {
+		return err
+	}
+	slug, err := assignSlug(tx, ec.SlugCategory, c.ID, requested, c.Name, false)
+	c.Slug = slug
+	return err
+}
+
+func updateCategory(tx *gorm.DB, id uint, c ec.Category) error {
+	renamed, err := renamedSince(tx, ec.SlugCategory, id, c.Name)
+	if err != nil {
+		return err
+	}
+	if err := tx.Model(&ec.Category{}).Where("id = ?", id).
+		Select("name", "department_id", "description", "seo_title", "seo_description").
+		Updates(c).Error; err != nil {
+		return err
+	}
+	_, err = assignSlug(tx, ec.SlugCategory, id, c.Slug, c.Name, renamed)
+	return err
 }
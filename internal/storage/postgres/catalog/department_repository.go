@@ -25,16 +25,47 @@ func (r *DepartmentRepository) List(ctx context.Context) ([]ec.Department, error
 	return out, nil
 }
 
+func (r *DepartmentRepository) ListArchived(ctx context.Context) ([]ec.Department, error) {
+	var out []ec.Department
+	if err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (r *DepartmentRepository) Create(ctx context.Context, d *ec.Department) error {
-	return r.db.WithContext(ctx).Create(d).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createDepartment(tx, d)
+	})
 }
 
 func (r *DepartmentRepository) Update(ctx context.Context, id uint, d ec.Department) error {
-	return r.db.WithContext(ctx).Model(&ec.Department{}).Where("id = ?", id).
-		Select("name").
-		Updates(d).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateDepartment(tx, id, d)
+	})
 }
 
-func (r *DepartmentRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&ec.Department{}, id).Error
+func createDepartment(tx *gorm.DB, d *ec.Department) error {
+	requested := d.Slug
+	d.Slug = ""
+	if err := tx.Create(d).Error; err != nil {
+		return err
+	}
+	slug, err := assignSlug(tx, ec.SlugDepartment, d.ID, requested, d.Name, false)
+	d.Slug = slug
+	return err
+}
+
+func updateDepartment(tx *gorm.DB, id uint, d ec.Department) error {
+	renamed, err := renamedSince(tx, ec.SlugDepartment, id, d.Name)
+	if err != nil {
+		return err
+	}
+	if err := tx.Model(&ec.Department{}).Where("id = ?", id).
+		Select("name", "description", "image_url", "seo_title", "seo_description").
+		Updates(d).Error; err != nil {
+		return err
+	}
+	_, err = assignSlug(tx, ec.SlugDepartment, id, d.Slug, d.Name, renamed)
+	return err
 }
@@ -0,0 +1,225 @@
+package catalog
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
+)
+
+type PriceRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceRepository(db *gorm.DB) storage.PriceRepository {
+	return &PriceRepository{db: db}
+}
+
+func (r *PriceRepository) ListHistory(ctx context.Context, productID uint, page query.Page) ([]ec.PriceChange, int64, error) {
+	q := r.db.WithContext(ctx).Model(&ec.PriceChange{}).Where("product_id = ?", productID)
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var out []ec.PriceChange
+	err := q.Order("created_at DESC, id DESC").Scopes(query.Paginate(page)).Find(&out).Error
+	return out, total, err
+}
+
+func (r *PriceRepository) ListSchedules(ctx context.Context, productID uint) ([]ec.PriceSchedule, error) {
+	var out []ec.PriceSchedule
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("starts_at, id").Find(&out).Error
+	return out, err
+}
+
+func (r *PriceRepository) FindSchedule(ctx context.Context, id uint) (*ec.PriceSchedule, error) {
+	var s ec.PriceSchedule
+	if err := r.db.WithContext(ctx).First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *PriceRepository) CreateSchedule(ctx context.Context, s *ec.PriceSchedule) error {
+	s.Status = ec.PriceSchedulePending
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+func (r *PriceRepository) DeleteSchedule(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Where("status = ?", ec.PriceSchedulePending).Delete(&ec.PriceSchedule{}, id)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
+
+func (r *PriceRepository) ListDue(ctx context.Context, now time.Time) ([]ec.PriceSchedule, error) {
+	var out []ec.PriceSchedule
+	err := r.db.WithContext(ctx).
+		Where("(status = ? AND starts_at <= ?) OR (status = ? AND ends_at <= ?)", ec.PriceSchedulePending, now, ec.PriceScheduleActive, now).
+		Order("starts_at, id").
+		Find(&out).Error
+	return out, err
+}
+
+// ApplySchedule carries out the next step of a schedule in one transaction:
+// a pending change sets its price, a pending sale starts, unless its end has
+// passed already or its price is no longer below the base price, and an
+// active sale ends. A change of an option that no longer exists is dropped.
+// Every price it sets is recorded. A schedule another instance got to first
+// is left alone.
+func (r *PriceRepository) ApplySchedule(ctx context.Context, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var s ec.PriceSchedule
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ?", []string{ec.PriceSchedulePending, ec.PriceScheduleActive}).
+			First(&s, id).Error
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		status := ec.PriceScheduleDone
+		switch {
+		case s.Kind == ec.PriceScheduleChange && s.OptionID != nil:
+			err := trackOptionPrice(tx, *s.OptionID, "", s.Price, ec.PriceChangeScheduled, &s.ID)
+			if err == gorm.ErrRecordNotFound {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&ec.ProductOption{}).Where("id = ?", *s.OptionID).Update("price_modifier_value", s.Price).Error; err != nil {
+				return err
+			}
+		case s.Kind == ec.PriceScheduleChange:
+			if err := trackProductPrice(tx, s.ProductID, s.Price, ec.PriceChangeScheduled, &s.ID); err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Model(&ec.Product{}).Where("id = ?", s.ProductID).Update("base_price", s.Price).Error; err != nil {
+				return err
+			}
+			if err := endSaleAbove(tx, s.ProductID, s.Price, now); err != nil {
+				return err
+			}
+		case s.Status == ec.PriceSchedulePending:
+			var p ec.Product
+			if err := tx.Unscoped().Select("id", "base_price", "sale_price").First(&p, s.ProductID).Error; err != nil {
+				return err
+			}
+			if !s.EndsAt.After(now) || !isBelow(s.Price, p.BasePrice) {
+				break
+			}
+			change := ec.PriceChange{ProductID: s.ProductID, OldPrice: p.Price(), NewPrice: s.Price, Reason: ec.PriceChangeSaleStart, ScheduleID: &s.ID}
+			if err := tx.Create(&change).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Model(&ec.Product{}).Where("id = ?", s.ProductID).
+				Updates(map[string]any{"sale_price": s.Price, "sale_ends_at": s.EndsAt}).Error; err != nil {
+				return err
+			}
+			status = ec.PriceScheduleActive
+		default:
+			var p ec.Product
+			if err := tx.Unscoped().Select("id", "base_price", "sale_price").First(&p, s.ProductID).Error; err != nil {
+				return err
+			}
+			change := ec.PriceChange{ProductID: s.ProductID, OldPrice: p.Price(), NewPrice: p.BasePrice, Reason: ec.PriceChangeSaleEnd, ScheduleID: &s.ID}
+			if err := tx.Create(&change).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Model(&ec.Product{}).Where("id = ?", s.ProductID).
+				Updates(map[string]any{"sale_price": nil, "sale_ends_at": nil}).Error; err != nil {
+				return err
+			}
+			if s.EndsAt.After(now) {
+				// cancelled while running
+				s.EndsAt = &now
+			}
+		}
+		return tx.Model(&s).Updates(map[string]any{"status": status, "ends_at": s.EndsAt}).Error
+	})
+}
+
+// endSaleAbove ends the running sale of a product early once its price is
+// no longer below the product's new base price.
+func endSaleAbove(tx *gorm.DB, productID uint, basePrice float64, now time.Time) error {
+	var p ec.Product
+	if err := tx.Unscoped().Select("id", "sale_price").First(&p, productID).Error; err != nil {
+		return err
+	}
+	if p.SalePrice == nil || isBelow(*p.SalePrice, basePrice) {
+		return nil
+	}
+	var running []ec.PriceSchedule
+	if err := tx.Where("product_id = ? AND kind = ? AND status = ?", productID, ec.PriceScheduleSale, ec.PriceScheduleActive).Find(&running).Error; err != nil {
+		return err
+	}
+	change := ec.PriceChange{ProductID: productID, OldPrice: *p.SalePrice, NewPrice: basePrice, Reason: ec.PriceChangeSaleEnd}
+	if len(running) > 0 {
+		change.ScheduleID = &running[0].ID
+	}
+	if err := tx.Create(&change).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&ec.Product{}).Where("id = ?", productID).
+		Updates(map[string]any{"sale_price": nil, "sale_ends_at": nil}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&ec.PriceSchedule{}).Where("product_id = ? AND kind = ? AND status = ?", productID, ec.PriceScheduleSale, ec.PriceScheduleActive).
+		Updates(map[string]any{"status": ec.PriceScheduleDone, "ends_at": now}).Error
+}
+
+// trackProductPrice records the change of a product's stored base price to
+// price, if it changes; it runs before the update, in its transaction.
+func trackProductPrice(tx *gorm.DB, productID uint, price float64, reason string, scheduleID *uint) error {
+	var p ec.Product
+	if err := tx.Unscoped().Select("id", "base_price").First(&p, productID).Error; err != nil {
+		return err
+	}
+	if samePrice(p.BasePrice, price) {
+		return nil
+	}
+	return tx.Create(&ec.PriceChange{ProductID: productID, OldPrice: p.BasePrice, NewPrice: price, Reason: reason, ScheduleID: scheduleID}).Error
+}
+
+// trackOptionPrice records the change of an option's stored price modifier,
+// if its type or value changes; an empty modifierType keeps the type. It
+// runs before the update, in its transaction.
+func trackOptionPrice(tx *gorm.DB, optionID uint, modifierType string, value float64, reason string, scheduleID *uint) error {
+	var o ec.ProductOption
+	if err := tx.First(&o, optionID).Error; err != nil {
+		return err
+	}
+	if modifierType == "" {
+		modifierType = o.PriceModifierType
+	}
+	if o.PriceModifierType == modifierType && samePrice(o.PriceModifierValue, value) {
+		return nil
+	}
+	return tx.Create(&ec.PriceChange{
+		ProductID:    o.ProductID,
+		OptionID:     &o.ID,
+		ModifierType: modifierType,
+		OldPrice:     o.PriceModifierValue,
+		NewPrice:     value,
+		Reason:       reason,
+		ScheduleID:   scheduleID,
+	}).Error
+}
+
+func samePrice(a, b float64) bool {
+	return math.Abs(a-b) < 0.005
+}
+
+// isBelow reports whether price a is at least a cent below b.
+func isBelow(a, b float64) bool {
+	return a < b-0.005
+}
@@ -0,0 +1,111 @@
+package catalog
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+)
+
+type ProductImageRepository struct {
+	db *gorm.DB
+}
+
+func NewProductImageRepository(db *gorm.DB) storage.ProductImageRepository {
+	return &ProductImageRepository{db: db}
+}
+
+func (r *ProductImageRepository) ListByProduct(ctx context.Context, productID uint) ([]ec.ProductImage, error) {
+	var items []ec.ProductImage
+	err := r.db.WithContext(ctx).Preload("Renditions").
+		Where("product_id = ?", productID).
+		Order("position, id").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *ProductImageRepository) FindByID(ctx context.Context, id uint) (*ec.ProductImage, error) {
+	var img ec.ProductImage
+	if err := r.db.WithContext(ctx).Preload("Renditions").First(&img, id).Error; err != nil {
+		return nil, err
+	}
+	return &img, nil
+}
+
+// Create stores the image together with its renditions.
+func (r *ProductImageRepository) Create(ctx context.Context, img *ec.ProductImage) error {
+	return r.db.WithContext(ctx).Create(img).Error
+}
+
+func (r *ProductImageRepository) Update(ctx context.Context, id uint, img ec.ProductImage) error {
+	return r.db.WithContext(ctx).Model(&ec.ProductImage{}).Where("id = ?", id).
+		Select("alt_text", "option_id").
+		Updates(img).Error
+}
+
+// Reorder sets the positions of the product's images to the order of ids.
+func (r *ProductImageRepository) Reorder(ctx context.Context, productID uint, ids []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, id := range ids {
+			if err := tx.Model(&ec.ProductImage{}).
+				Where("id = ? AND product_id = ?", id, productID).
+				Update("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *ProductImageRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&ec.ProductImage{}, id).Error
+}
+
+// SetCover sets the image shown for the product in listings.
+func (r *ProductImageRepository) SetCover(ctx context.Context, productID uint, url string) error {
+	return r.db.WithContext(ctx).Model(&ec.Product{}).Where("id = ?", productID).
+		UpdateColumn("image_url", url).Error
+}
+
+// ReferencedURLs lists every file URL the catalog and reviews point to.
+func (r *ProductImageRepository) ReferencedURLs(ctx context.Context) ([]string, error) {
+	var urls []string
+	err := r.db.WithContext(ctx).Raw(`SELECT url FROM image_renditions
+UNION SELECT original_url FROM product_images
+UNION SELECT image_url FROM products WHERE image_url <> ''
+UNION SELECT image_url FROM departments WHERE image_url <> ''
+UNION SELECT url FROM review_photos`).Scan(&urls).Error
+	return urls, err
+}
+
+// ListOriginalURLs lists the distinct original upload URLs of all galleries.
+func (r *ProductImageRepository) ListOriginalURLs(ctx context.Context) ([]string, error) {
+	var urls []string
+	err := r.db.WithContext(ctx).Model(&ec.ProductImage{}).Distinct().Pluck("original_url", &urls).Error
+	return urls, err
+}
+
+// ReplaceURL points every reference to from at to instead, e.g. after moving
+// a file to another store.
+func (r *ProductImageRepository) ReplaceURL(ctx context.Context, from, to string) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, u := range []struct{ table, column string }{
+			{"image_renditions", "url"},
+			{"product_images", "original_url"},
+			{"products", "image_url"},
+			{"departments", "image_url"},
+			{"review_photos", "url"},
+		} {
+			res := tx.Table(u.table).Where(u.column+" = ?", from).UpdateColumn(u.column, to)
+			if res.Error != nil {
+				return res.Error
+			}
+			n += res.RowsAffected
+		}
+		return nil
+	})
+	return n, err
+}
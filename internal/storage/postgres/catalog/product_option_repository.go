@@ -29,16 +29,37 @@ func (r *ProductOptionRepository) List(ctx context.Context, productID *uint) ([]
 	return items, nil
 }
 
+func (r *ProductOptionRepository) FindByID(ctx context.Context, id uint) (*ec.ProductOption, error) {
+	var o ec.ProductOption
+	if err := r.db.WithContext(ctx).First(&o, id).Error; err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
 func (r *ProductOptionRepository) Create(ctx context.Context, o *ec.ProductOption) error {
 	return r.db.WithContext(ctx).Create(o).Error
 }
 
+// Update records a change of the option's price modifier in the price
+// history.
 func (r *ProductOptionRepository) Update(ctx context.Context, id uint, o ec.ProductOption) error {
-	return r.db.WithContext(ctx).Model(&ec.ProductOption{}).Where("id = ?", id).
-		Select("product_id", "option_type", "option_name", "price_modifier_type", "price_modifier_value", "production_time_modifier_days", "production_time_modifier_percent").
-		Updates(o).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := trackOptionPrice(tx, id, o.PriceModifierType, o.PriceModifierValue, ec.PriceChangeManual, nil); err != nil {
+			return err
+		}
+		return tx.Model(&ec.ProductOption{}).Where("id = ?", id).
+			Select("product_id", "option_type", "option_name", "price_modifier_type", "price_modifier_value", "production_time_modifier_days", "production_time_modifier_percent").
+			Updates(o).Error
+	})
 }
 
+// Delete removes an option along with its pending price schedules.
 func (r *ProductOptionRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&ec.ProductOption{}, id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("option_id = ? AND status = ?", id, ec.PriceSchedulePending).Delete(&ec.PriceSchedule{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&ec.ProductOption{}, id).Error
+	})
 }
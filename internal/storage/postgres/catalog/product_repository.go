@@ -2,11 +2,14 @@ package catalog
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	ec "furniture-shop/internal/entities/catalog"
 	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
 )
 
 type ProductRepository struct {
@@ -17,39 +20,177 @@ func NewProductRepository(db *gorm.DB) storage.ProductRepository {
 	return &ProductRepository{db: db}
 }
 
-func (r *ProductRepository) ListByCategory(ctx context.Context, categoryID uint) ([]ec.Product, error) {
+var productSorts = query.Sorts{
+	ec.ProductSortNewest:     "products.created_at DESC, products.id DESC",
+	ec.ProductSortPriceAsc:   "COALESCE(products.sale_price, products.base_price) ASC, products.id",
+	ec.ProductSortPriceDesc:  "COALESCE(products.sale_price, products.base_price) DESC, products.id",
+	ec.ProductSortPopularity: "COALESCE(rc.count, 0) DESC, products.id",
+	ec.ProductSortName:       "products.name ASC, products.id",
+	ec.ProductSortRating:     "products.rating_average DESC, products.rating_count DESC, products.id",
+}
+
+// List returns one page of products matching f and the total number of matches.
+func (r *ProductRepository) List(ctx context.Context, f ec.ProductFilter, page query.Page) ([]ec.Product, int64, error) {
+	order, err := productSorts.Resolve(f.Sort, ec.ProductSortNewest)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	if err := r.filtered(ctx, f).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
 	var out []ec.Product
-	if err := r.db.WithContext(ctx).Where("category_id = ?", categoryID).Find(&out).Error; err != nil {
-		return nil, err
+	err = r.filtered(ctx, f).
+		Joins("LEFT JOIN recommendation_counters rc ON rc.product_id = products.id").
+		Order(order).
+		Scopes(query.Paginate(page), preloadBundle).
+		Find(&out).Error
+	applyBundles(out)
+	return out, total, err
+}
+
+func (r *ProductRepository) filtered(ctx context.Context, f ec.ProductFilter) *gorm.DB {
+	q := r.db.WithContext(ctx).Model(&ec.Product{})
+	if f.Archived {
+		q = q.Unscoped().Where("products.deleted_at IS NOT NULL")
+	}
+	if f.Status != "" {
+		q = q.Where("products.status = ?", f.Status)
+	}
+	if f.CategoryID != 0 {
+		q = q.Where("products.category_id = ?", f.CategoryID)
+	}
+	if f.PriceMin != nil {
+		q = q.Where("COALESCE(products.sale_price, products.base_price) >= ?", *f.PriceMin)
+	}
+	if f.PriceMax != nil {
+		q = q.Where("COALESCE(products.sale_price, products.base_price) <= ?", *f.PriceMax)
+	}
+	if f.Material != "" {
+		q = q.Where("lower(products.base_material) = lower(?)", f.Material)
+	}
+	if f.RatingMin != nil {
+		q = q.Where("products.rating_average >= ?", *f.RatingMin)
+	}
+	if f.InStock != nil {
+		if *f.InStock {
+			q = q.Where("products.quantity > 0")
+		} else {
+			q = q.Where("products.quantity <= 0")
+		}
+	}
+	for column, rng := range map[string]ec.IntRange{"default_width": f.Width, "default_height": f.Height, "default_depth": f.Depth} {
+		if rng.Min != nil {
+			q = q.Where("products."+column+" >= ?", *rng.Min)
+		}
+		if rng.Max != nil {
+			q = q.Where("products."+column+" <= ?", *rng.Max)
+		}
+	}
+	for _, o := range f.Options {
+		if o.Type != "" {
+			q = q.Where("EXISTS (SELECT 1 FROM product_options po WHERE po.product_id = products.id AND po.option_type = ? AND lower(po.option_name) = lower(?))", o.Type, o.Name)
+		} else {
+			q = q.Where("EXISTS (SELECT 1 FROM product_options po WHERE po.product_id = products.id AND lower(po.option_name) = lower(?))", o.Name)
+		}
+	}
+	for _, a := range f.Attributes {
+		sub := "SELECT 1 FROM product_attributes pa JOIN attribute_definitions ad ON ad.id = pa.attribute_id WHERE pa.product_id = products.id AND ad.category_id = products.category_id AND ad.code = ?"
+		args := []any{a.Code}
+		if a.Min != nil || a.Max != nil {
+			if a.Min != nil {
+				sub += " AND pa.number >= ?"
+				args = append(args, *a.Min)
+			}
+			if a.Max != nil {
+				sub += " AND pa.number <= ?"
+				args = append(args, *a.Max)
+			}
+		} else {
+			sub += " AND lower(pa.value) = lower(?)"
+			args = append(args, a.Value)
+		}
+		q = q.Where("EXISTS ("+sub+")", args...)
+	}
+	return q
+}
+
+// preloadAttributes loads the attribute values of products with their
+// definitions, in the order of the category's attributes.
+func preloadAttributes(db *gorm.DB) *gorm.DB {
+	return db.Preload("Attributes", func(db *gorm.DB) *gorm.DB {
+		return db.Select("product_attributes.*").
+			Joins("JOIN attribute_definitions ad ON ad.id = product_attributes.attribute_id").
+			Order("ad.position, ad.id")
+	}).Preload("Attributes.Attribute")
+}
+
+// preloadBundle loads the components of bundle products with their options,
+// which applyBundles needs to price them.
+func preloadBundle(db *gorm.DB) *gorm.DB {
+	return db.Preload("Bundle.Components", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }).
+		Preload("Bundle.Components.Product.Options")
+}
+
+func applyBundles(items []ec.Product) {
+	for i := range items {
+		items[i].ApplyBundle()
 	}
-	return out, nil
 }
 
 func (r *ProductRepository) FindByID(ctx context.Context, id uint) (*ec.Product, error) {
+	return r.find(r.db.WithContext(ctx), id)
+}
+
+// FindIncludingArchived also finds archived products, for order history and
+// documents describing past orders.
+func (r *ProductRepository) FindIncludingArchived(ctx context.Context, id uint) (*ec.Product, error) {
+	return r.find(r.db.WithContext(ctx).Unscoped(), id)
+}
+
+func (r *ProductRepository) find(db *gorm.DB, id uint) (*ec.Product, error) {
 	var p ec.Product
-	if err := r.db.WithContext(ctx).Preload("Options").First(&p, id).Error; err != nil {
+	err := db.Preload("Options").
+		Scopes(preloadAttributes, preloadBundle).
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }).
+		Preload("Images.Renditions").
+		First(&p, id).Error
+	if err != nil {
 		return nil, err
 	}
+	p.ApplyBundle()
 	return &p, nil
 }
 
-func (r *ProductRepository) Search(ctx context.Context, query string, limit int) ([]ec.Product, error) {
+// ListByIDs loads products with their options, attributes and bundles in the order
+// of ids, in one batch; unknown ids are skipped.
+func (r *ProductRepository) ListByIDs(ctx context.Context, ids []uint) ([]ec.Product, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
 	var items []ec.Product
-	like := "%" + query + "%"
-	if err := r.db.WithContext(ctx).
-		Where("name ILIKE ? OR short_description ILIKE ?", like, like).
-		Limit(limit).
-		Find(&items).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Options").Scopes(preloadAttributes, preloadBundle).Where("id IN ?", ids).Find(&items).Error; err != nil {
 		return nil, err
 	}
-	return items, nil
+	applyBundles(items)
+	byID := make(map[uint]ec.Product, len(items))
+	for _, p := range items {
+		byID[p.ID] = p
+	}
+	out := make([]ec.Product, 0, len(items))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			out = append(out, p)
+		}
+	}
+	return out, nil
 }
 
 func (r *ProductRepository) ListRecommendations(ctx context.Context, p *ec.Product, limit int) ([]ec.Product, error) {
 	var rec []ec.Product
 	q := r.db.WithContext(ctx).Model(&ec.Product{}).
 		Joins("LEFT JOIN recommendation_counters rc ON rc.product_id = products.id").
-		Where("products.category_id = ? AND products.id <> ?", p.CategoryID, p.ID).
+		Where("products.category_id = ? AND products.id <> ? AND products.status = ?", p.CategoryID, p.ID, ec.ProductPublished).
 		Order("COALESCE(rc.count,0) DESC, products.id ASC").
 		Limit(limit)
 	if err := q.Find(&rec).Error; err != nil {
@@ -60,39 +201,115 @@ func (r *ProductRepository) ListRecommendations(ctx context.Context, p *ec.Produ
 
 func (r *ProductRepository) ListAll(ctx context.Context) ([]ec.Product, error) {
 	var items []ec.Product
-	if err := r.db.WithContext(ctx).Find(&items).Error; err != nil {
+	if err := r.db.WithContext(ctx).Scopes(preloadBundle).Find(&items).Error; err != nil {
 		return nil, err
 	}
+	applyBundles(items)
 	return items, nil
 }
 
 func (r *ProductRepository) Create(ctx context.Context, p *ec.Product) error {
-	return r.db.WithContext(ctx).Create(p).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createProduct(tx, p)
+	})
 }
 
 func (r *ProductRepository) Update(ctx context.Context, id uint, p ec.Product) error {
-	return r.db.WithContext(ctx).Model(&ec.Product{}).Where("id = ?", id).
-		Select("name", "short_description", "long_description", "base_price", "base_production_time_days", "category_id", "image_url",
-			"default_width", "default_height", "default_depth", "base_material", "quantity").
-		Updates(p).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return updateProduct(tx, id, p, ec.PriceChangeManual)
+	})
 }
 
-func (r *ProductRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&ec.Product{}, id).Error
+func createProduct(tx *gorm.DB, p *ec.Product) error {
+	requested := p.Slug
+	p.Slug = ""
+	if err := tx.Create(p).Error; err != nil {
+		return err
+	}
+	slug, err := assignSlug(tx, ec.SlugProduct, p.ID, requested, p.Name, false)
+	p.Slug = slug
+	return err
 }
 
-func (r *ProductRepository) IncrementRecommendation(ctx context.Context, productID uint) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		var rc ec.RecommendationCounter
-		if err := tx.Where("product_id = ?", productID).First(&rc).Error; err != nil {
-			if err == gorm.ErrRecordNotFound {
-				rc = ec.RecommendationCounter{ProductID: productID, Count: 1}
-				return tx.Create(&rc).Error
+// updateProduct writes the editable columns of a product; a change of its
+// base price is recorded with reason, and a running sale no longer below
+// the new base price ends.
+func updateProduct(tx *gorm.DB, id uint, p ec.Product, reason string) error {
+	renamed, err := renamedSince(tx, ec.SlugProduct, id, p.Name)
+	if err != nil {
+		return err
+	}
+	if err := trackProductPrice(tx, id, p.BasePrice, reason, nil); err != nil {
+		return err
+	}
+	columns := []string{"sku", "name", "short_description", "long_description", "base_price", "base_production_time_days", "category_id", "image_url",
+		"default_width", "default_height", "default_depth", "base_material", "quantity", "seo_title", "seo_description"}
+	if p.Status != "" {
+		columns = append(columns, "status", "publish_at", "unpublish_at")
+	}
+	if err := tx.Model(&ec.Product{}).Where("id = ?", id).Select(columns).Updates(p).Error; err != nil {
+		return err
+	}
+	if err := endSaleAbove(tx, id, p.BasePrice, time.Now()); err != nil {
+		return err
+	}
+	if err := replaceAttributes(tx, id, p.CategoryID, p.Attributes); err != nil {
+		return err
+	}
+	_, err = assignSlug(tx, ec.SlugProduct, id, p.Slug, p.Name, renamed)
+	return err
+}
+
+// replaceAttributes drops the values of attributes outside the product's
+// category and, unless attrs is nil, replaces the remaining ones with attrs.
+func replaceAttributes(tx *gorm.DB, productID, categoryID uint, attrs []ec.ProductAttribute) error {
+	q := tx.Where("product_id = ?", productID)
+	if attrs == nil {
+		q = q.Where("attribute_id NOT IN (SELECT id FROM attribute_definitions WHERE category_id = ?)", categoryID)
+	}
+	if err := q.Delete(&ec.ProductAttribute{}).Error; err != nil {
+		return err
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	for i := range attrs {
+		attrs[i].ID = 0
+		attrs[i].ProductID = productID
+		attrs[i].Attribute = nil
+	}
+	return tx.Create(&attrs).Error
+}
+
+// ApplySchedule publishes the scheduled products whose publish time has
+// come and discontinues the published ones past their unpublish time. It
+// returns the ids of the products it changed.
+func (r *ProductRepository) ApplySchedule(ctx context.Context, now time.Time) ([]uint, error) {
+	var changed []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, step := range []struct{ from, to, column string }{
+			{ec.ProductScheduled, ec.ProductPublished, "publish_at"},
+			{ec.ProductPublished, ec.ProductDiscontinued, "unpublish_at"},
+		} {
+			var ids []uint
+			if err := tx.Model(&ec.Product{}).
+				Where("status = ? AND "+step.column+" <= ?", step.from, now).
+				Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				continue
+			}
+			if err := tx.Model(&ec.Product{}).Where("id IN ?", ids).
+				Updates(map[string]any{"status": step.to, "updated_at": now}).Error; err != nil {
+				return err
 			}
-			return err
+			changed = append(changed, ids...)
 		}
-		return tx.Model(&rc).UpdateColumn("count", gorm.Expr("count + 1")).Error
+		return nil
 	})
+	return changed, err
 }
 
 func (r *ProductRepository) AdjustQuantity(ctx context.Context, productID uint, delta int) error {
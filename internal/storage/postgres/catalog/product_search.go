@@ -0,0 +1,269 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	ec "furniture-shop/internal/entities/catalog"
+)
+
+// minTermSimilarity is the pg_trgm similarity above which an indexed word is
+// accepted as the correction of a misspelled query word.
+const minTermSimilarity = 0.3
+
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=12"
+
+// searchFrom matches the query both stemmed, against the English default
+// texts, and as typed, against the translations of the requested locale and
+// its fallbacks, indexed with 'simple'; v.vector joins the two.
+const searchFrom = `FROM products p
+JOIN categories c ON c.id = p.category_id
+JOIN departments d ON d.id = c.department_id
+CROSS JOIN LATERAL (SELECT to_tsquery('english', ?) || to_tsquery('simple', ?)) AS q(tsq)
+CROSS JOIN LATERAL (
+  SELECT COALESCE(p.search_vector, ''::tsvector) || tsvector_agg(l.search_vector)
+    FROM product_search_locales l
+   WHERE l.product_id = p.id AND l.locale = ANY(?::text[])
+) AS v(vector)`
+
+// Search runs a ranked full-text query. All words must match; when nothing
+// does, the query is retried with any word matching. Words that are not in the
+// index are expanded with their closest indexed spelling.
+func (r *ProductRepository) Search(ctx context.Context, p ec.ProductSearchParams) (*ec.ProductSearchResult, error) {
+	res := &ec.ProductSearchResult{Query: p.Query, Limit: p.Limit, Offset: p.Offset, Hits: []ec.ProductSearchHit{}}
+	words := searchWords(p.Query)
+	if len(words) == 0 {
+		return res, nil
+	}
+	corrections, err := r.correctWords(ctx, words)
+	if err != nil {
+		return nil, err
+	}
+	if len(corrections) > 0 {
+		res.Corrections = corrections
+	}
+
+	tsq := buildTSQuery(words, corrections, " & ")
+	if res.Total, err = r.countMatches(ctx, tsq, p); err != nil {
+		return nil, err
+	}
+	if res.Total == 0 && len(words) > 1 {
+		tsq = buildTSQuery(words, corrections, " | ")
+		if res.Total, err = r.countMatches(ctx, tsq, p); err != nil {
+			return nil, err
+		}
+	}
+	if res.Total == 0 {
+		return res, nil
+	}
+
+	where, args := searchFilters(tsq, p, "")
+	// highlight the texts of the requested locale, falling back field by
+	// field to the default text, escaped so that <mark> is the only markup
+	query := `SELECT p.id,
+  ts_rank_cd(v.vector, q.tsq, 32) AS rank,
+  ts_headline(CASE WHEN tr.name IS NULL THEN 'english' ELSE 'simple' END::regconfig, ` + htmlEscapeSQL("COALESCE(tr.name, p.name)") + `, q.tsq, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight,
+  ts_headline(CASE WHEN tr.short_description IS NULL AND tr.long_description IS NULL THEN 'english' ELSE 'simple' END::regconfig, ` + htmlEscapeSQL("concat_ws(' ', COALESCE(tr.short_description, p.short_description), COALESCE(tr.long_description, p.long_description))") + `, q.tsq, '` + headlineOptions + `') AS description_highlight
+` + searchFrom + `
+LEFT JOIN LATERAL (
+  SELECT (array_agg(value ORDER BY array_position(?::text[], locale)) FILTER (WHERE field = 'name'))[1] AS name,
+         (array_agg(value ORDER BY array_position(?::text[], locale)) FILTER (WHERE field = 'short_description'))[1] AS short_description,
+         (array_agg(value ORDER BY array_position(?::text[], locale)) FILTER (WHERE field = 'long_description'))[1] AS long_description
+    FROM catalog_translations
+   WHERE kind = 'product' AND record_id = p.id AND locale = ANY(?::text[])
+) tr ON true` + where + `
+ORDER BY rank DESC, p.id
+LIMIT ? OFFSET ?`
+	locales := localeArray(p.Locale)
+	args = append([]any{args[0], args[1], args[2], locales, locales, locales, locales}, args[3:]...)
+	var ranked []struct {
+		ID                   uint
+		Rank                 float64
+		NameHighlight        string
+		DescriptionHighlight string
+	}
+	if err := r.db.WithContext(ctx).Raw(query, append(args, p.Limit, p.Offset)...).Scan(&ranked).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(ranked))
+	for i, h := range ranked {
+		ids[i] = h.ID
+	}
+	var products []ec.Product
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]ec.Product, len(products))
+	for _, pr := range products {
+		byID[pr.ID] = pr
+	}
+	for _, h := range ranked {
+		res.Hits = append(res.Hits, ec.ProductSearchHit{
+			Product:              byID[h.ID],
+			Rank:                 h.Rank,
+			NameHighlight:        h.NameHighlight,
+			DescriptionHighlight: h.DescriptionHighlight,
+		})
+	}
+
+	if res.Facets.Departments, err = r.facet(ctx, tsq, p, "department", "d.id::text", "d.name"); err != nil {
+		return nil, err
+	}
+	if res.Facets.Categories, err = r.facet(ctx, tsq, p, "category", "c.id::text", "c.name"); err != nil {
+		return nil, err
+	}
+	if res.Facets.Materials, err = r.facet(ctx, tsq, p, "material", "p.base_material", "p.base_material"); err != nil {
+		return nil, err
+	}
+	bucket := priceBucketSQL()
+	if res.Facets.PriceRanges, err = r.facet(ctx, tsq, p, "price", bucket, bucket); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// localeArray is a text[] literal of the locales whose translations apply
+// to locale: the locale itself, then its base language (bg-BG, bg). The
+// default locale has no translations, so it needs no special case.
+func localeArray(locale string) string {
+	locales := []string{}
+	if locale != "" {
+		locales = append(locales, locale)
+	}
+	if base, _, ok := strings.Cut(locale, "-"); ok {
+		locales = append(locales, base)
+	}
+	return "{" + strings.Join(locales, ",") + "}"
+}
+
+func (r *ProductRepository) countMatches(ctx context.Context, tsq string, p ec.ProductSearchParams) (int64, error) {
+	where, args := searchFilters(tsq, p, "")
+	var n int64
+	err := r.db.WithContext(ctx).Raw("SELECT COUNT(*) "+searchFrom+where, args...).Scan(&n).Error
+	return n, err
+}
+
+// facet counts matches grouped by valueExpr, applying every filter except the facet's own.
+func (r *ProductRepository) facet(ctx context.Context, tsq string, p ec.ProductSearchParams, name, valueExpr, labelExpr string) ([]ec.FacetCount, error) {
+	where, args := searchFilters(tsq, p, name)
+	out := []ec.FacetCount{}
+	query := fmt.Sprintf("SELECT %s AS value, %s AS label, COUNT(*) AS count %s%s GROUP BY 1, 2 ORDER BY count DESC, label", valueExpr, labelExpr, searchFrom, where)
+	err := r.db.WithContext(ctx).Raw(query, args...).Scan(&out).Error
+	return out, err
+}
+
+// RefreshSearch recomputes the search vectors of the given products; clearing
+// the column fires the products_search_refresh trigger.
+func (r *ProductRepository) RefreshSearch(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Exec("UPDATE products SET search_vector = NULL WHERE id IN ?", ids).Error
+}
+
+// correctWords looks up the closest indexed word for every query word that is
+// not itself indexed.
+func (r *ProductRepository) correctWords(ctx context.Context, words []string) (map[string]string, error) {
+	var rows []struct {
+		Word string
+		Term string
+	}
+	err := r.db.WithContext(ctx).Raw(`SELECT w.word, c.term
+FROM unnest(string_to_array(?, ' ')) AS w(word)
+CROSS JOIN LATERAL (
+  SELECT s.term FROM product_search_terms s
+   WHERE similarity(s.term, w.word) >= ?
+   ORDER BY similarity(s.term, w.word) DESC, s.term
+   LIMIT 1
+) c
+WHERE NOT EXISTS (SELECT 1 FROM product_search_terms e WHERE e.term = w.word)`, strings.Join(words, " "), minTermSimilarity).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	for _, row := range rows {
+		out[row.Word] = row.Term
+	}
+	return out, nil
+}
+
+// searchFilters builds the WHERE clause shared by hits, count and facets; the
+// first three arguments are always the tsquery and locales bound in
+// searchFrom.
+func searchFilters(tsq string, p ec.ProductSearchParams, exclude string) (string, []any) {
+	conds := []string{"v.vector @@ q.tsq", "p.deleted_at IS NULL", "p.status = '" + ec.ProductPublished + "'"}
+	args := []any{tsq, tsq, localeArray(p.Locale)}
+	if p.DepartmentID != 0 && exclude != "department" {
+		conds = append(conds, "d.id = ?")
+		args = append(args, p.DepartmentID)
+	}
+	if p.CategoryID != 0 && exclude != "category" {
+		conds = append(conds, "c.id = ?")
+		args = append(args, p.CategoryID)
+	}
+	if p.Material != "" && exclude != "material" {
+		conds = append(conds, "lower(p.base_material) = lower(?)")
+		args = append(args, p.Material)
+	}
+	if exclude != "price" {
+		if p.PriceMin != nil {
+			conds = append(conds, "COALESCE(p.sale_price, p.base_price) >= ?")
+			args = append(args, *p.PriceMin)
+		}
+		if p.PriceMax != nil {
+			conds = append(conds, "COALESCE(p.sale_price, p.base_price) < ?")
+			args = append(args, *p.PriceMax)
+		}
+	}
+	return "\nWHERE " + strings.Join(conds, " AND "), args
+}
+
+// htmlEscapeSQL escapes the HTML special characters of a text expression.
+// The default text search parser reads the entities as tokens of their own,
+// which ts_headline copies but never marks.
+func htmlEscapeSQL(expr string) string {
+	return "replace(replace(replace(replace(" + expr + `, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;')`
+}
+
+// priceBucketSQL is ec.PriceBucket as a SQL expression over the current price.
+func priceBucketSQL() string {
+	var b strings.Builder
+	b.WriteString("CASE")
+	for _, bk := range ec.PriceBuckets {
+		if bk[1] == 0 {
+			fmt.Fprintf(&b, " ELSE '%g-'", bk[0])
+			continue
+		}
+		fmt.Fprintf(&b, " WHEN COALESCE(p.sale_price, p.base_price) < %g THEN '%g-%g'", bk[1], bk[0], bk[1])
+	}
+	b.WriteString(" END")
+	return b.String()
+}
+
+// searchWords lower-cases the query and splits it into letter/digit words,
+// which also keeps tsquery operators out of user input.
+func searchWords(q string) []string {
+	return strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// buildTSQuery joins the words with op. A word with a correction matches
+// either spelling, and the last word also matches as a prefix so that
+// results follow the user's typing.
+func buildTSQuery(words []string, corrections map[string]string, op string) string {
+	parts := make([]string, len(words))
+	for i, w := range words {
+		term := w
+		if i == len(words)-1 {
+			term += ":*"
+		}
+		if c, ok := corrections[w]; ok {
+			term = "(" + term + " | " + c + ")"
+		}
+		parts[i] = term
+	}
+	return strings.Join(parts, op)
+}
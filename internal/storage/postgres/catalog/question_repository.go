@@ -0,0 +1,152 @@
+package catalog
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
+)
+
+type QuestionRepository struct {
+	db *gorm.DB
+}
+
+func NewQuestionRepository(db *gorm.DB) storage.QuestionRepository {
+	return &QuestionRepository{db: db}
+}
+
+// List returns one page of questions matching f, newest first, with their
+// answers (official ones first) and, when not listing a single product's
+// questions, their product.
+func (r *QuestionRepository) List(ctx context.Context, f ec.QuestionFilter, page query.Page) ([]ec.Question, int64, error) {
+	var total int64
+	if err := r.filtered(ctx, f).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	q := r.filtered(ctx, f).Preload("Answers", func(db *gorm.DB) *gorm.DB {
+		if f.AnswerStatus != "" {
+			db = db.Where("status = ?", f.AnswerStatus)
+		}
+		return db.Order("official DESC, created_at, id")
+	})
+	if f.ProductID == 0 {
+		q = q.Preload("Product", func(db *gorm.DB) *gorm.DB { return db.Unscoped().Select("id, name, slug, image_url") })
+	}
+	var out []ec.Question
+	err := q.Order("questions.created_at DESC, questions.id DESC").Scopes(query.Paginate(page)).Find(&out).Error
+	return out, total, err
+}
+
+func (r *QuestionRepository) filtered(ctx context.Context, f ec.QuestionFilter) *gorm.DB {
+	q := r.db.WithContext(ctx).Model(&ec.Question{})
+	if f.ProductID != 0 {
+		q = q.Where("questions.product_id = ?", f.ProductID)
+	}
+	if f.UserID != 0 {
+		q = q.Where("questions.user_id = ?", f.UserID)
+	}
+	if f.Status != "" {
+		q = q.Where("questions.status = ?", f.Status)
+	}
+	if f.Answered != nil {
+		exists := "EXISTS (SELECT 1 FROM answers a WHERE a.question_id = questions.id AND a.status = ?)"
+		if !*f.Answered {
+			exists = "NOT " + exists
+		}
+		q = q.Where(exists, ec.QuestionApproved)
+	}
+	return q
+}
+
+// FindByID returns a question with all its answers.
+func (r *QuestionRepository) FindByID(ctx context.Context, id uint) (*ec.Question, error) {
+	var out ec.Question
+	err := r.db.WithContext(ctx).
+		Preload("Answers", func(db *gorm.DB) *gorm.DB { return db.Order("official DESC, created_at, id") }).
+		First(&out, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *QuestionRepository) Create(ctx context.Context, q *ec.Question) error {
+	return r.db.WithContext(ctx).Omit("Answers", "Product").Create(q).Error
+}
+
+func (r *QuestionRepository) SetStatus(ctx context.Context, id uint, status string, at time.Time) error {
+	return setModeration(r.db.WithContext(ctx).Model(&ec.Question{}), id, status, at)
+}
+
+func (r *QuestionRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("question_id = ?", id).Delete(&ec.Answer{}).Error; err != nil {
+			return err
+		}
+		res := tx.Delete(&ec.Question{}, id)
+		if res.Error == nil && res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return res.Error
+	})
+}
+
+// ListAnswers returns one page of answers in a moderation state, oldest
+// first, with their question and its product.
+func (r *QuestionRepository) ListAnswers(ctx context.Context, status string, page query.Page) ([]ec.Answer, int64, error) {
+	q := r.db.WithContext(ctx).Model(&ec.Answer{})
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var out []ec.Answer
+	err := q.Preload("Question").
+		Preload("Question.Product", func(db *gorm.DB) *gorm.DB { return db.Unscoped().Select("id, name, slug, image_url") }).
+		Order("created_at, id").
+		Scopes(query.Paginate(page)).
+		Find(&out).Error
+	return out, total, err
+}
+
+func (r *QuestionRepository) FindAnswer(ctx context.Context, id uint) (*ec.Answer, error) {
+	var out ec.Answer
+	if err := r.db.WithContext(ctx).First(&out, id).Error; err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (r *QuestionRepository) CreateAnswer(ctx context.Context, a *ec.Answer) error {
+	return r.db.WithContext(ctx).Omit("Question").Create(a).Error
+}
+
+func (r *QuestionRepository) SetAnswerStatus(ctx context.Context, id uint, status string, at time.Time) error {
+	return setModeration(r.db.WithContext(ctx).Model(&ec.Answer{}), id, status, at)
+}
+
+func (r *QuestionRepository) MarkAnswerNotified(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&ec.Answer{}).Where("id = ?", id).Update("notified_at", at).Error
+}
+
+func (r *QuestionRepository) DeleteAnswer(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&ec.Answer{}, id)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
+
+func setModeration(q *gorm.DB, id uint, status string, at time.Time) error {
+	res := q.Where("id = ?", id).Updates(map[string]any{"status": status, "moderated_at": at})
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
@@ -0,0 +1,127 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	ea "furniture-shop/internal/entities/analytics"
+	ec "furniture-shop/internal/entities/catalog"
+	eo "furniture-shop/internal/entities/orders"
+	"furniture-shop/internal/storage"
+)
+
+type RecommendationRepository struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository(db *gorm.DB) storage.RecommendationRepository {
+	return &RecommendationRepository{db: db}
+}
+
+// associationsSQL scores every pair of products sharing a basket by cosine
+// similarity (shared baskets / sqrt(baskets of a * baskets of b)) and keeps
+// the best pairs per product. %s is a query yielding (basket, product_id).
+const associationsSQL = `WITH baskets AS (%s),
+item_counts AS (
+  SELECT product_id, COUNT(DISTINCT basket) AS n FROM baskets GROUP BY product_id
+),
+pairs AS (
+  SELECT a.product_id, b.product_id AS related_product_id, COUNT(DISTINCT a.basket) AS support
+    FROM baskets a JOIN baskets b ON b.basket = a.basket AND b.product_id <> a.product_id
+   GROUP BY 1, 2
+  HAVING COUNT(DISTINCT a.basket) >= ?
+),
+scored AS (
+  SELECT p.product_id, p.related_product_id, p.support,
+         p.support / sqrt(ia.n::float * ib.n) AS score
+    FROM pairs p
+    JOIN item_counts ia ON ia.product_id = p.product_id
+    JOIN item_counts ib ON ib.product_id = p.related_product_id
+),
+ranked AS (
+  SELECT *, row_number() OVER (PARTITION BY product_id ORDER BY score DESC, related_product_id) AS rn FROM scored
+)
+INSERT INTO product_associations (product_id, related_product_id, kind, support, score, updated_at)
+SELECT product_id, related_product_id, ?, support, score, now() FROM ranked WHERE rn <= ?`
+
+const orderBasketsSQL = `SELECT oi.order_id::text AS basket, oi.product_id
+  FROM order_items oi JOIN orders o ON o.id = oi.order_id
+ WHERE o.created_at >= ? AND o.status <> ?`
+
+const viewBasketsSQL = `SELECT session_id AS basket, product_id
+  FROM events
+ WHERE type = ? AND occurred_at >= ? AND session_id <> ''`
+
+func (r *RecommendationRepository) RebuildBoughtTogether(ctx context.Context, since time.Time, minSupport, perProduct int) (int64, error) {
+	return r.rebuild(ctx, ec.RecommendBoughtTogether, orderBasketsSQL, []any{since, eo.OrderStatusCancelled}, minSupport, perProduct)
+}
+
+func (r *RecommendationRepository) RebuildViewedTogether(ctx context.Context, since time.Time, minSupport, perProduct int) (int64, error) {
+	return r.rebuild(ctx, ec.RecommendViewedTogether, viewBasketsSQL, []any{ea.EventProductViewed, since}, minSupport, perProduct)
+}
+
+// rebuild replaces every association of a kind in one transaction, so
+// readers never see a half-computed set.
+func (r *RecommendationRepository) rebuild(ctx context.Context, kind, baskets string, args []any, minSupport, perProduct int) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("kind = ?", kind).Delete(&ec.ProductAssociation{}).Error; err != nil {
+			return err
+		}
+		res := tx.Exec(fmt.Sprintf(associationsSQL, baskets), append(args, minSupport, kind, perProduct)...)
+		n = res.RowsAffected
+		return res.Error
+	})
+	return n, err
+}
+
+func (r *RecommendationRepository) ListAssociated(ctx context.Context, productID uint, kind string, limit int) ([]ec.Product, error) {
+	var out []ec.Product
+	err := r.db.WithContext(ctx).Model(&ec.Product{}).
+		Joins("JOIN product_associations pa ON pa.related_product_id = products.id").
+		Where("pa.product_id = ? AND pa.kind = ? AND products.status = ?", productID, kind, ec.ProductPublished).
+		Order("pa.score DESC, products.id").
+		Limit(limit).
+		Find(&out).Error
+	return out, err
+}
+
+// ListOverrides returns the overrides of a product together with the global
+// ones, or every override when productID is nil.
+func (r *RecommendationRepository) ListOverrides(ctx context.Context, productID *uint) ([]ec.RecommendationOverride, error) {
+	var out []ec.RecommendationOverride
+	q := r.db.WithContext(ctx)
+	order := "product_id, position, id"
+	if productID != nil {
+		// global and per-product pins share one sequence of positions;
+		// at equal positions the product's own pin comes first
+		q = q.Where("product_id IN ?", []uint{0, *productID})
+		order = "position, product_id DESC, id"
+	}
+	err := q.Order(order).Find(&out).Error
+	return out, err
+}
+
+// SaveOverride upserts by (product, related product), so switching a pin to an
+// exclusion replaces it.
+func (r *RecommendationRepository) SaveOverride(ctx context.Context, o *ec.RecommendationOverride) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}, {Name: "related_product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"action", "position", "updated_at"}),
+	}).Create(o).Error
+}
+
+func (r *RecommendationRepository) DeleteOverride(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&ec.RecommendationOverride{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package catalog
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
+)
+
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepository(db *gorm.DB) storage.ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+var reviewSorts = query.Sorts{
+	ec.ReviewSortNewest:     "reviews.created_at DESC, reviews.id DESC",
+	ec.ReviewSortOldest:     "reviews.created_at ASC, reviews.id",
+	ec.ReviewSortRatingDesc: "reviews.rating DESC, reviews.created_at DESC, reviews.id DESC",
+	ec.ReviewSortRatingAsc:  "reviews.rating ASC, reviews.created_at DESC, reviews.id DESC",
+}
+
+// List returns one page of reviews matching f with their photos, and with
+// their product when not listing a single product's reviews.
+func (r *ReviewRepository) List(ctx context.Context, f ec.ReviewFilter, page query.Page) ([]ec.Review, int64, error) {
+	order, err := reviewSorts.Resolve(f.Sort, ec.ReviewSortNewest)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	if err := r.filtered(ctx, f).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	q := r.filtered(ctx, f).
+		Preload("Photos", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") })
+	if f.ProductID == 0 {
+		q = q.Preload("Product", func(db *gorm.DB) *gorm.DB { return db.Unscoped().Select("id, name, slug, image_url") })
+	}
+	var out []ec.Review
+	err = q.Order(order).Scopes(query.Paginate(page)).Find(&out).Error
+	return out, total, err
+}
+
+func (r *ReviewRepository) filtered(ctx context.Context, f ec.ReviewFilter) *gorm.DB {
+	q := r.db.WithContext(ctx).Model(&ec.Review{})
+	if f.ProductID != 0 {
+		q = q.Where("reviews.product_id = ?", f.ProductID)
+	}
+	if f.UserID != 0 {
+		q = q.Where("reviews.user_id = ?", f.UserID)
+	}
+	if f.Status != "" {
+		q = q.Where("reviews.status = ?", f.Status)
+	}
+	if f.Rating != nil {
+		q = q.Where("reviews.rating = ?", *f.Rating)
+	}
+	if f.Verified != nil {
+		q = q.Where("reviews.verified = ?", *f.Verified)
+	}
+	if f.WithPhotos {
+		q = q.Where("EXISTS (SELECT 1 FROM review_photos rp WHERE rp.review_id = reviews.id)")
+	}
+	return q
+}
+
+func (r *ReviewRepository) FindByID(ctx context.Context, id uint) (*ec.Review, error) {
+	var out ec.Review
+	err := r.db.WithContext(ctx).
+		Preload("Photos", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }).
+		First(&out, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// FindByUser returns the review a user wrote of a product, or nil when
+// they have not reviewed it.
+func (r *ReviewRepository) FindByUser(ctx context.Context, productID, userID uint) (*ec.Review, error) {
+	var out ec.Review
+	res := r.db.WithContext(ctx).
+		Preload("Photos", func(db *gorm.DB) *gorm.DB { return db.Order("position, id") }).
+		Where("product_id = ? AND user_id = ?", productID, userID).
+		Limit(1).
+		Find(&out)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return nil, res.Error
+	}
+	return &out, nil
+}
+
+// Save creates rv, or updates it when it has an id. Photos replace the
+// existing ones unless nil.
+func (r *ReviewRepository) Save(ctx context.Context, rv *ec.Review) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		photos := rv.Photos
+		if rv.ID == 0 {
+			if err := tx.Omit("Photos", "Product").Create(rv).Error; err != nil {
+				return err
+			}
+		} else {
+			err := tx.Model(&ec.Review{}).Where("id = ?", rv.ID).
+				Select("rating", "title", "body", "author_name", "verified", "status", "moderation_note", "moderated_at").
+				Updates(rv).Error
+			if err != nil {
+				return err
+			}
+		}
+		if photos != nil {
+			if err := tx.Where("review_id = ?", rv.ID).Delete(&ec.ReviewPhoto{}).Error; err != nil {
+				return err
+			}
+			for i := range photos {
+				photos[i].ID = 0
+				photos[i].ReviewID = rv.ID
+				photos[i].Position = i
+			}
+			if len(photos) > 0 {
+				if err := tx.Create(&photos).Error; err != nil {
+					return err
+				}
+			}
+			rv.Photos = photos
+		}
+		return refreshRating(tx, rv.ProductID)
+	})
+}
+
+// Moderate sets the moderation outcome of a review and the verified flag
+// re-derived at the same time.
+func (r *ReviewRepository) Moderate(ctx context.Context, id uint, status, note string, verified bool, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rv ec.Review
+		if err := tx.Select("id, product_id").First(&rv, id).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&ec.Review{}).Where("id = ?", id).Updates(map[string]any{
+			"status":          status,
+			"moderation_note": note,
+			"verified":        verified,
+			"moderated_at":    at,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return refreshRating(tx, rv.ProductID)
+	})
+}
+
+func (r *ReviewRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rv ec.Review
+		if err := tx.Select("id, product_id").First(&rv, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ?", id).Delete(&ec.ReviewPhoto{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&ec.Review{}, id).Error; err != nil {
+			return err
+		}
+		return refreshRating(tx, rv.ProductID)
+	})
+}
+
+// Summary aggregates the approved reviews of a product per rating.
+func (r *ReviewRepository) Summary(ctx context.Context, productID uint) (*ec.RatingSummary, error) {
+	var rows []struct {
+		Rating   int
+		Count    int
+		Verified int
+	}
+	err := r.db.WithContext(ctx).Model(&ec.Review{}).
+		Select("rating, COUNT(*) AS count, COUNT(*) FILTER (WHERE verified) AS verified").
+		Where("product_id = ? AND status = ?", productID, ec.ReviewApproved).
+		Group("rating").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	out := &ec.RatingSummary{}
+	sum := 0
+	for _, row := range rows {
+		if row.Rating < 1 || row.Rating > 5 {
+			continue
+		}
+		out.Stars[row.Rating-1] = row.Count
+		out.Count += row.Count
+		out.Verified += row.Verified
+		sum += row.Rating * row.Count
+	}
+	if out.Count > 0 {
+		out.Average = float64(sum) / float64(out.Count)
+	}
+	return out, nil
+}
+
+// refreshRating recomputes the rating aggregates of a product from its
+// approved reviews.
+func refreshRating(tx *gorm.DB, productID uint) error {
+	return tx.Exec(`UPDATE products SET
+  rating_average = COALESCE((SELECT ROUND(AVG(rating)::numeric, 2) FROM reviews WHERE product_id = products.id AND status = ?), 0),
+  rating_count = (SELECT COUNT(*) FROM reviews WHERE product_id = products.id AND status = ?)
+WHERE id = ?`, ec.ReviewApproved, ec.ReviewApproved, productID).Error
+}
@@ -0,0 +1,149 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+)
+
+// slugTables maps slug kinds to the tables holding them.
+var slugTables = map[string]string{
+	ec.SlugDepartment: "departments",
+	ec.SlugCategory:   "categories",
+	ec.SlugProduct:    "products",
+}
+
+const maxSlugLen = 150
+
+type SlugRepository struct {
+	db *gorm.DB
+}
+
+func NewSlugRepository(db *gorm.DB) storage.SlugRepository {
+	return &SlugRepository{db: db}
+}
+
+// Resolve finds the record of kind whose current or former slug is slug.
+func (r *SlugRepository) Resolve(ctx context.Context, kind, slug string) (*ec.SlugRef, error) {
+	table, ok := slugTables[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown slug kind %q", kind)
+	}
+	var row struct {
+		ID   uint
+		Slug string
+	}
+	err := r.db.WithContext(ctx).Table(table).Select("id, slug").Where("slug = ?", slug).Take(&row).Error
+	if err == nil {
+		return &ec.SlugRef{ID: row.ID, Slug: row.Slug}, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	err = r.db.WithContext(ctx).Table(table+" t").
+		Select("t.id, t.slug").
+		Joins("JOIN slug_redirects sr ON sr.target_id = t.id").
+		Where("sr.kind = ? AND sr.slug = ?", kind, slug).
+		Take(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ec.SlugRef{ID: row.ID, Slug: row.Slug, Moved: true}, nil
+}
+
+// Backfill gives a slug to every record that has none, e.g. rows created
+// before slugs existed or by the seeder.
+func (r *SlugRepository) Backfill(ctx context.Context) (int, error) {
+	n := 0
+	for _, kind := range []string{ec.SlugDepartment, ec.SlugCategory, ec.SlugProduct} {
+		var rows []struct {
+			ID   uint
+			Name string
+		}
+		if err := r.db.WithContext(ctx).Table(slugTables[kind]).Select("id, name").Where("slug = ''").Order("id").Find(&rows).Error; err != nil {
+			return n, err
+		}
+		for _, row := range rows {
+			err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+				_, err := assignSlug(tx, kind, row.ID, "", row.Name, false)
+				return err
+			})
+			if err != nil {
+				return n, err
+			}
+			n++
+		}
+	}
+	return n, nil
+}
+
+// assignSlug sets the slug of record id of kind and returns it. A requested
+// slug is normalized and used; otherwise one is derived from name when the
+// record has none yet or was renamed. Taken slugs get a numeric suffix, and
+// the replaced slug is kept as a redirect to the record.
+func assignSlug(tx *gorm.DB, kind string, id uint, requested, name string, renamed bool) (string, error) {
+	table := slugTables[kind]
+	var current string
+	if err := tx.Table(table).Where("id = ?", id).Pluck("slug", &current).Error; err != nil {
+		return "", err
+	}
+	base := ec.Slugify(requested)
+	if base == "" {
+		if current != "" && !renamed {
+			return current, nil
+		}
+		base = ec.Slugify(name)
+	}
+	if len(base) > maxSlugLen {
+		base = strings.TrimSuffix(base[:maxSlugLen], "-")
+	}
+	// numeric slugs would be read as ids
+	if base == "" || strings.Trim(base, "0123456789") == "" {
+		base = strings.TrimSuffix(kind+"-"+base, "-")
+	}
+	slug := base
+	for n := 2; slug != current; n++ {
+		var taken int64
+		if err := tx.Table(table).Where("slug = ? AND id <> ?", slug, id).Count(&taken).Error; err != nil {
+			return "", err
+		}
+		if taken == 0 {
+			break
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	if slug == current {
+		return current, nil
+	}
+	if err := tx.Where("kind = ? AND slug = ?", kind, slug).Delete(&ec.SlugRedirect{}).Error; err != nil {
+		return "", err
+	}
+	if current != "" {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "kind"}, {Name: "slug"}},
+			DoUpdates: clause.AssignmentColumns([]string{"target_id", "created_at"}),
+		}).Create(&ec.SlugRedirect{Kind: kind, Slug: current, TargetID: id}).Error
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := tx.Table(table).Where("id = ?", id).UpdateColumn("slug", slug).Error; err != nil {
+		return "", err
+	}
+	return slug, nil
+}
+
+// renamedSince reports whether record id of kind has a name other than name.
+func renamedSince(tx *gorm.DB, kind string, id uint, name string) (bool, error) {
+	var old string
+	if err := tx.Table(slugTables[kind]).Where("id = ?", id).Pluck("name", &old).Error; err != nil {
+		return false, err
+	}
+	return old != name, nil
+}
@@ -0,0 +1,126 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+)
+
+type TransferRepository struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) storage.CatalogTransferRepository {
+	return &TransferRepository{db: db}
+}
+
+// Apply writes an import plan in one transaction, resolving references by
+// name and SKU as it goes, so that a failure leaves the catalog untouched.
+func (r *TransferRepository) Apply(ctx context.Context, plan *ec.ImportPlan) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var depts []ec.Department
+		if err := tx.Find(&depts).Error; err != nil {
+			return err
+		}
+		deptIDs := map[string]uint{}
+		for _, d := range depts {
+			deptIDs[ec.NameKey(d.Name)] = d.ID
+		}
+		for _, d := range plan.Departments {
+			if d.ID != 0 {
+				if err := updateDepartment(tx, d.ID, d); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := createDepartment(tx, &d); err != nil {
+				return err
+			}
+			deptIDs[ec.NameKey(d.Name)] = d.ID
+		}
+
+		var cats []ec.Category
+		if err := tx.Find(&cats).Error; err != nil {
+			return err
+		}
+		catIDs := map[string]uint{}
+		for _, c := range cats {
+			catIDs[categoryKey(c.DepartmentID, c.Name)] = c.ID
+		}
+		for _, pc := range plan.Categories {
+			c := pc.Category
+			deptID, ok := deptIDs[ec.NameKey(pc.Department)]
+			if !ok {
+				return fmt.Errorf("department %q not found", pc.Department)
+			}
+			c.DepartmentID = deptID
+			if c.ID != 0 {
+				if err := updateCategory(tx, c.ID, c); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := createCategory(tx, &c); err != nil {
+				return err
+			}
+			catIDs[categoryKey(deptID, c.Name)] = c.ID
+		}
+
+		productIDs := map[string]uint{}
+		for _, pp := range plan.Products {
+			p := pp.Product
+			catID, ok := catIDs[categoryKey(deptIDs[ec.NameKey(pp.Department)], pp.Category)]
+			if !ok {
+				return fmt.Errorf("category %q of department %q not found", pp.Category, pp.Department)
+			}
+			p.CategoryID = catID
+			if p.ID != 0 {
+				if err := updateProduct(tx, p.ID, p, ec.PriceChangeImport); err != nil {
+					return err
+				}
+			} else if err := createProduct(tx, &p); err != nil {
+				return err
+			}
+			productIDs[p.SKU] = p.ID
+		}
+
+		for _, po := range plan.Options {
+			o := po.ProductOption
+			if o.ProductID == 0 {
+				id, ok := productIDs[po.ProductSKU]
+				if !ok {
+					if err := tx.Model(&ec.Product{}).Where("sku = ?", po.ProductSKU).Pluck("id", &id).Error; err != nil {
+						return err
+					}
+				}
+				if id == 0 {
+					return fmt.Errorf("product %q not found", po.ProductSKU)
+				}
+				o.ProductID = id
+			}
+			if o.ID != 0 {
+				if err := trackOptionPrice(tx, o.ID, o.PriceModifierType, o.PriceModifierValue, ec.PriceChangeImport, nil); err != nil {
+					return err
+				}
+				if err := tx.Model(&ec.ProductOption{}).Where("id = ?", o.ID).
+					Select("option_type", "option_name", "price_modifier_type", "price_modifier_value", "production_time_modifier_days", "production_time_modifier_percent").
+					Updates(o).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Create(&o).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func categoryKey(departmentID uint, name string) string {
+	return fmt.Sprintf("%d/%s", departmentID, ec.NameKey(name))
+}
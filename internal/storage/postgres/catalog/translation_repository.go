@@ -0,0 +1,67 @@
+package catalog
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	ec "furniture-shop/internal/entities/catalog"
+	"furniture-shop/internal/storage"
+)
+
+type TranslationRepository struct {
+	db *gorm.DB
+}
+
+func NewTranslationRepository(db *gorm.DB) storage.TranslationRepository {
+	return &TranslationRepository{db: db}
+}
+
+func (r *TranslationRepository) List(ctx context.Context, kind string, ids []uint, locales []string) ([]ec.Translation, error) {
+	q := r.db.WithContext(ctx).Where("kind = ?", kind)
+	if ids != nil {
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		q = q.Where("record_id IN ?", ids)
+	}
+	if locales != nil {
+		if len(locales) == 0 {
+			return nil, nil
+		}
+		q = q.Where("locale IN ?", locales)
+	}
+	var out []ec.Translation
+	err := q.Order("record_id, locale, field").Find(&out).Error
+	return out, err
+}
+
+// Save upserts the given fields of a record in a locale; an empty value
+// removes the field's translation.
+func (r *TranslationRepository) Save(ctx context.Context, kind string, id uint, locale string, values map[string]string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for field, value := range values {
+			if value == "" {
+				if err := tx.Where("kind = ? AND record_id = ? AND locale = ? AND field = ?", kind, id, locale, field).
+					Delete(&ec.Translation{}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "kind"}, {Name: "record_id"}, {Name: "locale"}, {Name: "field"}},
+				DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+			}).Create(&ec.Translation{Kind: kind, RecordID: id, Locale: locale, Field: field, Value: value}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *TranslationRepository) Delete(ctx context.Context, kind string, id uint, locale string) error {
+	return r.db.WithContext(ctx).Where("kind = ? AND record_id = ? AND locale = ?", kind, id, locale).
+		Delete(&ec.Translation{}).Error
+}
@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	en "furniture-shop/internal/entities/notification"
+	"furniture-shop/internal/storage"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) storage.OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+func (r *OutboxRepository) Enqueue(ctx context.Context, m *en.OutboxMessage) error {
+	if m.Status == "" {
+		m.Status = en.OutboxStatusPending
+	}
+	if m.NextAttemptAt.IsZero() {
+		m.NextAttemptAt = time.Now().UTC()
+	}
+	return r.db.WithContext(ctx).Create(m).Error
+}
+
+// ClaimDue leases up to limit due messages to the caller. Leased rows are moved
+// to "sending" with NextAttemptAt pushed to now+lease, so a crashed worker's
+// messages become due again once the lease expires. SKIP LOCKED lets several
+// instances dispatch concurrently without picking the same rows.
+func (r *OutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]en.OutboxMessage, error) {
+	var out []en.OutboxMessage
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?", []string{en.OutboxStatusPending, en.OutboxStatusSending}, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&out).Error; err != nil {
+			return err
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(out))
+		for i := range out {
+			ids[i] = out[i].ID
+		}
+		return tx.Model(&en.OutboxMessage{}).Where("id IN ?", ids).
+			Updates(map[string]any{"status": en.OutboxStatusSending, "next_attempt_at": now.Add(lease)}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *OutboxRepository) MarkSent(ctx context.Context, id uint, attempts int, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&en.OutboxMessage{}).Where("id = ?", id).
+		Updates(map[string]any{"status": en.OutboxStatusSent, "attempts": attempts, "sent_at": at, "last_error": ""}).Error
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uint, attempts int, nextAttemptAt time.Time, lastError string, dead bool) error {
+	status := en.OutboxStatusPending
+	if dead {
+		status = en.OutboxStatusDead
+	}
+	return r.db.WithContext(ctx).Model(&en.OutboxMessage{}).Where("id = ?", id).
+		Updates(map[string]any{"status": status, "attempts": attempts, "next_attempt_at": nextAttemptAt, "last_error": lastError}).Error
+}
+
+func (r *OutboxRepository) List(ctx context.Context, status string, limit, offset int) ([]en.OutboxMessage, int64, error) {
+	var out []en.OutboxMessage
+	var total int64
+	q := r.db.WithContext(ctx).Model(&en.OutboxMessage{})
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := q.Order("created_at DESC").Limit(limit).Offset(offset).Find(&out).Error; err != nil {
+		return nil, 0, err
+	}
+	return out, total, nil
+}
+
+func (r *OutboxRepository) FindByID(ctx context.Context, id uint) (*en.OutboxMessage, error) {
+	var m en.OutboxMessage
+	if err := r.db.WithContext(ctx).First(&m, id).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Requeue makes a sent or dead message due immediately with a fresh attempt budget.
+func (r *OutboxRepository) Requeue(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&en.OutboxMessage{}).Where("id = ?", id).
+		Updates(map[string]any{"status": en.OutboxStatusPending, "attempts": 0, "next_attempt_at": time.Now().UTC(), "last_error": ""}).Error
+}
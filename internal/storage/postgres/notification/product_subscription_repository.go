@@ -0,0 +1,76 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	en "furniture-shop/internal/entities/notification"
+	"furniture-shop/internal/storage"
+)
+
+type ProductSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewProductSubscriptionRepository(db *gorm.DB) storage.ProductSubscriptionRepository {
+	return &ProductSubscriptionRepository{db: db}
+}
+
+// Save creates s unless the address already has the same subscription, in
+// which case s is loaded with the existing one, keeping its token.
+func (r *ProductSubscriptionRepository) Save(ctx context.Context, s *en.ProductSubscription) error {
+	return r.db.WithContext(ctx).
+		Where("product_id = ? AND kind = ? AND selected_options_json = ? AND email = ?", s.ProductID, s.Kind, s.SelectedOptionsJSON, s.Email).
+		FirstOrCreate(s).Error
+}
+
+func (r *ProductSubscriptionRepository) FindByID(ctx context.Context, id uint) (*en.ProductSubscription, error) {
+	var s en.ProductSubscription
+	if err := r.db.WithContext(ctx).First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *ProductSubscriptionRepository) FindByToken(ctx context.Context, token string) (*en.ProductSubscription, error) {
+	var s en.ProductSubscription
+	if err := r.db.WithContext(ctx).Where("token = ?", token).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListByProduct returns the confirmed subscriptions to a product.
+func (r *ProductSubscriptionRepository) ListByProduct(ctx context.Context, productID uint) ([]en.ProductSubscription, error) {
+	var out []en.ProductSubscription
+	err := r.db.WithContext(ctx).Where("product_id = ? AND confirmed_at IS NOT NULL", productID).Order("id").Find(&out).Error
+	return out, err
+}
+
+func (r *ProductSubscriptionRepository) ListByUser(ctx context.Context, userID uint) ([]en.ProductSubscription, error) {
+	var out []en.ProductSubscription
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&out).Error
+	return out, err
+}
+
+func (r *ProductSubscriptionRepository) Confirm(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&en.ProductSubscription{}).Where("id = ? AND confirmed_at IS NULL", id).Update("confirmed_at", at).Error
+}
+
+func (r *ProductSubscriptionRepository) SetConfirmationSent(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&en.ProductSubscription{}).Where("id = ?", id).Update("confirmation_sent_at", at).Error
+}
+
+func (r *ProductSubscriptionRepository) SetAlertPrice(ctx context.Context, id uint, price float64) error {
+	return r.db.WithContext(ctx).Model(&en.ProductSubscription{}).Where("id = ?", id).Update("alert_price", price).Error
+}
+
+func (r *ProductSubscriptionRepository) Delete(ctx context.Context, id uint) error {
+	res := r.db.WithContext(ctx).Delete(&en.ProductSubscription{}, id)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
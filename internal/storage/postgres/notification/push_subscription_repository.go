@@ -0,0 +1,53 @@
+package notification
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	en "furniture-shop/internal/entities/notification"
+	"furniture-shop/internal/storage"
+)
+
+type PushSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewPushSubscriptionRepository(db *gorm.DB) storage.PushSubscriptionRepository {
+	return &PushSubscriptionRepository{db: db}
+}
+
+// Save upserts by endpoint: a browser re-subscribing (or another user signing
+// in on the same browser) takes over the existing row.
+func (r *PushSubscriptionRepository) Save(ctx context.Context, s *en.PushSubscription) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "endpoint"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "p256dh", "auth", "user_agent", "updated_at"}),
+	}).Create(s).Error
+}
+
+func (r *PushSubscriptionRepository) FindByID(ctx context.Context, id uint) (*en.PushSubscription, error) {
+	var s en.PushSubscription
+	if err := r.db.WithContext(ctx).First(&s, id).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *PushSubscriptionRepository) ListByUser(ctx context.Context, userID uint) ([]en.PushSubscription, error) {
+	var out []en.PushSubscription
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&out).Error
+	return out, err
+}
+
+func (r *PushSubscriptionRepository) Delete(ctx context.Context, userID, id uint) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&en.PushSubscription{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	eo "furniture-shop/internal/entities/orders"
+	"furniture-shop/internal/storage"
+)
+
+var invoicePrefixes = map[string]string{
+	eo.InvoiceKindInvoice:    "INV",
+	eo.InvoiceKindCreditNote: "CN",
+}
+
+type InvoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) storage.InvoiceRepository {
+	return &InvoiceRepository{db: db}
+}
+
+// Issue assigns the next number of the invoice's kind and year and stores the
+// invoice with its lines. The sequence row is locked for the duration of the
+// transaction, so a failed insert rolls the counter back and no gaps appear.
+func (r *InvoiceRepository) Issue(ctx context.Context, inv *eo.Invoice) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if inv.IssuedAt.IsZero() {
+			inv.IssuedAt = time.Now().UTC()
+		}
+		inv.Year = inv.IssuedAt.Year()
+		seq := eo.InvoiceSequence{Kind: inv.Kind, Year: inv.Year}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&seq).Error; err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("kind = ? AND year = ?", inv.Kind, inv.Year).First(&seq).Error; err != nil {
+			return err
+		}
+		seq.LastNumber++
+		if err := tx.Model(&eo.InvoiceSequence{}).Where("kind = ? AND year = ?", inv.Kind, inv.Year).
+			Update("last_number", seq.LastNumber).Error; err != nil {
+			return err
+		}
+		inv.Sequence = seq.LastNumber
+		inv.Number = fmt.Sprintf("%s-%d-%06d", invoicePrefixes[inv.Kind], inv.Year, inv.Sequence)
+		return tx.Create(inv).Error
+	})
+}
+
+func (r *InvoiceRepository) FindByID(ctx context.Context, id uint) (*eo.Invoice, error) {
+	var inv eo.Invoice
+	if err := r.db.WithContext(ctx).Preload("Lines").First(&inv, id).Error; err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *InvoiceRepository) FindOrderInvoice(ctx context.Context, orderID uint) (*eo.Invoice, error) {
+	var inv eo.Invoice
+	if err := r.db.WithContext(ctx).Preload("Lines").
+		Where("order_id = ? AND kind = ? AND amendment_id IS NULL", orderID, eo.InvoiceKindInvoice).
+		First(&inv).Error; err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *InvoiceRepository) FindByAmendment(ctx context.Context, amendmentID uint) (*eo.Invoice, error) {
+	var inv eo.Invoice
+	if err := r.db.WithContext(ctx).Preload("Lines").Where("amendment_id = ?", amendmentID).First(&inv).Error; err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
+
+func (r *InvoiceRepository) ListByOrder(ctx context.Context, orderID uint) ([]eo.Invoice, error) {
+	var out []eo.Invoice
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("issued_at ASC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *InvoiceRepository) ListIssuedBetween(ctx context.Context, from, to time.Time, kind string) ([]eo.Invoice, error) {
+	var out []eo.Invoice
+	q := r.db.WithContext(ctx).Preload("Lines").Where("issued_at >= ? AND issued_at < ?", from, to)
+	if kind != "" {
+		q = q.Where("kind = ?", kind)
+	}
+	if err := q.Order("kind ASC, year ASC, sequence ASC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -2,11 +2,15 @@ package orders
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	ec "furniture-shop/internal/entities/catalog"
 	eo "furniture-shop/internal/entities/orders"
 	"furniture-shop/internal/storage"
+	"furniture-shop/internal/storage/query"
 )
 
 type OrderRepository struct {
@@ -50,16 +54,42 @@ func (r *OrderRepository) FindWithItems(ctx context.Context, id uint) (*eo.Order
 	return &o, nil
 }
 
-func (r *OrderRepository) ListAll(ctx context.Context, status string) ([]eo.Order, error) {
-	var orders []eo.Order
-	q := r.db.WithContext(ctx).Order("created_at DESC")
-	if status != "" {
-		q = q.Where("status = ?", status)
+var orderSorts = query.Sorts{
+	eo.OrderSortNewest:    "created_at DESC, id DESC",
+	eo.OrderSortOldest:    "created_at ASC, id ASC",
+	eo.OrderSortTotalDesc: "total_price DESC, id DESC",
+	eo.OrderSortTotalAsc:  "total_price ASC, id ASC",
+}
+
+// List returns one page of orders matching f and the total number of matches.
+func (r *OrderRepository) List(ctx context.Context, f eo.OrderFilter, page query.Page) ([]eo.Order, int64, error) {
+	order, err := orderSorts.Resolve(f.Sort, eo.OrderSortNewest)
+	if err != nil {
+		return nil, 0, err
 	}
-	if err := q.Find(&orders).Error; err != nil {
-		return nil, err
+	q := r.db.WithContext(ctx).Model(&eo.Order{})
+	if f.Status != "" {
+		q = q.Where("status = ?", f.Status)
 	}
-	return orders, nil
+	if f.PaymentStatus != "" {
+		q = q.Where("payment_status = ?", f.PaymentStatus)
+	}
+	if f.UserID != 0 {
+		q = q.Where("user_id = ?", f.UserID)
+	}
+	if f.From != nil {
+		q = q.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		q = q.Where("created_at < ?", *f.To)
+	}
+	var total int64
+	if err := q.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var orders []eo.Order
+	err = q.Order(order).Scopes(query.Paginate(page)).Find(&orders).Error
+	return orders, total, err
 }
 
 func (r *OrderRepository) UpdateStatus(ctx context.Context, id uint, status string) error {
@@ -77,3 +107,136 @@ func (r *OrderRepository) CountByStatus(ctx context.Context, status string) (int
 func (r *OrderRepository) UpdatePaymentStatus(ctx context.Context, id uint, status string) error {
 	return r.db.WithContext(ctx).Model(&eo.Order{}).Where("id = ?", id).Update("payment_status", status).Error
 }
+
+func (r *OrderRepository) UpdatePaymentReference(ctx context.Context, id uint, ref string) error {
+	return r.db.WithContext(ctx).Model(&eo.Order{}).Where("id = ?", id).Update("payment_reference", ref).Error
+}
+
+// ApplyAmendment writes the amended lines and totals of an order together
+// with its amendment record and the stock changes it causes, given as
+// quantity deltas per product. base is the order the amendment was computed
+// from; the order is locked and, when it is no longer amendable or differs
+// from base, eo.ErrAmendmentConflict is returned and nothing is written.
+func (r *OrderRepository) ApplyAmendment(ctx context.Context, base, o *eo.Order, removedItemIDs []uint, stock map[uint]int, a *eo.OrderAmendment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cur eo.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&cur, o.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("order_id = ?", o.ID).Find(&cur.Items).Error; err != nil {
+			return err
+		}
+		if !eo.Amendable(cur.Status) || cur.PaymentStatus != base.PaymentStatus ||
+			cur.TotalPrice != base.TotalPrice || !sameItems(cur.Items, base.Items) {
+			return eo.ErrAmendmentConflict
+		}
+		for pid, delta := range stock {
+			if err := tx.Model(&ec.Product{}).Where("id = ?", pid).
+				UpdateColumn("quantity", gorm.Expr("GREATEST(quantity + ?, 0)", delta)).Error; err != nil {
+				return err
+			}
+		}
+		if len(removedItemIDs) > 0 {
+			if err := tx.Where("order_id = ? AND id IN ?", o.ID, removedItemIDs).Delete(&eo.OrderItem{}).Error; err != nil {
+				return err
+			}
+		}
+		for i := range o.Items {
+			it := &o.Items[i]
+			it.OrderID = o.ID
+			if it.ID == 0 {
+				if err := tx.Create(it).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Model(&eo.OrderItem{}).Where("id = ? AND order_id = ?", it.ID, o.ID).
+				Select("quantity", "unit_price", "line_total", "calculated_production_time_days", "selected_options_json").
+				Updates(it).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&eo.Order{}).Where("id = ?", o.ID).
+			Select("total_price", "estimated_production_time_days").
+			Updates(o).Error; err != nil {
+			return err
+		}
+		a.OrderID = o.ID
+		return tx.Create(a).Error
+	})
+}
+
+// sameItems reports whether two sets of order lines agree on everything an
+// amendment is priced from.
+func sameItems(a, b []eo.OrderItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	byID := make(map[uint]eo.OrderItem, len(b))
+	for _, it := range b {
+		byID[it.ID] = it
+	}
+	for _, it := range a {
+		other, ok := byID[it.ID]
+		if !ok || it.ProductID != other.ProductID || it.Quantity != other.Quantity ||
+			it.UnitPrice != other.UnitPrice || it.SelectedOptionsJSON != other.SelectedOptionsJSON ||
+			it.BundleGroup != other.BundleGroup {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *OrderRepository) ListAmendments(ctx context.Context, orderID uint) ([]eo.OrderAmendment, error) {
+	var out []eo.OrderAmendment
+	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("created_at ASC").Find(&out).Error; err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *OrderRepository) FindAmendment(ctx context.Context, id uint) (*eo.OrderAmendment, error) {
+	var a eo.OrderAmendment
+	if err := r.db.WithContext(ctx).First(&a, id).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *OrderRepository) UpdateAmendmentSettlement(ctx context.Context, id uint, status, ref, checkoutURL string) error {
+	return r.db.WithContext(ctx).Model(&eo.OrderAmendment{}).Where("id = ?", id).
+		Updates(map[string]any{"settlement_status": status, "settlement_reference": ref, "checkout_url": checkoutURL}).Error
+}
+
+// UpdateDeliveryDate schedules (or clears) the delivery day of an order. A new
+// date re-arms the delivery-day reminder.
+func (r *OrderRepository) UpdateDeliveryDate(ctx context.Context, id uint, date *time.Time) error {
+	return r.db.WithContext(ctx).Model(&eo.Order{}).Where("id = ?", id).
+		Updates(map[string]any{"delivery_date": date, "delivery_reminder_sent_at": nil}).Error
+}
+
+// ListDeliveryRemindersDue returns shipped orders to be delivered on day that
+// have not been reminded about yet.
+func (r *OrderRepository) ListDeliveryRemindersDue(ctx context.Context, day time.Time) ([]eo.Order, error) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	var out []eo.Order
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND delivery_date >= ? AND delivery_date < ? AND delivery_reminder_sent_at IS NULL", eo.OrderStatusShipped, start, start.AddDate(0, 0, 1)).
+		Find(&out).Error
+	return out, err
+}
+
+func (r *OrderRepository) MarkDeliveryReminderSent(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&eo.Order{}).Where("id = ?", id).Update("delivery_reminder_sent_at", at).Error
+}
+
+func (r *OrderRepository) HasDelivered(ctx context.Context, userID, productID uint) (bool, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&eo.OrderItem{}).
+		Joins("JOIN orders o ON o.id = order_items.order_id").
+		Where("o.user_id = ? AND o.status = ?", userID, eo.OrderStatusDelivered).
+		Where("order_items.product_id = ? OR order_items.bundle_product_id = ?", productID, productID).
+		Limit(1).
+		Count(&n).Error
+	return n > 0, err
+}
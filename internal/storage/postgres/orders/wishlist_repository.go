@@ -0,0 +1,115 @@
+package orders
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	eo "furniture-shop/internal/entities/orders"
+	"furniture-shop/internal/storage"
+)
+
+type WishlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWishlistRepository(db *gorm.DB) storage.WishlistRepository {
+	return &WishlistRepository{db: db}
+}
+
+func preloadItems(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("created_at, id") })
+}
+
+func (r *WishlistRepository) ListByUser(ctx context.Context, userID uint) ([]eo.Wishlist, error) {
+	var out []eo.Wishlist
+	err := r.db.WithContext(ctx).Scopes(preloadItems).Where("user_id = ?", userID).Order("created_at, id").Find(&out).Error
+	return out, err
+}
+
+func (r *WishlistRepository) FindByID(ctx context.Context, id uint) (*eo.Wishlist, error) {
+	var w eo.Wishlist
+	if err := r.db.WithContext(ctx).Scopes(preloadItems).First(&w, id).Error; err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *WishlistRepository) FindByShareToken(ctx context.Context, token string) (*eo.Wishlist, error) {
+	var w eo.Wishlist
+	if err := r.db.WithContext(ctx).Scopes(preloadItems).Where("share_token = ?", token).First(&w).Error; err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func (r *WishlistRepository) Create(ctx context.Context, w *eo.Wishlist) error {
+	return r.db.WithContext(ctx).Omit("Items").Create(w).Error
+}
+
+func (r *WishlistRepository) Rename(ctx context.Context, id uint, name string) error {
+	return r.db.WithContext(ctx).Model(&eo.Wishlist{}).Where("id = ?", id).Update("name", name).Error
+}
+
+func (r *WishlistRepository) SetShareToken(ctx context.Context, id uint, token *string) error {
+	return r.db.WithContext(ctx).Model(&eo.Wishlist{}).Where("id = ?", id).Update("share_token", token).Error
+}
+
+func (r *WishlistRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("wishlist_id = ?", id).Delete(&eo.WishlistItem{}).Error; err != nil {
+			return err
+		}
+		res := tx.Delete(&eo.Wishlist{}, id)
+		if res.Error == nil && res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return res.Error
+	})
+}
+
+func (r *WishlistRepository) AddItem(ctx context.Context, item *eo.WishlistItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing eo.WishlistItem
+		err := tx.Where("wishlist_id = ? AND product_id = ? AND selected_options_json = ?", item.WishlistID, item.ProductID, item.SelectedOptionsJSON).
+			First(&existing).Error
+		if err == gorm.ErrRecordNotFound {
+			return tx.Omit("Wishlist").Create(item).Error
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&existing).UpdateColumn("quantity", gorm.Expr("quantity + ?", item.Quantity)).Error; err != nil {
+			return err
+		}
+		return tx.First(item, existing.ID).Error
+	})
+}
+
+func (r *WishlistRepository) UpdateItem(ctx context.Context, wishlistID, itemID uint, item eo.WishlistItem) error {
+	res := r.db.WithContext(ctx).Model(&eo.WishlistItem{}).
+		Where("id = ? AND wishlist_id = ?", itemID, wishlistID).
+		Select("quantity", "selected_options_json", "alert_price").
+		Updates(item)
+	if res.Error == nil && res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return res.Error
+}
+
+func (r *WishlistRepository) RemoveItems(ctx context.Context, wishlistID uint, itemIDs []uint) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("wishlist_id = ? AND id IN ?", wishlistID, itemIDs).Delete(&eo.WishlistItem{}).Error
+}
+
+func (r *WishlistRepository) ListItemsByProduct(ctx context.Context, productID uint) ([]eo.WishlistItem, error) {
+	var out []eo.WishlistItem
+	err := r.db.WithContext(ctx).Preload("Wishlist").Where("product_id = ?", productID).Order("id").Find(&out).Error
+	return out, err
+}
+
+func (r *WishlistRepository) SetAlertPrice(ctx context.Context, itemID uint, price float64) error {
+	return r.db.WithContext(ctx).Model(&eo.WishlistItem{}).Where("id = ?", itemID).Update("alert_price", price).Error
+}
@@ -4,7 +4,9 @@ import (
 	"gorm.io/gorm"
 
 	"furniture-shop/internal/storage"
+	pganalytics "furniture-shop/internal/storage/postgres/analytics"
 	pgadmin "furniture-shop/internal/storage/postgres/catalog"
+	pgnotification "furniture-shop/internal/storage/postgres/notification"
 	pgorders "furniture-shop/internal/storage/postgres/orders"
 	pguser "furniture-shop/internal/storage/postgres/user"
 )
@@ -12,12 +14,29 @@ import (
 // NewRepository wires Postgres-backed repositories
 func NewRepository(db *gorm.DB) *storage.Repository {
 	return &storage.Repository{
-		Users:          pguser.NewUserRepository(db),
-		Departments:    pgadmin.NewDepartmentRepository(db),
-		Categories:     pgadmin.NewCategoryRepository(db),
-		Products:       pgadmin.NewProductRepository(db),
-		ProductOptions: pgadmin.NewProductOptionRepository(db),
-		Orders:         pgorders.NewOrderRepository(db),
-		Carts:          pgorders.NewCartRepository(db),
+		Users:           pguser.NewUserRepository(db),
+		Departments:     pgadmin.NewDepartmentRepository(db),
+		Categories:      pgadmin.NewCategoryRepository(db),
+		Products:        pgadmin.NewProductRepository(db),
+		ProductOptions:  pgadmin.NewProductOptionRepository(db),
+		ProductImages:   pgadmin.NewProductImageRepository(db),
+		CatalogTransfer: pgadmin.NewTransferRepository(db),
+		Slugs:           pgadmin.NewSlugRepository(db),
+		Archive:         pgadmin.NewArchiveRepository(db),
+		Translations:    pgadmin.NewTranslationRepository(db),
+		Attributes:      pgadmin.NewAttributeRepository(db),
+		Bundles:         pgadmin.NewBundleRepository(db),
+		Prices:          pgadmin.NewPriceRepository(db),
+		Reviews:         pgadmin.NewReviewRepository(db),
+		Questions:       pgadmin.NewQuestionRepository(db),
+		Recommendations: pgadmin.NewRecommendationRepository(db),
+		Orders:          pgorders.NewOrderRepository(db),
+		Carts:           pgorders.NewCartRepository(db),
+		Wishlists:       pgorders.NewWishlistRepository(db),
+		Invoices:        pgorders.NewInvoiceRepository(db),
+		Outbox:          pgnotification.NewOutboxRepository(db),
+		PushSubs:        pgnotification.NewPushSubscriptionRepository(db),
+		ProductSubs:     pgnotification.NewProductSubscriptionRepository(db),
+		Events:          pganalytics.NewEventRepository(db),
 	}
 }
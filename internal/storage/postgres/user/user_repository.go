@@ -36,3 +36,7 @@ func (r *UserRepository) FindByID(ctx context.Context, id uint) (*eu.User, error
 	}
 	return &u, nil
 }
+
+func (r *UserRepository) UpdateNotifyChannels(ctx context.Context, id uint, channels string) error {
+	return r.db.WithContext(ctx).Model(&eu.User{}).Where("id = ?", id).Update("notify_channels", channels).Error
+}
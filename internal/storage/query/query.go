@@ -0,0 +1,96 @@
+// Package query holds the pagination and sorting primitives shared by the
+// listing endpoints: repositories take a Page and return the matching rows
+// together with the total count, services wrap both into a Result.
+package query
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultSize = 24
+	MaxSize     = 100
+)
+
+// ErrInvalidSort is returned for a sort key the listing does not support.
+var ErrInvalidSort = errors.New("invalid sort")
+
+// Page is a 1-based page number and page size.
+type Page struct {
+	Number int
+	Size   int
+}
+
+// NewPage clamps the requested page into the supported range.
+func NewPage(number, size int) Page {
+	if number < 1 {
+		number = 1
+	}
+	if size < 1 {
+		size = DefaultSize
+	}
+	if size > MaxSize {
+		size = MaxSize
+	}
+	return Page{Number: number, Size: size}
+}
+
+func (p Page) Offset() int {
+	return (p.Number - 1) * p.Size
+}
+
+// Paginate is a gorm scope limiting a query to the page.
+func Paginate(p Page) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Offset(p.Offset()).Limit(p.Size)
+	}
+}
+
+type PageInfo struct {
+	Total   int64 `json:"total"`
+	Page    int   `json:"page"`
+	Size    int   `json:"size"`
+	Pages   int   `json:"pages"`
+	HasNext bool  `json:"has_next"`
+	HasPrev bool  `json:"has_prev"`
+}
+
+type Result[T any] struct {
+	Items      []T      `json:"items"`
+	Pagination PageInfo `json:"pagination"`
+}
+
+func NewResult[T any](items []T, total int64, p Page) *Result[T] {
+	if items == nil {
+		items = []T{}
+	}
+	pages := int((total + int64(p.Size) - 1) / int64(p.Size))
+	return &Result[T]{
+		Items: items,
+		Pagination: PageInfo{
+			Total:   total,
+			Page:    p.Number,
+			Size:    p.Size,
+			Pages:   pages,
+			HasNext: p.Number < pages,
+			HasPrev: p.Number > 1,
+		},
+	}
+}
+
+// Sorts maps the public sort keys of a listing to ORDER BY clauses.
+type Sorts map[string]string
+
+// Resolve returns the clause for key, or for def when key is empty.
+func (s Sorts) Resolve(key, def string) (string, error) {
+	if key == "" {
+		key = def
+	}
+	clause, ok := s[key]
+	if !ok {
+		return "", ErrInvalidSort
+	}
+	return clause, nil
+}
@@ -2,10 +2,14 @@ package storage
 
 import (
 	"context"
+	"time"
 
+	ea "furniture-shop/internal/entities/analytics"
 	ec "furniture-shop/internal/entities/catalog"
+	en "furniture-shop/internal/entities/notification"
 	eo "furniture-shop/internal/entities/orders"
 	eu "furniture-shop/internal/entities/user"
+	"furniture-shop/internal/storage/query"
 )
 
 // User
@@ -13,14 +17,15 @@ type UserRepository interface {
 	Create(ctx context.Context, u *eu.User) error
 	FindByEmail(ctx context.Context, email string) (*eu.User, error)
 	FindByID(ctx context.Context, id uint) (*eu.User, error)
+	UpdateNotifyChannels(ctx context.Context, id uint, channels string) error
 }
 
 // Catalog
 type DepartmentRepository interface {
 	List(ctx context.Context) ([]ec.Department, error)
 	Create(ctx context.Context, d *ec.Department) error
+	ListArchived(ctx context.Context) ([]ec.Department, error)
 	Update(ctx context.Context, id uint, d ec.Department) error
-	Delete(ctx context.Context, id uint) error
 }
 
 // Category
@@ -28,21 +33,152 @@ type CategoryRepository interface {
 	ListByDepartment(ctx context.Context, departmentID uint) ([]ec.Category, error)
 	ListAll(ctx context.Context) ([]ec.Category, error)
 	Create(ctx context.Context, c *ec.Category) error
+	ListArchived(ctx context.Context) ([]ec.Category, error)
 	Update(ctx context.Context, id uint, c ec.Category) error
-	Delete(ctx context.Context, id uint) error
 }
 
 type ProductRepository interface {
-	ListByCategory(ctx context.Context, categoryID uint) ([]ec.Product, error)
+	List(ctx context.Context, f ec.ProductFilter, page query.Page) ([]ec.Product, int64, error)
 	FindByID(ctx context.Context, id uint) (*ec.Product, error)
-	Search(ctx context.Context, query string, limit int) ([]ec.Product, error)
+	FindIncludingArchived(ctx context.Context, id uint) (*ec.Product, error)
+	ListByIDs(ctx context.Context, ids []uint) ([]ec.Product, error)
+	Search(ctx context.Context, p ec.ProductSearchParams) (*ec.ProductSearchResult, error)
+	RefreshSearch(ctx context.Context, ids []uint) error
 	ListRecommendations(ctx context.Context, p *ec.Product, limit int) ([]ec.Product, error)
-	IncrementRecommendation(ctx context.Context, productID uint) error
 	ListAll(ctx context.Context) ([]ec.Product, error)
 	Create(ctx context.Context, p *ec.Product) error
 	Update(ctx context.Context, id uint, p ec.Product) error
-	Delete(ctx context.Context, id uint) error
 	AdjustQuantity(ctx context.Context, productID uint, delta int) error
+	ApplySchedule(ctx context.Context, now time.Time) ([]uint, error)
+}
+
+// ProductImageRepository stores product galleries and their renditions.
+type ProductImageRepository interface {
+	ListByProduct(ctx context.Context, productID uint) ([]ec.ProductImage, error)
+	FindByID(ctx context.Context, id uint) (*ec.ProductImage, error)
+	Create(ctx context.Context, img *ec.ProductImage) error
+	Update(ctx context.Context, id uint, img ec.ProductImage) error
+	Reorder(ctx context.Context, productID uint, ids []uint) error
+	Delete(ctx context.Context, id uint) error
+	SetCover(ctx context.Context, productID uint, url string) error
+	ReferencedURLs(ctx context.Context) ([]string, error)
+	ListOriginalURLs(ctx context.Context) ([]string, error)
+	ReplaceURL(ctx context.Context, from, to string) (int64, error)
+}
+
+// SlugRepository resolves department, category and product slugs, including
+// former ones kept as redirects.
+type SlugRepository interface {
+	Resolve(ctx context.Context, kind, slug string) (*ec.SlugRef, error)
+	Backfill(ctx context.Context) (int, error)
+}
+
+// CatalogArchiveRepository soft-deletes and restores departments, categories
+// and products, by slug kind. Both return the ids of the products whose
+// visibility changed.
+type CatalogArchiveRepository interface {
+	Archive(ctx context.Context, kind string, id uint, cascade bool) ([]uint, error)
+	Restore(ctx context.Context, kind string, id uint, cascade bool) ([]uint, error)
+}
+
+// AttributeRepository stores the attribute definitions of categories.
+type AttributeRepository interface {
+	ListByCategory(ctx context.Context, categoryID uint) ([]ec.AttributeDefinition, error)
+	FindByID(ctx context.Context, id uint) (*ec.AttributeDefinition, error)
+	Create(ctx context.Context, d *ec.AttributeDefinition) error
+	Update(ctx context.Context, id uint, d ec.AttributeDefinition) error
+	Delete(ctx context.Context, id uint) error
+	CountValues(ctx context.Context, id uint) (int64, error)
+	Facets(ctx context.Context, categoryID uint) ([]ec.AttributeFacet, error)
+}
+
+// BundleRepository stores the components of bundle products.
+type BundleRepository interface {
+	Find(ctx context.Context, productID uint) (*ec.Bundle, error)
+	ListContaining(ctx context.Context, productID uint) ([]uint, error)
+	Save(ctx context.Context, b *ec.Bundle) error
+	Delete(ctx context.Context, productID uint) error
+}
+
+// PriceRepository keeps the price history and price schedules of products.
+// Base price and option modifier updates made through the product, option
+// and import repositories are recorded as they are written.
+type PriceRepository interface {
+	ListHistory(ctx context.Context, productID uint, page query.Page) ([]ec.PriceChange, int64, error)
+	ListSchedules(ctx context.Context, productID uint) ([]ec.PriceSchedule, error)
+	FindSchedule(ctx context.Context, id uint) (*ec.PriceSchedule, error)
+	CreateSchedule(ctx context.Context, s *ec.PriceSchedule) error
+	// DeleteSchedule removes a schedule that has not started.
+	DeleteSchedule(ctx context.Context, id uint) error
+	// ListDue returns the pending schedules whose start has come and the
+	// running sales whose end has come.
+	ListDue(ctx context.Context, now time.Time) ([]ec.PriceSchedule, error)
+	// ApplySchedule starts or finishes a schedule, setting and recording
+	// the prices it calls for; a running sale is ended early.
+	ApplySchedule(ctx context.Context, id uint, now time.Time) error
+}
+
+// ReviewRepository stores product reviews and keeps the rating aggregates
+// of products in step with the approved ones.
+type ReviewRepository interface {
+	List(ctx context.Context, f ec.ReviewFilter, page query.Page) ([]ec.Review, int64, error)
+	FindByID(ctx context.Context, id uint) (*ec.Review, error)
+	FindByUser(ctx context.Context, productID, userID uint) (*ec.Review, error)
+	Save(ctx context.Context, r *ec.Review) error
+	Moderate(ctx context.Context, id uint, status, note string, verified bool, at time.Time) error
+	Delete(ctx context.Context, id uint) error
+	Summary(ctx context.Context, productID uint) (*ec.RatingSummary, error)
+}
+
+// QuestionRepository stores customer questions about products and their
+// answers.
+type QuestionRepository interface {
+	List(ctx context.Context, f ec.QuestionFilter, page query.Page) ([]ec.Question, int64, error)
+	FindByID(ctx context.Context, id uint) (*ec.Question, error)
+	Create(ctx context.Context, q *ec.Question) error
+	SetStatus(ctx context.Context, id uint, status string, at time.Time) error
+	Delete(ctx context.Context, id uint) error
+	ListAnswers(ctx context.Context, status string, page query.Page) ([]ec.Answer, int64, error)
+	FindAnswer(ctx context.Context, id uint) (*ec.Answer, error)
+	CreateAnswer(ctx context.Context, a *ec.Answer) error
+	SetAnswerStatus(ctx context.Context, id uint, status string, at time.Time) error
+	MarkAnswerNotified(ctx context.Context, id uint, at time.Time) error
+	DeleteAnswer(ctx context.Context, id uint) error
+}
+
+// TranslationRepository stores the translated text of catalog records.
+type TranslationRepository interface {
+	// List returns translations of records of a kind; nil ids means every
+	// record and nil locales every locale.
+	List(ctx context.Context, kind string, ids []uint, locales []string) ([]ec.Translation, error)
+	Save(ctx context.Context, kind string, id uint, locale string, values map[string]string) error
+	Delete(ctx context.Context, kind string, id uint, locale string) error
+}
+
+// CatalogTransferRepository applies bulk catalog imports.
+type CatalogTransferRepository interface {
+	Apply(ctx context.Context, plan *ec.ImportPlan) error
+}
+
+// RecommendationRepository stores the precomputed product associations and
+// the admin overrides behind product recommendations.
+type RecommendationRepository interface {
+	RebuildBoughtTogether(ctx context.Context, since time.Time, minSupport, perProduct int) (int64, error)
+	RebuildViewedTogether(ctx context.Context, since time.Time, minSupport, perProduct int) (int64, error)
+	ListAssociated(ctx context.Context, productID uint, kind string, limit int) ([]ec.Product, error)
+	ListOverrides(ctx context.Context, productID *uint) ([]ec.RecommendationOverride, error)
+	SaveOverride(ctx context.Context, o *ec.RecommendationOverride) error
+	DeleteOverride(ctx context.Context, id uint) error
+}
+
+// EventRepository stores raw behavior events and their daily aggregates.
+type EventRepository interface {
+	InsertBatch(ctx context.Context, events []ea.Event) (int64, error)
+	RollupDay(ctx context.Context, day time.Time) error
+	RecomputePopularity(ctx context.Context, since time.Time) error
+	PruneEvents(ctx context.Context, before time.Time) (int64, error)
+	ProductStats(ctx context.Context, f ea.StatsFilter, page query.Page) ([]ea.ProductStats, int64, error)
+	DailyTotals(ctx context.Context, from, to time.Time, productID uint) ([]ea.DailyTotals, error)
 }
 
 // Cart persistence
@@ -55,8 +191,30 @@ type CartRepository interface {
 	Clear(ctx context.Context, userID uint) error
 }
 
+// WishlistRepository stores wishlists and their items. Item methods are
+// scoped to the wishlist they belong to.
+type WishlistRepository interface {
+	ListByUser(ctx context.Context, userID uint) ([]eo.Wishlist, error)
+	FindByID(ctx context.Context, id uint) (*eo.Wishlist, error)
+	FindByShareToken(ctx context.Context, token string) (*eo.Wishlist, error)
+	Create(ctx context.Context, w *eo.Wishlist) error
+	Rename(ctx context.Context, id uint, name string) error
+	SetShareToken(ctx context.Context, id uint, token *string) error
+	Delete(ctx context.Context, id uint) error
+	// AddItem merges the item into an item of the same configuration,
+	// adding up the quantities.
+	AddItem(ctx context.Context, item *eo.WishlistItem) error
+	UpdateItem(ctx context.Context, wishlistID, itemID uint, item eo.WishlistItem) error
+	RemoveItems(ctx context.Context, wishlistID uint, itemIDs []uint) error
+	// ListItemsByProduct returns the items of a product in every wishlist,
+	// with their wishlist.
+	ListItemsByProduct(ctx context.Context, productID uint) ([]eo.WishlistItem, error)
+	SetAlertPrice(ctx context.Context, itemID uint, price float64) error
+}
+
 type ProductOptionRepository interface {
 	List(ctx context.Context, productID *uint) ([]ec.ProductOption, error)
+	FindByID(ctx context.Context, id uint) (*ec.ProductOption, error)
 	Create(ctx context.Context, o *ec.ProductOption) error
 	Update(ctx context.Context, id uint, o ec.ProductOption) error
 	Delete(ctx context.Context, id uint) error
@@ -68,19 +226,89 @@ type OrderRepository interface {
 	ListByUser(ctx context.Context, userID uint) ([]eo.Order, error)
 	FindByID(ctx context.Context, id uint) (*eo.Order, error)
 	FindWithItems(ctx context.Context, id uint) (*eo.Order, error)
-	ListAll(ctx context.Context, status string) ([]eo.Order, error)
+	List(ctx context.Context, f eo.OrderFilter, page query.Page) ([]eo.Order, int64, error)
 	UpdateStatus(ctx context.Context, id uint, status string) error
 	CountByStatus(ctx context.Context, status string) (int64, error)
 	UpdatePaymentStatus(ctx context.Context, id uint, status string) error
+	UpdatePaymentReference(ctx context.Context, id uint, ref string) error
+	ApplyAmendment(ctx context.Context, base, o *eo.Order, removedItemIDs []uint, stock map[uint]int, a *eo.OrderAmendment) error
+	ListAmendments(ctx context.Context, orderID uint) ([]eo.OrderAmendment, error)
+	FindAmendment(ctx context.Context, id uint) (*eo.OrderAmendment, error)
+	UpdateAmendmentSettlement(ctx context.Context, id uint, status, ref, checkoutURL string) error
+	UpdateDeliveryDate(ctx context.Context, id uint, date *time.Time) error
+	ListDeliveryRemindersDue(ctx context.Context, day time.Time) ([]eo.Order, error)
+	MarkDeliveryReminderSent(ctx context.Context, id uint, at time.Time) error
+	// HasDelivered reports whether a user received a product, alone or in a
+	// bundle, in a delivered order.
+	HasDelivered(ctx context.Context, userID, productID uint) (bool, error)
+}
+
+type InvoiceRepository interface {
+	Issue(ctx context.Context, inv *eo.Invoice) error
+	FindByID(ctx context.Context, id uint) (*eo.Invoice, error)
+	FindOrderInvoice(ctx context.Context, orderID uint) (*eo.Invoice, error)
+	FindByAmendment(ctx context.Context, amendmentID uint) (*eo.Invoice, error)
+	ListByOrder(ctx context.Context, orderID uint) ([]eo.Invoice, error)
+	ListIssuedBetween(ctx context.Context, from, to time.Time, kind string) ([]eo.Invoice, error)
+}
+
+// Notifications
+type OutboxRepository interface {
+	Enqueue(ctx context.Context, m *en.OutboxMessage) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]en.OutboxMessage, error)
+	MarkSent(ctx context.Context, id uint, attempts int, at time.Time) error
+	MarkFailed(ctx context.Context, id uint, attempts int, nextAttemptAt time.Time, lastError string, dead bool) error
+	List(ctx context.Context, status string, limit, offset int) ([]en.OutboxMessage, int64, error)
+	FindByID(ctx context.Context, id uint) (*en.OutboxMessage, error)
+	Requeue(ctx context.Context, id uint) error
+}
+
+type PushSubscriptionRepository interface {
+	Save(ctx context.Context, s *en.PushSubscription) error
+	FindByID(ctx context.Context, id uint) (*en.PushSubscription, error)
+	ListByUser(ctx context.Context, userID uint) ([]en.PushSubscription, error)
+	Delete(ctx context.Context, userID, id uint) error
+}
+
+// ProductSubscriptionRepository stores back-in-stock and price-drop
+// subscriptions. Save keeps an existing subscription of the same address,
+// product, kind and options instead of adding another.
+type ProductSubscriptionRepository interface {
+	Save(ctx context.Context, s *en.ProductSubscription) error
+	FindByID(ctx context.Context, id uint) (*en.ProductSubscription, error)
+	FindByToken(ctx context.Context, token string) (*en.ProductSubscription, error)
+	ListByProduct(ctx context.Context, productID uint) ([]en.ProductSubscription, error)
+	ListByUser(ctx context.Context, userID uint) ([]en.ProductSubscription, error)
+	Confirm(ctx context.Context, id uint, at time.Time) error
+	SetConfirmationSent(ctx context.Context, id uint, at time.Time) error
+	SetAlertPrice(ctx context.Context, id uint, price float64) error
+	Delete(ctx context.Context, id uint) error
 }
 
 // Repository is an aggregator passed into services
 type Repository struct {
-	Users          UserRepository
-	Departments    DepartmentRepository
-	Categories     CategoryRepository
-	Products       ProductRepository
-	ProductOptions ProductOptionRepository
-	Orders         OrderRepository
-	Carts          CartRepository
+	Users           UserRepository
+	Departments     DepartmentRepository
+	Categories      CategoryRepository
+	Products        ProductRepository
+	ProductOptions  ProductOptionRepository
+	ProductImages   ProductImageRepository
+	CatalogTransfer CatalogTransferRepository
+	Slugs           SlugRepository
+	Archive         CatalogArchiveRepository
+	Translations    TranslationRepository
+	Attributes      AttributeRepository
+	Bundles         BundleRepository
+	Prices          PriceRepository
+	Reviews         ReviewRepository
+	Questions       QuestionRepository
+	Recommendations RecommendationRepository
+	Orders          OrderRepository
+	Carts           CartRepository
+	Wishlists       WishlistRepository
+	Invoices        InvoiceRepository
+	Outbox          OutboxRepository
+	PushSubs        PushSubscriptionRepository
+	ProductSubs     ProductSubscriptionRepository
+	Events          EventRepository
 }